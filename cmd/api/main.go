@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"strconv"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Branch opening hours need time zones, the runtime image has none
 
 	"mobilka/config"
 	"mobilka/internal/api/routes"
@@ -55,14 +57,17 @@ func main() {
 	app := fiber.New(fiber.Config{
 		AppName:      "Fiber App",
 		ErrorHandler: errorHandler,
-		BodyLimit:    utils.MaxImageSize + 1024*1024, // Max image size + 1MB for other data
+		BodyLimit:    utils.MaxRequestBodySize,
 	})
 
+	// Events are shared by the routes and the listener keeping replicas in sync
+	eventService := service.NewEventService(db)
+
 	// Setup routes
-	routes.SetupRoutes(app, db, cfg)
+	routes.SetupRoutes(app, db, cfg, eventService)
 
 	// Ensure upload directories exist
-	uploadDirs := []string{cfg.ImageUploadPath}
+	uploadDirs := []string{cfg.ImageUploadPath, cfg.PaymentProofUploadPath}
 	for _, dir := range uploadDirs {
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			log.Fatalf("Failed to create upload directory %s: %v", dir, err)
@@ -70,9 +75,25 @@ func main() {
 	}
 
 	// Start subscription checker task
-	subscriptionChecker := setupSubscriptionChecker(db)
+	subscriptionChecker := setupSubscriptionChecker(db, eventService)
 	subscriptionChecker.Start()
 
+	// Start usage rollup task
+	usageRollup := setupUsageRollup(db)
+	usageRollup.Start()
+
+	// Start tier recalculator task
+	tierRecalculator := setupTierRecalculator(db)
+	tierRecalculator.Start()
+
+	// Start loyalty expiry task
+	loyaltyExpiry := setupLoyaltyExpiry(db, cfg)
+	loyaltyExpiry.Start()
+
+	// Start event listener task
+	eventListener := tasks.NewEventListener(eventService, 5*time.Second)
+	eventListener.Start()
+
 	// Print startup information
 	log.Printf("Server starting on port %d", cfg.ServerPort)
 	log.Printf("Environment: %s", cfg.Environment)
@@ -94,6 +115,18 @@ func main() {
 	// Stop subscription checker
 	subscriptionChecker.Stop()
 
+	// Stop usage rollup
+	usageRollup.Stop()
+
+	// Stop tier recalculator
+	tierRecalculator.Stop()
+
+	// Stop loyalty expiry
+	loyaltyExpiry.Stop()
+
+	// Stop event listener, which also ends the open event streams
+	eventListener.Stop()
+
 	// Shutdown server with 5 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -165,32 +198,91 @@ func setupSuperAdmin(db *pgxpool.Pool) error {
 }
 
 // Setup subscription checker task
-func setupSubscriptionChecker(db *pgxpool.Pool) *tasks.SubscriptionChecker {
+func setupSubscriptionChecker(db *pgxpool.Pool, eventService *service.EventService) *tasks.SubscriptionChecker {
 	// Create repositories needed for the subscription checker
 	adminRepo := repository.NewAdminRepository(db)
 	paymentRepo := repository.NewPaymentHistoryRepository(db)
 	subscriptionTierRepo := repository.NewSubscriptionTierRepository(db)
+	couponRepo := repository.NewCouponRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	paymentRefundRepo := repository.NewPaymentRefundRepository(db)
+	ledgerRepo := repository.NewLedgerRepository(db)
 
 	// Create payment service
-	paymentService := service.NewPaymentService(paymentRepo, adminRepo, subscriptionTierRepo)
+	paymentService := service.NewPaymentService(paymentRepo, adminRepo, subscriptionTierRepo, couponRepo, exchangeRateRepo, paymentRefundRepo, ledgerRepo, eventService)
 
 	// Create subscription checker with 12-hour interval
 	return tasks.NewSubscriptionChecker(paymentService, 12*time.Hour)
 }
 
-// Custom error handler
+// Setup usage rollup task
+func setupUsageRollup(db *pgxpool.Pool) *tasks.UsageRollup {
+	// Create repositories needed for the usage rollup
+	usageRepo := repository.NewUsageRepository(db)
+	adminRepo := repository.NewAdminRepository(db)
+
+	// Create usage service
+	usageService := service.NewUsageService(usageRepo, adminRepo)
+
+	// Create usage rollup with 1-hour interval
+	return tasks.NewUsageRollup(usageService, time.Hour)
+}
+
+// Setup tier recalculator task
+func setupTierRecalculator(db *pgxpool.Pool) *tasks.TierRecalculator {
+	// Create repositories needed for the tier recalculator
+	adminRepo := repository.NewAdminRepository(db)
+	subscriptionTierRepo := repository.NewSubscriptionTierRepository(db)
+	tierChangeRepo := repository.NewSubscriptionTierChangeRepository(db)
+
+	// Create tier change service
+	tierChangeService := service.NewTierChangeService(adminRepo, subscriptionTierRepo, tierChangeRepo, service.NewTelegramService())
+
+	// Create tier recalculator with 24-hour interval
+	return tasks.NewTierRecalculator(tierChangeService, 24*time.Hour)
+}
+
+// Setup loyalty expiry task
+func setupLoyaltyExpiry(db *pgxpool.Pool, cfg *config.Config) *tasks.LoyaltyExpiry {
+	// Create repositories needed for the loyalty expiry
+	loyaltyRepo := repository.NewLoyaltyRepository(db)
+	fcmTokenRepo := repository.NewFCMTokenRepository(db)
+
+	// Create loyalty service, sending the expiry reminders
+	loyaltyService := service.NewLoyaltyService(loyaltyRepo, service.NewPushService(cfg.FCMCredentialsFile, fcmTokenRepo))
+
+	// Create loyalty expiry with 1-hour interval
+	return tasks.NewLoyaltyExpiry(loyaltyService, time.Hour)
+}
+
+// errorHandler writes every error in the same envelope: a stable code clients can branch
+// on, a message, optional details such as the failed fields, and the request ID to quote
+// when reporting a problem
 func errorHandler(c *fiber.Ctx, err error) error {
-	// Default 500 status code
-	code := fiber.StatusInternalServerError
+	var appErr *utils.AppError
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		// Errors raised by Fiber itself and by handlers with only a status and a message
+		appErr = &utils.AppError{
+			Err:       err,
+			Message:   fiberErr.Message,
+			Code:      fiberErr.Code,
+			ErrorCode: utils.StatusErrorCode(fiberErr.Code),
+		}
+	} else {
+		appErr = utils.ResolveError(err)
+	}
 
-	if e, ok := err.(*fiber.Error); ok {
-		// Override status code if it's a Fiber error
-		code = e.Code
+	requestID, _ := c.Locals(utils.ContextRequestID).(string)
+	if appErr.Code >= fiber.StatusInternalServerError {
+		log.Printf("Request %s %s %s failed: %v", requestID, c.Method(), c.Path(), appErr.Err)
 	}
 
-	// Return JSON response
-	return c.Status(code).JSON(fiber.Map{
-		"status":  utils.StatusError,
-		"message": err.Error(),
+	return c.Status(appErr.Code).JSON(fiber.Map{
+		"status":     utils.StatusError,
+		"code":       appErr.ErrorCode,
+		"message":    appErr.Message,
+		"details":    appErr.Details,
+		"request_id": requestID,
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -25,8 +26,15 @@ type Config struct {
 	// Environment
 	Environment string
 
+	// Public HTTPS URL of the API, used for webhooks such as Telegram's
+	PublicURL string
+
+	// Firebase service account key file for push notifications to customers
+	FCMCredentialsFile string
+
 	// Upload paths
-	ImageUploadPath string
+	ImageUploadPath        string
+	PaymentProofUploadPath string
 }
 
 // Load loads configuration from environment variables
@@ -59,13 +67,23 @@ func Load() (*Config, error) {
 	// Environment
 	cfg.Environment = getEnv("ENVIRONMENT", "development")
 
+	// Public URL
+	cfg.PublicURL = strings.TrimRight(getEnv("PUBLIC_URL", ""), "/")
+
+	// Push notifications
+	cfg.FCMCredentialsFile = getEnv("FCM_CREDENTIALS_FILE", "")
+
 	// Upload paths
 	cfg.ImageUploadPath = getEnv("IMAGE_UPLOAD_PATH", "./uploads/images/")
+	cfg.PaymentProofUploadPath = getEnv("PAYMENT_PROOF_UPLOAD_PATH", "./uploads/payment-proofs/")
 
 	// Ensure upload directories exist
 	if err := ensureDir(cfg.ImageUploadPath); err != nil {
 		return nil, err
 	}
+	if err := ensureDir(cfg.PaymentProofUploadPath); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// actingAdminID returns the admin a request acts for: the authenticated admin, or for
+// super admins the requested admin when one is given
+func actingAdminID(c *fiber.Ctx, requested int) (int, error) {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return 0, fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+	if requested > 0 && role == utils.RoleSuperAdmin {
+		return requested, nil
+	}
+
+	return adminID, nil
+}
+
+// checkOwner checks that the authenticated admin owns a resource of ownerID. Super admins
+// can access every resource.
+func checkOwner(c *fiber.Ctx, ownerID int) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+	if role != utils.RoleSuperAdmin && ownerID != adminID {
+		return fiber.NewError(fiber.StatusForbidden, "Access denied")
+	}
+
+	return nil
+}
+
+// scopeToAdmin limits a list to the authenticated admin's own items. Super admins see
+// those of every admin, or of the admin_id filter.
+func scopeToAdmin(c *fiber.Ctx, params *models.ListParams) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+	if role != utils.RoleSuperAdmin {
+		params.AdminID = &adminID
+	}
+
+	return nil
+}
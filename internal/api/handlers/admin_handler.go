@@ -8,6 +8,7 @@ import (
 	"mobilka/internal/service"
 	"mobilka/internal/utils"
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,12 +16,14 @@ import (
 // AdminHandler handles admin requests
 type AdminHandler struct {
 	adminService *service.AdminService
+	usageService *service.UsageService
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(adminService *service.AdminService) *AdminHandler {
+func NewAdminHandler(adminService *service.AdminService, usageService *service.UsageService) *AdminHandler {
 	return &AdminHandler{
 		adminService: adminService,
+		usageService: usageService,
 	}
 }
 
@@ -28,59 +31,25 @@ func NewAdminHandler(adminService *service.AdminService) *AdminHandler {
 func (h *AdminHandler) Create(c *fiber.Ctx) error {
 	var req models.AdminCreateRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate required fields
-	if req.UserName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Username is required",
-		})
-	}
-
-	if req.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Email is required",
-		})
-	}
-
-	if req.CompanyName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Company name is required",
-		})
-	}
-
-	if req.SystemID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "System id is required",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Create admin
 	admin, err := h.adminService.Create(c.Context(), &req)
 	if err != nil {
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Subscription tier not found", fiber.StatusBadRequest)
+		}
+
 		// Check if it's a detailed app error
 		var appErr *utils.AppError
 		if errors.As(err, &appErr) {
-			return c.Status(appErr.Code).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": appErr.Message,
-			})
+			return appErr
 		}
 
 		// Default error response
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to create admin: " + err.Error(),
-		})
+		return utils.NewAppError(err, "Failed to create admin", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -90,28 +59,25 @@ func (h *AdminHandler) Create(c *fiber.Ctx) error {
 	})
 }
 
-// GetAll handles retrieving all admins
+// GetAll handles retrieving the admins, one page at a time
 func (h *AdminHandler) GetAll(c *fiber.Ctx) error {
-	// Get all admins
-	admins, err := h.adminService.GetAll(c.Context())
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	admins, meta, err := h.adminService.List(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve admins",
-		})
+		return listError(err, "Failed to retrieve admins")
 	}
 
 	// Convert to response objects
-	var responses []models.AdminResponse
+	responses := []models.AdminResponse{}
 	for _, admin := range admins {
 		responses = append(responses, admin.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
 // GetByID handles retrieving an admin by ID
@@ -119,26 +85,17 @@ func (h *AdminHandler) GetByID(c *fiber.Ctx) error {
 	// Get admin ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid admin ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
 	}
 
 	// Get admin
 	admin, err := h.adminService.GetByID(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Admin not found",
-			})
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve admin",
-		})
+		return utils.NewAppError(err, "Failed to retrieve admin", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -153,26 +110,17 @@ func (h *AdminHandler) GetProfile(c *fiber.Ctx) error {
 	// Get admin ID from context
 	userID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get admin
 	admin, err := h.adminService.GetByID(c.Context(), userID)
 	if err != nil {
-		if err == utils.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Admin not found",
-			})
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve profile",
-		})
+		return utils.NewAppError(err, "Failed to retrieve profile", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -187,10 +135,7 @@ func (h *AdminHandler) Update(c *fiber.Ctx) error {
 	// Get admin ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid admin ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
 	}
 
 	// Log the raw request body for debugging
@@ -200,10 +145,7 @@ func (h *AdminHandler) Update(c *fiber.Ctx) error {
 	var req models.AdminUpdateRequest
 	if err := c.BodyParser(&req); err != nil {
 		fmt.Printf("Error parsing request body: %v\n", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body: " + err.Error(),
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 
 	// Log parsed request for debugging
@@ -249,20 +191,23 @@ func (h *AdminHandler) Update(c *fiber.Ctx) error {
 		}
 	}
 
+	if err := utils.Validate(&req); err != nil {
+		return err
+	}
+
 	// Update admin
 	admin, err := h.adminService.Update(c.Context(), id, &req)
 	if err != nil {
-		if err == utils.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Admin not found",
-			})
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to update admin: " + err.Error(),
-		})
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+
+		return utils.NewAppError(err, "Failed to update admin", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -277,10 +222,7 @@ func (h *AdminHandler) ChangeDelivery(c *fiber.Ctx) error {
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Parse request body
@@ -288,26 +230,17 @@ func (h *AdminHandler) ChangeDelivery(c *fiber.Ctx) error {
 		Delivery int `json:"delivery"`
 	}
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Get the admin to update
 	admin, err := h.adminService.GetByID(c.Context(), adminID)
 	if err != nil {
-		if err == utils.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Admin not found",
-			})
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve admin",
-		})
+		return utils.NewAppError(err, "Failed to retrieve admin", fiber.StatusInternalServerError)
 	}
 
 	// Update admin's delivery status
@@ -321,10 +254,7 @@ func (h *AdminHandler) ChangeDelivery(c *fiber.Ctx) error {
 	// Update admin
 	updatedAdmin, err := h.adminService.Update(c.Context(), adminID, updateReq)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to update delivery status: " + err.Error(),
-		})
+		return utils.NewAppError(err, "Failed to update delivery status", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -340,26 +270,17 @@ func (h *AdminHandler) Delete(c *fiber.Ctx) error {
 	// Get admin ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid admin ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
 	}
 
 	// Delete admin
 	err = h.adminService.Delete(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Admin not found",
-			})
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to delete admin",
-		})
+		return utils.NewAppError(err, "Failed to delete admin", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -374,26 +295,23 @@ func (h *AdminHandler) GetByIDPublic(c *fiber.Ctx) error {
 	// Get admin ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid admin ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
 	}
 
-	// Get admin and increment users count
-	admin, err := h.adminService.GetByIDPublic(c.Context(), id)
+	// Identify the device by its device ID, falling back to the FCM token
+	deviceID := c.Get("X-Device-ID", c.Query("device_id"))
+	if deviceID == "" {
+		deviceID = c.Get("X-FCM-Token", c.Query("fcm_token"))
+	}
+
+	// Get admin and meter the device
+	admin, err := h.adminService.GetByIDPublic(c.Context(), id, deviceID)
 	if err != nil {
-		if err == utils.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Admin not found",
-			})
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve admin",
-		})
+		return utils.NewAppError(err, "Failed to retrieve admin", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -407,26 +325,17 @@ func (h *AdminHandler) GetByIDPublicMobile(c *fiber.Ctx) error {
 	// Get admin ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid admin ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
 	}
 
-	// Get admin and increment users count
+	// Get admin
 	admin, err := h.adminService.GetByID(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Admin not found",
-			})
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve profile",
-		})
+		return utils.NewAppError(err, "Failed to retrieve profile", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -435,3 +344,60 @@ func (h *AdminHandler) GetByIDPublicMobile(c *fiber.Ctx) error {
 		"data":   admin.ToResponse(),
 	})
 }
+
+// GetUsage handles retrieving daily and monthly active users of an admin.
+// Super admins pass the admin ID in the URL, admins see their own usage.
+func (h *AdminHandler) GetUsage(c *fiber.Ctx) error {
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+
+	var adminID int
+	if role == utils.RoleSuperAdmin && c.Params("id") != "" {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+		}
+		adminID = id
+	} else {
+		id, ok := c.Locals(utils.ContextUserID).(int)
+		if !ok {
+			return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+		}
+		adminID = id
+	}
+
+	// Parse date range, defaulting to the last 30 days
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -30)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid from date, expected YYYY-MM-DD")
+		}
+		from = parsed
+	}
+
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid to date, expected YYYY-MM-DD")
+		}
+		to = parsed
+	}
+
+	// Get usage
+	usage, err := h.usageService.GetUsage(c.Context(), adminID, from, to)
+	if err != nil {
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
+		}
+
+		return utils.NewAppError(err, "Failed to retrieve usage", fiber.StatusInternalServerError)
+	}
+
+	// Return response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   usage,
+	})
+}
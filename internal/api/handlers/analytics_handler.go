@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AnalyticsHandler handles revenue and subscription reporting requests (super admin only)
+type AnalyticsHandler struct {
+	analyticsService *service.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// parseAnalyticsFilter reads the from and to dates (YYYY-MM-DD, both inclusive) and the report
+// currency from the query, defaulting to the last 12 months including the current one
+func parseAnalyticsFilter(c *fiber.Ctx) (models.AnalyticsFilter, error) {
+	now := time.Now().UTC()
+	filter := models.AnalyticsFilter{
+		From:     time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -11, 0),
+		To:       time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1),
+		Currency: c.Query("currency"),
+	}
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return filter, utils.NewInvalidInputError("Invalid from date, expected YYYY-MM-DD")
+		}
+		filter.From = parsed
+	}
+
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return filter, utils.NewInvalidInputError("Invalid to date, expected YYYY-MM-DD")
+		}
+		filter.To = parsed.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
+
+// analyticsError maps analytics errors to a response
+func analyticsError(err error, fallback string) error {
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return utils.NewAppError(err, fallback, fiber.StatusInternalServerError)
+}
+
+// GetSummary handles retrieving the headline numbers of the business
+func (h *AnalyticsHandler) GetSummary(c *fiber.Ctx) error {
+	summary, err := h.analyticsService.GetSummary(c.Context(), c.Query("currency"))
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve analytics summary")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   summary,
+	})
+}
+
+// GetMRR handles retrieving the monthly recurring revenue by tier, now or at the "at" date
+func (h *AnalyticsHandler) GetMRR(c *fiber.Ctx) error {
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid at date, expected YYYY-MM-DD")
+		}
+		at = parsed
+	}
+
+	report, err := h.analyticsService.GetMRR(c.Context(), c.Query("currency"), at)
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve MRR")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   report,
+	})
+}
+
+// GetAdminMovements handles retrieving new, churned and reactivated admins per month
+func (h *AnalyticsHandler) GetAdminMovements(c *fiber.Ctx) error {
+	filter, err := parseAnalyticsFilter(c)
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve admin movements")
+	}
+
+	movements, err := h.analyticsService.GetAdminMovements(c.Context(), filter)
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve admin movements")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   movements,
+	})
+}
+
+// GetRevenue handles retrieving the verified revenue per day, week or month
+func (h *AnalyticsHandler) GetRevenue(c *fiber.Ctx) error {
+	filter, err := parseAnalyticsFilter(c)
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve revenue")
+	}
+
+	report, err := h.analyticsService.GetVerifiedRevenue(c.Context(), filter, c.Query("period"))
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve revenue")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   report,
+	})
+}
+
+// GetPaymentMethods handles retrieving the payment breakdown by payment method
+func (h *AnalyticsHandler) GetPaymentMethods(c *fiber.Ctx) error {
+	filter, err := parseAnalyticsFilter(c)
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve payment methods")
+	}
+
+	methods, err := h.analyticsService.GetPaymentMethodStats(c.Context(), filter)
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve payment methods")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   methods,
+	})
+}
+
+// GetVerificationLatency handles retrieving how long payments wait for verification
+func (h *AnalyticsHandler) GetVerificationLatency(c *fiber.Ctx) error {
+	filter, err := parseAnalyticsFilter(c)
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve verification latency")
+	}
+
+	latency, err := h.analyticsService.GetVerificationLatency(c.Context(), filter)
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve verification latency")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   latency,
+	})
+}
+
+// GetAtRiskAdmins handles retrieving admins whose subscription ends or ended recently,
+// within the "days" query parameter (default 7)
+func (h *AnalyticsHandler) GetAtRiskAdmins(c *fiber.Ctx) error {
+	days := 7
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid days")
+		}
+		days = parsed
+	}
+
+	admins, err := h.analyticsService.GetAtRiskAdmins(c.Context(), days)
+	if err != nil {
+		return analyticsError(err, "Failed to retrieve at-risk admins")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   admins,
+	})
+}
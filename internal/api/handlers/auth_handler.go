@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	"mobilka/internal/models"
 	"mobilka/internal/service"
 	"mobilka/internal/utils"
@@ -24,35 +26,18 @@ func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 func (h *AuthHandler) SuperAdminLogin(c *fiber.Ctx) error {
 	var req models.SuperAdminLoginRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate request
-	if req.Login == "" || req.Password == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Login and password are required",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Attempt login
 	superAdmin, token, err := h.authService.SuperAdminLogin(c.Context(), req.Login, req.Password)
 	if err != nil {
-		if err == utils.ErrInvalidCredentials {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Invalid credentials",
-			})
+		if errors.Is(err, utils.ErrInvalidCredentials) {
+			return utils.NewAppError(err, "Invalid credentials", fiber.StatusUnauthorized)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Login failed",
-		})
+		return utils.NewAppError(err, "Login failed", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -69,35 +54,18 @@ func (h *AuthHandler) SuperAdminLogin(c *fiber.Ctx) error {
 func (h *AuthHandler) AdminLogin(c *fiber.Ctx) error {
 	var req models.AdminLoginRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate request
-	if req.UserName == "" || req.SystemID == "" || req.Email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Username, system ID, and email are required",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Attempt login
 	admin, token, err := h.authService.AdminLogin(c.Context(), req.UserName, req.SystemID, req.Email)
 	if err != nil {
-		if err == utils.ErrInvalidCredentials {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Invalid credentials",
-			})
+		if errors.Is(err, utils.ErrInvalidCredentials) {
+			return utils.NewAppError(err, "Invalid credentials", fiber.StatusUnauthorized)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Login failed",
-		})
+		return utils.NewAppError(err, "Login failed", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -115,10 +83,7 @@ func (h *AuthHandler) SuperAdminChangePassword(c *fiber.Ctx) error {
 	// Get super admin ID from context
 	userID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Parse request
@@ -127,35 +92,18 @@ func (h *AuthHandler) SuperAdminChangePassword(c *fiber.Ctx) error {
 		NewPassword string `json:"new_password" validate:"required,min=8"`
 	}
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate request
-	if req.OldPassword == "" || req.NewPassword == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Old and new passwords are required",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Change password
 	err := h.authService.SuperAdminChangePassword(c.Context(), userID, req.OldPassword, req.NewPassword)
 	if err != nil {
-		if err == utils.ErrInvalidCredentials {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Invalid old password",
-			})
+		if errors.Is(err, utils.ErrInvalidCredentials) {
+			return utils.NewAppError(err, "Invalid old password", fiber.StatusUnauthorized)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Password change failed",
-		})
+		return utils.NewAppError(err, "Password change failed", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -21,35 +22,23 @@ func (h *BannerHandler) GetByID(c *fiber.Ctx) error {
 	// Get banner ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid banner ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid banner ID")
 	}
 
 	// Get banner
 	banner, err := h.bannerService.GetByID(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Banner not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Banner not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve banner",
-		})
+		return utils.NewAppError(err, "Failed to retrieve banner", fiber.StatusInternalServerError)
 	}
 
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
@@ -57,10 +46,7 @@ func (h *BannerHandler) GetByID(c *fiber.Ctx) error {
 
 	// Check if the user has access to this banner
 	if role != utils.RoleSuperAdmin && banner.AdminID != adminID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Access denied",
-		})
+		return fiber.NewError(fiber.StatusForbidden, "Access denied")
 	}
 
 	// Return response
@@ -75,55 +61,37 @@ func (h *BannerHandler) Update(c *fiber.Ctx) error {
     // Get banner ID from URL
     id, err := strconv.Atoi(c.Params("id"))
     if err != nil {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-            "status":  utils.StatusError,
-            "message": "Invalid banner ID",
-        })
+        return fiber.NewError(fiber.StatusBadRequest, "Invalid banner ID")
     }
 
     // Get admin ID from context
     adminID, ok := c.Locals(utils.ContextUserID).(int)
     if !ok {
-        return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-            "status":  utils.StatusError,
-            "message": "Unauthorized",
-        })
+        return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
     }
 
     // Get role from context
     role, _ := c.Locals(utils.ContextUserRole).(string)
 
     var req models.BannerUpdateRequest
-    if err := c.BodyParser(&req); err != nil {
-        return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-            "status":  utils.StatusError,
-            "message": "Invalid request body",
-        })
+    if err := parseBody(c, &req); err != nil {
+        return err
     }
 
     // First, get the existing banner to check ownership
     existingBanner, err := h.bannerService.GetByID(c.Context(), id)
     if err != nil {
-        if err == utils.ErrResourceNotFound {
-            return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-                "status":  utils.StatusError,
-                "message": "Banner not found",
-            })
+        if errors.Is(err, utils.ErrResourceNotFound) {
+            return utils.NewAppError(err, "Banner not found", fiber.StatusNotFound)
         }
-        return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-            "status":  utils.StatusError,
-            "message": "Failed to retrieve banner",
-        })
+        return utils.NewAppError(err, "Failed to retrieve banner", fiber.StatusInternalServerError)
     }
 
     // Check if user has permission to update this banner
     // Super admins can update any banner
     // Regular admins can only update their own banners
     if role != utils.RoleSuperAdmin && existingBanner.AdminID != adminID {
-        return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-            "status":  utils.StatusError,
-            "message": "You don't have permission to update this banner",
-        })
+        return fiber.NewError(fiber.StatusForbidden, "You don't have permission to update this banner")
     }
 
     // Allow changing adminID only for super admins
@@ -136,16 +104,10 @@ func (h *BannerHandler) Update(c *fiber.Ctx) error {
     // Update banner
     banner, err := h.bannerService.Update(c.Context(), id, targetAdminID, &req)
     if err != nil {
-        if err == utils.ErrResourceNotFound {
-            return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-                "status":  utils.StatusError,
-                "message": "Banner not found or access denied",
-            })
+        if errors.Is(err, utils.ErrResourceNotFound) {
+            return utils.NewAppError(err, "Banner not found or access denied", fiber.StatusNotFound)
         }
-        return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-            "status":  utils.StatusError,
-            "message": "Failed to update banner",
-        })
+        return utils.NewAppError(err, "Failed to update banner", fiber.StatusInternalServerError)
     }
 
     // Return response
@@ -160,35 +122,23 @@ func (h *BannerHandler) Delete(c *fiber.Ctx) error {
 	// Get banner ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid banner ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid banner ID")
 	}
 
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Delete banner
 	err = h.bannerService.Delete(c.Context(), id, adminID)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Banner not found or access denied",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Banner not found or access denied", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to delete banner",
-		})
+		return utils.NewAppError(err, "Failed to delete banner", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -211,43 +161,15 @@ func (h *BannerHandler) Create(c *fiber.Ctx) error {
 	// Get admin ID from context
 	contextAdminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
 	role, _ := c.Locals(utils.ContextUserRole).(string)
 	
 	var req models.BannerCreateRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate required fields
-	if req.Image == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Image is required",
-		})
-	}
-
-	if req.Title == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Title is required",
-		})
-	} 
-	
-	if req.Body == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Body is required",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Determine which admin ID to use
@@ -266,10 +188,7 @@ func (h *BannerHandler) Create(c *fiber.Ctx) error {
 	// Create banner
 	banner, err := h.bannerService.Create(c.Context(), adminID, &req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to create banner",
-		})
+		return utils.NewAppError(err, "Failed to create banner", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -284,43 +203,34 @@ func (h *BannerHandler) GetAll(c *fiber.Ctx) error {
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
 	role, _ := c.Locals(utils.ContextUserRole).(string)
 
-	var banners []*models.Banner
-	var err error
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
 
 	// Super admin can see all banners, admin can only see their own
-	if role == utils.RoleSuperAdmin {
-		banners, err = h.bannerService.GetAll(c.Context())
-	} else {
-		banners, err = h.bannerService.GetByAdminID(c.Context(), adminID)
+	if role != utils.RoleSuperAdmin {
+		params.AdminID = &adminID
 	}
 
+	banners, meta, err := h.bannerService.List(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve banners",
-		})
+		return listError(err, "Failed to retrieve banners")
 	}
 
 	// Convert to response objects
-	var responses []models.BannerResponse
+	responses := []models.BannerResponse{}
 	for _, banner := range banners {
 		responses = append(responses, banner.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
 func (h *BannerHandler) GetByIDPublicMobile(c *fiber.Ctx) error {
@@ -329,29 +239,24 @@ func (h *BannerHandler) GetByIDPublicMobile(c *fiber.Ctx) error {
 	fmt.Print(adminID)
 
 
-	var banners []*models.Banner
-	var err error
-
-	banners, err = h.bannerService.GetByAdminID(c.Context(), adminID)
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+	params.AdminID = &adminID
 
+	banners, meta, err := h.bannerService.List(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve banners",
-		})
+		return listError(err, "Failed to retrieve banners")
 	}
 
 	// Convert to response objects
-	var responses []models.BannerResponse
+	responses := []models.BannerResponse{}
 	for _, banner := range banners {
 		responses = append(responses, banner.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
 // GetPublicByAdminID handles retrieving all banners for a specific admin without authentication
@@ -360,30 +265,26 @@ func (h *BannerHandler) GetPublicByAdminID(c *fiber.Ctx) error {
 	// Get admin ID from URL
 	adminID, err := strconv.Atoi(c.Params("adminID"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid admin ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
 	}
 
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+	params.AdminID = &adminID
+
 	// Get banners
-	banners, err := h.bannerService.GetByAdminID(c.Context(), adminID)
+	banners, meta, err := h.bannerService.List(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve banners",
-		})
+		return listError(err, "Failed to retrieve banners")
 	}
 
 	// Convert to response objects
-	var responses []models.BannerResponse
+	responses := []models.BannerResponse{}
 	for _, banner := range banners {
 		responses = append(responses, banner.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseBody parses the request body into req and validates it against the validate tags of
+// req. A malformed body is a 400 error, a body that fails validation a *utils.ValidationError
+// that is answered with 422 and the failed fields.
+func parseBody(c *fiber.Ctx, req interface{}) error {
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	return utils.Validate(req)
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BranchHandler handles restaurant branch requests
+type BranchHandler struct {
+	branchService *service.BranchService
+}
+
+// NewBranchHandler creates a new branch handler
+func NewBranchHandler(branchService *service.BranchService) *BranchHandler {
+	return &BranchHandler{
+		branchService: branchService,
+	}
+}
+
+// Create handles creating a new branch
+func (h *BranchHandler) Create(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+
+	var req models.BranchCreateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	// Super admins can create branches for other admins
+	if req.AdminID > 0 && role == utils.RoleSuperAdmin {
+		adminID = req.AdminID
+	}
+
+	branch, err := h.branchService.Create(c.Context(), adminID, &req)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to create branch", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   branch.ToResponse(),
+	})
+}
+
+// GetAll handles retrieving the branches of the current admin, or of all admins for super admins
+func (h *BranchHandler) GetAll(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	if role != utils.RoleSuperAdmin {
+		params.AdminID = &adminID
+	}
+
+	branches, meta, err := h.branchService.List(c.Context(), params)
+	if err != nil {
+		return listError(err, "Failed to retrieve branches")
+	}
+
+	responses := []models.BranchResponse{}
+	for _, branch := range branches {
+		responses = append(responses, branch.ToResponse())
+	}
+
+	return listResponse(c, responses, meta)
+}
+
+// GetByID handles retrieving a branch by ID
+func (h *BranchHandler) GetByID(c *fiber.Ctx) error {
+	branch, err := h.ownBranch(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   branch.ToResponse(),
+	})
+}
+
+// Update handles updating a branch
+func (h *BranchHandler) Update(c *fiber.Ctx) error {
+	branch, err := h.ownBranch(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.BranchUpdateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	branch, err = h.branchService.Update(c.Context(), branch, &req)
+	if err != nil {
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Branch not found", fiber.StatusNotFound)
+		}
+		return utils.NewAppError(err, "Failed to update branch", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   branch.ToResponse(),
+	})
+}
+
+// Delete handles deleting a branch
+func (h *BranchHandler) Delete(c *fiber.Ctx) error {
+	branch, err := h.ownBranch(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.branchService.Delete(c.Context(), branch.ID); err != nil {
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Branch not found", fiber.StatusNotFound)
+		}
+		return utils.NewAppError(err, "Failed to delete branch", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Branch deleted successfully",
+	})
+}
+
+// ownBranch loads the branch of the id URL parameter and checks that the current admin
+// owns it. Super admins can access every branch.
+func (h *BranchHandler) ownBranch(c *fiber.Ctx) (*models.Branch, error) {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid branch ID")
+	}
+
+	branch, err := h.branchService.GetByID(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return nil, utils.NewAppError(err, "Branch not found", fiber.StatusNotFound)
+		}
+		return nil, utils.NewAppError(err, "Failed to retrieve branch", fiber.StatusInternalServerError)
+	}
+
+	if role != utils.RoleSuperAdmin && branch.AdminID != adminID {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Access denied")
+	}
+
+	return branch, nil
+}
+
+// GetPublicByAdminID handles retrieving the branches of an admin without authentication
+func (h *BranchHandler) GetPublicByAdminID(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	branches, meta, err := h.branchService.ListPublic(c.Context(), adminID, params)
+	if err != nil {
+		return listError(err, "Failed to retrieve branches")
+	}
+
+	responses := []models.BranchResponse{}
+	for _, branch := range branches {
+		responses = append(responses, branch.ToResponse())
+	}
+
+	return listResponse(c, responses, meta)
+}
+
+// GetPublicNearby handles retrieving the branches of an admin nearest to the lat and lng
+// query coordinates, within radius meters, without authentication
+func (h *BranchHandler) GetPublicNearby(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	var query models.NearbyBranchQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	if err := utils.Validate(&query); err != nil {
+		return err
+	}
+
+	branches, err := h.branchService.Nearby(c.Context(), adminID, &query)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve branches", fiber.StatusInternalServerError)
+	}
+
+	responses := []models.NearbyBranchResponse{}
+	for _, branch := range branches {
+		responses = append(responses, branch.ToResponse())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   responses,
+	})
+}
+
+// GetPublicByID handles retrieving a branch without authentication
+func (h *BranchHandler) GetPublicByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid branch ID")
+	}
+
+	branch, err := h.branchService.GetPublicByID(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Branch not found", fiber.StatusNotFound)
+		}
+		return utils.NewAppError(err, "Failed to retrieve branch", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   branch.ToResponse(),
+	})
+}
@@ -0,0 +1,429 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CatalogHandler handles menu catalog requests
+type CatalogHandler struct {
+	catalogService *service.CatalogService
+}
+
+// NewCatalogHandler creates a new catalog handler
+func NewCatalogHandler(catalogService *service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// catalogError maps catalog errors to a response: client errors keep their status,
+// missing resources are reported as notFound and anything else with the fallback message
+func catalogError(err error, notFound, fallback string) error {
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		return utils.NewAppError(err, notFound, fiber.StatusNotFound)
+	}
+	return utils.NewAppError(err, fallback, fiber.StatusInternalServerError)
+}
+
+// pathID parses the id URL parameter
+func pathID(c *fiber.Ctx, name string) (int, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid "+name+" ID")
+	}
+	return id, nil
+}
+
+// ownCategory loads the category of the id URL parameter and checks its owner
+func (h *CatalogHandler) ownCategory(c *fiber.Ctx) (*models.CatalogCategory, error) {
+	id, err := pathID(c, "category")
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := h.catalogService.GetCategoryByID(c.Context(), id)
+	if err != nil {
+		return nil, catalogError(err, "Category not found", "Failed to retrieve category")
+	}
+
+	if err := checkOwner(c, category.AdminID); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// CreateCategory handles creating a new category
+func (h *CatalogHandler) CreateCategory(c *fiber.Ctx) error {
+	var req models.CatalogCategoryCreateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	adminID, err := actingAdminID(c, req.AdminID)
+	if err != nil {
+		return err
+	}
+
+	category, err := h.catalogService.CreateCategory(c.Context(), adminID, &req)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to create category", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   category.ToResponse(),
+	})
+}
+
+// GetCategories handles retrieving the categories of the current admin
+func (h *CatalogHandler) GetCategories(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	if err := scopeToAdmin(c, &params); err != nil {
+		return err
+	}
+
+	categories, meta, err := h.catalogService.ListCategories(c.Context(), params)
+	if err != nil {
+		return listError(err, "Failed to retrieve categories")
+	}
+
+	responses := []models.CatalogCategoryResponse{}
+	for _, category := range categories {
+		responses = append(responses, category.ToResponse())
+	}
+
+	return listResponse(c, responses, meta)
+}
+
+// GetCategory handles retrieving a category by ID
+func (h *CatalogHandler) GetCategory(c *fiber.Ctx) error {
+	category, err := h.ownCategory(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   category.ToResponse(),
+	})
+}
+
+// UpdateCategory handles updating a category
+func (h *CatalogHandler) UpdateCategory(c *fiber.Ctx) error {
+	category, err := h.ownCategory(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.CatalogCategoryUpdateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	category, err = h.catalogService.UpdateCategory(c.Context(), category, &req)
+	if err != nil {
+		return catalogError(err, "Category not found", "Failed to update category")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   category.ToResponse(),
+	})
+}
+
+// DeleteCategory handles deleting a category. Categories with products can't be deleted.
+func (h *CatalogHandler) DeleteCategory(c *fiber.Ctx) error {
+	category, err := h.ownCategory(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.catalogService.DeleteCategory(c.Context(), category); err != nil {
+		return catalogError(err, "Category not found", "Failed to delete category")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Category deleted successfully",
+	})
+}
+
+// ownProduct loads the product of the id URL parameter and checks its owner
+func (h *CatalogHandler) ownProduct(c *fiber.Ctx) (*models.CatalogProduct, error) {
+	id, err := pathID(c, "product")
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := h.catalogService.GetProductByID(c.Context(), id)
+	if err != nil {
+		return nil, catalogError(err, "Product not found", "Failed to retrieve product")
+	}
+
+	if err := checkOwner(c, product.AdminID); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// CreateProduct handles creating a new product
+func (h *CatalogHandler) CreateProduct(c *fiber.Ctx) error {
+	var req models.CatalogProductCreateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	adminID, err := actingAdminID(c, req.AdminID)
+	if err != nil {
+		return err
+	}
+
+	product, err := h.catalogService.CreateProduct(c.Context(), adminID, &req)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to create product", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   product.ToResponse(),
+	})
+}
+
+// GetProducts handles retrieving the products of the current admin, optionally of the
+// category_id category only
+func (h *CatalogHandler) GetProducts(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	if err := scopeToAdmin(c, &params); err != nil {
+		return err
+	}
+
+	var categoryID *int
+	if value := c.Query("category_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid category ID")
+		}
+		categoryID = &id
+	}
+
+	products, meta, err := h.catalogService.ListProducts(c.Context(), params, categoryID)
+	if err != nil {
+		return listError(err, "Failed to retrieve products")
+	}
+
+	responses := []models.CatalogProductResponse{}
+	for _, product := range products {
+		responses = append(responses, product.ToResponse())
+	}
+
+	return listResponse(c, responses, meta)
+}
+
+// GetProduct handles retrieving a product by ID
+func (h *CatalogHandler) GetProduct(c *fiber.Ctx) error {
+	product, err := h.ownProduct(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   product.ToResponse(),
+	})
+}
+
+// UpdateProduct handles updating a product
+func (h *CatalogHandler) UpdateProduct(c *fiber.Ctx) error {
+	product, err := h.ownProduct(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.CatalogProductUpdateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	product, err = h.catalogService.UpdateProduct(c.Context(), product, &req)
+	if err != nil {
+		return catalogError(err, "Product not found", "Failed to update product")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   product.ToResponse(),
+	})
+}
+
+// DeleteProduct handles deleting a product
+func (h *CatalogHandler) DeleteProduct(c *fiber.Ctx) error {
+	product, err := h.ownProduct(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.catalogService.DeleteProduct(c.Context(), product); err != nil {
+		return catalogError(err, "Product not found", "Failed to delete product")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Product deleted successfully",
+	})
+}
+
+// ownModifierGroup loads the modifier group of the id URL parameter and checks its owner
+func (h *CatalogHandler) ownModifierGroup(c *fiber.Ctx) (*models.ModifierGroup, error) {
+	id, err := pathID(c, "modifier group")
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := h.catalogService.GetModifierGroupByID(c.Context(), id)
+	if err != nil {
+		return nil, catalogError(err, "Modifier group not found", "Failed to retrieve modifier group")
+	}
+
+	if err := checkOwner(c, group.AdminID); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// CreateModifierGroup handles creating a new modifier group
+func (h *CatalogHandler) CreateModifierGroup(c *fiber.Ctx) error {
+	var req models.ModifierGroupCreateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	adminID, err := actingAdminID(c, req.AdminID)
+	if err != nil {
+		return err
+	}
+
+	group, err := h.catalogService.CreateModifierGroup(c.Context(), adminID, &req)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to create modifier group", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   group.ToResponse(),
+	})
+}
+
+// GetModifierGroups handles retrieving the modifier groups of the current admin
+func (h *CatalogHandler) GetModifierGroups(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	if err := scopeToAdmin(c, &params); err != nil {
+		return err
+	}
+
+	groups, meta, err := h.catalogService.ListModifierGroups(c.Context(), params)
+	if err != nil {
+		return listError(err, "Failed to retrieve modifier groups")
+	}
+
+	responses := []models.ModifierGroupResponse{}
+	for _, group := range groups {
+		responses = append(responses, group.ToResponse())
+	}
+
+	return listResponse(c, responses, meta)
+}
+
+// GetModifierGroup handles retrieving a modifier group by ID
+func (h *CatalogHandler) GetModifierGroup(c *fiber.Ctx) error {
+	group, err := h.ownModifierGroup(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   group.ToResponse(),
+	})
+}
+
+// UpdateModifierGroup handles updating a modifier group
+func (h *CatalogHandler) UpdateModifierGroup(c *fiber.Ctx) error {
+	group, err := h.ownModifierGroup(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.ModifierGroupUpdateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	group, err = h.catalogService.UpdateModifierGroup(c.Context(), group, &req)
+	if err != nil {
+		return catalogError(err, "Modifier group not found", "Failed to update modifier group")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   group.ToResponse(),
+	})
+}
+
+// DeleteModifierGroup handles deleting a modifier group
+func (h *CatalogHandler) DeleteModifierGroup(c *fiber.Ctx) error {
+	group, err := h.ownModifierGroup(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.catalogService.DeleteModifierGroup(c.Context(), group); err != nil {
+		return catalogError(err, "Modifier group not found", "Failed to delete modifier group")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Modifier group deleted successfully",
+	})
+}
+
+// GetPublicByAdminID handles retrieving the menu of an admin without authentication. The
+// menu is cached, so clients may cache it for as long as well.
+func (h *CatalogHandler) GetPublicByAdminID(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	catalog, err := h.catalogService.GetPublicCatalog(c.Context(), adminID)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve catalog", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(int(service.PublicCatalogTTL.Seconds())))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   catalog,
+	})
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CouponHandler handles subscription coupon requests
+type CouponHandler struct {
+	couponService *service.CouponService
+}
+
+// NewCouponHandler creates a new coupon handler
+func NewCouponHandler(couponService *service.CouponService) *CouponHandler {
+	return &CouponHandler{
+		couponService: couponService,
+	}
+}
+
+// couponError maps coupon service errors to a response
+func couponError(err error, fallback string) error {
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		return utils.NewAppError(err, "Coupon not found", fiber.StatusNotFound)
+	}
+
+	if errors.Is(err, utils.ErrUserNotFound) {
+		return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
+	}
+
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return utils.NewAppError(err, fallback, fiber.StatusInternalServerError)
+}
+
+// Create handles creating a new coupon (super admin only)
+func (h *CouponHandler) Create(c *fiber.Ctx) error {
+	var req models.CouponCreateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	coupon, err := h.couponService.Create(c.Context(), &req)
+	if err != nil {
+		return couponError(err, "Failed to create coupon")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   coupon,
+	})
+}
+
+// GetAll handles retrieving the coupons, one page at a time (super admin only)
+func (h *CouponHandler) GetAll(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	coupons, meta, err := h.couponService.List(c.Context(), params)
+	if err != nil {
+		return listError(err, "Failed to retrieve coupons")
+	}
+
+	return listResponse(c, coupons, meta)
+}
+
+// GetByID handles retrieving a coupon by ID (super admin only)
+func (h *CouponHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid coupon ID")
+	}
+
+	coupon, err := h.couponService.GetByID(c.Context(), id)
+	if err != nil {
+		return couponError(err, "Failed to retrieve coupon")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   coupon,
+	})
+}
+
+// Update handles updating a coupon (super admin only)
+func (h *CouponHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid coupon ID")
+	}
+
+	var req models.CouponUpdateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	coupon, err := h.couponService.Update(c.Context(), id, &req)
+	if err != nil {
+		return couponError(err, "Failed to update coupon")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   coupon,
+	})
+}
+
+// Delete handles deleting a coupon (super admin only)
+func (h *CouponHandler) Delete(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid coupon ID")
+	}
+
+	if err := h.couponService.Delete(c.Context(), id); err != nil {
+		return couponError(err, "Failed to delete coupon")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Coupon deleted successfully",
+	})
+}
+
+// GetRedemptions handles retrieving the redemptions of a coupon, one page at a time (super admin only)
+func (h *CouponHandler) GetRedemptions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid coupon ID")
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	redemptions, meta, err := h.couponService.GetRedemptions(c.Context(), id, params)
+	if err != nil {
+		return couponError(err, "Failed to retrieve coupon redemptions")
+	}
+
+	return listResponse(c, redemptions, meta)
+}
+
+// GetReport handles retrieving redemption statistics of all coupons (super admin only)
+func (h *CouponHandler) GetReport(c *fiber.Ctx) error {
+	reports, err := h.couponService.GetReport(c.Context())
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve coupon report", fiber.StatusInternalServerError)
+	}
+
+	if reports == nil {
+		reports = []*models.CouponReport{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   reports,
+	})
+}
+
+// ApplyCoupon handles an admin applying a coupon to their subscription
+func (h *CouponHandler) ApplyCoupon(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.CouponApplyRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	redemption, coupon, err := h.couponService.ApplyCoupon(c.Context(), adminID, req.Code)
+	if err != nil {
+		return couponError(err, "Failed to apply coupon")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data": fiber.Map{
+			"redemption": redemption,
+			"coupon":     coupon,
+		},
+	})
+}
+
+// GetActiveCoupon handles retrieving the coupon applied to the admin's subscription
+func (h *CouponHandler) GetActiveCoupon(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	redemption, coupon, err := h.couponService.GetActiveCoupon(c.Context(), adminID)
+	if err != nil {
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "No coupon applied", fiber.StatusNotFound)
+		}
+
+		return utils.NewAppError(err, "Failed to retrieve coupon", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data": fiber.Map{
+			"redemption": redemption,
+			"coupon":     coupon,
+		},
+	})
+}
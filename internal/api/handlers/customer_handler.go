@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"strconv"
+
+	"mobilka/internal/api/middlewares"
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CustomerHandler handles customer login, account and admin customer requests
+type CustomerHandler struct {
+	customerService *service.CustomerService
+}
+
+// NewCustomerHandler creates a new customer handler
+func NewCustomerHandler(customerService *service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+	}
+}
+
+// RequestCode handles sending a login code by SMS to a customer of an admin
+func (h *CustomerHandler) RequestCode(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	var req models.CustomerOTPRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.customerService.RequestCode(c.Context(), adminID, &req); err != nil {
+		return utils.NewAppError(err, "Failed to send login code", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Login code sent",
+	})
+}
+
+// VerifyCode handles logging a customer in with a login code
+func (h *CustomerHandler) VerifyCode(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	var req models.CustomerOTPVerifyRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	auth, err := h.customerService.VerifyCode(c.Context(), adminID, &req)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to log in", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   auth,
+	})
+}
+
+// GetProfile handles retrieving the authenticated customer's profile
+func (h *CustomerHandler) GetProfile(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	customer, err := h.customerService.GetByID(c.Context(), customerID)
+	if err != nil {
+		return catalogError(err, "Customer not found", "Failed to retrieve profile")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   customer.ToResponse(),
+	})
+}
+
+// UpdateProfile handles updating the authenticated customer's profile and consents
+func (h *CustomerHandler) UpdateProfile(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.CustomerUpdateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	customer, err := h.customerService.UpdateProfile(c.Context(), customerID, &req)
+	if err != nil {
+		return catalogError(err, "Customer not found", "Failed to update profile")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   customer.ToResponse(),
+	})
+}
+
+// GetAddresses handles retrieving the authenticated customer's saved addresses
+func (h *CustomerHandler) GetAddresses(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	addresses, err := h.customerService.ListAddresses(c.Context(), customerID)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve addresses", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   addresses,
+	})
+}
+
+// CreateAddress handles saving a new address for the authenticated customer
+func (h *CustomerHandler) CreateAddress(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.CustomerAddressRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	address, err := h.customerService.CreateAddress(c.Context(), customerID, &req)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to save address", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   address,
+	})
+}
+
+// UpdateAddress handles replacing a saved address of the authenticated customer
+func (h *CustomerHandler) UpdateAddress(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	id, err := pathID(c, "address")
+	if err != nil {
+		return err
+	}
+
+	var req models.CustomerAddressRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	address, err := h.customerService.UpdateAddress(c.Context(), customerID, id, &req)
+	if err != nil {
+		return catalogError(err, "Address not found", "Failed to update address")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   address,
+	})
+}
+
+// DeleteAddress handles deleting a saved address of the authenticated customer
+func (h *CustomerHandler) DeleteAddress(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	id, err := pathID(c, "address")
+	if err != nil {
+		return err
+	}
+
+	if err := h.customerService.DeleteAddress(c.Context(), customerID, id); err != nil {
+		return catalogError(err, "Address not found", "Failed to delete address")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Address deleted successfully",
+	})
+}
+
+// GetAll handles retrieving the customers of the current admin
+func (h *CustomerHandler) GetAll(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	if err := scopeToAdmin(c, &params); err != nil {
+		return err
+	}
+
+	customers, meta, err := h.customerService.List(c.Context(), params)
+	if err != nil {
+		return listError(err, "Failed to retrieve customers")
+	}
+
+	responses := []models.CustomerResponse{}
+	for _, customer := range customers {
+		responses = append(responses, customer.ToResponse())
+	}
+
+	return listResponse(c, responses, meta)
+}
+
+// GetByID handles retrieving a customer of the current admin by ID
+func (h *CustomerHandler) GetByID(c *fiber.Ctx) error {
+	id, err := pathID(c, "customer")
+	if err != nil {
+		return err
+	}
+
+	customer, err := h.customerService.GetByID(c.Context(), id)
+	if err != nil {
+		return catalogError(err, "Customer not found", "Failed to retrieve customer")
+	}
+
+	if err := checkOwner(c, customer.AdminID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   customer.ToResponse(),
+	})
+}
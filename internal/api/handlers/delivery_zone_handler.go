@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"strconv"
+
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeliveryZoneHandler handles delivery zone requests
+type DeliveryZoneHandler struct {
+	zoneService *service.DeliveryZoneService
+}
+
+// NewDeliveryZoneHandler creates a new delivery zone handler
+func NewDeliveryZoneHandler(zoneService *service.DeliveryZoneService) *DeliveryZoneHandler {
+	return &DeliveryZoneHandler{
+		zoneService: zoneService,
+	}
+}
+
+// Create handles creating a new delivery zone
+func (h *DeliveryZoneHandler) Create(c *fiber.Ctx) error {
+	var req models.DeliveryZoneCreateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	adminID, err := actingAdminID(c, req.AdminID)
+	if err != nil {
+		return err
+	}
+
+	zone, err := h.zoneService.Create(c.Context(), adminID, &req)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to create delivery zone", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   zone.ToResponse(),
+	})
+}
+
+// GetAll handles retrieving the delivery zones of the current admin
+func (h *DeliveryZoneHandler) GetAll(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	if err := scopeToAdmin(c, &params); err != nil {
+		return err
+	}
+
+	zones, meta, err := h.zoneService.List(c.Context(), params)
+	if err != nil {
+		return listError(err, "Failed to retrieve delivery zones")
+	}
+
+	responses := []models.DeliveryZoneResponse{}
+	for _, zone := range zones {
+		responses = append(responses, zone.ToResponse())
+	}
+
+	return listResponse(c, responses, meta)
+}
+
+// GetByID handles retrieving a delivery zone by ID
+func (h *DeliveryZoneHandler) GetByID(c *fiber.Ctx) error {
+	zone, err := h.ownZone(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   zone.ToResponse(),
+	})
+}
+
+// Update handles updating a delivery zone
+func (h *DeliveryZoneHandler) Update(c *fiber.Ctx) error {
+	zone, err := h.ownZone(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.DeliveryZoneUpdateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	zone, err = h.zoneService.Update(c.Context(), zone, &req)
+	if err != nil {
+		return catalogError(err, "Delivery zone not found", "Failed to update delivery zone")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   zone.ToResponse(),
+	})
+}
+
+// Delete handles deleting a delivery zone
+func (h *DeliveryZoneHandler) Delete(c *fiber.Ctx) error {
+	zone, err := h.ownZone(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.zoneService.Delete(c.Context(), zone.ID); err != nil {
+		return catalogError(err, "Delivery zone not found", "Failed to delete delivery zone")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Delivery zone deleted successfully",
+	})
+}
+
+// ownZone loads the delivery zone of the id URL parameter and checks its owner
+func (h *DeliveryZoneHandler) ownZone(c *fiber.Ctx) (*models.DeliveryZone, error) {
+	id, err := pathID(c, "delivery zone")
+	if err != nil {
+		return nil, err
+	}
+
+	zone, err := h.zoneService.GetByID(c.Context(), id)
+	if err != nil {
+		return nil, catalogError(err, "Delivery zone not found", "Failed to retrieve delivery zone")
+	}
+
+	if err := checkOwner(c, zone.AdminID); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// GetPublicByAdminID handles retrieving the active delivery zones of an admin without
+// authentication, e.g. to draw them on a map
+func (h *DeliveryZoneHandler) GetPublicByAdminID(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	zones, err := h.zoneService.ListPublic(c.Context(), adminID)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve delivery zones", fiber.StatusInternalServerError)
+	}
+
+	responses := []models.DeliveryZoneResponse{}
+	for _, zone := range zones {
+		responses = append(responses, zone.ToResponse())
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   responses,
+	})
+}
+
+// GetPublicQuote handles telling whether an admin delivers to the lat and lng query
+// coordinates, and at what cost for the subtotal, without authentication
+func (h *DeliveryZoneHandler) GetPublicQuote(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	var query models.DeliveryQuoteQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+	if err := utils.Validate(&query); err != nil {
+		return err
+	}
+
+	quote, err := h.zoneService.QuoteByAdminID(c.Context(), adminID, &query)
+	if err != nil {
+		return catalogError(err, "Restaurant not found", "Failed to quote delivery")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   quote,
+	})
+}
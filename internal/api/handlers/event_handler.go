@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// eventHeartbeat is how often an idle event stream sends a comment, keeping proxies
+// from closing it
+const eventHeartbeat = 25 * time.Second
+
+// EventHandler handles the admin panel event stream
+type EventHandler struct {
+	eventService *service.EventService
+}
+
+// NewEventHandler creates a new event handler
+func NewEventHandler(eventService *service.EventService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+// Stream handles streaming the events of the current admin as Server-Sent Events. Super
+// admins receive the events of every admin, or of the admin_id query parameter. Events
+// missed while disconnected are not replayed, clients reload their data on reconnect.
+func (h *EventHandler) Stream(c *fiber.Ctx) error {
+	requested := c.QueryInt("admin_id")
+	adminID, err := actingAdminID(c, requested)
+	if err != nil {
+		return err
+	}
+
+	// Super admins follow every admin unless they pick one
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+	if role == utils.RoleSuperAdmin && requested == 0 {
+		adminID = 0
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	sub := h.eventService.Subscribe(adminID)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.eventService.Unsubscribe(sub)
+
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+
+		fmt.Fprint(w, "retry: 5000\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+
+				data, err := json.Marshal(event)
+				if err != nil {
+					log.Printf("Failed to encode %s event: %v", event.Type, err)
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+
+			// A failed flush means the client went away
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
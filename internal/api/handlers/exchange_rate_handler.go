@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExchangeRateHandler handles exchange rate requests
+type ExchangeRateHandler struct {
+	exchangeRateService *service.ExchangeRateService
+}
+
+// NewExchangeRateHandler creates a new exchange rate handler
+func NewExchangeRateHandler(exchangeRateService *service.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// SetRate handles setting the exchange rate of a currency pair (super admin only)
+func (h *ExchangeRateHandler) SetRate(c *fiber.Ctx) error {
+	superAdminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.ExchangeRateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	rate, err := h.exchangeRateService.SetRate(c.Context(), superAdminID, &req)
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+
+		return utils.NewAppError(err, "Failed to set exchange rate", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   rate,
+	})
+}
+
+// GetAll handles retrieving the exchange rates, one page at a time (super admin only)
+func (h *ExchangeRateHandler) GetAll(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	rates, meta, err := h.exchangeRateService.List(c.Context(), params)
+	if err != nil {
+		return listError(err, "Failed to retrieve exchange rates")
+	}
+
+	return listResponse(c, rates, meta)
+}
+
+// Delete handles deleting an exchange rate (super admin only)
+func (h *ExchangeRateHandler) Delete(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid exchange rate ID")
+	}
+
+	if err := h.exchangeRateService.Delete(c.Context(), id); err != nil {
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Exchange rate not found", fiber.StatusNotFound)
+		}
+
+		return utils.NewAppError(err, "Failed to delete exchange rate", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Exchange rate deleted successfully",
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportHandler handles spreadsheet export requests (super admin only)
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// exportFormat reads the export format from the query, defaulting to CSV
+func exportFormat(c *fiber.Ctx) string {
+	return c.Query("format", utils.ExportFormatCSV)
+}
+
+// streamExport sends an export as a download, writing it while rows are read from the
+// database. Once streaming has started the status can't change, so failures are logged.
+func streamExport(c *fiber.Ctx, name string, format string, export func(ctx context.Context, w *bufio.Writer) error) error {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("2006-01-02"), format)
+
+	c.Set(fiber.HeaderContentType, utils.ExportContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := export(context.Background(), w); err != nil {
+			log.Printf("Error exporting %s: %v", name, err)
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("Error sending %s export: %v", name, err)
+		}
+	})
+
+	return nil
+}
+
+// ExportPayments handles exporting payments as CSV or XLSX, with the filters of the payment
+// list. Pagination and sorting don't apply, exports hold every match oldest first.
+func (h *ExportHandler) ExportPayments(c *fiber.Ctx) error {
+	format := exportFormat(c)
+	if err := h.exportService.ValidateFormat(format); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+	filter := paymentFilter(c)
+
+	return streamExport(c, "payments", format, func(ctx context.Context, w *bufio.Writer) error {
+		return h.exportService.ExportPayments(ctx, w, format, params, filter)
+	})
+}
+
+// ExportAdmins handles exporting admins as CSV or XLSX, with the filters of the admin list
+// and optionally subscription_tier_id
+func (h *ExportHandler) ExportAdmins(c *fiber.Ctx) error {
+	format := exportFormat(c)
+	if err := h.exportService.ValidateFormat(format); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+	// Checked here, the stream can't report errors once the download has started
+	if params.AdminID != nil {
+		return utils.NewInvalidInputError("Filtering by admin is not supported by this list")
+	}
+
+	filter := models.AdminFilter{}
+
+	if value := c.Query("subscription_tier_id"); value != "" {
+		tierID, err := strconv.Atoi(value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid subscription tier ID")
+		}
+		filter.SubscriptionTierID = &tierID
+	}
+
+	return streamExport(c, "admins", format, func(ctx context.Context, w *bufio.Writer) error {
+		return h.exportService.ExportAdmins(ctx, w, format, params, filter)
+	})
+}
+
+// ExportInvoices handles exporting invoices as CSV or XLSX, with the filters of the invoice list
+func (h *ExportHandler) ExportInvoices(c *fiber.Ctx) error {
+	format := exportFormat(c)
+	if err := h.exportService.ValidateFormat(format); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+	currency := invoiceCurrency(c)
+
+	return streamExport(c, "invoices", format, func(ctx context.Context, w *bufio.Writer) error {
+		return h.exportService.ExportInvoices(ctx, w, format, params, currency)
+	})
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
+	"mobilka/internal/api/middlewares"
 	"mobilka/internal/models"
 	"mobilka/internal/service"
 	"mobilka/internal/utils"
@@ -27,28 +29,19 @@ func (h *FCMTokenHandler) Create(c *fiber.Ctx) error {
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	var req models.FCMTokenCreateRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Create FCM token
 	fcmToken, err := h.fcmTokenService.Create(c.Context(), adminID, &req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to create FCM token",
-		})
+		return utils.NewAppError(err, "Failed to create FCM token", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -63,43 +56,34 @@ func (h *FCMTokenHandler) GetAll(c *fiber.Ctx) error {
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
 	role, _ := c.Locals(utils.ContextUserRole).(string)
 
-	var fcmTokens []*models.FCMToken
-	var err error
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
 
 	// Super admin can see all FCM tokens, admin can only see their own
-	if role == utils.RoleSuperAdmin {
-		fcmTokens, err = h.fcmTokenService.GetAll(c.Context())
-	} else {
-		fcmTokens, err = h.fcmTokenService.GetByAdminID(c.Context(), adminID)
+	if role != utils.RoleSuperAdmin {
+		params.AdminID = &adminID
 	}
 
+	fcmTokens, meta, err := h.fcmTokenService.List(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve FCM tokens",
-		})
+		return listError(err, "Failed to retrieve FCM tokens")
 	}
 
 	// Convert to response objects
-	var responses []models.FCMTokenResponse
+	responses := []models.FCMTokenResponse{}
 	for _, fcmToken := range fcmTokens {
 		responses = append(responses, fcmToken.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
 // Delete handles deleting an FCM token
@@ -107,26 +91,17 @@ func (h *FCMTokenHandler) Delete(c *fiber.Ctx) error {
 	// Get FCM token ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid FCM token ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid FCM token ID")
 	}
 
 	// Delete FCM token
 	err = h.fcmTokenService.Delete(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "FCM token not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "FCM token not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to delete FCM token",
-		})
+		return utils.NewAppError(err, "Failed to delete FCM token", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -143,27 +118,18 @@ func (h *FCMTokenHandler) DeleteByToken(c *fiber.Ctx) error {
 		Token string `json:"token" validate:"required"`
 	}
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Delete FCM token
 	err := h.fcmTokenService.DeleteByToken(c.Context(), req.Token)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "FCM token not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "FCM token not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to delete FCM token",
-		})
+		return utils.NewAppError(err, "Failed to delete FCM token", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -172,3 +138,62 @@ func (h *FCMTokenHandler) DeleteByToken(c *fiber.Ctx) error {
 		"message": "FCM token deleted successfully",
 	})
 }
+
+// CreateForCustomer handles registering the device of the authenticated customer for
+// their push notifications
+func (h *FCMTokenHandler) CreateForCustomer(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	adminID, ok := middlewares.GetAdminID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.FCMTokenCreateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	fcmToken, err := h.fcmTokenService.SaveForCustomer(c.Context(), adminID, customerID, &req)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to save FCM token", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   fcmToken.ToResponse(),
+	})
+}
+
+// DeleteForCustomer handles stopping the push notifications of the authenticated customer
+// on a device when they log out
+func (h *FCMTokenHandler) DeleteForCustomer(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req struct {
+		Token string `json:"token" validate:"required"`
+	}
+
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	err := h.fcmTokenService.UnlinkCustomer(c.Context(), customerID, req.Token)
+	if err != nil {
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "FCM token not found", fiber.StatusNotFound)
+		}
+
+		return utils.NewAppError(err, "Failed to delete FCM token", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "FCM token deleted successfully",
+	})
+}
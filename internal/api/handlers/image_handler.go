@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	"mobilka/internal/service"
 	"mobilka/internal/utils"
 
@@ -24,27 +26,18 @@ func (h *ImageHandler) Upload(c *fiber.Ctx) error {
 	// Get the uploaded file
 	file, err := c.FormFile("image")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "No image provided",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "No image provided")
 	}
 
 	// Check file size
 	if file.Size > utils.MaxImageSize {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Image size exceeds the maximum allowed size",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Image size exceeds the maximum allowed size")
 	}
 
 	// Save the image
 	filename, err := h.imageService.SaveImage(file)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to upload image",
-		})
+		return utils.NewAppError(err, "Failed to upload image", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -62,10 +55,7 @@ func (h *ImageHandler) Get(c *fiber.Ctx) error {
 	// Get the filename from URL
 	filename := c.Params("filename")
 	if filename == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "No filename provided",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "No filename provided")
 	}
 
 	// Get the image path
@@ -80,26 +70,17 @@ func (h *ImageHandler) Delete(c *fiber.Ctx) error {
 	// Get the filename from URL
 	filename := c.Params("filename")
 	if filename == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "No filename provided",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "No filename provided")
 	}
 
 	// Delete the image
 	err := h.imageService.DeleteImage(filename)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Image not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Image not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to delete image",
-		})
+		return utils.NewAppError(err, "Failed to delete image", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InvoiceHandler handles invoice requests
+type InvoiceHandler struct {
+	invoiceService *service.InvoiceService
+}
+
+// NewInvoiceHandler creates a new invoice handler
+func NewInvoiceHandler(invoiceService *service.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+	}
+}
+
+// invoiceCurrency reads the optional currency filter of invoice lists from the query
+func invoiceCurrency(c *fiber.Ctx) string {
+	if value := c.Query("currency"); value != "" {
+		return utils.NormalizeCurrency(value)
+	}
+	return ""
+}
+
+// GetAll handles retrieving invoices, one page at a time. Admins see their own invoices,
+// super admins those of every admin. Besides the shared list filters it filters by currency.
+func (h *InvoiceHandler) GetAll(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	if err := scopeToAdmin(c, &params); err != nil {
+		return err
+	}
+
+	invoices, meta, err := h.invoiceService.List(c.Context(), params, invoiceCurrency(c))
+	if err != nil {
+		return listError(err, "Failed to retrieve invoices")
+	}
+
+	return listResponse(c, invoices, meta)
+}
+
+// GetByID handles retrieving an invoice of the current admin by ID
+func (h *InvoiceHandler) GetByID(c *fiber.Ctx) error {
+	id, err := pathID(c, "invoice")
+	if err != nil {
+		return err
+	}
+
+	invoice, err := h.invoiceService.GetByID(c.Context(), id)
+	if err != nil {
+		return catalogError(err, "Invoice not found", "Failed to retrieve invoice")
+	}
+
+	if err := checkOwner(c, invoice.AdminID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   invoice,
+	})
+}
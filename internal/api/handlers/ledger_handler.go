@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LedgerHandler handles admin balance and statement requests
+type LedgerHandler struct {
+	ledgerService *service.LedgerService
+}
+
+// NewLedgerHandler creates a new ledger handler
+func NewLedgerHandler(ledgerService *service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// ledgerError maps ledger errors to a response
+func ledgerError(err error, fallback string) error {
+	if errors.Is(err, utils.ErrUserNotFound) {
+		return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
+	}
+
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return utils.NewAppError(err, fallback, fiber.StatusInternalServerError)
+}
+
+// parseStatementRange reads the optional from and to dates (YYYY-MM-DD, both inclusive) from the query
+func parseStatementRange(c *fiber.Ctx) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, nil, utils.NewInvalidInputError("Invalid from date, expected YYYY-MM-DD")
+		}
+		from = &parsed
+	}
+
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, nil, utils.NewInvalidInputError("Invalid to date, expected YYYY-MM-DD")
+		}
+		parsed = parsed.AddDate(0, 0, 1)
+		to = &parsed
+	}
+
+	return from, to, nil
+}
+
+// statement writes the statement of an admin
+func (h *LedgerHandler) statement(c *fiber.Ctx, adminID int) error {
+	from, to, err := parseStatementRange(c)
+	if err != nil {
+		return ledgerError(err, "Invalid date range")
+	}
+
+	statement, err := h.ledgerService.GetStatement(c.Context(), adminID, c.Query("currency"), from, to)
+	if err != nil {
+		return ledgerError(err, "Failed to retrieve statement")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   statement,
+	})
+}
+
+// balances writes the prepaid balances of an admin
+func (h *LedgerHandler) balances(c *fiber.Ctx, adminID int) error {
+	balances, err := h.ledgerService.GetBalances(c.Context(), adminID)
+	if err != nil {
+		return ledgerError(err, "Failed to retrieve balance")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   balances,
+	})
+}
+
+// GetStatement handles retrieving the statement of the current admin
+func (h *LedgerHandler) GetStatement(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	return h.statement(c, adminID)
+}
+
+// GetBalances handles retrieving the prepaid balances of the current admin
+func (h *LedgerHandler) GetBalances(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	return h.balances(c, adminID)
+}
+
+// GetAdminStatement handles retrieving the statement of an admin (super admin only)
+func (h *LedgerHandler) GetAdminStatement(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminId"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	return h.statement(c, adminID)
+}
+
+// GetAdminBalances handles retrieving the prepaid balances of an admin (super admin only)
+func (h *LedgerHandler) GetAdminBalances(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminId"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	return h.balances(c, adminID)
+}
+
+// Credit handles granting balance to an admin (super admin only)
+func (h *LedgerHandler) Credit(c *fiber.Ctx) error {
+	superAdminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	adminID, err := strconv.Atoi(c.Params("adminId"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	var req models.LedgerCreditRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	txn, err := h.ledgerService.Credit(c.Context(), adminID, superAdminID, &req)
+	if err != nil {
+		return ledgerError(err, "Failed to credit balance")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   txn,
+	})
+}
+
+// Adjust handles correcting the balance of an admin (super admin only)
+func (h *LedgerHandler) Adjust(c *fiber.Ctx) error {
+	superAdminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	adminID, err := strconv.Atoi(c.Params("adminId"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	var req models.LedgerAdjustmentRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	txn, err := h.ledgerService.Adjust(c.Context(), adminID, superAdminID, &req)
+	if err != nil {
+		return ledgerError(err, "Failed to adjust balance")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   txn,
+	})
+}
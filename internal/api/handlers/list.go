@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseListParams reads the pagination, sorting and filters shared by all list endpoints
+// from the query:
+//   - limit and offset, or the older step and skip, or cursor (the next_cursor of the previous page)
+//   - sort, descending with a "-" prefix or order=desc
+//   - status, admin_id, q (text search), and from and to dates (YYYY-MM-DD, both inclusive)
+func parseListParams(c *fiber.Ctx) (models.ListParams, error) {
+	params := models.ListParams{
+		Cursor: c.Query("cursor"),
+		Status: c.Query("status"),
+		Search: c.Query("q"),
+	}
+
+	intQuery := func(names ...string) (int, error) {
+		for _, name := range names {
+			if value := c.Query(name); value != "" {
+				parsed, err := strconv.Atoi(value)
+				if err != nil || parsed < 0 {
+					return 0, utils.NewInvalidInputError("Invalid " + name + ", expected a non-negative number")
+				}
+				return parsed, nil
+			}
+		}
+		return 0, nil
+	}
+
+	var err error
+	if params.Limit, err = intQuery("limit", "step"); err != nil {
+		return params, err
+	}
+	if params.Offset, err = intQuery("offset", "skip"); err != nil {
+		return params, err
+	}
+
+	params.Sort = c.Query("sort")
+	if strings.HasPrefix(params.Sort, "-") {
+		params.Sort, params.Desc = params.Sort[1:], true
+	}
+	switch strings.ToLower(c.Query("order")) {
+	case "":
+	case "asc":
+		params.Desc = false
+	case "desc":
+		params.Desc = true
+	default:
+		return params, utils.NewInvalidInputError("Invalid order, expected asc or desc")
+	}
+
+	if value := c.Query("admin_id"); value != "" {
+		adminID, err := strconv.Atoi(value)
+		if err != nil {
+			return params, utils.NewInvalidInputError("Invalid admin ID")
+		}
+		params.AdminID = &adminID
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return params, utils.NewInvalidInputError("Invalid from date, expected YYYY-MM-DD")
+		}
+		params.From = &from
+	}
+
+	if value := c.Query("to"); value != "" {
+		to, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return params, utils.NewInvalidInputError("Invalid to date, expected YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+		params.To = &to
+	}
+
+	return params, nil
+}
+
+// listResponse writes one page of a list in the envelope shared by all list endpoints
+func listResponse(c *fiber.Ctx, data interface{}, meta *models.ListMeta) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   data,
+		"meta":   meta,
+	})
+}
+
+// listError maps list errors to a response: bad parameters keep their status, anything
+// else is reported with the fallback message
+func listError(err error, fallback string) error {
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	if errors.Is(err, utils.ErrUserNotFound) {
+		return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
+	}
+
+	return utils.NewAppError(err, fallback, fiber.StatusInternalServerError)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"mobilka/internal/api/middlewares"
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LoyaltyHandler handles loyalty program and points requests
+type LoyaltyHandler struct {
+	loyaltyService  *service.LoyaltyService
+	customerService *service.CustomerService
+}
+
+// NewLoyaltyHandler creates a new loyalty handler
+func NewLoyaltyHandler(loyaltyService *service.LoyaltyService, customerService *service.CustomerService) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyService:  loyaltyService,
+		customerService: customerService,
+	}
+}
+
+// GetProgram handles retrieving the loyalty program of the current admin. Super admins
+// pick the admin with the admin_id query parameter.
+func (h *LoyaltyHandler) GetProgram(c *fiber.Ctx) error {
+	adminID, err := actingAdminID(c, c.QueryInt("admin_id"))
+	if err != nil {
+		return err
+	}
+
+	program, err := h.loyaltyService.GetProgram(c.Context(), adminID)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve loyalty program", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   program,
+	})
+}
+
+// SaveProgram handles setting up or changing the loyalty program of the current admin
+func (h *LoyaltyHandler) SaveProgram(c *fiber.Ctx) error {
+	var req models.LoyaltyProgramRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	adminID, err := actingAdminID(c, req.AdminID)
+	if err != nil {
+		return err
+	}
+
+	program, err := h.loyaltyService.SaveProgram(c.Context(), adminID, &req)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to save loyalty program", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   program,
+	})
+}
+
+// GetTransactions handles retrieving the points ledger of the current admin's customers,
+// of one customer with the customer_id query parameter
+func (h *LoyaltyHandler) GetTransactions(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	if err := scopeToAdmin(c, &params); err != nil {
+		return err
+	}
+
+	var customerID *int
+	if id := c.QueryInt("customer_id"); id > 0 {
+		customerID = &id
+	}
+
+	transactions, meta, err := h.loyaltyService.ListTransactions(c.Context(), params, customerID)
+	if err != nil {
+		return listError(err, "Failed to retrieve loyalty transactions")
+	}
+
+	return listResponse(c, transactions, meta)
+}
+
+// GetCustomerBalance handles retrieving the points balance of a customer of the current admin
+func (h *LoyaltyHandler) GetCustomerBalance(c *fiber.Ctx) error {
+	id, err := pathID(c, "customer")
+	if err != nil {
+		return err
+	}
+
+	customer, err := h.customerService.GetByID(c.Context(), id)
+	if err != nil {
+		return catalogError(err, "Customer not found", "Failed to retrieve customer")
+	}
+
+	if err := checkOwner(c, customer.AdminID); err != nil {
+		return err
+	}
+
+	balance, err := h.loyaltyService.GetBalance(c.Context(), customer.AdminID, customer.ID)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve loyalty balance", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   balance,
+	})
+}
+
+// GetBalance handles retrieving the authenticated customer's points balance
+func (h *LoyaltyHandler) GetBalance(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	adminID, ok := middlewares.GetAdminID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	balance, err := h.loyaltyService.GetBalance(c.Context(), adminID, customerID)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve loyalty balance", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   balance,
+	})
+}
+
+// GetMyTransactions handles retrieving the authenticated customer's points ledger
+func (h *LoyaltyHandler) GetMyTransactions(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+	params.AdminID = nil
+
+	transactions, meta, err := h.loyaltyService.ListTransactions(c.Context(), params, &customerID)
+	if err != nil {
+		return listError(err, "Failed to retrieve loyalty transactions")
+	}
+
+	return listResponse(c, transactions, meta)
+}
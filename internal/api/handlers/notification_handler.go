@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -28,43 +29,15 @@ func (h *NotificationHandler) Create(c *fiber.Ctx) error {
 	// Get admin ID from context
 	contextAdminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
 	role, _ := c.Locals(utils.ContextUserRole).(string)
 
 	var req models.NotificationCreateRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate required fields
-	if req.Payload == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Payload is required",
-		})
-	}
-
-	if req.Title == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Title is required",
-		})
-	}
-
-	if req.Body == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Body is required",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Determine which admin ID to use
@@ -82,10 +55,7 @@ func (h *NotificationHandler) Create(c *fiber.Ctx) error {
 	// Create notification
 	notification, err := h.notificationService.Create(c.Context(), adminID, &req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to create notification",
-		})
+		return utils.NewAppError(err, "Failed to create notification", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -95,48 +65,39 @@ func (h *NotificationHandler) Create(c *fiber.Ctx) error {
 	})
 }
 
-// GetAll handles retrieving all notifications for the current admin
+// GetAll handles retrieving the notifications of the current admin, one page at a time
 func (h *NotificationHandler) GetAll(c *fiber.Ctx) error {
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
 	role, _ := c.Locals(utils.ContextUserRole).(string)
 
-	var notifications []*models.Notification
-	var err error
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
 
 	// Super admin can see all notifications, admin can only see their own
-	if role == utils.RoleSuperAdmin {
-		notifications, err = h.notificationService.GetAll(c.Context())
-	} else {
-		notifications, err = h.notificationService.GetByAdminID(c.Context(), adminID)
+	if role != utils.RoleSuperAdmin {
+		params.AdminID = &adminID
 	}
 
+	notifications, meta, err := h.notificationService.List(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve notifications",
-		})
+		return listError(err, "Failed to retrieve notifications")
 	}
 
 	// Convert to response objects
-	var responses []models.NotificationResponse
+	responses := []models.NotificationResponse{}
 	for _, notification := range notifications {
 		responses = append(responses, notification.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
 // GetByID handles retrieving a notification by ID
@@ -144,35 +105,23 @@ func (h *NotificationHandler) GetByID(c *fiber.Ctx) error {
 	// Get notification ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid notification ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid notification ID")
 	}
 
 	// Get notification
 	notification, err := h.notificationService.GetByID(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Notification not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Notification not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve notification",
-		})
+		return utils.NewAppError(err, "Failed to retrieve notification", fiber.StatusInternalServerError)
 	}
 
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
@@ -180,10 +129,7 @@ func (h *NotificationHandler) GetByID(c *fiber.Ctx) error {
 
 	// Check if the user has access to this notification
 	if role != utils.RoleSuperAdmin && notification.AdminID != adminID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Access denied",
-		})
+		return fiber.NewError(fiber.StatusForbidden, "Access denied")
 	}
 
 	// Return response
@@ -198,55 +144,37 @@ func (h *NotificationHandler) Update(c *fiber.Ctx) error {
 	// Get notification ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid notification ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid notification ID")
 	}
 
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
 	role, _ := c.Locals(utils.ContextUserRole).(string)
 
 	var req models.NotificationUpdateRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// First, get the existing notification to check ownership
 	existingNotification, err := h.notificationService.GetByID(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Notification not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Notification not found", fiber.StatusNotFound)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve notification",
-		})
+		return utils.NewAppError(err, "Failed to retrieve notification", fiber.StatusInternalServerError)
 	}
 
 	// Check if user has permission to update this notification
 	// Super admins can update any notification
 	// Regular admins can only update their own notifications
 	if role != utils.RoleSuperAdmin && existingNotification.AdminID != adminID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "You don't have permission to update this notification",
-		})
+		return fiber.NewError(fiber.StatusForbidden, "You don't have permission to update this notification")
 	}
 
 	// Allow changing adminID only for super admins
@@ -259,16 +187,10 @@ func (h *NotificationHandler) Update(c *fiber.Ctx) error {
 	// Update notification
 	notification, err := h.notificationService.Update(c.Context(), id, targetAdminID, &req)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Notification not found or access denied",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Notification not found or access denied", fiber.StatusNotFound)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to update notification",
-		})
+		return utils.NewAppError(err, "Failed to update notification", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -283,35 +205,23 @@ func (h *NotificationHandler) Delete(c *fiber.Ctx) error {
 	// Get notification ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid notification ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid notification ID")
 	}
 
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Delete notification
 	err = h.notificationService.Delete(c.Context(), id, adminID)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Notification not found or access denied",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Notification not found or access denied", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to delete notification",
-		})
+		return utils.NewAppError(err, "Failed to delete notification", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -321,50 +231,36 @@ func (h *NotificationHandler) Delete(c *fiber.Ctx) error {
 	})
 }
 
-// GetPublicByAdminID handles retrieving notifications for a specific admin with pagination
+// GetPublicByAdminID handles retrieving notifications for a specific admin with pagination.
+// Pages hold 10 notifications unless a limit (or step) is given.
 func (h *NotificationHandler) GetPublicByAdminID(c *fiber.Ctx) error {
 	// Get admin ID from URL
 	adminID, err := strconv.Atoi(c.Params("adminID"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid admin ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
 	}
 
 	// Parse pagination parameters
-	skip, err := strconv.Atoi(c.Query("skip", "0"))
-	if err != nil || skip < 0 {
-		skip = 0
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
 	}
-
-	step, err := strconv.Atoi(c.Query("step", "10"))
-	if err != nil || step <= 0 || step > 100 {
-		step = 10 // Default limit is 10, max is 100
+	params.AdminID = &adminID
+	if params.Limit == 0 {
+		params.Limit = 10
 	}
 
 	// Get notifications with pagination
-	notifications, err := h.notificationService.GetByAdminIDWithPagination(c.Context(), adminID, skip, step)
+	notifications, meta, err := h.notificationService.List(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve notifications",
-		})
+		return listError(err, "Failed to retrieve notifications")
 	}
 
 	// Convert to response objects
-	var responses []models.NotificationResponse
+	responses := []models.NotificationResponse{}
 	for _, notification := range notifications {
 		responses = append(responses, notification.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-		"meta": fiber.Map{
-			"skip": skip,
-			"step": step,
-		},
-	})
+	return listResponse(c, responses, meta)
 }
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"strconv"
+
+	"mobilka/internal/api/middlewares"
+	"mobilka/internal/models"
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OrderHandler handles order requests
+type OrderHandler struct {
+	orderService *service.OrderService
+}
+
+// NewOrderHandler creates a new order handler
+func NewOrderHandler(orderService *service.OrderService) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+// GetAll handles retrieving the orders of the current admin, or of all admins for super admins
+func (h *OrderHandler) GetAll(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	if err := scopeToAdmin(c, &params); err != nil {
+		return err
+	}
+
+	orders, meta, err := h.orderService.List(c.Context(), params)
+	if err != nil {
+		return listError(err, "Failed to retrieve orders")
+	}
+
+	responses := []models.OrderResponse{}
+	for _, order := range orders {
+		responses = append(responses, order.ToResponse())
+	}
+
+	return listResponse(c, responses, meta)
+}
+
+// GetByID handles retrieving an order by ID
+func (h *OrderHandler) GetByID(c *fiber.Ctx) error {
+	order, err := h.ownOrder(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   order.ToResponse(),
+	})
+}
+
+// UpdateStatus handles moving an order to its next status
+func (h *OrderHandler) UpdateStatus(c *fiber.Ctx) error {
+	order, err := h.ownOrder(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.OrderStatusRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	actorID, _ := c.Locals(utils.ContextUserID).(int)
+
+	order, err = h.orderService.ChangeStatus(c.Context(), order, req.Status, models.OrderActorAdmin, &actorID, req.Note)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to update order status", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   order.ToResponse(),
+	})
+}
+
+// ownOrder loads the order of the id URL parameter and checks its owner
+func (h *OrderHandler) ownOrder(c *fiber.Ctx) (*models.Order, error) {
+	id, err := pathID(c, "order")
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := h.orderService.GetByID(c.Context(), id)
+	if err != nil {
+		return nil, catalogError(err, "Order not found", "Failed to retrieve order")
+	}
+
+	if err := checkOwner(c, order.AdminID); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CreatePublic handles placing an order with an admin without authentication. The
+// response carries the tracking token the customer follows the order with.
+func (h *OrderHandler) CreatePublic(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	var req models.OrderCreateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	order, err := h.orderService.Create(c.Context(), adminID, nil, &req)
+	if err != nil {
+		return catalogError(err, "Restaurant not found", "Failed to place order")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   order.ToResponse(),
+	})
+}
+
+// CreateForCustomer handles placing an order for the authenticated customer with their
+// restaurant. The order earns loyalty points and may spend them.
+func (h *OrderHandler) CreateForCustomer(c *fiber.Ctx) error {
+	customerID, ok := middlewares.GetUserID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+	adminID, ok := middlewares.GetAdminID(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.OrderCreateRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	order, err := h.orderService.Create(c.Context(), adminID, &customerID, &req)
+	if err != nil {
+		return catalogError(err, "Restaurant not found", "Failed to place order")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   order.ToResponse(),
+	})
+}
+
+// Track handles retrieving an order by its tracking token without authentication
+func (h *OrderHandler) Track(c *fiber.Ctx) error {
+	order, err := h.orderService.GetByTrackingToken(c.Context(), c.Params("token"))
+	if err != nil {
+		return catalogError(err, "Order not found", "Failed to retrieve order")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   order.ToResponse(),
+	})
+}
+
+// CancelPublic handles a customer cancelling an order by its tracking token. The body
+// with the reason is optional.
+func (h *OrderHandler) CancelPublic(c *fiber.Ctx) error {
+	var req models.OrderCancelRequest
+	if len(c.Body()) > 0 {
+		if err := parseBody(c, &req); err != nil {
+			return err
+		}
+	}
+
+	order, err := h.orderService.GetByTrackingToken(c.Context(), c.Params("token"))
+	if err != nil {
+		return catalogError(err, "Order not found", "Failed to retrieve order")
+	}
+
+	order, err = h.orderService.CustomerCancel(c.Context(), order, req.Reason)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to cancel order", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   order.ToResponse(),
+	})
+}
+
+// RegisterTelegramWebhook handles pointing the admin's Telegram bot at the order webhook
+func (h *OrderHandler) RegisterTelegramWebhook(c *fiber.Ctx) error {
+	adminID, err := actingAdminID(c, c.QueryInt("admin_id"))
+	if err != nil {
+		return err
+	}
+
+	if err := h.orderService.RegisterTelegramWebhook(c.Context(), adminID); err != nil {
+		return catalogError(err, "Admin not found", "Failed to register Telegram webhook")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Telegram webhook registered successfully",
+	})
+}
+
+// TelegramWebhook handles the button presses Telegram sends for an admin's bot
+func (h *OrderHandler) TelegramWebhook(c *fiber.Ctx) error {
+	adminID, err := strconv.Atoi(c.Params("adminID"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	var update models.TelegramUpdate
+	if err := c.BodyParser(&update); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	secret := c.Get("X-Telegram-Bot-Api-Secret-Token")
+	if err := h.orderService.HandleTelegramUpdate(c.Context(), adminID, secret, &update); err != nil {
+		return catalogError(err, "Admin not found", "Failed to handle Telegram update")
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
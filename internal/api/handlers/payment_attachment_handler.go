@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"mobilka/internal/service"
+	"mobilka/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PaymentAttachmentHandler handles proof of payment requests
+type PaymentAttachmentHandler struct {
+	attachmentService *service.PaymentAttachmentService
+}
+
+// NewPaymentAttachmentHandler creates a new payment attachment handler
+func NewPaymentAttachmentHandler(attachmentService *service.PaymentAttachmentService) *PaymentAttachmentHandler {
+	return &PaymentAttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// paymentAttachmentError maps payment attachment service errors to a response
+func paymentAttachmentError(err error, notFound string, fallback string) error {
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		return utils.NewAppError(err, notFound, fiber.StatusNotFound)
+	}
+
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return utils.NewAppError(err, fallback, fiber.StatusInternalServerError)
+}
+
+// Upload handles an admin attaching proofs to one of their pending payments.
+// Files are sent as multipart form fields named "file", one or more times.
+func (h *PaymentAttachmentHandler) Upload(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	paymentID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid payment ID")
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "No file provided")
+	}
+
+	attachments, err := h.attachmentService.Upload(c.Context(), adminID, paymentID, form.File["file"])
+	if err != nil {
+		return paymentAttachmentError(err, "Payment not found", "Failed to upload payment attachment")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   attachments,
+	})
+}
+
+// Download handles retrieving the file of a payment attachment. Only the paying admin
+// and super admins have access.
+func (h *PaymentAttachmentHandler) Download(c *fiber.Ctx) error {
+	userID, _ := c.Locals(utils.ContextUserID).(int)
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid attachment ID")
+	}
+
+	attachment, path, err := h.attachmentService.GetForDownload(c.Context(), id, userID, role)
+	if err != nil {
+		return paymentAttachmentError(err, "Attachment not found", "Failed to retrieve payment attachment")
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	return c.SendFile(path)
+}
+
+// Delete handles an admin removing a proof from one of their pending payments
+func (h *PaymentAttachmentHandler) Delete(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid attachment ID")
+	}
+
+	if err := h.attachmentService.Delete(c.Context(), adminID, id); err != nil {
+		return paymentAttachmentError(err, "Attachment not found", "Failed to delete payment attachment")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Attachment deleted successfully",
+	})
+}
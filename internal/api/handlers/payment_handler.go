@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"mobilka/internal/models"
@@ -12,13 +13,15 @@ import (
 
 // PaymentHandler handles payment requests
 type PaymentHandler struct {
-	paymentService *service.PaymentService
+	paymentService    *service.PaymentService
+	attachmentService *service.PaymentAttachmentService
 }
 
 // NewPaymentHandler creates a new payment handler
-func NewPaymentHandler(paymentService *service.PaymentService) *PaymentHandler {
+func NewPaymentHandler(paymentService *service.PaymentService, attachmentService *service.PaymentAttachmentService) *PaymentHandler {
 	return &PaymentHandler{
-		paymentService: paymentService,
+		paymentService:    paymentService,
+		attachmentService: attachmentService,
 	}
 }
 
@@ -27,42 +30,27 @@ func (h *PaymentHandler) RecordPayment(c *fiber.Ctx) error {
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	var req models.PaymentCreateRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate required fields
-	if req.Amount <= 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Amount must be greater than zero",
-		})
-	}
-
-	if req.PaymentMethod == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Payment method is required",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Record payment
 	payment, err := h.paymentService.RecordPayment(c.Context(), adminID, &req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to record payment: " + err.Error(),
-		})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Subscription tier not found", fiber.StatusNotFound)
+		}
+
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+
+		return utils.NewAppError(err, "Failed to record payment", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -73,85 +61,124 @@ func (h *PaymentHandler) RecordPayment(c *fiber.Ctx) error {
 	})
 }
 
-// GetAdminPayments handles retrieving all payments for the current admin
+// GetAdminPayments handles retrieving the payments of the current admin, one page at a time
 func (h *PaymentHandler) GetAdminPayments(c *fiber.Ctx) error {
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
 	}
 
 	// Get admin payments
-	payments, err := h.paymentService.GetPaymentsByAdminID(c.Context(), adminID)
+	payments, meta, err := h.paymentService.GetPaymentsByAdminID(c.Context(), adminID, params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve payments",
-		})
+		return listError(err, "Failed to retrieve payments")
 	}
 
 	// Convert to response objects
-	var responses []models.PaymentHistoryResponse
+	responses := []models.PaymentHistoryResponse{}
 	for _, payment := range payments {
 		responses = append(responses, payment.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
-// GetAllPayments handles retrieving all payments (super admin only)
+// paymentFilter reads the payment filters besides the shared list filters from the query:
+// payment_method and currency
+func paymentFilter(c *fiber.Ctx) models.PaymentFilter {
+	filter := models.PaymentFilter{PaymentMethod: c.Query("payment_method")}
+	if value := c.Query("currency"); value != "" {
+		filter.Currency = utils.NormalizeCurrency(value)
+	}
+
+	return filter
+}
+
+// GetAllPayments handles retrieving all payments, one page at a time (super admin only).
+// Besides the shared list filters it filters by payment_method and currency.
 func (h *PaymentHandler) GetAllPayments(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
 	// Get all payments
-	payments, err := h.paymentService.GetAllPayments(c.Context())
+	payments, meta, err := h.paymentService.GetAllPayments(c.Context(), params, paymentFilter(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve payments",
-		})
+		return listError(err, "Failed to retrieve payments")
 	}
 
 	// Convert to response objects
-	var responses []models.PaymentHistoryResponse
+	responses := []models.PaymentHistoryResponse{}
 	for _, payment := range payments {
 		responses = append(responses, payment.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
-// GetPendingPayments handles retrieving all pending payments (super admin only)
+// GetPendingPayments handles retrieving the pending payments, one page at a time (super admin only)
 func (h *PaymentHandler) GetPendingPayments(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
 	// Get pending payments
-	payments, err := h.paymentService.GetPendingPayments(c.Context())
+	payments, meta, err := h.paymentService.GetPendingPayments(c.Context(), params)
+	if err != nil {
+		return listError(err, "Failed to retrieve pending payments")
+	}
+
+	// Get the proofs attached to the payments
+	paymentIDs := make([]int, len(payments))
+	for i, payment := range payments {
+		paymentIDs[i] = payment.ID
+	}
+
+	attachments, err := h.attachmentService.GetByPaymentIDs(c.Context(), paymentIDs...)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve payment attachments", fiber.StatusInternalServerError)
+	}
+
+	// Convert to response objects
+	responses := []models.PaymentHistoryResponse{}
+	for _, payment := range payments {
+		response := payment.ToResponse()
+		response.Attachments = attachments[payment.ID]
+		responses = append(responses, response)
+	}
+
+	return listResponse(c, responses, meta)
+}
+
+// GetFlaggedPayments handles retrieving the pending payments flagged for review, one page
+// at a time (super admin only)
+func (h *PaymentHandler) GetFlaggedPayments(c *fiber.Ctx) error {
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	// Get flagged payments
+	payments, meta, err := h.paymentService.GetFlaggedPayments(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve pending payments",
-		})
+		return listError(err, "Failed to retrieve flagged payments")
 	}
 
 	// Convert to response objects
-	var responses []models.PaymentHistoryResponse
+	responses := []models.PaymentHistoryResponse{}
 	for _, payment := range payments {
 		responses = append(responses, payment.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
 // GetPaymentByID handles retrieving a payment by ID
@@ -159,26 +186,17 @@ func (h *PaymentHandler) GetPaymentByID(c *fiber.Ctx) error {
 	// Get payment ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid payment ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid payment ID")
 	}
 
 	// Get payment
 	payment, err := h.paymentService.GetPaymentByID(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Payment not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Payment not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve payment",
-		})
+		return utils.NewAppError(err, "Failed to retrieve payment", fiber.StatusInternalServerError)
 	}
 
 	// Check if the request is from super admin or the admin who made the payment
@@ -186,16 +204,22 @@ func (h *PaymentHandler) GetPaymentByID(c *fiber.Ctx) error {
 	userID, _ := c.Locals(utils.ContextUserID).(int)
 
 	if role != utils.RoleSuperAdmin && payment.AdminID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Access denied",
-		})
+		return fiber.NewError(fiber.StatusForbidden, "Access denied")
+	}
+
+	// Get the proofs attached to the payment
+	attachments, err := h.attachmentService.GetByPaymentIDs(c.Context(), payment.ID)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve payment attachments", fiber.StatusInternalServerError)
 	}
 
+	response := payment.ToResponse()
+	response.Attachments = attachments[payment.ID]
+
 	// Return response
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status": utils.StatusSuccess,
-		"data":   payment.ToResponse(),
+		"data":   response,
 	})
 }
 
@@ -204,51 +228,33 @@ func (h *PaymentHandler) VerifyPayment(c *fiber.Ctx) error {
 	// Get super admin ID from context
 	superAdminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get payment ID from URL
 	paymentID, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid payment ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid payment ID")
 	}
 
 	var req models.PaymentVerifyRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate status
-	if req.Status != "verified" && req.Status != "rejected" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Status must be 'verified' or 'rejected'",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Verify payment
 	err = h.paymentService.VerifyPayment(c.Context(), paymentID, superAdminID, &req)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Payment not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Payment not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to verify payment: " + err.Error(),
-		})
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+
+		return utils.NewAppError(err, "Failed to verify payment", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -270,58 +276,53 @@ func (h *PaymentHandler) GetSubscriptionInfo(c *fiber.Ctx) error {
 		// Super admin can check any admin's subscription
 		id, err := strconv.Atoi(c.Params("id"))
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Invalid admin ID",
-			})
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
 		}
 		adminID = id
 	} else {
 		// Regular admins can only check their own subscription
 		adminID, ok = c.Locals(utils.ContextUserID).(int)
 		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Unauthorized",
-			})
+			return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 		}
 	}
 
 	// Check and update subscription status if needed
 	admin, err := h.paymentService.CheckSubscriptionStatus(c.Context(), adminID)
 	if err != nil {
-		if err == utils.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Admin not found",
-			})
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to check subscription status",
-		})
+		return utils.NewAppError(err, "Failed to check subscription status", fiber.StatusInternalServerError)
 	}
 
 	// Get detailed subscription info
 	admin, tier, latestPayment, err := h.paymentService.GetSubscriptionInfo(c.Context(), adminID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve subscription information",
-		})
+		return utils.NewAppError(err, "Failed to retrieve subscription information", fiber.StatusInternalServerError)
 	}
 
 	// Calculate subscription fee
-	fee, recommendedTier, err := h.paymentService.CalculateMonthlySubscriptionFee(c.Context(), admin.Users)
+	fee, recommendedTier, err := h.paymentService.CalculateMonthlySubscriptionFee(c.Context(), admin.ID, admin.Users)
 	if err != nil {
 		fee = 0
 	}
 
+	// Calculate the fee for the admin's billing interval
+	intervalFee, _, err := h.paymentService.CalculateSubscriptionFee(c.Context(), admin.ID, admin.Users, admin.BillingInterval)
+	if err != nil {
+		intervalFee = 0
+	}
+
 	// Prepare response
 	response := fiber.Map{
 		"admin":                admin.ToResponse(),
 		"monthly_fee":          fee,
+		"billing_interval":     admin.BillingInterval,
+		"interval_fee":         intervalFee,
+		"interval_months":      models.BillingIntervalMonths[admin.BillingInterval],
+		"currency":             admin.BillingCurrency,
 		"subscription_status":  admin.SubscriptionStatus,
 		"is_access_restricted": admin.IsAccessRestricted,
 	}
@@ -344,3 +345,204 @@ func (h *PaymentHandler) GetSubscriptionInfo(c *fiber.Ctx) error {
 		"data":   response,
 	})
 }
+
+// QuoteTierChange handles calculating the price of moving to another subscription tier
+func (h *PaymentHandler) QuoteTierChange(c *fiber.Ctx) error {
+	// Get admin ID from context
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	// Get target tier ID from query
+	tierID, err := strconv.Atoi(c.Query("tier_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid subscription tier ID")
+	}
+
+	// Calculate quote
+	quote, err := h.paymentService.QuoteTierChange(c.Context(), adminID, tierID)
+	if err != nil {
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Subscription tier not found", fiber.StatusNotFound)
+		}
+
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
+		}
+
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+
+		return utils.NewAppError(err, "Failed to calculate tier change", fiber.StatusInternalServerError)
+	}
+
+	// Return response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   quote,
+	})
+}
+
+// SetBillingInterval handles an admin choosing how often they pay for their subscription
+func (h *PaymentHandler) SetBillingInterval(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	var req models.BillingIntervalRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	admin, err := h.paymentService.SetBillingInterval(c.Context(), adminID, req.BillingInterval)
+	if err != nil {
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
+		}
+
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+
+		return utils.NewAppError(err, "Failed to update billing interval", fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   admin.ToResponse(),
+	})
+}
+
+// paymentError maps payment service errors to a response
+func paymentError(err error, fallback string) error {
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		return utils.NewAppError(err, "Payment not found", fiber.StatusNotFound)
+	}
+
+	if errors.Is(err, utils.ErrUserNotFound) {
+		return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
+	}
+
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return utils.NewAppError(err, fallback, fiber.StatusInternalServerError)
+}
+
+// RenewFromBalance handles paying the next billing period of the current admin from the prepaid balance
+func (h *PaymentHandler) RenewFromBalance(c *fiber.Ctx) error {
+	adminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	payment, err := h.paymentService.RenewFromBalance(c.Context(), adminID)
+	if err != nil {
+		return paymentError(err, "Failed to renew subscription from balance")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  utils.StatusSuccess,
+		"message": "Subscription renewed from balance",
+		"data":    payment.ToResponse(),
+	})
+}
+
+// RefundPayment handles refunding all or part of a verified payment (super admin only)
+func (h *PaymentHandler) RefundPayment(c *fiber.Ctx) error {
+	superAdminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	paymentID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid payment ID")
+	}
+
+	var req models.PaymentRefundRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	refund, err := h.paymentService.RefundPayment(c.Context(), paymentID, superAdminID, &req)
+	if err != nil {
+		return paymentError(err, "Failed to refund payment")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   refund,
+	})
+}
+
+// ReversePayment handles reversing a payment that was verified by mistake (super admin only)
+func (h *PaymentHandler) ReversePayment(c *fiber.Ctx) error {
+	superAdminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	paymentID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid payment ID")
+	}
+
+	var req models.PaymentReversalRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	reversal, err := h.paymentService.ReversePayment(c.Context(), paymentID, superAdminID, &req)
+	if err != nil {
+		return paymentError(err, "Failed to reverse payment")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   reversal,
+	})
+}
+
+// GetPaymentRefunds handles retrieving the refunds of a payment (super admin only)
+func (h *PaymentHandler) GetPaymentRefunds(c *fiber.Ctx) error {
+	paymentID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid payment ID")
+	}
+
+	refunds, err := h.paymentService.GetPaymentRefunds(c.Context(), paymentID)
+	if err != nil {
+		return paymentError(err, "Failed to retrieve payment refunds")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   refunds,
+	})
+}
+
+// GetPaymentAuditLog handles retrieving the audit trail of a payment (super admin only)
+func (h *PaymentHandler) GetPaymentAuditLog(c *fiber.Ctx) error {
+	paymentID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid payment ID")
+	}
+
+	entries, err := h.paymentService.GetPaymentAuditLog(c.Context(), paymentID)
+	if err != nil {
+		return paymentError(err, "Failed to retrieve payment audit log")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   entries,
+	})
+}
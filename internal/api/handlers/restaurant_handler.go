@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -14,12 +15,14 @@ import (
 // RestaurantHandler handles restaurant requests
 type RestaurantHandler struct {
 	restaurantService *service.RestaurantService
+	branchService     *service.BranchService
 }
 
 // NewRestaurantHandler creates a new restaurant handler
-func NewRestaurantHandler(restaurantService *service.RestaurantService) *RestaurantHandler {
+func NewRestaurantHandler(restaurantService *service.RestaurantService, branchService *service.BranchService) *RestaurantHandler {
 	return &RestaurantHandler{
 		restaurantService: restaurantService,
+		branchService:     branchService,
 	}
 }
 
@@ -28,29 +31,15 @@ func (h *RestaurantHandler) Create(c *fiber.Ctx) error {
 	// Get admin ID from context
 	contextAdminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
 	role, _ := c.Locals(utils.ContextUserRole).(string)
 
 	var req models.RestaurantCreateRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate required fields
-	if req.Text == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Text is required",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Determine which admin ID to use
@@ -68,10 +57,7 @@ func (h *RestaurantHandler) Create(c *fiber.Ctx) error {
 	// Create or update restaurant
 	restaurant, err := h.restaurantService.Create(c.Context(), adminID, &req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to create/update restaurant",
-		})
+		return utils.NewAppError(err, "Failed to create/update restaurant", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -87,43 +73,43 @@ func (h *RestaurantHandler) GetAll(c *fiber.Ctx) error {
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
 	role, _ := c.Locals(utils.ContextUserRole).(string)
 
-	var restaurants []*models.Restaurant
-	var err error
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
 
 	// Super admin can see all restaurants, admin can only see their own
-	if role == utils.RoleSuperAdmin {
-		restaurants, err = h.restaurantService.GetAll(c.Context())
-	} else {
-		restaurants, err = h.restaurantService.GetByAdminID(c.Context(), adminID)
+	if role != utils.RoleSuperAdmin {
+		params.AdminID = &adminID
 	}
 
+	restaurants, meta, err := h.restaurantService.List(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve restaurants",
-		})
+		return listError(err, "Failed to retrieve restaurants")
+	}
+
+	// The restaurant is open while any of its branches is
+	isOpen, nextOpeningAt, err := h.branchService.OpeningStatus(c.Context(), adminID)
+	if err != nil {
+		return utils.NewAppError(err, "Failed to retrieve opening hours", fiber.StatusInternalServerError)
 	}
 
 	// Convert to response objects
-	var responses []models.RestaurantResponse
+	responses := []models.RestaurantResponse{}
 	for _, restaurant := range restaurants {
-		responses = append(responses, restaurant.ToResponse())
+		response := restaurant.ToResponse()
+		response.IsOpenNow = isOpen
+		response.NextOpeningAt = nextOpeningAt
+		responses = append(responses, response)
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
 // GetByID handles retrieving a restaurant by ID
@@ -131,35 +117,23 @@ func (h *RestaurantHandler) GetByID(c *fiber.Ctx) error {
 	// Get restaurant ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid restaurant ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid restaurant ID")
 	}
 
 	// Get restaurant
 	restaurant, err := h.restaurantService.GetByID(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Restaurant not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Restaurant not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve restaurant",
-		})
+		return utils.NewAppError(err, "Failed to retrieve restaurant", fiber.StatusInternalServerError)
 	}
 
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
@@ -167,10 +141,7 @@ func (h *RestaurantHandler) GetByID(c *fiber.Ctx) error {
 
 	// Check if the user has access to this restaurant
 	if role != utils.RoleSuperAdmin && restaurant.AdminID != adminID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Access denied",
-		})
+		return fiber.NewError(fiber.StatusForbidden, "Access denied")
 	}
 
 	// Return response
@@ -185,55 +156,37 @@ func (h *RestaurantHandler) Update(c *fiber.Ctx) error {
 	// Get restaurant ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid restaurant ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid restaurant ID")
 	}
 
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get role from context
 	role, _ := c.Locals(utils.ContextUserRole).(string)
 
 	var req models.RestaurantUpdateRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// First, get the existing restaurant to check ownership
 	existingRestaurant, err := h.restaurantService.GetByID(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Restaurant not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Restaurant not found", fiber.StatusNotFound)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve restaurant",
-		})
+		return utils.NewAppError(err, "Failed to retrieve restaurant", fiber.StatusInternalServerError)
 	}
 
 	// Check if user has permission to update this restaurant
 	// Super admins can update any restaurant
 	// Regular admins can only update their own restaurants
 	if role != utils.RoleSuperAdmin && existingRestaurant.AdminID != adminID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "You don't have permission to update this restaurant",
-		})
+		return fiber.NewError(fiber.StatusForbidden, "You don't have permission to update this restaurant")
 	}
 
 	// Allow changing adminID only for super admins
@@ -246,16 +199,10 @@ func (h *RestaurantHandler) Update(c *fiber.Ctx) error {
 	// Update restaurant
 	restaurant, err := h.restaurantService.Update(c.Context(), id, targetAdminID, &req)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Restaurant not found or access denied",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Restaurant not found or access denied", fiber.StatusNotFound)
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to update restaurant",
-		})
+		return utils.NewAppError(err, "Failed to update restaurant", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -270,35 +217,23 @@ func (h *RestaurantHandler) Delete(c *fiber.Ctx) error {
 	// Get restaurant ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid restaurant ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid restaurant ID")
 	}
 
 	// Get admin ID from context
 	adminID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Delete restaurant
 	err = h.restaurantService.Delete(c.Context(), id, adminID)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Restaurant not found or access denied",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Restaurant not found or access denied", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to delete restaurant",
-		})
+		return utils.NewAppError(err, "Failed to delete restaurant", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -313,30 +248,26 @@ func (h *RestaurantHandler) GetPublicByAdminID(c *fiber.Ctx) error {
 	// Get admin ID from URL
 	adminID, err := strconv.Atoi(c.Params("adminID"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid admin ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
 	}
+	params.AdminID = &adminID
 
 	// Get restaurants
-	restaurants, err := h.restaurantService.GetByAdminID(c.Context(), adminID)
+	restaurants, meta, err := h.restaurantService.List(c.Context(), params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve restaurants",
-		})
+		return listError(err, "Failed to retrieve restaurants")
 	}
 
 	// Convert to response objects
-	var responses []models.RestaurantResponse
+	responses := []models.RestaurantResponse{}
 	for _, restaurant := range restaurants {
 		responses = append(responses, restaurant.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"strconv"
 	"strings"
@@ -15,12 +16,17 @@ import (
 // SubscriptionTierHandler handles subscription tier requests
 type SubscriptionTierHandler struct {
 	subscriptionTierService *service.SubscriptionTierService
+	tierChangeService       *service.TierChangeService
 }
 
 // NewSubscriptionTierHandler creates a new subscription tier handler
-func NewSubscriptionTierHandler(subscriptionTierService *service.SubscriptionTierService) *SubscriptionTierHandler {
+func NewSubscriptionTierHandler(
+	subscriptionTierService *service.SubscriptionTierService,
+	tierChangeService *service.TierChangeService,
+) *SubscriptionTierHandler {
 	return &SubscriptionTierHandler{
 		subscriptionTierService: subscriptionTierService,
+		tierChangeService:       tierChangeService,
 	}
 }
 
@@ -28,28 +34,19 @@ func NewSubscriptionTierHandler(subscriptionTierService *service.SubscriptionTie
 func (h *SubscriptionTierHandler) Create(c *fiber.Ctx) error {
 	var req models.SubscriptionTierCreateRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
-	}
-
-	// Validate required fields
-	if req.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Name is required",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Create subscription tier
 	tier, err := h.subscriptionTierService.Create(c.Context(), &req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to create subscription tier: " + err.Error(),
-		})
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+
+		return utils.NewAppError(err, "Failed to create subscription tier", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -59,45 +56,33 @@ func (h *SubscriptionTierHandler) Create(c *fiber.Ctx) error {
 	})
 }
 
-// GetAll handles retrieving all subscription tiers
+// GetAll handles retrieving the subscription tiers, one page at a time
 func (h *SubscriptionTierHandler) GetAll(c *fiber.Ctx) error {
-	// Get all subscription tiers
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
 
-	tiers, err := h.subscriptionTierService.GetAll(c.Context())
+	tiers, meta, err := h.subscriptionTierService.List(c.Context(), params)
 	if err != nil {
 		// Log the detailed error for debugging
 		log.Printf("Error retrieving subscription tiers: %v", err)
 
 		// Check if it's a database connection or schema error
 		if strings.Contains(err.Error(), "does not exist") {
-			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Subscription system is being initialized. Please try again later.",
-			})
+			return fiber.NewError(fiber.StatusServiceUnavailable, "Subscription system is being initialized. Please try again later.")
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve subscription tiers",
-		})
-	}
-
-	// If no tiers exist yet, return an empty array instead of null
-	if tiers == nil {
-		tiers = []*models.SubscriptionTier{}
+		return listError(err, "Failed to retrieve subscription tiers")
 	}
 
 	// Convert to response objects
-	var responses []models.SubscriptionTierResponse
+	responses := []models.SubscriptionTierResponse{}
 	for _, tier := range tiers {
 		responses = append(responses, tier.ToResponse())
 	}
 
-	// Return response
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"status": utils.StatusSuccess,
-		"data":   responses,
-	})
+	return listResponse(c, responses, meta)
 }
 
 // GetByID handles retrieving a subscription tier by ID
@@ -105,26 +90,17 @@ func (h *SubscriptionTierHandler) GetByID(c *fiber.Ctx) error {
 	// Get subscription tier ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid subscription tier ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid subscription tier ID")
 	}
 
 	// Get subscription tier
 	tier, err := h.subscriptionTierService.GetByID(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Subscription tier not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Subscription tier not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve subscription tier",
-		})
+		return utils.NewAppError(err, "Failed to retrieve subscription tier", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -139,34 +115,27 @@ func (h *SubscriptionTierHandler) Update(c *fiber.Ctx) error {
 	// Get subscription tier ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid subscription tier ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid subscription tier ID")
 	}
 
 	var req models.SubscriptionTierUpdateRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid request body",
-		})
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
 	// Update subscription tier
 	tier, err := h.subscriptionTierService.Update(c.Context(), id, &req)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Subscription tier not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Subscription tier not found", fiber.StatusNotFound)
+		}
+
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to update subscription tier",
-		})
+		return utils.NewAppError(err, "Failed to update subscription tier", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -181,26 +150,17 @@ func (h *SubscriptionTierHandler) Delete(c *fiber.Ctx) error {
 	// Get subscription tier ID from URL
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Invalid subscription tier ID",
-		})
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid subscription tier ID")
 	}
 
 	// Delete subscription tier
 	err = h.subscriptionTierService.Delete(c.Context(), id)
 	if err != nil {
-		if err == utils.ErrResourceNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Subscription tier not found",
-			})
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Subscription tier not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to delete subscription tier",
-		})
+		return utils.NewAppError(err, "Failed to delete subscription tier", fiber.StatusInternalServerError)
 	}
 
 	// Return response
@@ -209,3 +169,88 @@ func (h *SubscriptionTierHandler) Delete(c *fiber.Ctx) error {
 		"message": "Subscription tier deleted successfully",
 	})
 }
+
+// PinAdminTier handles pinning an admin to a subscription tier (super admin only)
+func (h *SubscriptionTierHandler) PinAdminTier(c *fiber.Ctx) error {
+	// Get super admin ID from context
+	superAdminID, ok := c.Locals(utils.ContextUserID).(int)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	// Get admin ID from URL
+	adminID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+	}
+
+	var req models.SubscriptionTierPinRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	// Pin tier
+	admin, err := h.tierChangeService.PinTier(c.Context(), adminID, superAdminID, &req)
+	if err != nil {
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Admin not found", fiber.StatusNotFound)
+		}
+
+		if errors.Is(err, utils.ErrResourceNotFound) {
+			return utils.NewAppError(err, "Subscription tier not found", fiber.StatusNotFound)
+		}
+
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			return appErr
+		}
+
+		return utils.NewAppError(err, "Failed to update subscription tier", fiber.StatusInternalServerError)
+	}
+
+	// Return response
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": utils.StatusSuccess,
+		"data":   admin.ToResponse(),
+	})
+}
+
+// GetTierChanges handles retrieving the tier change log of an admin, one page at a time.
+// Super admins pass the admin ID in the URL, admins see their own log.
+func (h *SubscriptionTierHandler) GetTierChanges(c *fiber.Ctx) error {
+	role, _ := c.Locals(utils.ContextUserRole).(string)
+
+	var adminID int
+	if role == utils.RoleSuperAdmin && c.Params("id") != "" {
+		id, err := strconv.Atoi(c.Params("id"))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid admin ID")
+		}
+		adminID = id
+	} else {
+		id, ok := c.Locals(utils.ContextUserID).(int)
+		if !ok {
+			return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+		}
+		adminID = id
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		return listError(err, "Invalid list parameters")
+	}
+
+	// Get tier changes
+	changes, meta, err := h.tierChangeService.GetChangesByAdminID(c.Context(), adminID, params)
+	if err != nil {
+		return listError(err, "Failed to retrieve subscription tier changes")
+	}
+
+	// Convert to response objects
+	responses := []models.SubscriptionTierChangeResponse{}
+	for _, change := range changes {
+		responses = append(responses, change.ToResponse())
+	}
+
+	return listResponse(c, responses, meta)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	"mobilka/internal/service"
 	"mobilka/internal/utils"
 
@@ -24,26 +26,17 @@ func (h *SuperAdminHandler) GetProfile(c *fiber.Ctx) error {
 	// Get super admin ID from context
 	userID, ok := c.Locals(utils.ContextUserID).(int)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Unauthorized",
-		})
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 	}
 
 	// Get super admin profile
 	superAdmin, err := h.superAdminService.GetByID(c.Context(), userID)
 	if err != nil {
-		if err == utils.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Super admin not found",
-			})
+		if errors.Is(err, utils.ErrUserNotFound) {
+			return utils.NewAppError(err, "Super admin not found", fiber.StatusNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Failed to retrieve profile",
-		})
+		return utils.NewAppError(err, "Failed to retrieve profile", fiber.StatusInternalServerError)
 	}
 
 	// Return response
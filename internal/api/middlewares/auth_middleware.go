@@ -8,58 +8,99 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-// Protected middleware ensures that the request is authenticated with a valid JWT
+// Protected middleware ensures that the request is authenticated with a valid admin or
+// super admin JWT
 func Protected() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Get the Authorization header
-		authHeader := c.Get("Authorization")
-
-		// Check if the Authorization header is empty
-		if authHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Unauthorized: Missing authorization header",
-			})
+		claims, err := bearerClaims(c)
+		if err != nil {
+			return err
 		}
 
-		// Extract the token from the Authorization header
-		// Format: Bearer <token>
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Unauthorized: Invalid authorization format",
-			})
+		// Customer tokens only open the customer's own account routes
+		if claims.Role == utils.RoleCustomer {
+			return fiber.NewError(fiber.StatusForbidden, "Forbidden: Admin access required")
 		}
 
-		// Parse and validate the token
-		claims, err := utils.ParseToken(parts[1])
+		// Store user ID and role in context for use in handlers
+		c.Locals(utils.ContextUserID, claims.ID)
+		c.Locals(utils.ContextUserRole, claims.Role)
+
+		// Continue to the next middleware or handler
+		return c.Next()
+	}
+}
+
+// CustomerProtected middleware ensures that the request is authenticated with a valid
+// customer JWT
+func CustomerProtected() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, err := bearerClaims(c)
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Unauthorized: " + err.Error(),
-			})
+			return err
 		}
 
-		// Store user ID and role in context for use in handlers
+		if claims.Role != utils.RoleCustomer {
+			return fiber.NewError(fiber.StatusForbidden, "Forbidden: Customer access required")
+		}
+
+		// Store the customer ID, role and the admin the customer belongs to in context
 		c.Locals(utils.ContextUserID, claims.ID)
 		c.Locals(utils.ContextUserRole, claims.Role)
+		c.Locals(utils.ContextAdminID, claims.AdminID)
 
 		// Continue to the next middleware or handler
 		return c.Next()
 	}
 }
 
+// bearerClaims parses and validates the JWT in the Authorization header
+func bearerClaims(c *fiber.Ctx) (*utils.Claims, error) {
+	// Get the Authorization header
+	authHeader := c.Get("Authorization")
+
+	// Check if the Authorization header is empty
+	if authHeader == "" {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Unauthorized: Missing authorization header")
+	}
+
+	// Extract the token from the Authorization header
+	// Format: Bearer <token>
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Unauthorized: Invalid authorization format")
+	}
+
+	// Parse and validate the token
+	claims, err := utils.ParseToken(parts[1])
+	if err != nil {
+		return nil, utils.NewAppError(utils.ErrInvalidToken, "Unauthorized: "+err.Error(), fiber.StatusUnauthorized)
+	}
+
+	return claims, nil
+}
+
+// TokenFromQuery middleware accepts the JWT in the access_token query parameter for
+// clients that can't set headers, such as the browser EventSource. Use it before Protected.
+func TokenFromQuery() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request().Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+
+		return c.Next()
+	}
+}
+
 // AdminOnly middleware ensures that the request is from an admin
 func AdminOnly() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Get the user role from context (set by Protected middleware)
 		role, ok := c.Locals(utils.ContextUserRole).(string)
 		if !ok || role != utils.RoleAdmin {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Forbidden: Admin access required",
-			})
+			return fiber.NewError(fiber.StatusForbidden, "Forbidden: Admin access required")
 		}
 
 		// Continue to the next middleware or handler
@@ -73,10 +114,7 @@ func SuperAdminOnly() fiber.Handler {
 		// Get the user role from context (set by Protected middleware)
 		role, ok := c.Locals(utils.ContextUserRole).(string)
 		if !ok || role != utils.RoleSuperAdmin {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Forbidden: Super admin access required",
-			})
+			return fiber.NewError(fiber.StatusForbidden, "Forbidden: Super admin access required")
 		}
 
 		// Continue to the next middleware or handler
@@ -95,3 +133,9 @@ func GetUserRole(c *fiber.Ctx) (string, bool) {
 	role, ok := c.Locals(utils.ContextUserRole).(string)
 	return role, ok
 }
+
+// GetAdminID gets the ID of the admin a customer belongs to from the context
+func GetAdminID(c *fiber.Ctx) (int, bool) {
+	id, ok := c.Locals(utils.ContextAdminID).(int)
+	return id, ok
+}
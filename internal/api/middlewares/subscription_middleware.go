@@ -25,28 +25,23 @@ func SubscriptionChecker(paymentService *service.PaymentService) fiber.Handler {
 		// Check if user is authenticated
 		adminID, ok := c.Locals(utils.ContextUserID).(int)
 		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Unauthorized",
-			})
+			return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
 		}
 
 		// Check subscription access
 		hasAccess, err := paymentService.CheckAdminAccess(c.Context(), adminID)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Failed to check subscription status",
-			})
+			return utils.NewAppError(err, "Failed to check subscription status", fiber.StatusInternalServerError)
 		}
 
 		// If access is restricted, return subscription needed error
 		if !hasAccess {
-			return c.Status(fiber.StatusPaymentRequired).JSON(fiber.Map{
-				"status":  utils.StatusError,
-				"message": "Subscription payment required. Please make a payment to continue using the service.",
-				"code":    "SUBSCRIPTION_REQUIRED",
-			})
+			return &utils.AppError{
+				Err:       utils.ErrForbidden,
+				Message:   "Subscription payment required. Please make a payment to continue using the service.",
+				Code:      fiber.StatusPaymentRequired,
+				ErrorCode: utils.CodeSubscriptionRequired,
+			}
 		}
 
 		// Continue to the next middleware or handler
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupAccountRoutes sets up the self-service routes of logged-in customers, for customer
+// tokens only
+func SetupAccountRoutes(api fiber.Router, customerHandler *handlers.CustomerHandler, orderHandler *handlers.OrderHandler,
+	loyaltyHandler *handlers.LoyaltyHandler, fcmTokenHandler *handlers.FCMTokenHandler) {
+	accountRoutes := api.Group("/account")
+	accountRoutes.Use(middlewares.CustomerProtected())
+	accountRoutes.Get("/profile", customerHandler.GetProfile)
+	accountRoutes.Put("/profile", customerHandler.UpdateProfile)
+	accountRoutes.Get("/addresses", customerHandler.GetAddresses)
+	accountRoutes.Post("/addresses", customerHandler.CreateAddress)
+	accountRoutes.Put("/addresses/:id", customerHandler.UpdateAddress)
+	accountRoutes.Delete("/addresses/:id", customerHandler.DeleteAddress)
+
+	accountRoutes.Post("/orders", orderHandler.CreateForCustomer)
+
+	accountRoutes.Get("/loyalty", loyaltyHandler.GetBalance)
+	accountRoutes.Get("/loyalty/transactions", loyaltyHandler.GetMyTransactions)
+
+	accountRoutes.Post("/fcm-tokens", fcmTokenHandler.CreateForCustomer)
+	accountRoutes.Post("/fcm-tokens/delete-by-token", fcmTokenHandler.DeleteForCustomer)
+}
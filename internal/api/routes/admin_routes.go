@@ -19,10 +19,12 @@ func SetupAdminRoutes(api fiber.Router, adminHandler *handlers.AdminHandler) {
 	adminRoutes.Get("/:id", adminHandler.GetByID)
 	adminRoutes.Put("/:id", adminHandler.Update)
 	adminRoutes.Delete("/:id", adminHandler.Delete)
+	adminRoutes.Get("/:id/usage", adminHandler.GetUsage)
 
 	// Admin profile route for regular admins
 	adminProfileRoutes := api.Group("/admin")
 	adminProfileRoutes.Use(middlewares.Protected(), middlewares.AdminOnly())
 	adminProfileRoutes.Get("/profile", adminHandler.GetProfile)
 	adminProfileRoutes.Put("/change-delivery", adminHandler.ChangeDelivery)
+	adminProfileRoutes.Get("/usage", adminHandler.GetUsage)
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupAnalyticsRoutes sets up all routes related to revenue and subscription reporting
+func SetupAnalyticsRoutes(api fiber.Router, analyticsHandler *handlers.AnalyticsHandler) {
+	// Analytics routes - super admin only
+	analyticsRoutes := api.Group("/superadmin/analytics")
+	analyticsRoutes.Use(middlewares.Protected(), middlewares.SuperAdminOnly())
+	analyticsRoutes.Get("/summary", analyticsHandler.GetSummary)
+	analyticsRoutes.Get("/mrr", analyticsHandler.GetMRR)
+	analyticsRoutes.Get("/admins", analyticsHandler.GetAdminMovements)
+	analyticsRoutes.Get("/revenue", analyticsHandler.GetRevenue)
+	analyticsRoutes.Get("/payment-methods", analyticsHandler.GetPaymentMethods)
+	analyticsRoutes.Get("/verification-latency", analyticsHandler.GetVerificationLatency)
+	analyticsRoutes.Get("/at-risk", analyticsHandler.GetAtRiskAdmins)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupBranchRoutes sets up all routes related to restaurant branches
+func SetupBranchRoutes(api fiber.Router, branchHandler *handlers.BranchHandler) {
+	// Branch routes
+	branchRoutes := api.Group("/branches")
+	branchRoutes.Use(middlewares.Protected())
+	branchRoutes.Post("/", branchHandler.Create)
+	branchRoutes.Get("/", branchHandler.GetAll)
+	branchRoutes.Get("/:id", branchHandler.GetByID)
+	branchRoutes.Put("/:id", branchHandler.Update)
+	branchRoutes.Delete("/:id", branchHandler.Delete)
+}
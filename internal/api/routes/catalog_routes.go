@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupCatalogRoutes sets up all routes related to the menu catalog
+func SetupCatalogRoutes(api fiber.Router, catalogHandler *handlers.CatalogHandler) {
+	catalogRoutes := api.Group("/catalog")
+	catalogRoutes.Use(middlewares.Protected())
+
+	// Category routes
+	catalogRoutes.Post("/categories", catalogHandler.CreateCategory)
+	catalogRoutes.Get("/categories", catalogHandler.GetCategories)
+	catalogRoutes.Get("/categories/:id", catalogHandler.GetCategory)
+	catalogRoutes.Put("/categories/:id", catalogHandler.UpdateCategory)
+	catalogRoutes.Delete("/categories/:id", catalogHandler.DeleteCategory)
+
+	// Product routes
+	catalogRoutes.Post("/products", catalogHandler.CreateProduct)
+	catalogRoutes.Get("/products", catalogHandler.GetProducts)
+	catalogRoutes.Get("/products/:id", catalogHandler.GetProduct)
+	catalogRoutes.Put("/products/:id", catalogHandler.UpdateProduct)
+	catalogRoutes.Delete("/products/:id", catalogHandler.DeleteProduct)
+
+	// Modifier group routes
+	catalogRoutes.Post("/modifier-groups", catalogHandler.CreateModifierGroup)
+	catalogRoutes.Get("/modifier-groups", catalogHandler.GetModifierGroups)
+	catalogRoutes.Get("/modifier-groups/:id", catalogHandler.GetModifierGroup)
+	catalogRoutes.Put("/modifier-groups/:id", catalogHandler.UpdateModifierGroup)
+	catalogRoutes.Delete("/modifier-groups/:id", catalogHandler.DeleteModifierGroup)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupCouponRoutes sets up all routes related to subscription coupons
+func SetupCouponRoutes(api fiber.Router, couponHandler *handlers.CouponHandler) {
+	// Coupon management routes - super admin only
+	couponRoutes := api.Group("/coupons")
+	couponRoutes.Use(middlewares.Protected(), middlewares.SuperAdminOnly())
+	couponRoutes.Post("/", couponHandler.Create)
+	couponRoutes.Get("/", couponHandler.GetAll)
+	couponRoutes.Get("/report", couponHandler.GetReport)
+	couponRoutes.Get("/:id", couponHandler.GetByID)
+	couponRoutes.Put("/:id", couponHandler.Update)
+	couponRoutes.Delete("/:id", couponHandler.Delete)
+	couponRoutes.Get("/:id/redemptions", couponHandler.GetRedemptions)
+
+	// Admin coupon routes
+	adminCouponRoutes := api.Group("/payments/coupon")
+	adminCouponRoutes.Use(middlewares.Protected(), middlewares.AdminOnly())
+	adminCouponRoutes.Post("/", couponHandler.ApplyCoupon)
+	adminCouponRoutes.Get("/", couponHandler.GetActiveCoupon)
+}
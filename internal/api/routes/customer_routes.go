@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupCustomerRoutes sets up all routes related to customers
+func SetupCustomerRoutes(api fiber.Router, customerHandler *handlers.CustomerHandler) {
+	// Admin customer routes
+	customerRoutes := api.Group("/customers")
+	customerRoutes.Use(middlewares.Protected())
+	customerRoutes.Get("/", customerHandler.GetAll)
+	customerRoutes.Get("/:id", customerHandler.GetByID)
+}
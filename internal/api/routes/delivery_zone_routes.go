@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupDeliveryZoneRoutes sets up all routes related to delivery zones
+func SetupDeliveryZoneRoutes(api fiber.Router, zoneHandler *handlers.DeliveryZoneHandler) {
+	// Delivery zone routes
+	zoneRoutes := api.Group("/delivery-zones")
+	zoneRoutes.Use(middlewares.Protected())
+	zoneRoutes.Post("/", zoneHandler.Create)
+	zoneRoutes.Get("/", zoneHandler.GetAll)
+	zoneRoutes.Get("/:id", zoneHandler.GetByID)
+	zoneRoutes.Put("/:id", zoneHandler.Update)
+	zoneRoutes.Delete("/:id", zoneHandler.Delete)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupEventRoutes sets up the admin panel event stream
+func SetupEventRoutes(api fiber.Router, eventHandler *handlers.EventHandler) {
+	// Event routes
+	api.Get("/events", middlewares.TokenFromQuery(), middlewares.Protected(), eventHandler.Stream)
+}
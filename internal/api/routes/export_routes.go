@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupExportRoutes sets up all routes related to spreadsheet exports
+func SetupExportRoutes(api fiber.Router, exportHandler *handlers.ExportHandler) {
+	// Export routes - super admin only
+	exportRoutes := api.Group("/superadmin/exports")
+	exportRoutes.Use(middlewares.Protected(), middlewares.SuperAdminOnly())
+	exportRoutes.Get("/payments", exportHandler.ExportPayments)
+	exportRoutes.Get("/admins", exportHandler.ExportAdmins)
+	exportRoutes.Get("/invoices", exportHandler.ExportInvoices)
+}
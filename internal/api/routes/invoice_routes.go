@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupInvoiceRoutes sets up all routes related to invoices
+func SetupInvoiceRoutes(api fiber.Router, invoiceHandler *handlers.InvoiceHandler) {
+	// Invoice routes - admins see their own, super admins every admin's
+	invoiceRoutes := api.Group("/invoices")
+	invoiceRoutes.Use(middlewares.Protected())
+	invoiceRoutes.Get("/", invoiceHandler.GetAll)
+	invoiceRoutes.Get("/:id", invoiceHandler.GetByID)
+}
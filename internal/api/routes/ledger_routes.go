@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupLedgerRoutes sets up all routes related to admin balances and statements
+func SetupLedgerRoutes(api fiber.Router, ledgerHandler *handlers.LedgerHandler) {
+	// Admin ledger routes
+	adminLedgerRoutes := api.Group("/ledger")
+	adminLedgerRoutes.Use(middlewares.Protected(), middlewares.AdminOnly())
+	adminLedgerRoutes.Get("/balance", ledgerHandler.GetBalances)
+	adminLedgerRoutes.Get("/statement", ledgerHandler.GetStatement)
+
+	// Super admin ledger routes
+	superadminLedgerRoutes := api.Group("/superadmin/ledger")
+	superadminLedgerRoutes.Use(middlewares.Protected(), middlewares.SuperAdminOnly())
+	superadminLedgerRoutes.Get("/:adminId/balance", ledgerHandler.GetAdminBalances)
+	superadminLedgerRoutes.Get("/:adminId/statement", ledgerHandler.GetAdminStatement)
+	superadminLedgerRoutes.Post("/:adminId/credit", ledgerHandler.Credit)
+	superadminLedgerRoutes.Post("/:adminId/adjustment", ledgerHandler.Adjust)
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupLoyaltyRoutes sets up all routes related to the loyalty program
+func SetupLoyaltyRoutes(api fiber.Router, loyaltyHandler *handlers.LoyaltyHandler) {
+	loyaltyRoutes := api.Group("/loyalty")
+	loyaltyRoutes.Use(middlewares.Protected())
+	loyaltyRoutes.Get("/program", loyaltyHandler.GetProgram)
+	loyaltyRoutes.Put("/program", loyaltyHandler.SaveProgram)
+	loyaltyRoutes.Get("/transactions", loyaltyHandler.GetTransactions)
+	loyaltyRoutes.Get("/customers/:id", loyaltyHandler.GetCustomerBalance)
+}
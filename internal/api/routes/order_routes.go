@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"mobilka/internal/api/handlers"
+	"mobilka/internal/api/middlewares"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupOrderRoutes sets up all routes related to orders
+func SetupOrderRoutes(api fiber.Router, orderHandler *handlers.OrderHandler) {
+	// Order routes
+	orderRoutes := api.Group("/orders")
+	orderRoutes.Use(middlewares.Protected())
+	orderRoutes.Get("/", orderHandler.GetAll)
+	orderRoutes.Get("/:id", orderHandler.GetByID)
+	orderRoutes.Put("/:id/status", orderHandler.UpdateStatus)
+	orderRoutes.Post("/telegram/webhook", orderHandler.RegisterTelegramWebhook)
+}
@@ -17,10 +17,13 @@ func SetupSubscriptionTierRoutes(api fiber.Router, subscriptionTierHandler *hand
 	subscriptionTierRoutes.Get("/:id", subscriptionTierHandler.GetByID)
 	subscriptionTierRoutes.Put("/:id", subscriptionTierHandler.Update)
 	subscriptionTierRoutes.Delete("/:id", subscriptionTierHandler.Delete)
+	subscriptionTierRoutes.Put("/admins/:id/pin", subscriptionTierHandler.PinAdminTier)
+	subscriptionTierRoutes.Get("/admins/:id/changes", subscriptionTierHandler.GetTierChanges)
 }
 
 // SetupPaymentRoutes sets up all routes related to payment operations
-func SetupPaymentRoutes(api fiber.Router, paymentHandler *handlers.PaymentHandler, subscriptionTierHandler *handlers.SubscriptionTierHandler) {
+func SetupPaymentRoutes(api fiber.Router, paymentHandler *handlers.PaymentHandler, subscriptionTierHandler *handlers.SubscriptionTierHandler,
+	exchangeRateHandler *handlers.ExchangeRateHandler, paymentAttachmentHandler *handlers.PaymentAttachmentHandler) {
 	// Public subscription tier routes (for admins to see available tiers)
 	api.Get("/public/subscription-tiers", subscriptionTierHandler.GetAll)
 
@@ -30,13 +33,35 @@ func SetupPaymentRoutes(api fiber.Router, paymentHandler *handlers.PaymentHandle
 	adminPaymentRoutes.Post("/", paymentHandler.RecordPayment)
 	adminPaymentRoutes.Get("/", paymentHandler.GetAdminPayments)
 	adminPaymentRoutes.Get("/subscription", paymentHandler.GetSubscriptionInfo)
+	adminPaymentRoutes.Get("/tier-change-quote", paymentHandler.QuoteTierChange)
+	adminPaymentRoutes.Put("/billing-interval", paymentHandler.SetBillingInterval)
+	adminPaymentRoutes.Post("/renew-from-balance", paymentHandler.RenewFromBalance)
+	adminPaymentRoutes.Get("/tier-changes", subscriptionTierHandler.GetTierChanges)
+	adminPaymentRoutes.Get("/:id<int>", paymentHandler.GetPaymentByID)
+	adminPaymentRoutes.Post("/:id<int>/attachments", paymentAttachmentHandler.Upload)
+	adminPaymentRoutes.Delete("/attachments/:id<int>", paymentAttachmentHandler.Delete)
 
 	// Super admin payment routes
 	superadminPaymentRoutes := api.Group("/superadmin/payments")
 	superadminPaymentRoutes.Use(middlewares.Protected(), middlewares.SuperAdminOnly())
 	superadminPaymentRoutes.Get("/", paymentHandler.GetAllPayments)
 	superadminPaymentRoutes.Get("/pending", paymentHandler.GetPendingPayments)
+	superadminPaymentRoutes.Get("/flagged", paymentHandler.GetFlaggedPayments)
 	superadminPaymentRoutes.Get("/:id", paymentHandler.GetPaymentByID)
 	superadminPaymentRoutes.Post("/:id/verify", paymentHandler.VerifyPayment)
+	superadminPaymentRoutes.Post("/:id/refund", paymentHandler.RefundPayment)
+	superadminPaymentRoutes.Post("/:id/reverse", paymentHandler.ReversePayment)
+	superadminPaymentRoutes.Get("/:id/refunds", paymentHandler.GetPaymentRefunds)
+	superadminPaymentRoutes.Get("/:id/audit", paymentHandler.GetPaymentAuditLog)
 	superadminPaymentRoutes.Get("/admin/:id/subscription", paymentHandler.GetSubscriptionInfo)
+
+	// Payment attachment download - the paying admin and super admins
+	api.Get("/payment-attachments/:id", middlewares.Protected(), paymentAttachmentHandler.Download)
+
+	// Exchange rate routes - super admin only
+	exchangeRateRoutes := api.Group("/superadmin/exchange-rates")
+	exchangeRateRoutes.Use(middlewares.Protected(), middlewares.SuperAdminOnly())
+	exchangeRateRoutes.Get("/", exchangeRateHandler.GetAll)
+	exchangeRateRoutes.Post("/", exchangeRateHandler.SetRate)
+	exchangeRateRoutes.Delete("/:id", exchangeRateHandler.Delete)
 }
@@ -13,13 +13,17 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // SetupRoutes sets up all the routes for the application
-func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config) {
+func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config, eventService *service.EventService) {
 	// Apply global middlewares
-	app.Use(logger.New())
+	app.Use(requestid.New(requestid.Config{ContextKey: utils.ContextRequestID}))
+	app.Use(logger.New(logger.Config{
+		Format: "${time} | ${locals:" + utils.ContextRequestID + "} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${error}\n",
+	}))
 	app.Use(recover.New())
 	app.Use(cors.New())
 
@@ -30,35 +34,84 @@ func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config) {
 	notificationRepo := repository.NewNotificationRepository(db)
 	fcmTokenRepo := repository.NewFCMTokenRepository(db)
 	restaurantRepo := repository.NewRestaurantRepository(db) // Add new repository
+	branchRepo := repository.NewBranchRepository(db)
+	catalogRepo := repository.NewCatalogRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	deliveryZoneRepo := repository.NewDeliveryZoneRepository(db)
+	customerRepo := repository.NewCustomerRepository(db)
+	loyaltyRepo := repository.NewLoyaltyRepository(db)
 
 	subscriptionTierRepo := repository.NewSubscriptionTierRepository(db)
 	paymentRepo := repository.NewPaymentHistoryRepository(db)
+	tierChangeRepo := repository.NewSubscriptionTierChangeRepository(db)
+	usageRepo := repository.NewUsageRepository(db)
+	trialRepo := repository.NewSubscriptionTrialRepository(db)
+	couponRepo := repository.NewCouponRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	paymentRefundRepo := repository.NewPaymentRefundRepository(db)
+	paymentAttachmentRepo := repository.NewPaymentAttachmentRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	ledgerRepo := repository.NewLedgerRepository(db)
+	invoiceRepo := repository.NewInvoiceRepository(db)
 
 	// Create services
 	authService := service.NewAuthService(superAdminRepo, adminRepo)
 	superAdminService := service.NewSuperAdminService(superAdminRepo)
-	adminService := service.NewAdminService(adminRepo)
+	adminService := service.NewAdminService(adminRepo, usageRepo, subscriptionTierRepo, trialRepo)
+	usageService := service.NewUsageService(usageRepo, adminRepo)
 	bannerService := service.NewBannerService(bannerRepo)
-	notificationService := service.NewNotificationService(notificationRepo, fcmTokenRepo)
+	notificationService := service.NewNotificationService(notificationRepo, fcmTokenRepo, eventService)
 	fcmTokenService := service.NewFCMTokenService(fcmTokenRepo)
 	imageService := service.NewImageService(cfg.ImageUploadPath)
 	restaurantService := service.NewRestaurantService(restaurantRepo) // Add new service
+	telegramService := service.NewTelegramService()
+	branchService := service.NewBranchService(branchRepo)
+	catalogService := service.NewCatalogService(catalogRepo, imageService)
+	deliveryZoneService := service.NewDeliveryZoneService(deliveryZoneRepo, branchRepo, adminRepo)
+	pushService := service.NewPushService(cfg.FCMCredentialsFile, fcmTokenRepo)
+	loyaltyService := service.NewLoyaltyService(loyaltyRepo, pushService)
+	orderService := service.NewOrderService(orderRepo, catalogRepo, branchRepo, adminRepo, deliveryZoneService, loyaltyService, eventService, telegramService, cfg.PublicURL)
+	smsService := service.NewSmsService(adminRepo)
+	customerService := service.NewCustomerService(customerRepo, adminRepo, smsService)
 
 	subscriptionTierService := service.NewSubscriptionTierService(subscriptionTierRepo)
-	paymentService := service.NewPaymentService(paymentRepo, adminRepo, subscriptionTierRepo)
+	paymentService := service.NewPaymentService(paymentRepo, adminRepo, subscriptionTierRepo, couponRepo, exchangeRateRepo, paymentRefundRepo, ledgerRepo, eventService)
+	ledgerService := service.NewLedgerService(ledgerRepo, adminRepo)
+	invoiceService := service.NewInvoiceService(invoiceRepo)
+	couponService := service.NewCouponService(couponRepo, adminRepo)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo)
+	paymentProofService := service.NewImageService(cfg.PaymentProofUploadPath)
+	paymentAttachmentService := service.NewPaymentAttachmentService(paymentAttachmentRepo, paymentRepo, paymentProofService)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, adminRepo)
+	exportService := service.NewExportService(paymentRepo, adminRepo, invoiceRepo)
+	tierChangeService := service.NewTierChangeService(adminRepo, subscriptionTierRepo, tierChangeRepo, telegramService)
 
 	// Create handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	superAdminHandler := handlers.NewSuperAdminHandler(superAdminService)
-	adminHandler := handlers.NewAdminHandler(adminService)
+	adminHandler := handlers.NewAdminHandler(adminService, usageService)
 	bannerHandler := handlers.NewBannerHandler(bannerService)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
 	fcmTokenHandler := handlers.NewFCMTokenHandler(fcmTokenService)
 	imageHandler := handlers.NewImageHandler(imageService)
-	restaurantHandler := handlers.NewRestaurantHandler(restaurantService) // Add new handler
-
-	subscriptionTierHandler := handlers.NewSubscriptionTierHandler(subscriptionTierService)
-	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	restaurantHandler := handlers.NewRestaurantHandler(restaurantService, branchService) // Add new handler
+	branchHandler := handlers.NewBranchHandler(branchService)
+	catalogHandler := handlers.NewCatalogHandler(catalogService)
+	deliveryZoneHandler := handlers.NewDeliveryZoneHandler(deliveryZoneService)
+	orderHandler := handlers.NewOrderHandler(orderService)
+	eventHandler := handlers.NewEventHandler(eventService)
+	customerHandler := handlers.NewCustomerHandler(customerService)
+	loyaltyHandler := handlers.NewLoyaltyHandler(loyaltyService, customerService)
+
+	subscriptionTierHandler := handlers.NewSubscriptionTierHandler(subscriptionTierService, tierChangeService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService, paymentAttachmentService)
+	paymentAttachmentHandler := handlers.NewPaymentAttachmentHandler(paymentAttachmentService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceService)
+	couponHandler := handlers.NewCouponHandler(couponService)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService)
 
 	// Setup API routes
 	api := app.Group("/api")
@@ -72,27 +125,39 @@ func SetupRoutes(app *fiber.App, db *pgxpool.Pool, cfg *config.Config) {
 	SetupFCMTokenRoutes(api, fcmTokenHandler)
 	SetupImageRoutes(app, api, imageHandler)
 	SetupRestaurantRoutes(api, restaurantHandler) // Add new routes
+	SetupBranchRoutes(api, branchHandler)
+	SetupCatalogRoutes(api, catalogHandler)
+	SetupDeliveryZoneRoutes(api, deliveryZoneHandler)
+	SetupOrderRoutes(api, orderHandler)
+	SetupEventRoutes(api, eventHandler)
+	SetupCustomerRoutes(api, customerHandler)
+	SetupAccountRoutes(api, customerHandler, orderHandler, loyaltyHandler, fcmTokenHandler)
+	SetupLoyaltyRoutes(api, loyaltyHandler)
 
 	// Setup public routes
 	publicRoutes := api.Group("/public")
-	SetupPublicRoutes(publicRoutes, bannerHandler, notificationHandler, restaurantHandler) // Update public routes
+	SetupPublicRoutes(publicRoutes, bannerHandler, notificationHandler, restaurantHandler, branchHandler, catalogHandler, deliveryZoneHandler, orderHandler, customerHandler) // Update public routes
 
 	SetupSubscriptionTierRoutes(api, subscriptionTierHandler)
-	SetupPaymentRoutes(api, paymentHandler, subscriptionTierHandler)
+	SetupPaymentRoutes(api, paymentHandler, subscriptionTierHandler, exchangeRateHandler, paymentAttachmentHandler)
+	SetupCouponRoutes(api, couponHandler)
+	SetupAnalyticsRoutes(api, analyticsHandler)
+	SetupExportRoutes(api, exportHandler)
+	SetupLedgerRoutes(api, ledgerHandler)
+	SetupInvoiceRoutes(api, invoiceHandler)
 
 	// Setup 404 handler
 	app.Use(func(c *fiber.Ctx) error {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"status":  utils.StatusError,
-			"message": "Resource not found",
-		})
+		return fiber.NewError(fiber.StatusNotFound, "Resource not found")
 	})
 }
 
 // SetupPublicRoutes sets up all the public routes
 func SetupPublicRoutes(publicRoutes fiber.Router, bannerHandler *handlers.BannerHandler,
 	notificationHandler *handlers.NotificationHandler,
-	restaurantHandler *handlers.RestaurantHandler) {
+	restaurantHandler *handlers.RestaurantHandler, branchHandler *handlers.BranchHandler,
+	catalogHandler *handlers.CatalogHandler, deliveryZoneHandler *handlers.DeliveryZoneHandler,
+	orderHandler *handlers.OrderHandler, customerHandler *handlers.CustomerHandler) {
 
 	// Banner routes
 	publicRoutes.Get("/banners/admin/:adminID", bannerHandler.GetPublicByAdminID)
@@ -102,4 +167,26 @@ func SetupPublicRoutes(publicRoutes fiber.Router, bannerHandler *handlers.Banner
 
 	// Restaurant routes
 	publicRoutes.Get("/restaurants/admin/:adminID", restaurantHandler.GetPublicByAdminID)
+
+	// Branch routes
+	publicRoutes.Get("/branches/admin/:adminID", branchHandler.GetPublicByAdminID)
+	publicRoutes.Get("/branches/admin/:adminID/nearby", branchHandler.GetPublicNearby)
+	publicRoutes.Get("/branches/:id", branchHandler.GetPublicByID)
+
+	// Catalog routes
+	publicRoutes.Get("/catalog/admin/:adminID", catalogHandler.GetPublicByAdminID)
+
+	// Delivery zone routes
+	publicRoutes.Get("/delivery-zones/admin/:adminID", deliveryZoneHandler.GetPublicByAdminID)
+	publicRoutes.Get("/delivery-zones/admin/:adminID/quote", deliveryZoneHandler.GetPublicQuote)
+
+	// Order routes
+	publicRoutes.Post("/orders/admin/:adminID", orderHandler.CreatePublic)
+	publicRoutes.Get("/orders/track/:token", orderHandler.Track)
+	publicRoutes.Post("/orders/track/:token/cancel", orderHandler.CancelPublic)
+	publicRoutes.Post("/telegram/orders/:adminID", orderHandler.TelegramWebhook)
+
+	// Customer login routes
+	publicRoutes.Post("/customers/admin/:adminID/otp", customerHandler.RequestCode)
+	publicRoutes.Post("/customers/admin/:adminID/otp/verify", customerHandler.VerifyCode)
 }
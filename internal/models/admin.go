@@ -22,12 +22,17 @@ type Admin struct {
 	PaymentPassword        string     `json:"payment_password"`
 	BotToken               string     `json:"bot_token"`
 	BotChatID              string     `json:"bot_chat_id"`
-	Delivery               int        `json:"delivery"`
+	Delivery               int        `json:"delivery"` // 0 turns delivery off, delivery zones set where and at what cost
 	Users                  int        `json:"users"`
 	SubscriptionTierID     *int       `json:"subscription_tier_id"`
 	SubscriptionStatus     string     `json:"subscription_status"`
 	SubscriptionExpiresAt  *time.Time `json:"subscription_expires_at"`
 	IsAccessRestricted     bool       `json:"is_access_restricted"`
+	PendingTierID          *int       `json:"pending_subscription_tier_id"`
+	PendingTierEffectiveAt *time.Time `json:"pending_tier_effective_at"`
+	SubscriptionTierPinned bool       `json:"subscription_tier_pinned"`
+	BillingCurrency        string     `json:"billing_currency"`
+	BillingInterval        string     `json:"billing_interval"`
 	CreatedAt              time.Time  `json:"created_at"`
 	UpdatedAt              time.Time  `json:"updated_at"`
 }
@@ -38,7 +43,7 @@ type AdminCreateRequest struct {
 	Email              string `json:"email" validate:"required,email"`
 	CompanyName        string `json:"company_name" validate:"required"`
 	Delivery           int    `json:"delivery"`
-	SystemID           string `json:"system_id"`
+	SystemID           string `json:"system_id" validate:"required"`
 	SystemToken        string `json:"system_token"`
 	SmsToken           string `json:"sms_token"`
 	SmsEmail           string `json:"sms_email"`
@@ -49,6 +54,7 @@ type AdminCreateRequest struct {
 	BotToken           string `json:"bot_token"`
 	BotChatID          string `json:"bot_chat_id"`
 	SubscriptionTierID *int   `json:"subscription_tier_id"`
+	BillingCurrency    string `json:"billing_currency" validate:"omitempty,len=3"`
 }
 
 // AdminUpdateRequest represents the update request for an admin
@@ -69,6 +75,12 @@ type AdminUpdateRequest struct {
 	BotChatID          string `json:"bot_chat_id"`
 	AdminID            int    `json:"admin_id,omitempty"`
 	SubscriptionTierID *int   `json:"subscription_tier_id"`
+	BillingCurrency    string `json:"billing_currency" validate:"omitempty,len=3"`
+}
+
+// BillingIntervalRequest represents an admin's request to change their billing interval
+type BillingIntervalRequest struct {
+	BillingInterval string `json:"billing_interval" validate:"required,oneof=monthly quarterly yearly"`
 }
 
 // AdminLoginRequest represents the login request for admin
@@ -103,7 +115,12 @@ type AdminResponse struct {
 	SubscriptionStatus     string     `json:"subscription_status"`
 	SubscriptionExpiresAt  *time.Time `json:"subscription_expires_at"`
 	IsAccessRestricted     bool       `json:"is_access_restricted"`
-	MonthlySubscriptionFee float64    `json:"monthly_subscription_fee"`
+	PendingTierID          *int       `json:"pending_subscription_tier_id,omitempty"`
+	PendingTierEffectiveAt *time.Time `json:"pending_tier_effective_at,omitempty"`
+	SubscriptionTierPinned bool       `json:"subscription_tier_pinned"`
+	BillingCurrency        string     `json:"billing_currency"`
+	BillingInterval        string     `json:"billing_interval"`
+	MonthlySubscriptionFee int64      `json:"monthly_subscription_fee"`
 	CreatedAt              time.Time  `json:"created_at"`
 	UpdatedAt              time.Time  `json:"updated_at"`
 }
@@ -133,7 +150,33 @@ func (a *Admin) ToResponse() AdminResponse {
 		SubscriptionStatus:     a.SubscriptionStatus,
 		SubscriptionExpiresAt:  a.SubscriptionExpiresAt,
 		IsAccessRestricted:     a.IsAccessRestricted,
+		PendingTierID:          a.PendingTierID,
+		PendingTierEffectiveAt: a.PendingTierEffectiveAt,
+		SubscriptionTierPinned: a.SubscriptionTierPinned,
+		BillingCurrency:        a.BillingCurrency,
+		BillingInterval:        a.BillingInterval,
 		CreatedAt:              a.CreatedAt,
 		UpdatedAt:              a.UpdatedAt,
 	}
 }
+
+// HasActivePeriod reports whether the admin has paid time left on an active subscription
+func (a *Admin) HasActivePeriod(now time.Time) bool {
+	return a.SubscriptionStatus == "active" &&
+		a.SubscriptionExpiresAt != nil &&
+		a.SubscriptionExpiresAt.After(now)
+}
+
+// BillingPeriodStart returns where a newly paid period starts: at the current expiry
+// while the subscription is still running, otherwise now
+func (a *Admin) BillingPeriodStart(now time.Time) time.Time {
+	if a.HasActivePeriod(now) {
+		return *a.SubscriptionExpiresAt
+	}
+	return now
+}
+
+// AdminFilter holds the admin export filters besides the shared list filters
+type AdminFilter struct {
+	SubscriptionTierID *int
+}
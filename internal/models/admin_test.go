@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdminBillingPeriodStart(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	future := now.AddDate(0, 0, 10)
+	past := now.AddDate(0, 0, -10)
+
+	tests := []struct {
+		name       string
+		admin      Admin
+		wantActive bool
+		wantStart  time.Time
+	}{
+		{"running subscription", Admin{SubscriptionStatus: "active", SubscriptionExpiresAt: &future}, true, future},
+		{"expired subscription", Admin{SubscriptionStatus: "active", SubscriptionExpiresAt: &past}, false, now},
+		{"expires now", Admin{SubscriptionStatus: "active", SubscriptionExpiresAt: &now}, false, now},
+		{"no expiry", Admin{SubscriptionStatus: "active"}, false, now},
+		{"trial", Admin{SubscriptionStatus: "trial", SubscriptionExpiresAt: &future}, false, now},
+		{"marked expired", Admin{SubscriptionStatus: "expired", SubscriptionExpiresAt: &future}, false, now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.admin.HasActivePeriod(now); got != tt.wantActive {
+				t.Errorf("HasActivePeriod() = %v, want %v", got, tt.wantActive)
+			}
+			if got := tt.admin.BillingPeriodStart(now); !got.Equal(tt.wantStart) {
+				t.Errorf("BillingPeriodStart() = %v, want %v", got, tt.wantStart)
+			}
+		})
+	}
+}
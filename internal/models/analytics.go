@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+)
+
+// AnalyticsFilter holds the date range and reporting currency of an analytics query.
+// From is inclusive and To is exclusive.
+type AnalyticsFilter struct {
+	From     time.Time
+	To       time.Time
+	Currency string
+}
+
+// AnalyticsSummary represents the headline numbers of the business
+type AnalyticsSummary struct {
+	ActiveAdmins        int            `json:"active_admins"`
+	AdminsByStatus      map[string]int `json:"admins_by_status"`
+	RestrictedAdmins    int            `json:"restricted_admins"`
+	PendingPayments     int            `json:"pending_payments"`
+	MRR                 int64          `json:"mrr"` // Minor units of Currency
+	Currency            string         `json:"currency"`
+	UnconvertedPayments int            `json:"unconverted_payments"`
+}
+
+// TierMRR represents the monthly recurring revenue of one subscription tier
+type TierMRR struct {
+	SubscriptionTierID *int   `json:"subscription_tier_id"`
+	TierName           string `json:"tier_name"`
+	Admins             int    `json:"admins"`
+	MRR                int64  `json:"mrr"` // Minor units of the report currency
+}
+
+// MRRReport represents the monthly recurring revenue by tier at a point in time.
+// Payments without an exchange rate to the report currency are left out and counted.
+type MRRReport struct {
+	At                  time.Time  `json:"at"`
+	Currency            string     `json:"currency"`
+	Total               int64      `json:"total"`
+	Tiers               []*TierMRR `json:"tiers"`
+	UnconvertedPayments int        `json:"unconverted_payments"`
+}
+
+// AdminMovement represents how many admins started, stopped and resumed paying in a month
+type AdminMovement struct {
+	Month       time.Time `json:"month"`
+	Signups     int       `json:"signups"`
+	Paying      int       `json:"paying"`
+	New         int       `json:"new"`
+	Churned     int       `json:"churned"`
+	Reactivated int       `json:"reactivated"`
+}
+
+// RevenuePeriod represents the verified revenue of one period
+type RevenuePeriod struct {
+	PeriodStart         time.Time `json:"period_start"`
+	Payments            int       `json:"payments"`
+	Revenue             int64     `json:"revenue"` // Minor units of the report currency
+	UnconvertedPayments int       `json:"unconverted_payments"`
+}
+
+// RevenueReport represents the verified revenue per period within a date range
+type RevenueReport struct {
+	Period              string           `json:"period"` // day, week, month
+	Currency            string           `json:"currency"`
+	Total               int64            `json:"total"`
+	Periods             []*RevenuePeriod `json:"periods"`
+	UnconvertedPayments int              `json:"unconverted_payments"`
+}
+
+// PaymentMethodStats represents the payments made with one payment method
+type PaymentMethodStats struct {
+	PaymentMethod       string `json:"payment_method"`
+	Payments            int    `json:"payments"`
+	Verified            int    `json:"verified"`
+	Rejected            int    `json:"rejected"`
+	Pending             int    `json:"pending"`
+	VerifiedRevenue     int64  `json:"verified_revenue"` // Minor units of the report currency
+	UnconvertedPayments int    `json:"unconverted_payments"`
+}
+
+// VerificationLatency represents how long super admins take to process payments
+type VerificationLatency struct {
+	Processed          int     `json:"processed"`
+	AverageHours       float64 `json:"average_hours"`
+	MedianHours        float64 `json:"median_hours"`
+	MaxHours           float64 `json:"max_hours"`
+	Pending            int     `json:"pending"`
+	OldestPendingHours float64 `json:"oldest_pending_hours"`
+}
+
+// AtRiskAdmin represents an admin whose subscription is about to lapse or just lapsed
+type AtRiskAdmin struct {
+	AdminID               int        `json:"admin_id"`
+	CompanyName           string     `json:"company_name"`
+	Email                 string     `json:"email"`
+	SubscriptionStatus    string     `json:"subscription_status"`
+	TierName              string     `json:"tier_name"`
+	SubscriptionExpiresAt *time.Time `json:"subscription_expires_at"`
+	DaysLeft              int        `json:"days_left"`
+	LastPaymentAt         *time.Time `json:"last_payment_at"`
+	HasPendingPayment     bool       `json:"has_pending_payment"`
+	Reason                string     `json:"reason"` // trial_ending, expiring, expired
+}
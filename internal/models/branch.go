@@ -0,0 +1,151 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Branch statuses
+const (
+	BranchStatusActive            = "active"
+	BranchStatusTemporarilyClosed = "temporarily_closed"
+	BranchStatusInactive          = "inactive" // Hidden from the public endpoints
+)
+
+// DefaultNearbyRadius is the radius of nearby branch searches without one, in meters
+const DefaultNearbyRadius = 10000
+
+// Branch represents a location of an admin's restaurant
+type Branch struct {
+	ID           int          `json:"id"`
+	AdminID      int          `json:"admin_id"`
+	Name         string       `json:"name"`
+	Address      string       `json:"address"`
+	Phone        string       `json:"phone"`
+	Latitude     *float64     `json:"latitude"`
+	Longitude    *float64     `json:"longitude"`
+	Photos       []string     `json:"photos"` // Image URLs from the image service
+	Status       string       `json:"status"`
+	SortOrder    int          `json:"sort_order"`
+	TimeZone     string       `json:"time_zone"` // IANA name, e.g. Asia/Tashkent
+	OpeningHours WeeklyHours  `json:"opening_hours"`
+	SpecialDays  []SpecialDay `json:"special_days"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// BranchCreateRequest represents the creation request for a branch
+type BranchCreateRequest struct {
+	AdminID      int          `json:"admin_id"`
+	Name         string       `json:"name" validate:"required,max=255"`
+	Address      string       `json:"address"`
+	Phone        string       `json:"phone" validate:"max=50"`
+	Latitude     *float64     `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude    *float64     `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	Photos       []string     `json:"photos" validate:"max=20"`
+	Status       string       `json:"status" validate:"omitempty,oneof=active temporarily_closed inactive"`
+	SortOrder    int          `json:"sort_order"`
+	TimeZone     string       `json:"time_zone" validate:"max=64"`
+	OpeningHours WeeklyHours  `json:"opening_hours"`
+	SpecialDays  []SpecialDay `json:"special_days"`
+}
+
+// BranchUpdateRequest represents the update request for a branch. Omitted fields keep
+// their current values.
+type BranchUpdateRequest struct {
+	Name         string        `json:"name" validate:"max=255"`
+	Address      *string       `json:"address"`
+	Phone        *string       `json:"phone" validate:"omitempty,max=50"`
+	Latitude     *float64      `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude    *float64      `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	Photos       *[]string     `json:"photos" validate:"omitempty,max=20"`
+	Status       string        `json:"status" validate:"omitempty,oneof=active temporarily_closed inactive"`
+	SortOrder    *int          `json:"sort_order"`
+	TimeZone     string        `json:"time_zone" validate:"max=64"`
+	OpeningHours *WeeklyHours  `json:"opening_hours"` // Replaces the whole week
+	SpecialDays  *[]SpecialDay `json:"special_days"`  // Replaces all special days
+}
+
+// BranchResponse represents the response for a branch
+type BranchResponse struct {
+	ID            int          `json:"id"`
+	AdminID       int          `json:"admin_id"`
+	Name          string       `json:"name"`
+	Address       string       `json:"address"`
+	Phone         string       `json:"phone"`
+	Latitude      *float64     `json:"latitude"`
+	Longitude     *float64     `json:"longitude"`
+	Photos        []string     `json:"photos"`
+	Status        string       `json:"status"`
+	SortOrder     int          `json:"sort_order"`
+	TimeZone      string       `json:"time_zone"`
+	OpeningHours  WeeklyHours  `json:"opening_hours"`
+	SpecialDays   []SpecialDay `json:"special_days"`
+	IsOpenNow     *bool        `json:"is_open_now"`     // Null without opening hours
+	NextOpeningAt *time.Time   `json:"next_opening_at"` // Null while open or without opening hours
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// ToResponse converts a Branch to BranchResponse
+func (b *Branch) ToResponse() BranchResponse {
+	photos := b.Photos
+	if photos == nil {
+		photos = []string{}
+	}
+
+	specialDays := b.SpecialDays
+	if specialDays == nil {
+		specialDays = []SpecialDay{}
+	}
+
+	isOpen, nextOpeningAt := b.OpeningStatus(time.Now())
+
+	return BranchResponse{
+		ID:            b.ID,
+		AdminID:       b.AdminID,
+		Name:          b.Name,
+		Address:       b.Address,
+		Phone:         b.Phone,
+		Latitude:      b.Latitude,
+		Longitude:     b.Longitude,
+		Photos:        photos,
+		Status:        b.Status,
+		SortOrder:     b.SortOrder,
+		TimeZone:      b.TimeZone,
+		OpeningHours:  b.OpeningHours,
+		SpecialDays:   specialDays,
+		IsOpenNow:     isOpen,
+		NextOpeningAt: nextOpeningAt,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+	}
+}
+
+// NearbyBranchQuery represents the query of a nearby branch search
+type NearbyBranchQuery struct {
+	Latitude  *float64 `query:"lat" json:"lat" validate:"required,gte=-90,lte=90"`
+	Longitude *float64 `query:"lng" json:"lng" validate:"required,gte=-180,lte=180"`
+	Radius    float64  `query:"radius" json:"radius" validate:"omitempty,gt=0,lte=500000"` // Meters
+	Limit     int      `query:"limit" json:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// NearbyBranch is a branch found by a nearby search, with its distance from the searched point
+type NearbyBranch struct {
+	Branch         *Branch
+	DistanceMeters float64
+}
+
+// NearbyBranchResponse represents the response for a nearby branch
+type NearbyBranchResponse struct {
+	BranchResponse
+	DistanceMeters float64 `json:"distance_meters"`
+}
+
+// ToResponse converts a NearbyBranch to NearbyBranchResponse
+func (n *NearbyBranch) ToResponse() NearbyBranchResponse {
+	return NearbyBranchResponse{
+		BranchResponse: n.Branch.ToResponse(),
+		DistanceMeters: math.Round(n.DistanceMeters),
+	}
+}
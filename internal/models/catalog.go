@@ -0,0 +1,278 @@
+package models
+
+import (
+	"time"
+)
+
+// CatalogCategory groups the products of an admin's menu
+type CatalogCategory struct {
+	ID          int       `json:"id"`
+	AdminID     int       `json:"admin_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Image       string    `json:"image"` // Filename from the image service
+	SortOrder   int       `json:"sort_order"`
+	IsActive    bool      `json:"is_active"` // Inactive categories are hidden from the public menu
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CatalogCategoryCreateRequest represents the creation request for a category
+type CatalogCategoryCreateRequest struct {
+	AdminID     int    `json:"admin_id"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description"`
+	Image       string `json:"image" validate:"max=255"`
+	SortOrder   int    `json:"sort_order"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+// CatalogCategoryUpdateRequest represents the update request for a category. Omitted
+// fields keep their current values.
+type CatalogCategoryUpdateRequest struct {
+	Name        string  `json:"name" validate:"max=255"`
+	Description *string `json:"description"`
+	Image       *string `json:"image" validate:"omitempty,max=255"`
+	SortOrder   *int    `json:"sort_order"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// CatalogProduct is a product of an admin's menu
+type CatalogProduct struct {
+	ID               int       `json:"id"`
+	AdminID          int       `json:"admin_id"`
+	CategoryID       int       `json:"category_id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	Price            int64     `json:"price"` // Minor units of currency
+	Currency         string    `json:"currency"`
+	Images           []string  `json:"images"`       // Filenames from the image service
+	IsAvailable      bool      `json:"is_available"` // False while sold out
+	IsActive         bool      `json:"is_active"`    // Inactive products are hidden from the public menu
+	SortOrder        int       `json:"sort_order"`
+	ModifierGroupIDs []int     `json:"modifier_group_ids"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CatalogProductCreateRequest represents the creation request for a product
+type CatalogProductCreateRequest struct {
+	AdminID          int      `json:"admin_id"`
+	CategoryID       int      `json:"category_id" validate:"required"`
+	Name             string   `json:"name" validate:"required,max=255"`
+	Description      string   `json:"description"`
+	Price            int64    `json:"price" validate:"min=0"`
+	Currency         string   `json:"currency" validate:"omitempty,len=3"`
+	Images           []string `json:"images" validate:"max=10,dive,required,max=255"`
+	IsAvailable      *bool    `json:"is_available"`
+	IsActive         *bool    `json:"is_active"`
+	SortOrder        int      `json:"sort_order"`
+	ModifierGroupIDs []int    `json:"modifier_group_ids" validate:"dive,gt=0"`
+}
+
+// CatalogProductUpdateRequest represents the update request for a product. Omitted
+// fields keep their current values.
+type CatalogProductUpdateRequest struct {
+	CategoryID       int       `json:"category_id"`
+	Name             string    `json:"name" validate:"max=255"`
+	Description      *string   `json:"description"`
+	Price            *int64    `json:"price" validate:"omitempty,min=0"`
+	Currency         string    `json:"currency" validate:"omitempty,len=3"`
+	Images           *[]string `json:"images" validate:"omitempty,max=10,dive,required,max=255"`
+	IsAvailable      *bool     `json:"is_available"`
+	IsActive         *bool     `json:"is_active"`
+	SortOrder        *int      `json:"sort_order"`
+	ModifierGroupIDs *[]int    `json:"modifier_group_ids" validate:"omitempty,dive,gt=0"` // Replaces all linked groups
+}
+
+// ModifierGroup is a choice offered with products, e.g. size or extras. A customer
+// selects between MinSelect and MaxSelect of its options.
+type ModifierGroup struct {
+	ID        int              `json:"id"`
+	AdminID   int              `json:"admin_id"`
+	Name      string           `json:"name"`
+	MinSelect int              `json:"min_select"`
+	MaxSelect int              `json:"max_select"`
+	SortOrder int              `json:"sort_order"`
+	Options   []ModifierOption `json:"options"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ModifierOption is an option of a modifier group
+type ModifierOption struct {
+	ID          int    `json:"id"`
+	GroupID     int    `json:"group_id"`
+	Name        string `json:"name"`
+	Price       int64  `json:"price"` // Added to the product price, minor units
+	IsAvailable bool   `json:"is_available"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// ModifierOptionRequest represents an option in a modifier group request. Options with
+// the ID of an existing option update it, others are added.
+type ModifierOptionRequest struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Price       int64  `json:"price" validate:"min=0"`
+	IsAvailable *bool  `json:"is_available"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// ModifierGroupCreateRequest represents the creation request for a modifier group
+type ModifierGroupCreateRequest struct {
+	AdminID   int                     `json:"admin_id"`
+	Name      string                  `json:"name" validate:"required,max=255"`
+	MinSelect int                     `json:"min_select" validate:"min=0"`
+	MaxSelect int                     `json:"max_select" validate:"required,min=1"`
+	SortOrder int                     `json:"sort_order"`
+	Options   []ModifierOptionRequest `json:"options" validate:"required,max=50,dive"`
+}
+
+// ModifierGroupUpdateRequest represents the update request for a modifier group. Omitted
+// fields keep their current values.
+type ModifierGroupUpdateRequest struct {
+	Name      string                   `json:"name" validate:"max=255"`
+	MinSelect *int                     `json:"min_select" validate:"omitempty,min=0"`
+	MaxSelect *int                     `json:"max_select" validate:"omitempty,min=1"`
+	SortOrder *int                     `json:"sort_order"`
+	Options   *[]ModifierOptionRequest `json:"options" validate:"omitempty,min=1,max=50,dive"` // Replaces all options
+}
+
+// CatalogCategoryResponse represents the response for a category
+type CatalogCategoryResponse struct {
+	ID          int       `json:"id"`
+	AdminID     int       `json:"admin_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Image       string    `json:"image"`
+	SortOrder   int       `json:"sort_order"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ToResponse converts a CatalogCategory to CatalogCategoryResponse
+func (c *CatalogCategory) ToResponse() CatalogCategoryResponse {
+	return CatalogCategoryResponse{
+		ID:          c.ID,
+		AdminID:     c.AdminID,
+		Name:        c.Name,
+		Description: c.Description,
+		Image:       c.Image,
+		SortOrder:   c.SortOrder,
+		IsActive:    c.IsActive,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+// CatalogProductResponse represents the response for a product
+type CatalogProductResponse struct {
+	ID               int       `json:"id"`
+	AdminID          int       `json:"admin_id"`
+	CategoryID       int       `json:"category_id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	Price            int64     `json:"price"`
+	Currency         string    `json:"currency"`
+	Images           []string  `json:"images"`
+	IsAvailable      bool      `json:"is_available"`
+	IsActive         bool      `json:"is_active"`
+	SortOrder        int       `json:"sort_order"`
+	ModifierGroupIDs []int     `json:"modifier_group_ids"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ToResponse converts a CatalogProduct to CatalogProductResponse
+func (p *CatalogProduct) ToResponse() CatalogProductResponse {
+	images := p.Images
+	if images == nil {
+		images = []string{}
+	}
+
+	groupIDs := p.ModifierGroupIDs
+	if groupIDs == nil {
+		groupIDs = []int{}
+	}
+
+	return CatalogProductResponse{
+		ID:               p.ID,
+		AdminID:          p.AdminID,
+		CategoryID:       p.CategoryID,
+		Name:             p.Name,
+		Description:      p.Description,
+		Price:            p.Price,
+		Currency:         p.Currency,
+		Images:           images,
+		IsAvailable:      p.IsAvailable,
+		IsActive:         p.IsActive,
+		SortOrder:        p.SortOrder,
+		ModifierGroupIDs: groupIDs,
+		CreatedAt:        p.CreatedAt,
+		UpdatedAt:        p.UpdatedAt,
+	}
+}
+
+// ModifierGroupResponse represents the response for a modifier group
+type ModifierGroupResponse struct {
+	ID        int              `json:"id"`
+	AdminID   int              `json:"admin_id"`
+	Name      string           `json:"name"`
+	MinSelect int              `json:"min_select"`
+	MaxSelect int              `json:"max_select"`
+	SortOrder int              `json:"sort_order"`
+	Options   []ModifierOption `json:"options"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ToResponse converts a ModifierGroup to ModifierGroupResponse
+func (g *ModifierGroup) ToResponse() ModifierGroupResponse {
+	options := g.Options
+	if options == nil {
+		options = []ModifierOption{}
+	}
+
+	return ModifierGroupResponse{
+		ID:        g.ID,
+		AdminID:   g.AdminID,
+		Name:      g.Name,
+		MinSelect: g.MinSelect,
+		MaxSelect: g.MaxSelect,
+		SortOrder: g.SortOrder,
+		Options:   options,
+		CreatedAt: g.CreatedAt,
+		UpdatedAt: g.UpdatedAt,
+	}
+}
+
+// PublicCatalog is the menu of an admin as shown in the mobile app: active categories in
+// order, each with its active products and their modifier groups
+type PublicCatalog struct {
+	AdminID     int                     `json:"admin_id"`
+	Categories  []PublicCatalogCategory `json:"categories"`
+	GeneratedAt time.Time               `json:"generated_at"`
+}
+
+// PublicCatalogCategory is a category of the public menu
+type PublicCatalogCategory struct {
+	ID          int                    `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Image       string                 `json:"image"`
+	Products    []PublicCatalogProduct `json:"products"`
+}
+
+// PublicCatalogProduct is a product of the public menu
+type PublicCatalogProduct struct {
+	ID             int                     `json:"id"`
+	Name           string                  `json:"name"`
+	Description    string                  `json:"description"`
+	Price          int64                   `json:"price"`
+	Currency       string                  `json:"currency"`
+	Images         []string                `json:"images"`
+	IsAvailable    bool                    `json:"is_available"`
+	ModifierGroups []ModifierGroupResponse `json:"modifier_groups"`
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Coupon represents a discount code for subscriptions
+type Coupon struct {
+	ID              int        `json:"id"`
+	Code            string     `json:"code"`
+	Description     string     `json:"description"`
+	DiscountType    string     `json:"discount_type"`    // percent, fixed
+	DiscountPercent float64    `json:"discount_percent"` // Percentage of percent discounts
+	DiscountAmount  int64      `json:"discount_amount"`  // Minor units of Currency per month of fixed discounts
+	Currency        string     `json:"currency"`
+	DurationMonths  *int       `json:"duration_months"` // nil means forever
+	MaxRedemptions  *int       `json:"max_redemptions"` // nil means unlimited
+	RedemptionCount int        `json:"redemption_count"`
+	ValidTierIDs    []int      `json:"valid_tier_ids"` // empty means every tier
+	ValidFrom       *time.Time `json:"valid_from"`
+	ValidUntil      *time.Time `json:"valid_until"`
+	IsActive        bool       `json:"is_active"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CouponRedemption represents a coupon applied to an admin's subscription
+type CouponRedemption struct {
+	ID              int       `json:"id"`
+	CouponID        int       `json:"coupon_id"`
+	AdminID         int       `json:"admin_id"`
+	AdminName       string    `json:"admin_name,omitempty"`
+	MonthsRemaining *int      `json:"months_remaining"` // nil means forever
+	Status          string    `json:"status"`           // active, completed
+	RedeemedAt      time.Time `json:"redeemed_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CouponCreateRequest represents the request to create a coupon
+type CouponCreateRequest struct {
+	Code            string     `json:"code" validate:"required"`
+	Description     string     `json:"description"`
+	DiscountType    string     `json:"discount_type" validate:"required,oneof=percent fixed"`
+	DiscountPercent float64    `json:"discount_percent" validate:"omitempty,gt=0,lte=100"`
+	DiscountAmount  int64      `json:"discount_amount" validate:"omitempty,gt=0"` // Minor units of Currency per month
+	Currency        string     `json:"currency" validate:"omitempty,len=3"`
+	DurationMonths  *int       `json:"duration_months" validate:"omitempty,min=1"`
+	MaxRedemptions  *int       `json:"max_redemptions" validate:"omitempty,min=1"`
+	ValidTierIDs    []int      `json:"valid_tier_ids"`
+	ValidFrom       *time.Time `json:"valid_from"`
+	ValidUntil      *time.Time `json:"valid_until"`
+}
+
+// CouponUpdateRequest represents the request to update a coupon
+type CouponUpdateRequest struct {
+	Description     string     `json:"description"`
+	DiscountType    string     `json:"discount_type" validate:"omitempty,oneof=percent fixed"`
+	DiscountPercent *float64   `json:"discount_percent" validate:"omitempty,gt=0,lte=100"`
+	DiscountAmount  *int64     `json:"discount_amount" validate:"omitempty,gt=0"`
+	Currency        string     `json:"currency" validate:"omitempty,len=3"`
+	DurationMonths  *int       `json:"duration_months" validate:"omitempty,min=1"`
+	MaxRedemptions  *int       `json:"max_redemptions" validate:"omitempty,min=1"`
+	ValidTierIDs    []int      `json:"valid_tier_ids"`
+	ValidFrom       *time.Time `json:"valid_from"`
+	ValidUntil      *time.Time `json:"valid_until"`
+	IsActive        *bool      `json:"is_active"`
+}
+
+// CouponApplyRequest represents an admin's request to apply a coupon
+type CouponApplyRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// CouponReport represents redemption statistics of a coupon
+type CouponReport struct {
+	CouponID         int    `json:"coupon_id"`
+	Code             string `json:"code"`
+	RedemptionCount  int    `json:"redemption_count"`
+	ActiveCount      int    `json:"active_count"`
+	VerifiedPayments int    `json:"verified_payments"`
+	TotalDiscount    int64  `json:"total_discount"` // Minor units of Currency
+	Currency         string `json:"currency"`
+}
+
+// AppliesToTier reports whether the coupon may be used with a tier
+func (c *Coupon) AppliesToTier(tierID int) bool {
+	if len(c.ValidTierIDs) == 0 {
+		return true
+	}
+	for _, id := range c.ValidTierIDs {
+		if id == tierID {
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesToCurrency reports whether the coupon can discount a price in a currency.
+// Fixed discounts only apply to prices in the coupon's own currency.
+func (c *Coupon) AppliesToCurrency(currency string) bool {
+	return c.DiscountType != "fixed" || c.Currency == currency
+}
+
+// IsRedeemable reports whether the coupon can be applied at the given time
+func (c *Coupon) IsRedeemable(now time.Time) bool {
+	if !c.IsActive {
+		return false
+	}
+	if c.ValidFrom != nil && now.Before(*c.ValidFrom) {
+		return false
+	}
+	if c.ValidUntil != nil && now.After(*c.ValidUntil) {
+		return false
+	}
+	if c.MaxRedemptions != nil && c.RedemptionCount >= *c.MaxRedemptions {
+		return false
+	}
+	return true
+}
+
+// DiscountedPrice returns the price in minor units of a billing period of periodMonths
+// months after the coupon discount, when the coupon covers months of them. Only the share
+// of the price for the covered months is discounted, fixed discounts once per month.
+func (c *Coupon) DiscountedPrice(price int64, periodMonths, months int) int64 {
+	if periodMonths <= 0 || months <= 0 {
+		return price
+	}
+	if months > periodMonths {
+		months = periodMonths
+	}
+
+	covered := float64(price) * float64(months) / float64(periodMonths)
+	var discount float64
+	switch c.DiscountType {
+	case "percent":
+		discount = covered * c.DiscountPercent / 100
+	case "fixed":
+		discount = math.Min(float64(c.DiscountAmount)*float64(months), covered)
+	}
+
+	discounted := price - int64(math.Round(discount))
+	if discounted < 0 {
+		discounted = 0
+	}
+
+	return discounted
+}
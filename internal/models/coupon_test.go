@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestCouponDiscountedPrice(t *testing.T) {
+	half := Coupon{DiscountType: "percent", DiscountPercent: 50}
+	fixed := Coupon{DiscountType: "fixed", DiscountAmount: 1000}
+
+	tests := []struct {
+		name         string
+		coupon       Coupon
+		price        int64
+		periodMonths int
+		months       int
+		want         int64
+	}{
+		{"percent monthly", half, 10000, 1, 1, 5000},
+		{"percent quarterly", half, 27000, 3, 3, 13500},
+		{"percent quarterly partly covered", half, 27000, 3, 1, 22500},
+		{"percent yearly", half, 96000, 12, 12, 48000},
+		{"percent yearly partly covered", half, 96000, 12, 3, 84000},
+		{"percent rounds", Coupon{DiscountType: "percent", DiscountPercent: 33.33}, 1000, 1, 1, 667},
+		{"percent full", Coupon{DiscountType: "percent", DiscountPercent: 100}, 96000, 12, 12, 0},
+		{"fixed monthly", fixed, 10000, 1, 1, 9000},
+		{"fixed quarterly", fixed, 27000, 3, 3, 24000},
+		{"fixed quarterly partly covered", fixed, 27000, 3, 2, 25000},
+		{"fixed yearly", fixed, 96000, 12, 12, 84000},
+		{"fixed yearly partly covered", fixed, 96000, 12, 3, 93000},
+		{"fixed above the monthly price", Coupon{DiscountType: "fixed", DiscountAmount: 20000}, 10000, 1, 1, 0},
+		{"fixed above the price of the covered months", Coupon{DiscountType: "fixed", DiscountAmount: 20000}, 96000, 12, 3, 72000},
+		{"more months than the period", half, 10000, 1, 6, 5000},
+		{"no months covered", half, 10000, 1, 0, 10000},
+		{"unknown type", Coupon{DiscountType: "other", DiscountPercent: 50}, 10000, 1, 1, 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.coupon.DiscountedPrice(tt.price, tt.periodMonths, tt.months); got != tt.want {
+				t.Errorf("DiscountedPrice(%d, %d, %d) = %d, want %d", tt.price, tt.periodMonths, tt.months, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+)
+
+// Customer is an app user of an admin's restaurant. Customers log in with their phone
+// number and a code sent by SMS, and are separate per admin.
+type Customer struct {
+	ID               int               `json:"id"`
+	AdminID          int               `json:"admin_id"`
+	Phone            string            `json:"phone"` // International format, e.g. +998901234567
+	Name             string            `json:"name"`
+	Email            string            `json:"email"`
+	MarketingSMS     bool              `json:"marketing_sms"`  // Consents to promotional SMS
+	MarketingPush    bool              `json:"marketing_push"` // Consents to promotional push notifications
+	TermsAcceptedAt  *time.Time        `json:"terms_accepted_at"`
+	ConsentUpdatedAt *time.Time        `json:"consent_updated_at"`
+	LastLoginAt      *time.Time        `json:"last_login_at"`
+	Addresses        []CustomerAddress `json:"addresses"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// CustomerAddress is a saved delivery address of a customer
+type CustomerAddress struct {
+	ID         int       `json:"id"`
+	CustomerID int       `json:"customer_id"`
+	Label      string    `json:"label"` // e.g. Home, Work
+	Address    string    `json:"address"`
+	Details    string    `json:"details"` // Entrance, floor, apartment
+	Latitude   *float64  `json:"latitude"`
+	Longitude  *float64  `json:"longitude"`
+	IsDefault  bool      `json:"is_default"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CustomerOTP is a one-time login code sent to a phone number
+type CustomerOTP struct {
+	ID         int
+	AdminID    int
+	Phone      string
+	CodeHash   string
+	Attempts   int
+	ExpiresAt  time.Time
+	ConsumedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// CustomerOTPRequest represents the request sending a login code to a phone number
+type CustomerOTPRequest struct {
+	Phone string `json:"phone" validate:"required,max=20"`
+}
+
+// CustomerOTPVerifyRequest represents the request logging in with a code
+type CustomerOTPVerifyRequest struct {
+	Phone string `json:"phone" validate:"required,max=20"`
+	Code  string `json:"code" validate:"required,len=6"`
+}
+
+// CustomerUpdateRequest represents the update request of a customer's own profile
+type CustomerUpdateRequest struct {
+	Name          *string `json:"name" validate:"omitempty,max=255"`
+	Email         *string `json:"email" validate:"omitempty,max=255"` // Empty clears it
+	MarketingSMS  *bool   `json:"marketing_sms"`
+	MarketingPush *bool   `json:"marketing_push"`
+	AcceptTerms   *bool   `json:"accept_terms"` // True records the time the terms were accepted
+}
+
+// CustomerAddressRequest represents the request saving an address. Updates replace the
+// whole address.
+type CustomerAddressRequest struct {
+	Label     string   `json:"label" validate:"max=50"`
+	Address   string   `json:"address" validate:"required,max=500"`
+	Details   string   `json:"details" validate:"max=500"`
+	Latitude  *float64 `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	IsDefault bool     `json:"is_default"`
+}
+
+// CustomerResponse represents the response for a customer
+type CustomerResponse struct {
+	ID               int               `json:"id"`
+	AdminID          int               `json:"admin_id"`
+	Phone            string            `json:"phone"`
+	Name             string            `json:"name"`
+	Email            string            `json:"email"`
+	MarketingSMS     bool              `json:"marketing_sms"`
+	MarketingPush    bool              `json:"marketing_push"`
+	TermsAcceptedAt  *time.Time        `json:"terms_accepted_at"`
+	ConsentUpdatedAt *time.Time        `json:"consent_updated_at"`
+	LastLoginAt      *time.Time        `json:"last_login_at"`
+	Addresses        []CustomerAddress `json:"addresses"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// ToResponse converts a Customer to CustomerResponse
+func (c *Customer) ToResponse() CustomerResponse {
+	addresses := c.Addresses
+	if addresses == nil {
+		addresses = []CustomerAddress{}
+	}
+
+	return CustomerResponse{
+		ID:               c.ID,
+		AdminID:          c.AdminID,
+		Phone:            c.Phone,
+		Name:             c.Name,
+		Email:            c.Email,
+		MarketingSMS:     c.MarketingSMS,
+		MarketingPush:    c.MarketingPush,
+		TermsAcceptedAt:  c.TermsAcceptedAt,
+		ConsentUpdatedAt: c.ConsentUpdatedAt,
+		LastLoginAt:      c.LastLoginAt,
+		Addresses:        addresses,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+	}
+}
+
+// CustomerAuthResponse represents the response of a customer login
+type CustomerAuthResponse struct {
+	Token    string           `json:"token"`
+	IsNew    bool             `json:"is_new"` // The customer signed up with this login
+	Customer CustomerResponse `json:"customer"`
+}
@@ -0,0 +1,215 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Delivery fee types
+const (
+	DeliveryFeeFlat       = "flat"
+	DeliveryFeeDistance   = "distance"    // Tiered by meters from the zone's branch
+	DeliveryFeeOrderTotal = "order_total" // Tiered by the order subtotal
+)
+
+// earthRadiusMeters is the mean radius of the Earth used for distances
+const earthRadiusMeters = 6371000
+
+// GeoPoint is a point on the map
+type GeoPoint struct {
+	Latitude  float64 `json:"lat" validate:"gte=-90,lte=90"`
+	Longitude float64 `json:"lng" validate:"gte=-180,lte=180"`
+}
+
+// DeliveryFeeTier is the fee charged from a distance in meters or an order subtotal on,
+// up to the next tier
+type DeliveryFeeTier struct {
+	From int64 `json:"from" validate:"min=0"`
+	Fee  int64 `json:"fee" validate:"min=0"`
+}
+
+// DeliveryZone is an area an admin delivers to, drawn as a polygon
+type DeliveryZone struct {
+	ID             int               `json:"id"`
+	AdminID        int               `json:"admin_id"`
+	BranchID       *int              `json:"branch_id"` // Distance fees are measured from it
+	Name           string            `json:"name"`
+	Polygon        []GeoPoint        `json:"polygon"`
+	MinOrderAmount int64             `json:"min_order_amount"` // Minor units of currency
+	Currency       string            `json:"currency"`
+	FeeType        string            `json:"fee_type"`
+	Fee            int64             `json:"fee"` // Flat fee
+	FeeTiers       []DeliveryFeeTier `json:"fee_tiers"`
+	EtaMinutes     int               `json:"eta_minutes"`
+	IsActive       bool              `json:"is_active"`
+	SortOrder      int               `json:"sort_order"` // The first matching zone applies where zones overlap
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// boundaryTolerance is how far from a zone edge, in degrees, a point still lies on it
+const boundaryTolerance = 1e-9
+
+// Contains reports whether a point lies inside the zone polygon or on its boundary. Zones
+// are small enough to treat coordinates as planar, but must not cross the 180th meridian.
+func (z *DeliveryZone) Contains(point GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(z.Polygon)-1; i < len(z.Polygon); j, i = i, i+1 {
+		a, b := z.Polygon[i], z.Polygon[j]
+		if onSegment(point, a, b) {
+			return true
+		}
+		if (a.Latitude > point.Latitude) != (b.Latitude > point.Latitude) {
+			crossing := a.Longitude + (point.Latitude-a.Latitude)/(b.Latitude-a.Latitude)*(b.Longitude-a.Longitude)
+			if point.Longitude < crossing {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// onSegment reports whether a point lies on the segment between a and b
+func onSegment(point, a, b GeoPoint) bool {
+	cross := (b.Longitude-a.Longitude)*(point.Latitude-a.Latitude) - (b.Latitude-a.Latitude)*(point.Longitude-a.Longitude)
+	length := math.Hypot(b.Longitude-a.Longitude, b.Latitude-a.Latitude)
+	if math.Abs(cross) > boundaryTolerance*length {
+		return false
+	}
+
+	return point.Longitude >= math.Min(a.Longitude, b.Longitude)-boundaryTolerance &&
+		point.Longitude <= math.Max(a.Longitude, b.Longitude)+boundaryTolerance &&
+		point.Latitude >= math.Min(a.Latitude, b.Latitude)-boundaryTolerance &&
+		point.Latitude <= math.Max(a.Latitude, b.Latitude)+boundaryTolerance
+}
+
+// FeeFor returns the delivery fee for a distance in meters and an order subtotal
+func (z *DeliveryZone) FeeFor(distanceMeters float64, subtotal int64) int64 {
+	var value float64
+	switch z.FeeType {
+	case DeliveryFeeDistance:
+		value = distanceMeters
+	case DeliveryFeeOrderTotal:
+		value = float64(subtotal)
+	default:
+		return z.Fee
+	}
+
+	// Tiers are sorted by From, starting at 0, and apply from exactly their From on
+	fee := int64(0)
+	for _, tier := range z.FeeTiers {
+		if float64(tier.From) > value {
+			break
+		}
+		fee = tier.Fee
+	}
+	return fee
+}
+
+// DistanceMeters returns the great-circle distance between two points
+func DistanceMeters(a, b GeoPoint) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// DeliveryZoneCreateRequest represents the creation request for a delivery zone
+type DeliveryZoneCreateRequest struct {
+	AdminID        int               `json:"admin_id"`
+	BranchID       *int              `json:"branch_id" validate:"omitempty,gt=0"`
+	Name           string            `json:"name" validate:"required,max=255"`
+	Polygon        []GeoPoint        `json:"polygon" validate:"required,min=3,max=1000,dive"`
+	MinOrderAmount int64             `json:"min_order_amount" validate:"min=0"`
+	Currency       string            `json:"currency" validate:"omitempty,len=3"`
+	FeeType        string            `json:"fee_type" validate:"required,oneof=flat distance order_total"`
+	Fee            int64             `json:"fee" validate:"min=0"`
+	FeeTiers       []DeliveryFeeTier `json:"fee_tiers" validate:"max=50,dive"`
+	EtaMinutes     int               `json:"eta_minutes" validate:"required,min=1,max=1440"`
+	IsActive       *bool             `json:"is_active"`
+	SortOrder      int               `json:"sort_order"`
+}
+
+// DeliveryZoneUpdateRequest represents the update request for a delivery zone
+type DeliveryZoneUpdateRequest struct {
+	BranchID       *int               `json:"branch_id" validate:"omitempty,min=0"` // 0 unlinks the branch
+	Name           string             `json:"name" validate:"max=255"`
+	Polygon        *[]GeoPoint        `json:"polygon" validate:"omitempty,min=3,max=1000,dive"`
+	MinOrderAmount *int64             `json:"min_order_amount" validate:"omitempty,min=0"`
+	Currency       string             `json:"currency" validate:"omitempty,len=3"`
+	FeeType        string             `json:"fee_type" validate:"omitempty,oneof=flat distance order_total"`
+	Fee            *int64             `json:"fee" validate:"omitempty,min=0"`
+	FeeTiers       *[]DeliveryFeeTier `json:"fee_tiers" validate:"omitempty,max=50,dive"`
+	EtaMinutes     *int               `json:"eta_minutes" validate:"omitempty,min=1,max=1440"`
+	IsActive       *bool              `json:"is_active"`
+	SortOrder      *int               `json:"sort_order"`
+}
+
+// DeliveryZoneResponse represents the response for a delivery zone
+type DeliveryZoneResponse struct {
+	ID             int               `json:"id"`
+	AdminID        int               `json:"admin_id"`
+	BranchID       *int              `json:"branch_id"`
+	Name           string            `json:"name"`
+	Polygon        []GeoPoint        `json:"polygon"`
+	MinOrderAmount int64             `json:"min_order_amount"`
+	Currency       string            `json:"currency"`
+	FeeType        string            `json:"fee_type"`
+	Fee            int64             `json:"fee"`
+	FeeTiers       []DeliveryFeeTier `json:"fee_tiers"`
+	EtaMinutes     int               `json:"eta_minutes"`
+	IsActive       bool              `json:"is_active"`
+	SortOrder      int               `json:"sort_order"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// ToResponse converts a DeliveryZone to DeliveryZoneResponse
+func (z *DeliveryZone) ToResponse() DeliveryZoneResponse {
+	feeTiers := z.FeeTiers
+	if feeTiers == nil {
+		feeTiers = []DeliveryFeeTier{}
+	}
+
+	return DeliveryZoneResponse{
+		ID:             z.ID,
+		AdminID:        z.AdminID,
+		BranchID:       z.BranchID,
+		Name:           z.Name,
+		Polygon:        z.Polygon,
+		MinOrderAmount: z.MinOrderAmount,
+		Currency:       z.Currency,
+		FeeType:        z.FeeType,
+		Fee:            z.Fee,
+		FeeTiers:       feeTiers,
+		EtaMinutes:     z.EtaMinutes,
+		IsActive:       z.IsActive,
+		SortOrder:      z.SortOrder,
+		CreatedAt:      z.CreatedAt,
+		UpdatedAt:      z.UpdatedAt,
+	}
+}
+
+// DeliveryQuoteQuery represents the query asking whether an admin delivers to a point
+type DeliveryQuoteQuery struct {
+	Latitude  *float64 `query:"lat" json:"lat" validate:"required,gte=-90,lte=90"`
+	Longitude *float64 `query:"lng" json:"lng" validate:"required,gte=-180,lte=180"`
+	Subtotal  int64    `query:"subtotal" json:"subtotal" validate:"min=0"` // Order subtotal, minor units
+}
+
+// DeliveryQuote tells whether an admin delivers to a point, and at what cost
+type DeliveryQuote struct {
+	Deliverable    bool     `json:"deliverable"`
+	Reason         string   `json:"reason,omitempty"` // Why delivery isn't possible
+	ZoneID         int      `json:"zone_id,omitempty"`
+	ZoneName       string   `json:"zone_name,omitempty"`
+	Fee            int64    `json:"fee"`
+	Currency       string   `json:"currency,omitempty"`
+	MinOrderAmount int64    `json:"min_order_amount"`
+	MeetsMinimum   bool     `json:"meets_minimum"` // Whether the subtotal reaches MinOrderAmount
+	EtaMinutes     int      `json:"eta_minutes,omitempty"`
+	DistanceMeters *float64 `json:"distance_meters,omitempty"` // From the zone's branch, when it has coordinates
+}
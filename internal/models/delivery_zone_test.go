@@ -0,0 +1,132 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDeliveryZoneContains(t *testing.T) {
+	square := DeliveryZone{Polygon: []GeoPoint{
+		{Latitude: 41.30, Longitude: 69.20},
+		{Latitude: 41.30, Longitude: 69.30},
+		{Latitude: 41.40, Longitude: 69.30},
+		{Latitude: 41.40, Longitude: 69.20},
+	}}
+	// An L shape, concave at 41.35/69.25
+	lShape := DeliveryZone{Polygon: []GeoPoint{
+		{Latitude: 41.30, Longitude: 69.20},
+		{Latitude: 41.30, Longitude: 69.30},
+		{Latitude: 41.35, Longitude: 69.30},
+		{Latitude: 41.35, Longitude: 69.25},
+		{Latitude: 41.40, Longitude: 69.25},
+		{Latitude: 41.40, Longitude: 69.20},
+	}}
+	triangle := DeliveryZone{Polygon: []GeoPoint{
+		{Latitude: 41.30, Longitude: 69.20},
+		{Latitude: 41.30, Longitude: 69.30},
+		{Latitude: 41.40, Longitude: 69.25},
+	}}
+
+	tests := []struct {
+		name  string
+		zone  DeliveryZone
+		point GeoPoint
+		want  bool
+	}{
+		{"square center", square, GeoPoint{41.35, 69.25}, true},
+		{"square outside east", square, GeoPoint{41.35, 69.31}, false},
+		{"square outside north", square, GeoPoint{41.41, 69.25}, false},
+		{"square outside in line with an edge", square, GeoPoint{41.30, 69.35}, false},
+		{"square south edge", square, GeoPoint{41.30, 69.25}, true},
+		{"square north edge", square, GeoPoint{41.40, 69.25}, true},
+		{"square west edge", square, GeoPoint{41.35, 69.20}, true},
+		{"square east edge", square, GeoPoint{41.35, 69.30}, true},
+		{"square south west vertex", square, GeoPoint{41.30, 69.20}, true},
+		{"square north east vertex", square, GeoPoint{41.40, 69.30}, true},
+		{"square just outside a vertex", square, GeoPoint{41.40001, 69.30001}, false},
+		{"L shape inside the lower arm", lShape, GeoPoint{41.32, 69.28}, true},
+		{"L shape inside the upper arm", lShape, GeoPoint{41.38, 69.22}, true},
+		{"L shape in the notch", lShape, GeoPoint{41.38, 69.28}, false},
+		{"L shape reflex vertex", lShape, GeoPoint{41.35, 69.25}, true},
+		{"L shape ray through a vertex", lShape, GeoPoint{41.35, 69.22}, true},
+		{"triangle inside", triangle, GeoPoint{41.33, 69.25}, true},
+		{"triangle slanted edge", triangle, GeoPoint{41.35, 69.275}, true},
+		{"triangle outside the slanted edge", triangle, GeoPoint{41.35, 69.28}, false},
+		{"triangle apex", triangle, GeoPoint{41.40, 69.25}, true},
+		{"empty polygon", DeliveryZone{}, GeoPoint{41.35, 69.25}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.zone.Contains(tt.point); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.point, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliveryZoneFeeFor(t *testing.T) {
+	tiers := []DeliveryFeeTier{
+		{From: 0, Fee: 5000},
+		{From: 1000, Fee: 8000},
+		{From: 5000, Fee: 12000},
+	}
+	byDistance := DeliveryZone{FeeType: DeliveryFeeDistance, FeeTiers: tiers}
+	// Free delivery from 100000 on
+	byTotal := DeliveryZone{FeeType: DeliveryFeeOrderTotal, FeeTiers: []DeliveryFeeTier{
+		{From: 0, Fee: 10000},
+		{From: 100000, Fee: 0},
+	}}
+
+	tests := []struct {
+		name     string
+		zone     DeliveryZone
+		distance float64
+		subtotal int64
+		want     int64
+	}{
+		{"flat", DeliveryZone{FeeType: DeliveryFeeFlat, Fee: 7000}, 12345, 500, 7000},
+		{"flat ignores tiers", DeliveryZone{FeeType: DeliveryFeeFlat, Fee: 7000, FeeTiers: tiers}, 12345, 500, 7000},
+		{"distance zero", byDistance, 0, 0, 5000},
+		{"distance within the first tier", byDistance, 999, 0, 5000},
+		{"distance just below a tier", byDistance, 999.9, 0, 5000},
+		{"distance at a tier", byDistance, 1000, 0, 8000},
+		{"distance just above a tier", byDistance, 1000.1, 0, 8000},
+		{"distance at the last tier", byDistance, 5000, 0, 12000},
+		{"distance beyond the last tier", byDistance, 50000, 0, 12000},
+		{"distance ignores the subtotal", byDistance, 10, 1000000, 5000},
+		{"order total below free delivery", byTotal, 0, 99999, 10000},
+		{"order total at free delivery", byTotal, 0, 100000, 0},
+		{"order total ignores the distance", byTotal, 50000, 100001, 0},
+		{"no tiers", DeliveryZone{FeeType: DeliveryFeeDistance}, 1000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.zone.FeeFor(tt.distance, tt.subtotal); got != tt.want {
+				t.Errorf("FeeFor(%v, %d) = %d, want %d", tt.distance, tt.subtotal, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistanceMeters(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b GeoPoint
+		want float64
+	}{
+		{"same point", GeoPoint{41.31, 69.24}, GeoPoint{41.31, 69.24}, 0},
+		{"one degree of latitude", GeoPoint{0, 0}, GeoPoint{1, 0}, 111195},
+		{"one degree of longitude on the equator", GeoPoint{0, 10}, GeoPoint{0, 11}, 111195},
+		{"antipodes", GeoPoint{0, 0}, GeoPoint{0, 180}, math.Pi * earthRadiusMeters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DistanceMeters(tt.a, tt.b); math.Abs(got-tt.want) > 1 {
+				t.Errorf("DistanceMeters(%v, %v) = %.0f, want %.0f", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types pushed to the admin panel
+const (
+	EventOrderCreated       = "order.created"
+	EventOrderStatusChanged = "order.status_changed"
+	EventPaymentVerified    = "payment.verified"
+	EventNotificationStats  = "notification.stats"
+)
+
+// Event is a change pushed to the admin panel of the admin it belongs to
+type Event struct {
+	Type      string          `json:"type"`
+	AdminID   int             `json:"admin_id"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// OrderEventData is the data of order events. Clients fetch the full order when needed.
+type OrderEventData struct {
+	OrderID      int    `json:"order_id"`
+	Type         string `json:"type"`
+	Status       string `json:"status"`
+	FromStatus   string `json:"from_status,omitempty"`
+	CustomerName string `json:"customer_name"`
+	Total        int64  `json:"total"`
+	Currency     string `json:"currency"`
+}
+
+// PaymentEventData is the data of payment events
+type PaymentEventData struct {
+	PaymentID int    `json:"payment_id"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	IsTopUp   bool   `json:"is_top_up"`
+}
+
+// NotificationStatsEventData is the data of notification stats events. Apps fetch
+// notifications themselves, so the stats count the devices registered to receive them.
+type NotificationStatsEventData struct {
+	NotificationID int    `json:"notification_id"`
+	Title          string `json:"title"`
+	Devices        int    `json:"devices"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// ExchangeRate represents a manually maintained currency exchange rate.
+// One unit of BaseCurrency is worth Rate units of QuoteCurrency.
+type ExchangeRate struct {
+	ID            int       `json:"id"`
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          float64   `json:"rate"`
+	EffectiveDate time.Time `json:"effective_date"`
+	CreatedBy     *int      `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ExchangeRateRequest represents the request to set an exchange rate
+type ExchangeRateRequest struct {
+	BaseCurrency  string  `json:"base_currency" validate:"required,len=3"`
+	QuoteCurrency string  `json:"quote_currency" validate:"required,len=3"`
+	Rate          float64 `json:"rate" validate:"required,gt=0"`
+	EffectiveDate string  `json:"effective_date"` // YYYY-MM-DD, defaults to today
+}
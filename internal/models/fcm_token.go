@@ -6,11 +6,12 @@ import (
 
 // FCMToken model represents the FCM token entity
 type FCMToken struct {
-	ID        int       `json:"id"`
-	AdminID   int       `json:"admin_id"`
-	FCMToken  string    `json:"fcm_token"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         int       `json:"id"`
+	AdminID    int       `json:"admin_id"`
+	CustomerID *int      `json:"customer_id"` // The customer logged in on the device
+	FCMToken   string    `json:"fcm_token"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // FCMTokenCreateRequest represents the creation request for an FCM token
@@ -20,20 +21,22 @@ type FCMTokenCreateRequest struct {
 
 // FCMTokenResponse represents the response for FCM token
 type FCMTokenResponse struct {
-	ID        int       `json:"id"`
-	AdminID   int       `json:"admin_id"`
-	FCMToken  string    `json:"fcm_token"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         int       `json:"id"`
+	AdminID    int       `json:"admin_id"`
+	CustomerID *int      `json:"customer_id"`
+	FCMToken   string    `json:"fcm_token"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 // ToResponse converts FCMToken model to FCMTokenResponse
 func (ft *FCMToken) ToResponse() FCMTokenResponse {
 	return FCMTokenResponse{
-		ID:        ft.ID,
-		AdminID:   ft.AdminID,
-		FCMToken:  ft.FCMToken,
-		CreatedAt: ft.CreatedAt,
-		UpdatedAt: ft.UpdatedAt,
+		ID:         ft.ID,
+		AdminID:    ft.AdminID,
+		CustomerID: ft.CustomerID,
+		FCMToken:   ft.FCMToken,
+		CreatedAt:  ft.CreatedAt,
+		UpdatedAt:  ft.UpdatedAt,
 	}
 }
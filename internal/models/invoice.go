@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Invoice is the invoice of a verified payment. Amounts are minor units of Currency, the
+// currency the payment was made in.
+type Invoice struct {
+	ID              int        `json:"id"`
+	Number          string     `json:"number"` // INV-<year issued>-<payment ID>
+	PaymentID       int        `json:"payment_id"`
+	AdminID         int        `json:"admin_id"`
+	CompanyName     string     `json:"company_name"`
+	IssuedAt        time.Time  `json:"issued_at"`
+	Currency        string     `json:"currency"`
+	TierName        string     `json:"tier_name"`
+	BillingInterval string     `json:"billing_interval"`
+	Months          int        `json:"months"`
+	PeriodStart     *time.Time `json:"period_start"`
+	PeriodEnd       *time.Time `json:"period_end"`
+	IsTopUp         bool       `json:"is_top_up"`
+	Subtotal        int64      `json:"subtotal"` // Before the coupon discount
+	DiscountAmount  int64      `json:"discount_amount"`
+	ProratedAmount  int64      `json:"prorated_amount"` // Upgrade proration included in Total
+	Total           int64      `json:"total"`
+	RefundedAmount  int64      `json:"refunded_amount"`
+	NetAmount       int64      `json:"net_amount"` // Total less refunds
+	Status          string     `json:"status"`     // verified, refunded or reversed
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+)
+
+// Ledger accounts. The admin_balance account holds what an admin has prepaid: credits raise
+// it and debits lower it. The other accounts are the counterparts on the business side.
+const (
+	LedgerAccountAdminBalance = "admin_balance"
+	LedgerAccountCash         = "cash"
+	LedgerAccountRevenue      = "revenue"
+	LedgerAccountCredits      = "credits"
+	LedgerAccountAdjustments  = "adjustments"
+)
+
+// Ledger transaction types
+const (
+	LedgerTypePayment    = "payment"    // Money received from the admin
+	LedgerTypeCharge     = "charge"     // A subscription period billed to the admin
+	LedgerTypeCredit     = "credit"     // Balance granted to the admin, e.g. goodwill or a refunded period
+	LedgerTypeRefund     = "refund"     // Money paid back to the admin
+	LedgerTypeAdjustment = "adjustment" // Manual correction in either direction
+)
+
+// PaymentMethodBalance marks subscription periods paid from the prepaid balance
+const PaymentMethodBalance = "balance"
+
+// LedgerTransaction represents one business event of an admin's account. Its entries
+// always balance: the debits equal the credits.
+type LedgerTransaction struct {
+	ID          int            `json:"id"`
+	AdminID     int            `json:"admin_id"`
+	Type        string         `json:"type"` // payment, charge, credit, refund, adjustment
+	Currency    string         `json:"currency"`
+	Amount      int64          `json:"amount"` // Minor units of Currency
+	Description string         `json:"description"`
+	PaymentID   *int           `json:"payment_id"`
+	RefundID    *int           `json:"refund_id"`
+	CreatedBy   *int           `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Entries     []*LedgerEntry `json:"entries,omitempty"`
+}
+
+// LedgerEntry represents a debit or credit line of a ledger transaction
+type LedgerEntry struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	Account       string `json:"account"`
+	Debit         int64  `json:"debit"`
+	Credit        int64  `json:"credit"`
+}
+
+// NewLedgerTransaction creates a transaction that moves amount from the debit to the credit account
+func NewLedgerTransaction(adminID int, txType string, currency string, amount int64, debitAccount, creditAccount string, description string) *LedgerTransaction {
+	return &LedgerTransaction{
+		AdminID:     adminID,
+		Type:        txType,
+		Currency:    currency,
+		Amount:      amount,
+		Description: description,
+		Entries: []*LedgerEntry{
+			{Account: debitAccount, Debit: amount},
+			{Account: creditAccount, Credit: amount},
+		},
+	}
+}
+
+// IsBalanced reports whether the debits of a transaction equal its credits
+func (t *LedgerTransaction) IsBalanced() bool {
+	var debit, credit int64
+	for _, entry := range t.Entries {
+		debit += entry.Debit
+		credit += entry.Credit
+	}
+	return len(t.Entries) >= 2 && debit == credit
+}
+
+// BalanceChange returns how much a transaction raises the admin's balance, negative when it lowers it
+func (t *LedgerTransaction) BalanceChange() int64 {
+	var change int64
+	for _, entry := range t.Entries {
+		if entry.Account == LedgerAccountAdminBalance {
+			change += entry.Credit - entry.Debit
+		}
+	}
+	return change
+}
+
+// LedgerStatementLine represents a transaction on an admin's statement with the balance after it
+type LedgerStatementLine struct {
+	TransactionID int       `json:"transaction_id"`
+	Type          string    `json:"type"`
+	Description   string    `json:"description"`
+	PaymentID     *int      `json:"payment_id"`
+	RefundID      *int      `json:"refund_id"`
+	Change        int64     `json:"change"` // Positive raises the balance
+	Balance       int64     `json:"balance"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LedgerStatement represents an admin's account in one currency over a date range
+type LedgerStatement struct {
+	AdminID        int                    `json:"admin_id"`
+	Currency       string                 `json:"currency"`
+	From           *time.Time             `json:"from"`
+	To             *time.Time             `json:"to"`
+	OpeningBalance int64                  `json:"opening_balance"`
+	ClosingBalance int64                  `json:"closing_balance"`
+	Lines          []*LedgerStatementLine `json:"lines"`
+}
+
+// LedgerCreditRequest represents a super admin granting balance to an admin
+type LedgerCreditRequest struct {
+	Amount      int64  `json:"amount" validate:"required,min=1"`    // Minor units of Currency
+	Currency    string `json:"currency" validate:"omitempty,len=3"` // Defaults to the admin's billing currency
+	Description string `json:"description" validate:"required"`
+}
+
+// LedgerAdjustmentRequest represents a super admin correcting an admin's balance
+type LedgerAdjustmentRequest struct {
+	Amount      int64  `json:"amount" validate:"required"`          // Minor units, negative lowers the balance
+	Currency    string `json:"currency" validate:"omitempty,len=3"` // Defaults to the admin's billing currency
+	Description string `json:"description" validate:"required"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// List page sizes
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// ListParams holds the pagination, sorting and filters of a list request. Each list
+// decides which sort keys and filters it supports.
+type ListParams struct {
+	Limit   int
+	Offset  int        // Ignored when Cursor is set
+	Cursor  string     // Opaque position returned as next_cursor by the previous page
+	Sort    string     // Sort key, empty for the list's default order
+	Desc    bool       // Sort descending
+	Status  string     // Status filter
+	From    *time.Time // Inclusive start of the date filter
+	To      *time.Time // Exclusive end of the date filter
+	AdminID *int       // Owning admin filter
+	Search  string     // Text search, e.g. on the company name
+}
+
+// ListMeta describes the page of a list response
+type ListMeta struct {
+	Total      int     `json:"total"` // Items matching the filters on all pages
+	Limit      int     `json:"limit"`
+	Offset     int     `json:"offset"`
+	Sort       string  `json:"sort"`
+	Order      string  `json:"order"` // asc or desc
+	NextCursor *string `json:"next_cursor"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"time"
+)
+
+// Loyalty transaction types
+const (
+	LoyaltyEarn   = "earn"   // Points earned by a completed order
+	LoyaltyRedeem = "redeem" // Points spent as an order discount
+	LoyaltyRefund = "refund" // Spent points returned when the order is cancelled
+	LoyaltyExpire = "expire" // Points that expired unspent
+)
+
+// LoyaltyProgram holds the earn and burn rules of an admin's loyalty program
+type LoyaltyProgram struct {
+	AdminID          int       `json:"admin_id"`
+	IsActive         bool      `json:"is_active"`
+	Currency         string    `json:"currency"`          // Orders in other currencies neither earn nor spend points
+	EarnBasisPoints  int       `json:"earn_basis_points"` // Share of the order total earned, 250 = 2.5%
+	VisitPoints      int64     `json:"visit_points"`      // Earned per completed order
+	MinOrderAmount   int64     `json:"min_order_amount"`  // Orders below earn nothing, minor units
+	PointValue       int64     `json:"point_value"`       // Minor units a point is worth
+	RedeemMaxPercent int       `json:"redeem_max_percent"`
+	RedeemMaxPoints  int64     `json:"redeem_max_points"` // Per order, 0 means no cap
+	MinRedeemPoints  int64     `json:"min_redeem_points"`
+	ExpiryDays       int       `json:"expiry_days"`        // 0 means points never expire
+	ExpiryNoticeDays int       `json:"expiry_notice_days"` // Days before expiry the customer is reminded, 0 means never
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// EarnedPoints returns the points earned by an order paying total for its food. Cashback
+// is converted to points at the point value, rounding down.
+func (p *LoyaltyProgram) EarnedPoints(total int64) int64 {
+	if total < p.MinOrderAmount || total <= 0 {
+		return 0
+	}
+
+	return total*int64(p.EarnBasisPoints)/10000/p.PointValue + p.VisitPoints
+}
+
+// MaxRedeemable returns the most points an order with subtotal can spend out of balance
+func (p *LoyaltyProgram) MaxRedeemable(subtotal, balance int64) int64 {
+	points := subtotal * int64(p.RedeemMaxPercent) / 100 / p.PointValue
+	if p.RedeemMaxPoints > 0 && points > p.RedeemMaxPoints {
+		points = p.RedeemMaxPoints
+	}
+	if points > balance {
+		points = balance
+	}
+	if points < p.MinRedeemPoints {
+		return 0
+	}
+	return points
+}
+
+// ExpiresAt returns when points earned at a time expire, or nil when they never do
+func (p *LoyaltyProgram) ExpiresAt(earned time.Time) *time.Time {
+	if p.ExpiryDays == 0 {
+		return nil
+	}
+
+	expiresAt := earned.AddDate(0, 0, p.ExpiryDays)
+	return &expiresAt
+}
+
+// LoyaltyTransaction is an entry of the points ledger of a customer
+type LoyaltyTransaction struct {
+	ID          int        `json:"id"`
+	AdminID     int        `json:"admin_id"`
+	CustomerID  int        `json:"customer_id"`
+	OrderID     *int       `json:"order_id"`
+	Type        string     `json:"type"`
+	Points      int64      `json:"points"`    // Negative for redeem and expire
+	Remaining   int64      `json:"remaining"` // Unspent points of an earn or refund entry
+	ExpiresAt   *time.Time `json:"expires_at"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// LoyaltyExpiryNotice is a reminder due to a customer whose points expire soon
+type LoyaltyExpiryNotice struct {
+	AdminID    int
+	CustomerID int
+	Points     int64
+	ExpiresAt  time.Time // The earliest expiry of the points
+	LotIDs     []int     // The ledger entries the reminder covers
+}
+
+// LoyaltyProgramRequest represents the request saving the loyalty program of an admin
+type LoyaltyProgramRequest struct {
+	AdminID          int    `json:"admin_id"`
+	IsActive         bool   `json:"is_active"`
+	Currency         string `json:"currency" validate:"omitempty,len=3"`
+	EarnBasisPoints  int    `json:"earn_basis_points" validate:"min=0,max=10000"`
+	VisitPoints      int64  `json:"visit_points" validate:"min=0"`
+	MinOrderAmount   int64  `json:"min_order_amount" validate:"min=0"`
+	PointValue       int64  `json:"point_value" validate:"required,min=1"`
+	RedeemMaxPercent int    `json:"redeem_max_percent" validate:"required,min=1,max=100"`
+	RedeemMaxPoints  int64  `json:"redeem_max_points" validate:"min=0"`
+	MinRedeemPoints  int64  `json:"min_redeem_points" validate:"min=0"`
+	ExpiryDays       int    `json:"expiry_days" validate:"min=0,max=3650"`
+	ExpiryNoticeDays int    `json:"expiry_notice_days" validate:"min=0,max=365"`
+}
+
+// LoyaltyExpiry tells how many points expire soon, and when the first of them do
+type LoyaltyExpiry struct {
+	Points    int64     `json:"points"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// LoyaltyBalance represents the points balance of a customer
+type LoyaltyBalance struct {
+	CustomerID int             `json:"customer_id"`
+	Points     int64           `json:"points"`
+	Value      int64           `json:"value"` // What the points are worth, minor units of currency
+	Currency   string          `json:"currency"`
+	Expiring   *LoyaltyExpiry  `json:"expiring"` // Points expiring within the notice period, 30 days without one
+	Program    *LoyaltyProgram `json:"program"`  // Nil when the admin has no active program
+}
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// openingSearchDays is how far ahead the next opening of a branch is searched
+const openingSearchDays = 31
+
+// OpeningInterval is a period a branch is open, as HH:MM local times. A closing time at
+// or before the opening time closes after midnight, on the next day.
+type OpeningInterval struct {
+	Opens  string `json:"opens" validate:"required,len=5"`
+	Closes string `json:"closes" validate:"required,len=5"`
+}
+
+// WeeklyHours holds the opening intervals of each weekday. A day without intervals is closed.
+type WeeklyHours struct {
+	Monday    []OpeningInterval `json:"monday"`
+	Tuesday   []OpeningInterval `json:"tuesday"`
+	Wednesday []OpeningInterval `json:"wednesday"`
+	Thursday  []OpeningInterval `json:"thursday"`
+	Friday    []OpeningInterval `json:"friday"`
+	Saturday  []OpeningInterval `json:"saturday"`
+	Sunday    []OpeningInterval `json:"sunday"`
+}
+
+// SpecialDay replaces the weekly hours of one date, e.g. a holiday
+type SpecialDay struct {
+	Date      string            `json:"date" validate:"required,len=10"` // YYYY-MM-DD
+	Name      string            `json:"name"`
+	Intervals []OpeningInterval `json:"intervals"` // Empty when the branch is closed that day
+}
+
+// Day returns the intervals of a weekday
+func (w *WeeklyHours) Day(weekday time.Weekday) []OpeningInterval {
+	switch weekday {
+	case time.Monday:
+		return w.Monday
+	case time.Tuesday:
+		return w.Tuesday
+	case time.Wednesday:
+		return w.Wednesday
+	case time.Thursday:
+		return w.Thursday
+	case time.Friday:
+		return w.Friday
+	case time.Saturday:
+		return w.Saturday
+	default:
+		return w.Sunday
+	}
+}
+
+// IsEmpty reports whether no weekday has opening hours
+func (w *WeeklyHours) IsEmpty() bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if len(w.Day(day)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseClock parses an HH:MM time into minutes after midnight. 24:00 is accepted as the
+// end of a day.
+func ParseClock(value string) (int, error) {
+	invalid := fmt.Errorf("invalid time %q, expected HH:MM", value)
+	if len(value) != 5 || value[2] != ':' {
+		return 0, invalid
+	}
+
+	hours, err := strconv.Atoi(value[:2])
+	if err != nil {
+		return 0, invalid
+	}
+	minutes, err := strconv.Atoi(value[3:])
+	if err != nil {
+		return 0, invalid
+	}
+
+	if hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes > 0) {
+		return 0, invalid
+	}
+	return hours*60 + minutes, nil
+}
+
+// Validate checks the times of an interval
+func (i OpeningInterval) Validate() error {
+	opens, err := ParseClock(i.Opens)
+	if err != nil {
+		return err
+	}
+	if opens == 24*60 {
+		return fmt.Errorf("invalid opening time %q", i.Opens)
+	}
+	_, err = ParseClock(i.Closes)
+	return err
+}
+
+// openPeriod is an interval placed on a calendar date
+type openPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+// intervalsOn returns the intervals of a date: those of a special day, otherwise those of
+// its weekday
+func (b *Branch) intervalsOn(date time.Time) []OpeningInterval {
+	key := date.Format("2006-01-02")
+	for _, day := range b.SpecialDays {
+		if day.Date == key {
+			return day.Intervals
+		}
+	}
+	return b.OpeningHours.Day(date.Weekday())
+}
+
+// periodsOn returns the open periods starting on a date in the branch's time zone,
+// ordered by start
+func (b *Branch) periodsOn(date time.Time, loc *time.Location) []openPeriod {
+	var periods []openPeriod
+	for _, interval := range b.intervalsOn(date) {
+		opens, err := ParseClock(interval.Opens)
+		if err != nil {
+			continue
+		}
+		closes, err := ParseClock(interval.Closes)
+		if err != nil {
+			continue
+		}
+
+		start := time.Date(date.Year(), date.Month(), date.Day(), opens/60, opens%60, 0, 0, loc)
+		endDate := date
+		if closes <= opens {
+			endDate = date.AddDate(0, 0, 1)
+		}
+		end := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), closes/60, closes%60, 0, 0, loc)
+		periods = append(periods, openPeriod{start: start, end: end})
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].start.Before(periods[j].start)
+	})
+	return periods
+}
+
+// CombinedOpeningStatus tells whether any of the branches is open at now and, when none is,
+// when the first of them opens next. Both are nil when no branch has opening hours.
+func CombinedOpeningStatus(branches []*Branch, now time.Time) (isOpen *bool, nextOpeningAt *time.Time) {
+	for _, branch := range branches {
+		open, next := branch.OpeningStatus(now)
+		if open == nil {
+			continue
+		}
+		if *open {
+			return open, nil
+		}
+
+		isOpen = open
+		if next != nil && (nextOpeningAt == nil || next.Before(*nextOpeningAt)) {
+			nextOpeningAt = next
+		}
+	}
+
+	return isOpen, nextOpeningAt
+}
+
+// OpeningStatus tells whether a branch is open at now and, when it is closed, when it
+// opens next, evaluated in the branch's time zone. Both are nil for a branch without
+// opening hours. A temporarily closed or inactive branch is never open.
+func (b *Branch) OpeningStatus(now time.Time) (isOpen *bool, nextOpeningAt *time.Time) {
+	if b.OpeningHours.IsEmpty() && len(b.SpecialDays) == 0 {
+		return nil, nil
+	}
+
+	closed := false
+	if b.Status != BranchStatusActive {
+		return &closed, nil
+	}
+
+	loc, err := time.LoadLocation(b.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	// Periods of yesterday may still be open after midnight
+	for _, period := range b.periodsOn(today.AddDate(0, 0, -1), loc) {
+		if !local.Before(period.start) && local.Before(period.end) {
+			open := true
+			return &open, nil
+		}
+	}
+
+	for days := 0; days <= openingSearchDays; days++ {
+		for _, period := range b.periodsOn(today.AddDate(0, 0, days), loc) {
+			if !local.Before(period.start) && local.Before(period.end) {
+				open := true
+				return &open, nil
+			}
+			if period.start.After(local) {
+				next := period.start
+				return &closed, &next
+			}
+		}
+	}
+
+	return &closed, nil
+}
@@ -0,0 +1,331 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"00:00", 0, false},
+		{"09:30", 570, false},
+		{"23:59", 1439, false},
+		{"24:00", 1440, false},
+		{"24:01", 0, true},
+		{"25:00", 0, true},
+		{"12:60", 0, true},
+		{"9:30", 0, true},
+		{"09-30", 0, true},
+		{"ab:cd", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseClock(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseClock(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseClock(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpeningIntervalValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval OpeningInterval
+		wantErr  bool
+	}{
+		{"day", OpeningInterval{"09:00", "18:00"}, false},
+		{"closes at midnight", OpeningInterval{"09:00", "24:00"}, false},
+		{"closes after midnight", OpeningInterval{"22:00", "02:00"}, false},
+		{"opens at end of day", OpeningInterval{"24:00", "02:00"}, true},
+		{"invalid opening", OpeningInterval{"9:00", "18:00"}, true},
+		{"invalid closing", OpeningInterval{"09:00", "18:75"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.interval.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBranchOpeningStatus(t *testing.T) {
+	// 2026-10-19 is a Monday
+	at := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	interval := func(opens, closes string) []OpeningInterval {
+		return []OpeningInterval{{Opens: opens, Closes: closes}}
+	}
+
+	tests := []struct {
+		name     string
+		branch   Branch
+		now      string
+		wantOpen *bool
+		wantNext string // Empty when no next opening is expected
+	}{
+		{
+			name:   "no opening hours",
+			branch: Branch{Status: BranchStatusActive},
+			now:    "2026-10-19 12:00",
+		},
+		{
+			name:     "open during the day",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")}},
+			now:      "2026-10-19 12:00",
+			wantOpen: boolPtr(true),
+		},
+		{
+			name:     "opens at the opening minute",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")}},
+			now:      "2026-10-19 09:00",
+			wantOpen: boolPtr(true),
+		},
+		{
+			name:     "closed at the closing minute",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00"), Tuesday: interval("09:00", "18:00")}},
+			now:      "2026-10-19 18:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-20 09:00",
+		},
+		{
+			name:     "before opening",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")}},
+			now:      "2026-10-19 07:30",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-19 09:00",
+		},
+		{
+			name: "between intervals of a day",
+			branch: Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Monday: []OpeningInterval{
+				{Opens: "17:00", Closes: "22:00"},
+				{Opens: "09:00", Closes: "14:00"},
+			}}},
+			now:      "2026-10-19 15:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-19 17:00",
+		},
+		{
+			name:     "open after midnight on the interval of yesterday",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Sunday: interval("22:00", "02:00")}},
+			now:      "2026-10-19 01:00",
+			wantOpen: boolPtr(true),
+		},
+		{
+			name:     "closed when the interval of yesterday ended",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Sunday: interval("22:00", "02:00")}},
+			now:      "2026-10-19 02:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-25 22:00",
+		},
+		{
+			name:     "open before midnight on an interval closing after it",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Monday: interval("22:00", "02:00")}},
+			now:      "2026-10-19 23:30",
+			wantOpen: boolPtr(true),
+		},
+		{
+			name:     "round the clock",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Monday: interval("00:00", "00:00")}},
+			now:      "2026-10-19 23:59",
+			wantOpen: boolPtr(true),
+		},
+		{
+			name:     "closes at 24:00",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Monday: interval("09:00", "24:00")}},
+			now:      "2026-10-19 23:59",
+			wantOpen: boolPtr(true),
+		},
+		{
+			name:     "closed at the end of a day closing at 24:00",
+			branch:   Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{Monday: interval("09:00", "24:00"), Tuesday: interval("09:00", "24:00")}},
+			now:      "2026-10-20 00:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-20 09:00",
+		},
+		{
+			name: "special day closes a weekday",
+			branch: Branch{
+				Status:       BranchStatusActive,
+				OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")},
+				SpecialDays:  []SpecialDay{{Date: "2026-10-19", Name: "Holiday"}},
+			},
+			now:      "2026-10-19 12:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-26 09:00",
+		},
+		{
+			name: "special day replaces the hours of a weekday",
+			branch: Branch{
+				Status:       BranchStatusActive,
+				OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")},
+				SpecialDays:  []SpecialDay{{Date: "2026-10-19", Intervals: interval("13:00", "16:00")}},
+			},
+			now:      "2026-10-19 10:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-19 13:00",
+		},
+		{
+			name: "special day opens a closed weekday",
+			branch: Branch{
+				Status:       BranchStatusActive,
+				OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")},
+				SpecialDays:  []SpecialDay{{Date: "2026-10-20", Intervals: interval("10:00", "12:00")}},
+			},
+			now:      "2026-10-19 19:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-20 10:00",
+		},
+		{
+			name: "special day of yesterday closes after midnight",
+			branch: Branch{
+				Status:       BranchStatusActive,
+				OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")},
+				SpecialDays:  []SpecialDay{{Date: "2026-10-18", Intervals: interval("20:00", "03:00")}},
+			},
+			now:      "2026-10-19 02:00",
+			wantOpen: boolPtr(true),
+		},
+		{
+			name: "special day of yesterday replaces an interval closing after midnight",
+			branch: Branch{
+				Status:       BranchStatusActive,
+				OpeningHours: WeeklyHours{Sunday: interval("22:00", "02:00"), Monday: interval("09:00", "18:00")},
+				SpecialDays:  []SpecialDay{{Date: "2026-10-18"}},
+			},
+			now:      "2026-10-19 01:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-19 09:00",
+		},
+		{
+			name:     "only special days",
+			branch:   Branch{Status: BranchStatusActive, SpecialDays: []SpecialDay{{Date: "2026-10-31", Intervals: interval("18:00", "23:00")}}},
+			now:      "2026-10-19 12:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-31 18:00",
+		},
+		{
+			name:     "no opening within the search window",
+			branch:   Branch{Status: BranchStatusActive, SpecialDays: []SpecialDay{{Date: "2027-01-01", Intervals: interval("18:00", "23:00")}}},
+			now:      "2026-10-19 12:00",
+			wantOpen: boolPtr(false),
+		},
+		{
+			name:     "temporarily closed",
+			branch:   Branch{Status: BranchStatusTemporarilyClosed, OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")}},
+			now:      "2026-10-19 12:00",
+			wantOpen: boolPtr(false),
+		},
+		{
+			name: "evaluated in the time zone of the branch",
+			branch: Branch{
+				Status:       BranchStatusActive,
+				TimeZone:     "Asia/Tashkent",
+				OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")},
+			},
+			now:      "2026-10-19 05:00", // 10:00 in Tashkent
+			wantOpen: boolPtr(true),
+		},
+		{
+			name:     "unknown time zone falls back to UTC",
+			branch:   Branch{Status: BranchStatusActive, TimeZone: "Nowhere/Nothing", OpeningHours: WeeklyHours{Monday: interval("09:00", "18:00")}},
+			now:      "2026-10-19 08:00",
+			wantOpen: boolPtr(false),
+			wantNext: "2026-10-19 09:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isOpen, next := tt.branch.OpeningStatus(at(tt.now))
+
+			switch {
+			case tt.wantOpen == nil && isOpen != nil:
+				t.Errorf("isOpen = %v, want nil", *isOpen)
+			case tt.wantOpen != nil && isOpen == nil:
+				t.Errorf("isOpen = nil, want %v", *tt.wantOpen)
+			case tt.wantOpen != nil && *isOpen != *tt.wantOpen:
+				t.Errorf("isOpen = %v, want %v", *isOpen, *tt.wantOpen)
+			}
+
+			switch {
+			case tt.wantNext == "" && next != nil:
+				t.Errorf("nextOpeningAt = %v, want nil", next)
+			case tt.wantNext != "" && next == nil:
+				t.Errorf("nextOpeningAt = nil, want %s", tt.wantNext)
+			case tt.wantNext != "" && !next.Equal(at(tt.wantNext)):
+				t.Errorf("nextOpeningAt = %v, want %s", next.UTC(), tt.wantNext)
+			}
+		})
+	}
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func TestCombinedOpeningStatus(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) // Monday
+	branch := func(opens, closes string) *Branch {
+		return &Branch{Status: BranchStatusActive, OpeningHours: WeeklyHours{
+			Monday:  []OpeningInterval{{Opens: opens, Closes: closes}},
+			Tuesday: []OpeningInterval{{Opens: opens, Closes: closes}},
+		}}
+	}
+	at := func(day, hour int) *time.Time {
+		value := time.Date(2026, 10, day, hour, 0, 0, 0, time.UTC)
+		return &value
+	}
+
+	tests := []struct {
+		name     string
+		branches []*Branch
+		wantOpen *bool
+		wantNext *time.Time
+	}{
+		{"no branches", nil, nil, nil},
+		{"no opening hours", []*Branch{{Status: BranchStatusActive}}, nil, nil},
+		{"one open", []*Branch{branch("18:00", "23:00"), branch("09:00", "18:00")}, boolPtr(true), nil},
+		{"all closed", []*Branch{branch("18:00", "23:00"), branch("14:00", "16:00")}, boolPtr(false), at(19, 14)},
+		{"closed for today", []*Branch{branch("07:00", "10:00"), branch("18:00", "23:00")}, boolPtr(false), at(19, 18)},
+		{"temporarily closed branch", []*Branch{{Status: BranchStatusTemporarilyClosed, OpeningHours: branch("09:00", "18:00").OpeningHours}}, boolPtr(false), nil},
+		{"branch without hours", []*Branch{{Status: BranchStatusActive}, branch("14:00", "16:00")}, boolPtr(false), at(19, 14)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isOpen, next := CombinedOpeningStatus(tt.branches, now)
+
+			switch {
+			case (isOpen == nil) != (tt.wantOpen == nil):
+				t.Errorf("isOpen = %v, want %v", isOpen, tt.wantOpen)
+			case isOpen != nil && *isOpen != *tt.wantOpen:
+				t.Errorf("isOpen = %v, want %v", *isOpen, *tt.wantOpen)
+			}
+
+			switch {
+			case (next == nil) != (tt.wantNext == nil):
+				t.Errorf("nextOpeningAt = %v, want %v", next, tt.wantNext)
+			case next != nil && !next.Equal(*tt.wantNext):
+				t.Errorf("nextOpeningAt = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package models
+
+import (
+	"time"
+)
+
+// Order types
+const (
+	OrderTypePickup   = "pickup"
+	OrderTypeDelivery = "delivery"
+)
+
+// Order statuses
+const (
+	OrderStatusNew            = "new"
+	OrderStatusAccepted       = "accepted"
+	OrderStatusCooking        = "cooking"
+	OrderStatusReady          = "ready"
+	OrderStatusOutForDelivery = "out_for_delivery"
+	OrderStatusCompleted      = "completed"
+	OrderStatusCancelled      = "cancelled"
+)
+
+// Actors that change the status of an order
+const (
+	OrderActorAdmin    = "admin"
+	OrderActorCustomer = "customer"
+	OrderActorTelegram = "telegram" // Kitchen staff using the admin's Telegram chat
+)
+
+// orderTransitions holds the statuses each status can move to. Completed and cancelled
+// orders are final.
+var orderTransitions = map[string][]string{
+	OrderStatusNew:            {OrderStatusAccepted, OrderStatusCancelled},
+	OrderStatusAccepted:       {OrderStatusCooking, OrderStatusCancelled},
+	OrderStatusCooking:        {OrderStatusReady, OrderStatusCancelled},
+	OrderStatusReady:          {OrderStatusOutForDelivery, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusOutForDelivery: {OrderStatusCompleted, OrderStatusCancelled},
+}
+
+// Order is an order placed from the mobile app
+type Order struct {
+	ID                int                  `json:"id"`
+	AdminID           int                  `json:"admin_id"`
+	BranchID          *int                 `json:"branch_id"`
+	CustomerID        *int                 `json:"customer_id"`    // Set when a logged in customer placed the order
+	TrackingToken     string               `json:"tracking_token"` // Lets the customer track the order
+	Type              string               `json:"type"`
+	Status            string               `json:"status"`
+	CustomerName      string               `json:"customer_name"`
+	CustomerPhone     string               `json:"customer_phone"`
+	DeliveryAddress   string               `json:"delivery_address"`
+	DeliveryLatitude  *float64             `json:"delivery_latitude"`
+	DeliveryLongitude *float64             `json:"delivery_longitude"`
+	Notes             string               `json:"notes"`
+	Subtotal          int64                `json:"subtotal"` // Minor units of currency
+	DeliveryFee       int64                `json:"delivery_fee"`
+	Discount          int64                `json:"discount"`
+	PointsRedeemed    int64                `json:"points_redeemed"` // Loyalty points spent on Discount
+	Total             int64                `json:"total"`
+	Currency          string               `json:"currency"`
+	CancelReason      string               `json:"cancel_reason"`
+	Items             []OrderItem          `json:"items"`
+	History           []OrderStatusHistory `json:"history"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// OrderItem is a product of an order. Names and prices are copied from the catalog, so
+// later catalog changes don't alter placed orders.
+type OrderItem struct {
+	ID        int                 `json:"id"`
+	OrderID   int                 `json:"order_id"`
+	ProductID *int                `json:"product_id"`
+	Name      string              `json:"name"`
+	UnitPrice int64               `json:"unit_price"` // Product price with its modifiers
+	Quantity  int                 `json:"quantity"`
+	Total     int64               `json:"total"`
+	Notes     string              `json:"notes"`
+	Modifiers []OrderItemModifier `json:"modifiers"`
+}
+
+// OrderItemModifier is a modifier option selected for an order item
+type OrderItemModifier struct {
+	ID         int    `json:"id"`
+	OptionID   *int   `json:"option_id"`
+	GroupName  string `json:"group_name"`
+	OptionName string `json:"option_name"`
+	Price      int64  `json:"price"`
+}
+
+// OrderStatusHistory records a status change of an order
+type OrderStatusHistory struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"` // admin, customer or telegram
+	ActorID    *int      `json:"actor_id"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CanTransition reports whether the order may move to a status. Only delivery orders go
+// out for delivery, and those are completed once delivered.
+func (o *Order) CanTransition(status string) bool {
+	if status == OrderStatusOutForDelivery && o.Type != OrderTypeDelivery {
+		return false
+	}
+	if status == OrderStatusCompleted && o.Status == OrderStatusReady && o.Type == OrderTypeDelivery {
+		return false
+	}
+
+	for _, next := range orderTransitions[o.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStatuses returns the statuses the order may move to
+func (o *Order) NextStatuses() []string {
+	statuses := []string{}
+	for _, next := range orderTransitions[o.Status] {
+		if o.CanTransition(next) {
+			statuses = append(statuses, next)
+		}
+	}
+	return statuses
+}
+
+// OrderModifierRequest represents a selected modifier option in an order request
+type OrderModifierRequest struct {
+	OptionID int `json:"option_id" validate:"required,gt=0"`
+}
+
+// OrderItemRequest represents a product in an order request
+type OrderItemRequest struct {
+	ProductID int                    `json:"product_id" validate:"required,gt=0"`
+	Quantity  int                    `json:"quantity" validate:"required,min=1,max=100"`
+	Notes     string                 `json:"notes" validate:"max=500"`
+	Modifiers []OrderModifierRequest `json:"modifiers" validate:"max=50,dive"`
+}
+
+// OrderCreateRequest represents the request placing an order
+type OrderCreateRequest struct {
+	BranchID          *int               `json:"branch_id" validate:"omitempty,gt=0"`
+	Type              string             `json:"type" validate:"required,oneof=pickup delivery"`
+	CustomerName      string             `json:"customer_name" validate:"max=255"`
+	CustomerPhone     string             `json:"customer_phone" validate:"required,max=50"`
+	DeliveryAddress   string             `json:"delivery_address" validate:"max=500"`
+	DeliveryLatitude  *float64           `json:"delivery_latitude" validate:"omitempty,gte=-90,lte=90"`
+	DeliveryLongitude *float64           `json:"delivery_longitude" validate:"omitempty,gte=-180,lte=180"`
+	Notes             string             `json:"notes" validate:"max=1000"`
+	Items             []OrderItemRequest `json:"items" validate:"required,max=100,dive"`
+	RedeemPoints      int64              `json:"redeem_points" validate:"min=0"` // Loyalty points to spend, for logged in customers
+}
+
+// OrderStatusRequest represents the request changing the status of an order
+type OrderStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=accepted cooking ready out_for_delivery completed cancelled"`
+	Note   string `json:"note" validate:"max=500"` // Reason when cancelling
+}
+
+// OrderCancelRequest represents the request of a customer cancelling an order
+type OrderCancelRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// OrderResponse represents the response for an order
+type OrderResponse struct {
+	ID                int                  `json:"id"`
+	AdminID           int                  `json:"admin_id"`
+	BranchID          *int                 `json:"branch_id"`
+	CustomerID        *int                 `json:"customer_id"`
+	TrackingToken     string               `json:"tracking_token"`
+	Type              string               `json:"type"`
+	Status            string               `json:"status"`
+	NextStatuses      []string             `json:"next_statuses"`
+	CustomerName      string               `json:"customer_name"`
+	CustomerPhone     string               `json:"customer_phone"`
+	DeliveryAddress   string               `json:"delivery_address"`
+	DeliveryLatitude  *float64             `json:"delivery_latitude"`
+	DeliveryLongitude *float64             `json:"delivery_longitude"`
+	Notes             string               `json:"notes"`
+	Subtotal          int64                `json:"subtotal"`
+	DeliveryFee       int64                `json:"delivery_fee"`
+	Discount          int64                `json:"discount"`
+	PointsRedeemed    int64                `json:"points_redeemed"`
+	Total             int64                `json:"total"`
+	Currency          string               `json:"currency"`
+	CancelReason      string               `json:"cancel_reason"`
+	Items             []OrderItem          `json:"items"`
+	History           []OrderStatusHistory `json:"history"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// ToResponse converts an Order to OrderResponse
+func (o *Order) ToResponse() OrderResponse {
+	items := o.Items
+	if items == nil {
+		items = []OrderItem{}
+	}
+
+	history := o.History
+	if history == nil {
+		history = []OrderStatusHistory{}
+	}
+
+	return OrderResponse{
+		ID:                o.ID,
+		AdminID:           o.AdminID,
+		BranchID:          o.BranchID,
+		CustomerID:        o.CustomerID,
+		TrackingToken:     o.TrackingToken,
+		Type:              o.Type,
+		Status:            o.Status,
+		NextStatuses:      o.NextStatuses(),
+		CustomerName:      o.CustomerName,
+		CustomerPhone:     o.CustomerPhone,
+		DeliveryAddress:   o.DeliveryAddress,
+		DeliveryLatitude:  o.DeliveryLatitude,
+		DeliveryLongitude: o.DeliveryLongitude,
+		Notes:             o.Notes,
+		Subtotal:          o.Subtotal,
+		DeliveryFee:       o.DeliveryFee,
+		Discount:          o.Discount,
+		PointsRedeemed:    o.PointsRedeemed,
+		Total:             o.Total,
+		Currency:          o.Currency,
+		CancelReason:      o.CancelReason,
+		Items:             items,
+		History:           history,
+		CreatedAt:         o.CreatedAt,
+		UpdatedAt:         o.UpdatedAt,
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderCanTransition(t *testing.T) {
+	tests := []struct {
+		name      string
+		orderType string
+		from      string
+		to        string
+		want      bool
+	}{
+		{"accept", OrderTypePickup, OrderStatusNew, OrderStatusAccepted, true},
+		{"cancel new", OrderTypePickup, OrderStatusNew, OrderStatusCancelled, true},
+		{"skip accepting", OrderTypePickup, OrderStatusNew, OrderStatusCooking, false},
+		{"start cooking", OrderTypeDelivery, OrderStatusAccepted, OrderStatusCooking, true},
+		{"finish cooking", OrderTypeDelivery, OrderStatusCooking, OrderStatusReady, true},
+		{"back to cooking", OrderTypeDelivery, OrderStatusReady, OrderStatusCooking, false},
+		{"pickup collected", OrderTypePickup, OrderStatusReady, OrderStatusCompleted, true},
+		{"pickup out for delivery", OrderTypePickup, OrderStatusReady, OrderStatusOutForDelivery, false},
+		{"delivery out for delivery", OrderTypeDelivery, OrderStatusReady, OrderStatusOutForDelivery, true},
+		{"delivery completed before going out", OrderTypeDelivery, OrderStatusReady, OrderStatusCompleted, false},
+		{"delivery delivered", OrderTypeDelivery, OrderStatusOutForDelivery, OrderStatusCompleted, true},
+		{"cancel out for delivery", OrderTypeDelivery, OrderStatusOutForDelivery, OrderStatusCancelled, true},
+		{"cancel ready", OrderTypePickup, OrderStatusReady, OrderStatusCancelled, true},
+		{"completed is final", OrderTypePickup, OrderStatusCompleted, OrderStatusCancelled, false},
+		{"cancelled is final", OrderTypePickup, OrderStatusCancelled, OrderStatusNew, false},
+		{"same status", OrderTypePickup, OrderStatusCooking, OrderStatusCooking, false},
+		{"unknown status", OrderTypePickup, OrderStatusNew, "shipped", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := Order{Type: tt.orderType, Status: tt.from}
+			if got := order.CanTransition(tt.to); got != tt.want {
+				t.Errorf("CanTransition(%s -> %s) for %s = %v, want %v", tt.from, tt.to, tt.orderType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderNextStatuses(t *testing.T) {
+	tests := []struct {
+		name      string
+		orderType string
+		status    string
+		want      []string
+	}{
+		{"pickup ready", OrderTypePickup, OrderStatusReady, []string{OrderStatusCompleted, OrderStatusCancelled}},
+		{"delivery ready", OrderTypeDelivery, OrderStatusReady, []string{OrderStatusOutForDelivery, OrderStatusCancelled}},
+		{"completed", OrderTypeDelivery, OrderStatusCompleted, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := Order{Type: tt.orderType, Status: tt.status}
+			if got := order.NextStatuses(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NextStatuses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// PaymentAttachment represents a proof of payment, e.g. a receipt photo or PDF
+type PaymentAttachment struct {
+	ID           int       `json:"id"`
+	PaymentID    int       `json:"payment_id"`
+	AdminID      int       `json:"admin_id"`
+	Filename     string    `json:"-"` // Stored file name, only served through the access-checked endpoint
+	OriginalName string    `json:"original_name"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	URL          string    `json:"url"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// SetURL sets the URL the attachment can be downloaded from
+func (a *PaymentAttachment) SetURL() {
+	a.URL = fmt.Sprintf("/api/payment-attachments/%d", a.ID)
+}
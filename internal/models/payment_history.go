@@ -8,15 +8,26 @@ import (
 type PaymentHistory struct {
 	ID                 int        `json:"id"`
 	AdminID            int        `json:"admin_id"`
-	Amount             float64    `json:"amount"`
+	Amount             int64      `json:"amount"` // Minor units of Currency
+	Currency           string     `json:"currency"`
 	PaymentDate        time.Time  `json:"payment_date"`
 	PaymentMethod      string     `json:"payment_method"`
 	TransactionID      string     `json:"transaction_id"`
 	SubscriptionTierID *int       `json:"subscription_tier_id"`
 	PeriodStart        *time.Time `json:"period_start"`
 	PeriodEnd          *time.Time `json:"period_end"`
-	Status             string     `json:"status"` // pending, verified, rejected
+	BillingInterval    string     `json:"billing_interval"`
+	Months             int        `json:"months"`
+	ProratedAmount     int64      `json:"prorated_amount"`
+	Status             string     `json:"status"` // pending, verified, rejected, refunded, reversed
 	Notes              string     `json:"notes"`
+	NeedsReview        bool       `json:"needs_review"`
+	ReviewReason       string     `json:"review_reason"`
+	CouponRedemptionID *int       `json:"coupon_redemption_id"`
+	DiscountAmount     int64      `json:"discount_amount"`
+	DiscountedMonths   int        `json:"discounted_months"`
+	RefundedAmount     int64      `json:"refunded_amount"`
+	IsTopUp            bool       `json:"is_top_up"` // Adds to the prepaid balance instead of paying for a period
 	VerifiedBy         *int       `json:"verified_by"`
 	VerifiedAt         *time.Time `json:"verified_at"`
 	CreatedAt          time.Time  `json:"created_at"`
@@ -25,10 +36,14 @@ type PaymentHistory struct {
 
 // PaymentCreateRequest represents the request to record a payment
 type PaymentCreateRequest struct {
-	Amount        float64 `json:"amount" validate:"required,min=0"`
-	PaymentMethod string  `json:"payment_method" validate:"required"`
-	TransactionID string  `json:"transaction_id"`
-	Notes         string  `json:"notes"`
+	Amount             int64  `json:"amount" validate:"required,min=1"`    // Minor units of Currency
+	Currency           string `json:"currency" validate:"omitempty,len=3"` // Defaults to the admin's billing currency
+	PaymentMethod      string `json:"payment_method" validate:"required"`
+	TransactionID      string `json:"transaction_id"`
+	Notes              string `json:"notes"`
+	SubscriptionTierID *int   `json:"subscription_tier_id"`                                                 // Optional target tier, e.g. for an upgrade
+	BillingInterval    string `json:"billing_interval" validate:"omitempty,oneof=monthly quarterly yearly"` // Defaults to the admin's billing interval
+	TopUp              bool   `json:"top_up"`                                                               // Add the amount to the prepaid balance
 }
 
 // PaymentVerifyRequest represents the request to verify a payment
@@ -41,24 +56,35 @@ type PaymentVerifyRequest struct {
 
 // PaymentHistoryResponse represents the response for a payment record
 type PaymentHistoryResponse struct {
-	ID                   int        `json:"id"`
-	AdminID              int        `json:"admin_id"`
-	AdminName            string     `json:"admin_name,omitempty"` // Added for convenience in listing
-	Amount               float64    `json:"amount"`
-	PaymentDate          time.Time  `json:"payment_date"`
-	PaymentMethod        string     `json:"payment_method"`
-	TransactionID        string     `json:"transaction_id"`
-	SubscriptionTierID   *int       `json:"subscription_tier_id"`
-	SubscriptionTierName string     `json:"subscription_tier_name,omitempty"`
-	PeriodStart          *time.Time `json:"period_start"`
-	PeriodEnd            *time.Time `json:"period_end"`
-	Status               string     `json:"status"`
-	Notes                string     `json:"notes"`
-	VerifiedBy           *int       `json:"verified_by"`
-	VerifiedByName       string     `json:"verified_by_name,omitempty"`
-	VerifiedAt           *time.Time `json:"verified_at"`
-	CreatedAt            time.Time  `json:"created_at"`
-	UpdatedAt            time.Time  `json:"updated_at"`
+	ID                   int                  `json:"id"`
+	AdminID              int                  `json:"admin_id"`
+	AdminName            string               `json:"admin_name,omitempty"` // Added for convenience in listing
+	Amount               int64                `json:"amount"`
+	Currency             string               `json:"currency"`
+	PaymentDate          time.Time            `json:"payment_date"`
+	PaymentMethod        string               `json:"payment_method"`
+	TransactionID        string               `json:"transaction_id"`
+	SubscriptionTierID   *int                 `json:"subscription_tier_id"`
+	SubscriptionTierName string               `json:"subscription_tier_name,omitempty"`
+	PeriodStart          *time.Time           `json:"period_start"`
+	PeriodEnd            *time.Time           `json:"period_end"`
+	BillingInterval      string               `json:"billing_interval"`
+	Months               int                  `json:"months"`
+	ProratedAmount       int64                `json:"prorated_amount"`
+	Status               string               `json:"status"`
+	Notes                string               `json:"notes"`
+	NeedsReview          bool                 `json:"needs_review"`
+	ReviewReason         string               `json:"review_reason,omitempty"`
+	CouponRedemptionID   *int                 `json:"coupon_redemption_id,omitempty"`
+	DiscountAmount       int64                `json:"discount_amount"`
+	RefundedAmount       int64                `json:"refunded_amount"`
+	IsTopUp              bool                 `json:"is_top_up"`
+	VerifiedBy           *int                 `json:"verified_by"`
+	VerifiedByName       string               `json:"verified_by_name,omitempty"`
+	VerifiedAt           *time.Time           `json:"verified_at"`
+	Attachments          []*PaymentAttachment `json:"attachments,omitempty"`
+	CreatedAt            time.Time            `json:"created_at"`
+	UpdatedAt            time.Time            `json:"updated_at"`
 }
 
 // ToResponse converts PaymentHistory to PaymentHistoryResponse
@@ -67,17 +93,66 @@ func (p *PaymentHistory) ToResponse() PaymentHistoryResponse {
 		ID:                 p.ID,
 		AdminID:            p.AdminID,
 		Amount:             p.Amount,
+		Currency:           p.Currency,
 		PaymentDate:        p.PaymentDate,
 		PaymentMethod:      p.PaymentMethod,
 		TransactionID:      p.TransactionID,
 		SubscriptionTierID: p.SubscriptionTierID,
 		PeriodStart:        p.PeriodStart,
 		PeriodEnd:          p.PeriodEnd,
+		BillingInterval:    p.BillingInterval,
+		Months:             p.Months,
+		ProratedAmount:     p.ProratedAmount,
 		Status:             p.Status,
 		Notes:              p.Notes,
+		NeedsReview:        p.NeedsReview,
+		ReviewReason:       p.ReviewReason,
+		CouponRedemptionID: p.CouponRedemptionID,
+		DiscountAmount:     p.DiscountAmount,
+		RefundedAmount:     p.RefundedAmount,
+		IsTopUp:            p.IsTopUp,
 		VerifiedBy:         p.VerifiedBy,
 		VerifiedAt:         p.VerifiedAt,
 		CreatedAt:          p.CreatedAt,
 		UpdatedAt:          p.UpdatedAt,
 	}
 }
+
+// TierChangeQuote represents the price of moving an admin to another subscription tier
+type TierChangeQuote struct {
+	CurrentTierID   *int       `json:"current_tier_id"`
+	NewTierID       int        `json:"new_tier_id"`
+	NewTierName     string     `json:"new_tier_name"`
+	MonthlyPrice    int64      `json:"monthly_price"`
+	BillingInterval string     `json:"billing_interval"`
+	IntervalPrice   int64      `json:"interval_price"`
+	ProratedAmount  int64      `json:"prorated_amount"`
+	Currency        string     `json:"currency"`
+	RemainingDays   int        `json:"remaining_days"`
+	CurrentPeriodTo *time.Time `json:"current_period_to"`
+}
+
+// SubscriptionRenewal is the change to an admin's subscription that a payment pays for. It
+// is applied in the transaction recording the payment, so a billed payment always extends
+// the subscription. Without a PeriodEnd the paid Months are stacked on the admin's current
+// period, and PeriodStart and PeriodEnd are set to the period paid for.
+type SubscriptionRenewal struct {
+	AdminID            int
+	SubscriptionTierID *int
+	Months             int
+	PeriodStart        *time.Time
+	PeriodEnd          *time.Time
+	BillingInterval    string // Empty keeps the admin's interval
+	CouponRedemptionID *int   // Redemption whose discounted months the payment uses up
+	CouponMonths       int
+}
+
+// PaymentFilter holds the payment filters besides the shared list filters, for payment
+// listings and exports
+type PaymentFilter struct {
+	PaymentMethod string
+	Currency      string
+	From          *time.Time
+	To            *time.Time
+	NeedsReview   bool // Only payments flagged for review
+}
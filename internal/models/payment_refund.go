@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Payment refund types
+const (
+	PaymentRefundTypeRefund   = "refund"   // Money was returned, possibly partially
+	PaymentRefundTypeReversal = "reversal" // The payment was verified by mistake
+)
+
+// PaymentRefund represents money returned for a verified payment, or the reversal of one.
+// The subscription time it paid for is taken back in proportion to the amount.
+type PaymentRefund struct {
+	ID             int        `json:"id"`
+	PaymentID      int        `json:"payment_id"`
+	AdminID        int        `json:"admin_id"`
+	Type           string     `json:"type"`   // refund, reversal
+	Amount         int64      `json:"amount"` // Minor units of Currency
+	Currency       string     `json:"currency"`
+	Reason         string     `json:"reason"`
+	RemovedSeconds int64      `json:"removed_seconds"`
+	NewExpiresAt   *time.Time `json:"new_expires_at"`
+	CreatedBy      *int       `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// PaymentRefundRequest represents the request to refund a verified payment
+type PaymentRefundRequest struct {
+	Amount *int64 `json:"amount" validate:"omitempty,min=1"` // Minor units, defaults to everything not refunded yet
+	Reason string `json:"reason" validate:"required"`
+}
+
+// PaymentReversalRequest represents the request to reverse a payment verified by mistake
+type PaymentReversalRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// PaymentAuditLog represents one change in the life of a payment
+type PaymentAuditLog struct {
+	ID        int       `json:"id"`
+	PaymentID int       `json:"payment_id"`
+	AdminID   int       `json:"admin_id"`
+	Action    string    `json:"action"` // recorded, verified, rejected, refunded, reversed
+	ActorID   *int      `json:"actor_id"`
+	Amount    int64     `json:"amount"`
+	Currency  string    `json:"currency"`
+	Details   string    `json:"details"`
+	CreatedAt time.Time `json:"created_at"`
+}
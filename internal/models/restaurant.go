@@ -4,7 +4,8 @@ import (
 	"time"
 )
 
-// Restaurant model represents the restaurant entity
+// Restaurant model represents the restaurant entity: the profile shared by all branches
+// of an admin. Each location is a Branch.
 type Restaurant struct {
 	ID          int         `json:"id"`
 	AdminID     int         `json:"admin_id"`
@@ -19,7 +20,7 @@ type Restaurant struct {
 type Contacts struct {
 	Phone    string `json:"phone"`
 	Gmail    string `json:"gmail"`
-	Location string `json:"location"`
+	Location string `json:"location"` // Free text, superseded by the branch addresses
 }
 
 // SocialMedia represents social media links for a restaurant
@@ -47,13 +48,15 @@ type RestaurantUpdateRequest struct {
 
 // RestaurantResponse represents the response for restaurant
 type RestaurantResponse struct {
-	ID          int         `json:"id"`
-	AdminID     int         `json:"admin_id"`
-	Text        string      `json:"text"`
-	Contacts    Contacts    `json:"contacts"`
-	SocialMedia SocialMedia `json:"social_media"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+	ID            int         `json:"id"`
+	AdminID       int         `json:"admin_id"`
+	Text          string      `json:"text"`
+	Contacts      Contacts    `json:"contacts"`
+	SocialMedia   SocialMedia `json:"social_media"`
+	IsOpenNow     *bool       `json:"is_open_now,omitempty"`     // Any branch open, public responses only
+	NextOpeningAt *time.Time  `json:"next_opening_at,omitempty"` // First branch opening while all are closed
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
 }
 
 // ToResponse converts Restaurant model to RestaurantResponse
@@ -4,46 +4,84 @@ import (
 	"time"
 )
 
+// Billing interval constants
+const (
+	BillingIntervalMonthly   = "monthly"
+	BillingIntervalQuarterly = "quarterly"
+	BillingIntervalYearly    = "yearly"
+)
+
+// BillingIntervalMonths maps each billing interval to its length in months
+var BillingIntervalMonths = map[string]int{
+	BillingIntervalMonthly:   1,
+	BillingIntervalQuarterly: 3,
+	BillingIntervalYearly:    12,
+}
+
 // SubscriptionTier represents a pricing tier for admin subscriptions
 type SubscriptionTier struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	MinUsers    int       `json:"min_users"`
-	MaxUsers    *int      `json:"max_users"` // Pointer to allow NULL for unlimited users
-	Price       float64   `json:"price"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int                     `json:"id"`
+	Name        string                  `json:"name"`
+	MinUsers    int                     `json:"min_users"`
+	MaxUsers    *int                    `json:"max_users"` // Pointer to allow NULL for unlimited users
+	Price       int64                   `json:"price"`     // Minor units of Currency
+	Currency    string                  `json:"currency"`
+	TrialDays   int                     `json:"trial_days"`
+	Description string                  `json:"description"`
+	Prices      []SubscriptionTierPrice `json:"prices,omitempty"` // Interval-specific prices
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+// SubscriptionTierPrice represents the price of a tier for a billing interval
+type SubscriptionTierPrice struct {
+	ID                 int       `json:"id"`
+	SubscriptionTierID int       `json:"subscription_tier_id"`
+	BillingInterval    string    `json:"billing_interval"`
+	Price              int64     `json:"price"` // Minor units of the tier currency
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
 
 // SubscriptionTierCreateRequest represents the request to create a subscription tier
 type SubscriptionTierCreateRequest struct {
-	Name        string  `json:"name" validate:"required"`
-	MinUsers    int     `json:"min_users" validate:"required,min=0"`
-	MaxUsers    *int    `json:"max_users"`
-	Price       float64 `json:"price" validate:"required,min=0"`
-	Description string  `json:"description"`
+	Name        string `json:"name" validate:"required"`
+	MinUsers    int    `json:"min_users" validate:"min=0"`
+	MaxUsers    *int   `json:"max_users"`
+	Price       int64  `json:"price" validate:"min=0"` // Minor units of Currency, zero for a free tier
+	Currency    string `json:"currency" validate:"omitempty,len=3"`
+	TrialDays   int    `json:"trial_days" validate:"min=0"`
+	Description string `json:"description"`
+	// Prices per billing interval, e.g. {"yearly": 10000}
+	IntervalPrices map[string]*int64 `json:"interval_prices"`
 }
 
 // SubscriptionTierUpdateRequest represents the request to update a subscription tier
 type SubscriptionTierUpdateRequest struct {
-	Name        string  `json:"name"`
-	MinUsers    int     `json:"min_users" validate:"min=0"`
-	MaxUsers    *int    `json:"max_users"`
-	Price       float64 `json:"price" validate:"min=0"`
-	Description string  `json:"description"`
+	Name        string `json:"name"`
+	MinUsers    int    `json:"min_users" validate:"min=0"`
+	MaxUsers    *int   `json:"max_users"`
+	Price       int64  `json:"price" validate:"min=0"` // Minor units of Currency
+	Currency    string `json:"currency" validate:"omitempty,len=3"`
+	TrialDays   *int   `json:"trial_days" validate:"omitempty,min=0"`
+	Description string `json:"description"`
+	// Prices per billing interval; a null price removes the interval price
+	IntervalPrices map[string]*int64 `json:"interval_prices"`
 }
 
 // SubscriptionTierResponse represents the response for a subscription tier
 type SubscriptionTierResponse struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	MinUsers    int       `json:"min_users"`
-	MaxUsers    *int      `json:"max_users"`
-	Price       float64   `json:"price"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int                     `json:"id"`
+	Name        string                  `json:"name"`
+	MinUsers    int                     `json:"min_users"`
+	MaxUsers    *int                    `json:"max_users"`
+	Price       int64                   `json:"price"`
+	Currency    string                  `json:"currency"`
+	TrialDays   int                     `json:"trial_days"`
+	Description string                  `json:"description"`
+	Prices      []SubscriptionTierPrice `json:"prices"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
 }
 
 // ToResponse converts SubscriptionTier to SubscriptionTierResponse
@@ -54,8 +92,28 @@ func (s *SubscriptionTier) ToResponse() SubscriptionTierResponse {
 		MinUsers:    s.MinUsers,
 		MaxUsers:    s.MaxUsers,
 		Price:       s.Price,
+		Currency:    s.Currency,
+		TrialDays:   s.TrialDays,
 		Description: s.Description,
+		Prices:      s.Prices,
 		CreatedAt:   s.CreatedAt,
 		UpdatedAt:   s.UpdatedAt,
 	}
 }
+
+// PriceFor returns the price of one billing interval together with its length in months.
+// Intervals without their own price cost the monthly price times the number of months.
+func (s *SubscriptionTier) PriceFor(interval string) (int64, int) {
+	months, ok := BillingIntervalMonths[interval]
+	if !ok {
+		return s.Price, 1
+	}
+
+	for _, price := range s.Prices {
+		if price.BillingInterval == interval {
+			return price.Price, months
+		}
+	}
+
+	return s.Price * int64(months), months
+}
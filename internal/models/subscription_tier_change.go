@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// SubscriptionTierChange represents a logged change of an admin's subscription tier
+type SubscriptionTierChange struct {
+	ID          int       `json:"id"`
+	AdminID     int       `json:"admin_id"`
+	OldTierID   *int      `json:"old_tier_id"`
+	NewTierID   *int      `json:"new_tier_id"`
+	UserCount   int       `json:"user_count"`
+	Reason      string    `json:"reason"` // usage, manual
+	Status      string    `json:"status"` // scheduled, applied, cancelled
+	EffectiveAt time.Time `json:"effective_at"`
+	ChangedBy   *int      `json:"changed_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SubscriptionTierPinRequest represents the request to pin an admin to a tier
+type SubscriptionTierPinRequest struct {
+	SubscriptionTierID *int `json:"subscription_tier_id"`
+	Pinned             bool `json:"pinned"`
+}
+
+// SubscriptionTierChangeResponse represents the response for a tier change
+type SubscriptionTierChangeResponse struct {
+	ID          int       `json:"id"`
+	AdminID     int       `json:"admin_id"`
+	OldTierID   *int      `json:"old_tier_id"`
+	NewTierID   *int      `json:"new_tier_id"`
+	UserCount   int       `json:"user_count"`
+	Reason      string    `json:"reason"`
+	Status      string    `json:"status"`
+	EffectiveAt time.Time `json:"effective_at"`
+	ChangedBy   *int      `json:"changed_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ToResponse converts SubscriptionTierChange to SubscriptionTierChangeResponse
+func (c *SubscriptionTierChange) ToResponse() SubscriptionTierChangeResponse {
+	return SubscriptionTierChangeResponse{
+		ID:          c.ID,
+		AdminID:     c.AdminID,
+		OldTierID:   c.OldTierID,
+		NewTierID:   c.NewTierID,
+		UserCount:   c.UserCount,
+		Reason:      c.Reason,
+		Status:      c.Status,
+		EffectiveAt: c.EffectiveAt,
+		ChangedBy:   c.ChangedBy,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+// TelegramInlineButton is a button under a Telegram message that sends CallbackData
+// back to the bot's webhook when pressed
+type TelegramInlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// TelegramUpdate is an update Telegram sends to a bot's webhook. Only button presses
+// are used.
+type TelegramUpdate struct {
+	UpdateID      int                    `json:"update_id"`
+	CallbackQuery *TelegramCallbackQuery `json:"callback_query"`
+}
+
+// TelegramCallbackQuery is a press of an inline button
+type TelegramCallbackQuery struct {
+	ID      string           `json:"id"`
+	From    TelegramUser     `json:"from"`
+	Message *TelegramMessage `json:"message"`
+	Data    string           `json:"data"`
+}
+
+// TelegramUser is a Telegram user
+type TelegramUser struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	Username  string `json:"username"`
+}
+
+// TelegramMessage is a Telegram message
+type TelegramMessage struct {
+	MessageID int          `json:"message_id"`
+	Chat      TelegramChat `json:"chat"`
+	Text      string       `json:"text"`
+}
+
+// TelegramChat is a Telegram chat
+type TelegramChat struct {
+	ID int64 `json:"id"`
+}
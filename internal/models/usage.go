@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// ActiveUsers represents a daily or monthly active-user rollup for an admin
+type ActiveUsers struct {
+	AdminID     int       `json:"admin_id"`
+	Period      string    `json:"period"` // day, month
+	PeriodStart time.Time `json:"period_start"`
+	ActiveUsers int       `json:"active_users"`
+}
+
+// UsageResponse represents the active-user rollups of an admin
+type UsageResponse struct {
+	AdminID int            `json:"admin_id"`
+	Users   int            `json:"users"` // Monthly active users used for billing
+	Daily   []*ActiveUsers `json:"daily"`
+	Monthly []*ActiveUsers `json:"monthly"`
+}
@@ -9,6 +9,7 @@ import (
 	"mobilka/internal/models"
 	"mobilka/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -74,9 +75,11 @@ func (r *AdminRepository) Create(ctx context.Context, admin *models.Admin) error
             user_name, email, company_name, system_id, system_token, 
             system_token_updated_time, sms_token, sms_token_updated_time, sms_email, 
             sms_password, sms_message, payment_username, payment_password, bot_token,
-            bot_chat_id, delivery
+            bot_chat_id, delivery, subscription_tier_id, subscription_status,
+            subscription_expires_at, is_access_restricted, billing_currency, billing_interval
         ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+            $17, $18, $19, $20, $21, $22
         ) RETURNING id, created_at, updated_at
     `
 
@@ -97,6 +100,12 @@ func (r *AdminRepository) Create(ctx context.Context, admin *models.Admin) error
 		admin.BotToken,
 		admin.BotChatID,
 		admin.Delivery,
+		admin.SubscriptionTierID,
+		admin.SubscriptionStatus,
+		admin.SubscriptionExpiresAt,
+		admin.IsAccessRestricted,
+		admin.BillingCurrency,
+		admin.BillingInterval,
 	).Scan(
 		&admin.ID,
 		&admin.CreatedAt,
@@ -118,7 +127,7 @@ func (r *AdminRepository) GetByID(ctx context.Context, id int) (*models.Admin, e
 			id, user_name, email, company_name, system_id, system_token, 
 			system_token_updated_time, sms_token, sms_token_updated_time, sms_email, 
 			sms_password, sms_message, payment_username, payment_password, bot_token,
-			bot_chat_id, delivery, users, created_at, updated_at
+			bot_chat_id, delivery, users, billing_currency, billing_interval, created_at, updated_at
 		FROM admin
 		WHERE id = $1
 	`
@@ -143,6 +152,8 @@ func (r *AdminRepository) GetByID(ctx context.Context, id int) (*models.Admin, e
 		&admin.BotChatID,
 		&admin.Delivery,
 		&admin.Users,
+		&admin.BillingCurrency,
+		&admin.BillingInterval,
 		&admin.CreatedAt,
 		&admin.UpdatedAt,
 	)
@@ -157,60 +168,65 @@ func (r *AdminRepository) GetByID(ctx context.Context, id int) (*models.Admin, e
 	return &admin, nil
 }
 
-// GetAll retrieves all admins
-func (r *AdminRepository) GetAll(ctx context.Context) ([]*models.Admin, error) {
-	query := `
-		SELECT 
-			id, user_name, email, company_name, system_id, system_token, 
-			system_token_updated_time, sms_token, sms_token_updated_time, sms_email, 
-			sms_password, sms_message, payment_username, payment_password, bot_token,
-			bot_chat_id, delivery, users, created_at, updated_at
-		FROM admin
-		ORDER BY id
-	`
+// adminList describes how admin lists are filtered and sorted
+var adminList = listSpec{
+	table: "admin",
+	columns: `id, user_name, email, company_name, system_id, system_token,
+		system_token_updated_time, sms_token, sms_token_updated_time, sms_email,
+		sms_password, sms_message, payment_username, payment_password, bot_token,
+		bot_chat_id, delivery, users, billing_currency, billing_interval, created_at, updated_at`,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":           {"id", "BIGINT"},
+		"created_at":   {"created_at", "TIMESTAMPTZ"},
+		"company_name": {"company_name", "TEXT"},
+		"users":        {"users", "BIGINT"},
+	},
+	defaultSort:   "id",
+	statusColumn:  "subscription_status",
+	dateColumn:    "created_at",
+	searchColumns: []string{"company_name", "user_name", "email"},
+}
 
-	rows, err := r.db.Query(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var admins []*models.Admin
-	for rows.Next() {
-		var admin models.Admin
-		err := rows.Scan(
-			&admin.ID,
-			&admin.UserName,
-			&admin.Email,
-			&admin.CompanyName,
-			&admin.SystemID,
-			&admin.SystemToken,
-			&admin.SystemTokenUpdatedTime,
-			&admin.SmsToken,
-			&admin.SmsTokenUpdatedTime,
-			&admin.SmsEmail,
-			&admin.SmsPassword,
-			&admin.SmsMessage,
-			&admin.PaymentUsername,
-			&admin.PaymentPassword,
-			&admin.BotToken,
-			&admin.BotChatID,
-			&admin.Delivery,
-			&admin.Users,
-			&admin.CreatedAt,
-			&admin.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		admins = append(admins, &admin)
+// scanAdmin scans an admin row selected with the adminList columns, followed by any extra
+// columns into extra
+func scanAdmin(row pgx.Row, extra ...interface{}) (*models.Admin, error) {
+	var admin models.Admin
+	dest := []interface{}{
+		&admin.ID,
+		&admin.UserName,
+		&admin.Email,
+		&admin.CompanyName,
+		&admin.SystemID,
+		&admin.SystemToken,
+		&admin.SystemTokenUpdatedTime,
+		&admin.SmsToken,
+		&admin.SmsTokenUpdatedTime,
+		&admin.SmsEmail,
+		&admin.SmsPassword,
+		&admin.SmsMessage,
+		&admin.PaymentUsername,
+		&admin.PaymentPassword,
+		&admin.BotToken,
+		&admin.BotChatID,
+		&admin.Delivery,
+		&admin.Users,
+		&admin.BillingCurrency,
+		&admin.BillingInterval,
+		&admin.CreatedAt,
+		&admin.UpdatedAt,
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
-	return admins, nil
+	return &admin, nil
+}
+
+// List retrieves one page of admins
+func (r *AdminRepository) List(ctx context.Context, params models.ListParams) ([]*models.Admin, *models.ListMeta, error) {
+	return queryList(ctx, r.db, adminList, params, nil, scanAdmin)
 }
 
 // GetByEmail retrieves an admin by email
@@ -220,7 +236,7 @@ func (r *AdminRepository) GetByEmail(ctx context.Context, email string) (*models
 			id, user_name, email, company_name, system_id, system_token, 
 			system_token_updated_time, sms_token, sms_token_updated_time, sms_email, 
 			sms_password, sms_message, payment_username, payment_password, bot_token,
-			bot_chat_id, delivery, users, created_at, updated_at
+			bot_chat_id, delivery, users, billing_currency, billing_interval, created_at, updated_at
 		FROM admin
 		WHERE email = $1
 	`
@@ -245,6 +261,8 @@ func (r *AdminRepository) GetByEmail(ctx context.Context, email string) (*models
 		&admin.BotChatID,
 		&admin.Delivery,
 		&admin.Users,
+		&admin.BillingCurrency,
+		&admin.BillingInterval,
 		&admin.CreatedAt,
 		&admin.UpdatedAt,
 	)
@@ -266,7 +284,7 @@ func (r *AdminRepository) GetByUserNameAndSystemID(ctx context.Context, userName
 			id, user_name, email, company_name, system_id, system_token, 
 			system_token_updated_time, sms_token, sms_token_updated_time, sms_email, 
 			sms_password, sms_message, payment_username, payment_password, bot_token,
-			bot_chat_id, delivery, users, created_at, updated_at
+			bot_chat_id, delivery, users, billing_currency, billing_interval, created_at, updated_at
 		FROM admin
 		WHERE user_name = $1 AND system_id = $2
 	`
@@ -291,6 +309,8 @@ func (r *AdminRepository) GetByUserNameAndSystemID(ctx context.Context, userName
 		&admin.BotChatID,
 		&admin.Delivery,
 		&admin.Users,
+		&admin.BillingCurrency,
+		&admin.BillingInterval,
 		&admin.CreatedAt,
 		&admin.UpdatedAt,
 	)
@@ -312,7 +332,7 @@ func (r *AdminRepository) GetByCredentials(ctx context.Context, userName, system
 			id, user_name, email, company_name, system_id, system_token, 
 			system_token_updated_time, sms_token, sms_token_updated_time, sms_email, 
 			sms_password, sms_message, payment_username, payment_password, bot_token,
-			bot_chat_id, delivery, users, created_at, updated_at
+			bot_chat_id, delivery, users, billing_currency, billing_interval, created_at, updated_at
 		FROM admin
 		WHERE user_name = $1 AND system_id = $2 AND email = $3
 	`
@@ -337,6 +357,8 @@ func (r *AdminRepository) GetByCredentials(ctx context.Context, userName, system
 		&admin.BotChatID,
 		&admin.Delivery,
 		&admin.Users,
+		&admin.BillingCurrency,
+		&admin.BillingInterval,
 		&admin.CreatedAt,
 		&admin.UpdatedAt,
 	)
@@ -413,7 +435,8 @@ func (r *AdminRepository) Update(ctx context.Context, id int, admin *models.Admi
             payment_password = $10,
             bot_token = $11,
             bot_chat_id = $12,
-            delivery = $13
+            delivery = $13,
+            billing_currency = $14
         WHERE id = $1
         RETURNING updated_at
     `
@@ -432,6 +455,7 @@ func (r *AdminRepository) Update(ctx context.Context, id int, admin *models.Admi
 		admin.BotToken,
 		admin.BotChatID,
 		admin.Delivery,
+		admin.BillingCurrency,
 	).Scan(&admin.UpdatedAt)
 
 	if err != nil {
@@ -473,24 +497,3 @@ func (r *AdminRepository) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
-
-// IncrementUsersCount increments the users count for an admin
-func (r *AdminRepository) IncrementUsersCount(ctx context.Context, id int) error {
-	query := `
-		UPDATE admin
-		SET users = users + 1
-		WHERE id = $1
-		RETURNING users
-	`
-
-	var users int
-	err := r.db.QueryRow(ctx, query, id).Scan(&users)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return utils.ErrUserNotFound
-		}
-		return err
-	}
-
-	return nil
-}
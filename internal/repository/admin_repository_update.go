@@ -128,12 +128,12 @@ func (r *AdminRepository) GetActiveCount(ctx context.Context) (int, error) {
 	return count, nil
 }
 
-// ExpireSubscriptions expires all subscriptions that have passed their expiration date
+// ExpireSubscriptions expires all subscriptions and trials that have passed their expiration date
 func (r *AdminRepository) ExpireSubscriptions(ctx context.Context) (int, error) {
 	query := `
 		UPDATE admin
 		SET subscription_status = 'expired', is_access_restricted = true
-		WHERE subscription_status = 'active' 
+		WHERE subscription_status IN ('active', 'trial')
 		  AND subscription_expires_at IS NOT NULL
 		  AND subscription_expires_at < CURRENT_TIMESTAMP
 	`
@@ -154,8 +154,9 @@ func (r *AdminRepository) GetByIDWithSubscriptionInfo(ctx context.Context, id in
 			a.system_token_updated_time, a.sms_token, a.sms_token_updated_time, a.sms_email, 
 			a.sms_password, a.sms_message, a.payment_username, a.payment_password, 
 			a.users, a.subscription_tier_id, a.subscription_status, a.subscription_expires_at,
-			a.is_access_restricted, a.created_at, a.updated_at,
-			st.id, st.name, st.min_users, st.max_users, st.price, st.description, 
+			a.is_access_restricted, a.pending_subscription_tier_id, a.pending_tier_effective_at,
+			a.subscription_tier_pinned, a.billing_currency, a.billing_interval, a.created_at, a.updated_at,
+			st.id, st.name, st.min_users, st.max_users, st.price, st.currency, st.trial_days, st.description,
 			st.created_at, st.updated_at
 		FROM admin a
 		LEFT JOIN subscription_tier st ON a.subscription_tier_id = st.id
@@ -166,11 +167,15 @@ func (r *AdminRepository) GetByIDWithSubscriptionInfo(ctx context.Context, id in
 	var subscriptionTier models.SubscriptionTier
 	var subscriptionTierID sql.NullInt32
 	var subscriptionExpiresAt sql.NullTime
+	var pendingTierID sql.NullInt32
+	var pendingTierEffectiveAt sql.NullTime
 	var tierID sql.NullInt32
 	var tierName sql.NullString
 	var tierMinUsers sql.NullInt32
 	var tierMaxUsers sql.NullInt32
-	var tierPrice sql.NullFloat64
+	var tierPrice sql.NullInt64
+	var tierCurrency sql.NullString
+	var tierTrialDays sql.NullInt32
 	var tierDescription sql.NullString
 	var tierCreatedAt sql.NullTime
 	var tierUpdatedAt sql.NullTime
@@ -195,6 +200,11 @@ func (r *AdminRepository) GetByIDWithSubscriptionInfo(ctx context.Context, id in
 		&admin.SubscriptionStatus,
 		&subscriptionExpiresAt,
 		&admin.IsAccessRestricted,
+		&pendingTierID,
+		&pendingTierEffectiveAt,
+		&admin.SubscriptionTierPinned,
+		&admin.BillingCurrency,
+		&admin.BillingInterval,
 		&admin.CreatedAt,
 		&admin.UpdatedAt,
 		&tierID,
@@ -202,6 +212,8 @@ func (r *AdminRepository) GetByIDWithSubscriptionInfo(ctx context.Context, id in
 		&tierMinUsers,
 		&tierMaxUsers,
 		&tierPrice,
+		&tierCurrency,
+		&tierTrialDays,
 		&tierDescription,
 		&tierCreatedAt,
 		&tierUpdatedAt,
@@ -223,6 +235,15 @@ func (r *AdminRepository) GetByIDWithSubscriptionInfo(ctx context.Context, id in
 		admin.SubscriptionExpiresAt = &subscriptionExpiresAt.Time
 	}
 
+	if pendingTierID.Valid {
+		val := int(pendingTierID.Int32)
+		admin.PendingTierID = &val
+	}
+
+	if pendingTierEffectiveAt.Valid {
+		admin.PendingTierEffectiveAt = &pendingTierEffectiveAt.Time
+	}
+
 	// If there's no subscription tier, return just the admin
 	if !tierID.Valid {
 		return &admin, nil, nil
@@ -245,7 +266,15 @@ func (r *AdminRepository) GetByIDWithSubscriptionInfo(ctx context.Context, id in
 	}
 
 	if tierPrice.Valid {
-		subscriptionTier.Price = tierPrice.Float64
+		subscriptionTier.Price = tierPrice.Int64
+	}
+
+	if tierCurrency.Valid {
+		subscriptionTier.Currency = tierCurrency.String
+	}
+
+	if tierTrialDays.Valid {
+		subscriptionTier.TrialDays = int(tierTrialDays.Int32)
 	}
 
 	if tierDescription.Valid {
@@ -282,3 +311,205 @@ func (r *AdminRepository) CheckAdminAccess(ctx context.Context, adminID int) (bo
 
 	return hasAccess, nil
 }
+
+// GetAllForTierRecalculation retrieves the usage and subscription fields of all admins
+func (r *AdminRepository) GetAllForTierRecalculation(ctx context.Context) ([]*models.Admin, error) {
+	query := `
+		SELECT
+			id, company_name, bot_token, bot_chat_id, users, subscription_tier_id,
+			subscription_status, subscription_expires_at, pending_subscription_tier_id,
+			pending_tier_effective_at, subscription_tier_pinned
+		FROM admin
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var admins []*models.Admin
+	for rows.Next() {
+		var admin models.Admin
+		var subscriptionTierID sql.NullInt32
+		var subscriptionExpiresAt sql.NullTime
+		var pendingTierID sql.NullInt32
+		var pendingTierEffectiveAt sql.NullTime
+
+		err := rows.Scan(
+			&admin.ID,
+			&admin.CompanyName,
+			&admin.BotToken,
+			&admin.BotChatID,
+			&admin.Users,
+			&subscriptionTierID,
+			&admin.SubscriptionStatus,
+			&subscriptionExpiresAt,
+			&pendingTierID,
+			&pendingTierEffectiveAt,
+			&admin.SubscriptionTierPinned,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if subscriptionTierID.Valid {
+			val := int(subscriptionTierID.Int32)
+			admin.SubscriptionTierID = &val
+		}
+
+		if subscriptionExpiresAt.Valid {
+			admin.SubscriptionExpiresAt = &subscriptionExpiresAt.Time
+		}
+
+		if pendingTierID.Valid {
+			val := int(pendingTierID.Int32)
+			admin.PendingTierID = &val
+		}
+
+		if pendingTierEffectiveAt.Valid {
+			admin.PendingTierEffectiveAt = &pendingTierEffectiveAt.Time
+		}
+
+		admins = append(admins, &admin)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return admins, nil
+}
+
+// SetPendingTier schedules a tier change for an admin, or clears it when tierID is nil
+func (r *AdminRepository) SetPendingTier(ctx context.Context, id int, tierID *int, effectiveAt *time.Time) error {
+	query := `
+		UPDATE admin
+		SET pending_subscription_tier_id = $2, pending_tier_effective_at = $3
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, id, tierID, effectiveAt)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateSubscriptionTier sets an admin's tier and pin flag and clears any scheduled change
+func (r *AdminRepository) UpdateSubscriptionTier(ctx context.Context, id int, tierID *int, pinned bool) error {
+	query := `
+		UPDATE admin
+		SET subscription_tier_id = $2,
+		    subscription_tier_pinned = $3,
+		    pending_subscription_tier_id = NULL,
+		    pending_tier_effective_at = NULL
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, id, tierID, pinned)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateBillingInterval changes the billing interval of an admin
+func (r *AdminRepository) UpdateBillingInterval(ctx context.Context, id int, interval string) error {
+	query := `UPDATE admin SET billing_interval = $2 WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, id, interval)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Stream calls fn for every admin matching the list filters of params and an admin filter,
+// together with the name of their tier. Rows are read one at a time so exports of any size
+// don't have to fit in memory.
+func (r *AdminRepository) Stream(ctx context.Context, params models.ListParams, filter models.AdminFilter, fn func(admin *models.Admin, tierName string) error) error {
+	q := &listQuery{}
+	if filter.SubscriptionTierID != nil {
+		q.add("subscription_tier_id = $%d", *filter.SubscriptionTierID)
+	}
+	if err := adminList.filters(q, params); err != nil {
+		return err
+	}
+
+	query := `
+		SELECT
+			id, user_name, email, company_name, delivery, users, subscription_tier_id,
+			COALESCE((SELECT st.name FROM subscription_tier st WHERE st.id = admin.subscription_tier_id), ''),
+			subscription_status, subscription_expires_at, is_access_restricted,
+			billing_currency, billing_interval, created_at, updated_at
+		FROM admin
+		` + q.where() + `
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, q.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var admin models.Admin
+		var tierName string
+		var subscriptionTierID sql.NullInt32
+		var subscriptionExpiresAt sql.NullTime
+
+		err := rows.Scan(
+			&admin.ID,
+			&admin.UserName,
+			&admin.Email,
+			&admin.CompanyName,
+			&admin.Delivery,
+			&admin.Users,
+			&subscriptionTierID,
+			&tierName,
+			&admin.SubscriptionStatus,
+			&subscriptionExpiresAt,
+			&admin.IsAccessRestricted,
+			&admin.BillingCurrency,
+			&admin.BillingInterval,
+			&admin.CreatedAt,
+			&admin.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		if subscriptionTierID.Valid {
+			val := int(subscriptionTierID.Int32)
+			admin.SubscriptionTierID = &val
+		}
+
+		if subscriptionExpiresAt.Valid {
+			admin.SubscriptionExpiresAt = &subscriptionExpiresAt.Time
+		}
+
+		if err := fn(&admin, tierName); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,389 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mobilka/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// convertedPaymentsCTE adds to every payment the rate converting its currency to the report
+// currency $1, as of the payment date. The inverse rate is used when only that one exists,
+// and the rate is NULL when there is neither.
+const convertedPaymentsCTE = `
+	converted_payment AS (
+		SELECT p.*,
+			CASE WHEN p.currency = $1 THEN 1 ELSE COALESCE(
+				(SELECT er.rate FROM exchange_rate er
+				 WHERE er.base_currency = p.currency AND er.quote_currency = $1 AND er.effective_date <= p.payment_date
+				 ORDER BY er.effective_date DESC LIMIT 1),
+				(SELECT 1 / er.rate FROM exchange_rate er
+				 WHERE er.base_currency = $1 AND er.quote_currency = p.currency AND er.effective_date <= p.payment_date
+				 ORDER BY er.effective_date DESC LIMIT 1)
+			) END AS rate
+		FROM payment_history p
+	)`
+
+// revenuePeriods maps the supported revenue periods to their date_trunc unit
+var revenuePeriods = map[string]string{
+	"day":   "day",
+	"week":  "week",
+	"month": "month",
+}
+
+// AnalyticsRepository handles reporting queries over admins and payments
+type AnalyticsRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAnalyticsRepository creates a new analytics repository
+func NewAnalyticsRepository(db *pgxpool.Pool) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		db: db,
+	}
+}
+
+// CountAdminsByStatus counts admins per subscription status, together with the restricted ones
+func (r *AnalyticsRepository) CountAdminsByStatus(ctx context.Context) (map[string]int, int, error) {
+	query := `
+		SELECT subscription_status, COUNT(*), COUNT(*) FILTER (WHERE is_access_restricted)
+		FROM admin
+		GROUP BY subscription_status
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	restricted := 0
+	for rows.Next() {
+		var status string
+		var count, restrictedCount int
+		if err := rows.Scan(&status, &count, &restrictedCount); err != nil {
+			return nil, 0, err
+		}
+		counts[status] = count
+		restricted += restrictedCount
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return counts, restricted, nil
+}
+
+// CountPendingPayments counts the payments waiting for verification
+func (r *AnalyticsRepository) CountPendingPayments(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM payment_history WHERE status = 'pending'`
+
+	var count int
+	err := r.db.QueryRow(ctx, query).Scan(&count)
+	return count, err
+}
+
+// GetMRRByTier calculates the monthly recurring revenue per tier at a point in time from the
+// verified payments whose period covers it. Upgrade proration is not recurring and is left out,
+// refunded amounts are no revenue.
+func (r *AnalyticsRepository) GetMRRByTier(ctx context.Context, currency string, at time.Time) ([]*models.TierMRR, int, error) {
+	query := `
+		WITH ` + convertedPaymentsCTE + `
+		SELECT cp.subscription_tier_id, COALESCE(st.name, ''), COUNT(DISTINCT cp.admin_id),
+			COALESCE(SUM(ROUND(GREATEST(cp.amount - cp.prorated_amount - cp.refunded_amount, 0) * cp.rate / GREATEST(cp.months, 1))), 0)::BIGINT,
+			COUNT(*) FILTER (WHERE cp.rate IS NULL)
+		FROM converted_payment cp
+		LEFT JOIN subscription_tier st ON st.id = cp.subscription_tier_id
+		WHERE cp.status = 'verified' AND cp.period_start <= $2 AND cp.period_end > $2
+		GROUP BY cp.subscription_tier_id, st.name, st.min_users
+		ORDER BY st.min_users NULLS LAST
+	`
+
+	rows, err := r.db.Query(ctx, query, currency, at)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	tiers := []*models.TierMRR{}
+	unconverted := 0
+	for rows.Next() {
+		var tier models.TierMRR
+		var tierID sql.NullInt32
+		var tierUnconverted int
+		if err := rows.Scan(&tierID, &tier.TierName, &tier.Admins, &tier.MRR, &tierUnconverted); err != nil {
+			return nil, 0, err
+		}
+		if tierID.Valid {
+			val := int(tierID.Int32)
+			tier.SubscriptionTierID = &val
+		}
+		unconverted += tierUnconverted
+		tiers = append(tiers, &tier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return tiers, unconverted, nil
+}
+
+// GetAdminMovements counts per month within a date range the admins who signed up, paid,
+// paid for the first time, stopped paying and resumed paying after a gap. An admin pays in
+// a month when a verified payment period overlaps it.
+func (r *AnalyticsRepository) GetAdminMovements(ctx context.Context, from, to time.Time) ([]*models.AdminMovement, error) {
+	query := `
+		WITH months AS (
+			SELECT generate_series(date_trunc('month', $1::timestamptz), date_trunc('month', $2::timestamptz - interval '1 second'), interval '1 month') AS month
+		),
+		paid AS (
+			SELECT DISTINCT p.admin_id, m.month
+			FROM payment_history p
+			CROSS JOIN LATERAL generate_series(date_trunc('month', p.period_start), p.period_end - interval '1 second', interval '1 month') AS m(month)
+			WHERE p.status = 'verified' AND p.period_start IS NOT NULL AND p.period_end > p.period_start
+		),
+		first_paid AS (
+			SELECT admin_id, MIN(month) AS month FROM paid GROUP BY admin_id
+		)
+		SELECT m.month,
+			(SELECT COUNT(*) FROM admin a WHERE date_trunc('month', a.created_at) = m.month),
+			(SELECT COUNT(*) FROM paid cur WHERE cur.month = m.month),
+			(SELECT COUNT(*) FROM first_paid f WHERE f.month = m.month),
+			(SELECT COUNT(*) FROM paid prev
+			 WHERE prev.month = m.month - interval '1 month'
+			   AND NOT EXISTS (SELECT 1 FROM paid cur WHERE cur.admin_id = prev.admin_id AND cur.month = m.month)),
+			(SELECT COUNT(*) FROM paid cur
+			 WHERE cur.month = m.month
+			   AND NOT EXISTS (SELECT 1 FROM paid prev WHERE prev.admin_id = cur.admin_id AND prev.month = m.month - interval '1 month')
+			   AND EXISTS (SELECT 1 FROM paid earlier WHERE earlier.admin_id = cur.admin_id AND earlier.month < m.month - interval '1 month'))
+		FROM months m
+		ORDER BY m.month
+	`
+
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []*models.AdminMovement{}
+	for rows.Next() {
+		var movement models.AdminMovement
+		err := rows.Scan(
+			&movement.Month,
+			&movement.Signups,
+			&movement.Paying,
+			&movement.New,
+			&movement.Churned,
+			&movement.Reactivated,
+		)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, &movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// GetVerifiedRevenue sums the verified payments, net of partial refunds, per day, week or
+// month of verification within a date range. Periods paid from the prepaid balance are left
+// out, the money for them was counted when the balance was topped up.
+func (r *AnalyticsRepository) GetVerifiedRevenue(ctx context.Context, filter models.AnalyticsFilter, period string) ([]*models.RevenuePeriod, error) {
+	unit, ok := revenuePeriods[period]
+	if !ok {
+		return nil, fmt.Errorf("unsupported revenue period %q", period)
+	}
+
+	query := `
+		WITH ` + convertedPaymentsCTE + `
+		SELECT date_trunc('` + unit + `', verified_at) AS period_start, COUNT(*),
+			COALESCE(SUM(ROUND((amount - refunded_amount) * rate)), 0)::BIGINT,
+			COUNT(*) FILTER (WHERE rate IS NULL)
+		FROM converted_payment
+		WHERE status = 'verified' AND payment_method <> 'balance' AND verified_at >= $2 AND verified_at < $3
+		GROUP BY period_start
+		ORDER BY period_start
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.Currency, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	periods := []*models.RevenuePeriod{}
+	for rows.Next() {
+		var revenue models.RevenuePeriod
+		if err := rows.Scan(&revenue.PeriodStart, &revenue.Payments, &revenue.Revenue, &revenue.UnconvertedPayments); err != nil {
+			return nil, err
+		}
+		periods = append(periods, &revenue)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+// GetPaymentMethodStats breaks down the payments made within a date range by payment method,
+// leaving out periods paid from the prepaid balance
+func (r *AnalyticsRepository) GetPaymentMethodStats(ctx context.Context, filter models.AnalyticsFilter) ([]*models.PaymentMethodStats, error) {
+	query := `
+		WITH ` + convertedPaymentsCTE + `
+		SELECT payment_method, COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'verified'),
+			COUNT(*) FILTER (WHERE status = 'rejected'),
+			COUNT(*) FILTER (WHERE status = 'pending'),
+			COALESCE(SUM(ROUND((amount - refunded_amount) * rate)) FILTER (WHERE status = 'verified'), 0)::BIGINT,
+			COUNT(*) FILTER (WHERE status = 'verified' AND rate IS NULL)
+		FROM converted_payment
+		WHERE payment_method <> 'balance' AND payment_date >= $2 AND payment_date < $3
+		GROUP BY payment_method
+		ORDER BY COUNT(*) DESC, payment_method
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.Currency, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	methods := []*models.PaymentMethodStats{}
+	for rows.Next() {
+		var stats models.PaymentMethodStats
+		err := rows.Scan(
+			&stats.PaymentMethod,
+			&stats.Payments,
+			&stats.Verified,
+			&stats.Rejected,
+			&stats.Pending,
+			&stats.VerifiedRevenue,
+			&stats.UnconvertedPayments,
+		)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, &stats)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return methods, nil
+}
+
+// GetVerificationLatency measures the time from recording to verifying or rejecting the
+// payments processed within a date range, and how long the current backlog has waited
+func (r *AnalyticsRepository) GetVerificationLatency(ctx context.Context, from, to time.Time) (*models.VerificationLatency, error) {
+	query := `
+		WITH processed AS (
+			SELECT (EXTRACT(EPOCH FROM verified_at - created_at) / 3600)::FLOAT8 AS hours
+			FROM payment_history
+			WHERE status IN ('verified', 'rejected') AND payment_method <> 'balance'
+			  AND verified_at >= $1 AND verified_at < $2
+		),
+		pending AS (
+			SELECT (EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - created_at) / 3600)::FLOAT8 AS hours
+			FROM payment_history
+			WHERE status = 'pending'
+		)
+		SELECT
+			(SELECT COUNT(*) FROM processed),
+			(SELECT COALESCE(AVG(hours), 0)::FLOAT8 FROM processed),
+			(SELECT COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY hours), 0)::FLOAT8 FROM processed),
+			(SELECT COALESCE(MAX(hours), 0)::FLOAT8 FROM processed),
+			(SELECT COUNT(*) FROM pending),
+			(SELECT COALESCE(MAX(hours), 0)::FLOAT8 FROM pending)
+	`
+
+	var latency models.VerificationLatency
+	err := r.db.QueryRow(ctx, query, from, to).Scan(
+		&latency.Processed,
+		&latency.AverageHours,
+		&latency.MedianHours,
+		&latency.MaxHours,
+		&latency.Pending,
+		&latency.OldestPendingHours,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &latency, nil
+}
+
+// GetAtRiskAdmins retrieves admins whose subscription or trial ends within the given number
+// of days, and admins whose subscription expired within as many days
+func (r *AnalyticsRepository) GetAtRiskAdmins(ctx context.Context, days int) ([]*models.AtRiskAdmin, error) {
+	query := `
+		SELECT a.id, a.company_name, a.email, a.subscription_status, COALESCE(st.name, ''),
+			a.subscription_expires_at, lp.last_payment_at,
+			EXISTS (SELECT 1 FROM payment_history p WHERE p.admin_id = a.id AND p.status = 'pending')
+		FROM admin a
+		LEFT JOIN subscription_tier st ON st.id = a.subscription_tier_id
+		LEFT JOIN LATERAL (
+			SELECT MAX(p.payment_date) AS last_payment_at
+			FROM payment_history p
+			WHERE p.admin_id = a.id AND p.status = 'verified'
+		) lp ON true
+		WHERE a.subscription_expires_at IS NOT NULL
+		  AND ((a.subscription_status IN ('active', 'trial')
+		        AND a.subscription_expires_at < CURRENT_TIMESTAMP + make_interval(days => $1))
+		    OR (a.subscription_status = 'expired'
+		        AND a.subscription_expires_at >= CURRENT_TIMESTAMP - make_interval(days => $1)))
+		ORDER BY a.subscription_expires_at
+	`
+
+	rows, err := r.db.Query(ctx, query, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	admins := []*models.AtRiskAdmin{}
+	for rows.Next() {
+		var admin models.AtRiskAdmin
+		var expiresAt, lastPaymentAt sql.NullTime
+		err := rows.Scan(
+			&admin.AdminID,
+			&admin.CompanyName,
+			&admin.Email,
+			&admin.SubscriptionStatus,
+			&admin.TierName,
+			&expiresAt,
+			&lastPaymentAt,
+			&admin.HasPendingPayment,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if expiresAt.Valid {
+			admin.SubscriptionExpiresAt = &expiresAt.Time
+		}
+		if lastPaymentAt.Valid {
+			admin.LastPaymentAt = &lastPaymentAt.Time
+		}
+		admins = append(admins, &admin)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return admins, nil
+}
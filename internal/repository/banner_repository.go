@@ -9,6 +9,7 @@ import (
 	"mobilka/internal/models"
 	"mobilka/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -82,83 +83,45 @@ func (r *BannerRepository) GetByID(ctx context.Context, id int) (*models.Banner,
 	return &banner, nil
 }
 
-// GetByAdminID retrieves all banners for a specific admin
-func (r *BannerRepository) GetByAdminID(ctx context.Context, adminID int) ([]*models.Banner, error) {
-	query := `
-		SELECT id, admin_id, image, title, body, created_at, updated_at
-		FROM banner
-		WHERE admin_id = $1
-		ORDER BY id
-	`
+// bannerList describes how banner lists are filtered and sorted
+var bannerList = listSpec{
+	table:    "banner",
+	columns:  "id, admin_id, image, title, body, created_at, updated_at",
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+		"title":      {"title", "TEXT"},
+	},
+	defaultSort:   "id",
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"title", "body"},
+}
 
-	rows, err := r.db.Query(ctx, query, adminID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var banners []*models.Banner
-	for rows.Next() {
-		var banner models.Banner
-		err := rows.Scan(
-			&banner.ID,
-			&banner.AdminID,
-			&banner.Image,
-			&banner.Title,
-			&banner.Body,
-			&banner.CreatedAt,
-			&banner.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		banners = append(banners, &banner)
+// scanBanner scans a banner row selected with the bannerList columns, followed by any extra columns into extra
+func scanBanner(row pgx.Row, extra ...interface{}) (*models.Banner, error) {
+	var banner models.Banner
+	dest := []interface{}{
+		&banner.ID,
+		&banner.AdminID,
+		&banner.Image,
+		&banner.Title,
+		&banner.Body,
+		&banner.CreatedAt,
+		&banner.UpdatedAt,
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
-	return banners, nil
+	return &banner, nil
 }
 
-// GetAll retrieves all banners
-func (r *BannerRepository) GetAll(ctx context.Context) ([]*models.Banner, error) {
-	query := `
-		SELECT id, admin_id, image, title, body, created_at, updated_at
-		FROM banner
-		ORDER BY id
-	`
-
-	rows, err := r.db.Query(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var banners []*models.Banner
-	for rows.Next() {
-		var banner models.Banner
-		err := rows.Scan(
-			&banner.ID,
-			&banner.AdminID,
-			&banner.Image,
-			&banner.Title,
-			&banner.Body,
-			&banner.CreatedAt,
-			&banner.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		banners = append(banners, &banner)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return banners, nil
+// List retrieves one page of banners
+func (r *BannerRepository) List(ctx context.Context, params models.ListParams) ([]*models.Banner, *models.ListMeta, error) {
+	return queryList(ctx, r.db, bannerList, params, nil, scanBanner)
 }
 
 // Update updates a banner
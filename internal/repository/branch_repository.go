@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// branchColumns is the column list shared by all branch queries
+const branchColumns = `
+	id, admin_id, name, address, phone, latitude, longitude, photos, status, sort_order,
+	time_zone, opening_hours, special_days, created_at, updated_at
+`
+
+// BranchRepository handles database operations for restaurant branches
+type BranchRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewBranchRepository creates a new branch repository
+func NewBranchRepository(db *pgxpool.Pool) *BranchRepository {
+	return &BranchRepository{
+		db: db,
+	}
+}
+
+// scanBranch scans a single branch row selected with branchColumns, followed by any extra
+// columns into extra
+func scanBranch(row pgx.Row, extra ...interface{}) (*models.Branch, error) {
+	var branch models.Branch
+	var openingHoursJSON, specialDaysJSON []byte
+
+	dest := []interface{}{
+		&branch.ID,
+		&branch.AdminID,
+		&branch.Name,
+		&branch.Address,
+		&branch.Phone,
+		&branch.Latitude,
+		&branch.Longitude,
+		&branch.Photos,
+		&branch.Status,
+		&branch.SortOrder,
+		&branch.TimeZone,
+		&openingHoursJSON,
+		&specialDaysJSON,
+		&branch.CreatedAt,
+		&branch.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(openingHoursJSON, &branch.OpeningHours); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal opening hours: %w", err)
+	}
+
+	if err := json.Unmarshal(specialDaysJSON, &branch.SpecialDays); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal special days: %w", err)
+	}
+
+	return &branch, nil
+}
+
+// marshalOpeningHours converts the opening hours of a branch to JSON
+func marshalOpeningHours(branch *models.Branch) ([]byte, []byte, error) {
+	openingHoursJSON, err := json.Marshal(branch.OpeningHours)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal opening hours: %w", err)
+	}
+
+	specialDays := branch.SpecialDays
+	if specialDays == nil {
+		specialDays = []models.SpecialDay{}
+	}
+	specialDaysJSON, err := json.Marshal(specialDays)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal special days: %w", err)
+	}
+
+	return openingHoursJSON, specialDaysJSON, nil
+}
+
+// stringsParam converts a list of strings to a value that is never NULL in the database
+func stringsParam(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// Create creates a new branch
+func (r *BranchRepository) Create(ctx context.Context, branch *models.Branch) error {
+	openingHoursJSON, specialDaysJSON, err := marshalOpeningHours(branch)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO restaurant_branch (
+			admin_id, name, address, phone, latitude, longitude, photos, status, sort_order,
+			time_zone, opening_hours, special_days
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		branch.AdminID,
+		branch.Name,
+		branch.Address,
+		branch.Phone,
+		branch.Latitude,
+		branch.Longitude,
+		stringsParam(branch.Photos),
+		branch.Status,
+		branch.SortOrder,
+		branch.TimeZone,
+		openingHoursJSON,
+		specialDaysJSON,
+	).Scan(
+		&branch.ID,
+		&branch.CreatedAt,
+		&branch.UpdatedAt,
+	)
+}
+
+// GetByID retrieves a branch by ID
+func (r *BranchRepository) GetByID(ctx context.Context, id int) (*models.Branch, error) {
+	query := `SELECT ` + branchColumns + ` FROM restaurant_branch WHERE id = $1`
+
+	branch, err := scanBranch(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+// branchList describes how branch lists are filtered and sorted
+var branchList = listSpec{
+	table:    "restaurant_branch",
+	columns:  branchColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"name":       {"name", "TEXT"},
+		"sort_order": {"sort_order", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "sort_order",
+	statusColumn:  "status",
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"name", "address"},
+}
+
+// List retrieves one page of branches. With publicOnly inactive branches are left out.
+func (r *BranchRepository) List(ctx context.Context, params models.ListParams, publicOnly bool) ([]*models.Branch, *models.ListMeta, error) {
+	var scope *listQuery
+	if publicOnly {
+		scope = &listQuery{}
+		scope.add("status <> $%d", models.BranchStatusInactive)
+	}
+
+	return queryList(ctx, r.db, branchList, params, scope, scanBranch)
+}
+
+// ListPublicByAdminID retrieves every branch of an admin that is visible to customers
+func (r *BranchRepository) ListPublicByAdminID(ctx context.Context, adminID int) ([]*models.Branch, error) {
+	query := `
+		SELECT ` + branchColumns + `
+		FROM restaurant_branch
+		WHERE admin_id = $1 AND status <> $2
+		ORDER BY sort_order, id
+	`
+
+	rows, err := r.db.Query(ctx, query, adminID, models.BranchStatusInactive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	branches := []*models.Branch{}
+	for rows.Next() {
+		branch, err := scanBranch(rows)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, branch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return branches, nil
+}
+
+// Nearby retrieves the public branches of an admin within radius meters of a point,
+// nearest first. The great-circle distance is the haversine formula on a spherical earth.
+func (r *BranchRepository) Nearby(ctx context.Context, adminID int, latitude, longitude, radius float64, limit int) ([]*models.NearbyBranch, error) {
+	query := `
+		SELECT ` + branchColumns + `, distance
+		FROM (
+			SELECT *, 6371000 * 2 * ASIN(SQRT(LEAST(1,
+				POWER(SIN(RADIANS(latitude - $2) / 2), 2) +
+				COS(RADIANS($2)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - $3) / 2), 2)
+			))) AS distance
+			FROM restaurant_branch
+			WHERE admin_id = $1 AND status <> $4 AND latitude IS NOT NULL
+		) branch
+		WHERE distance <= $5
+		ORDER BY distance, id
+		LIMIT $6
+	`
+
+	rows, err := r.db.Query(ctx, query, adminID, latitude, longitude, models.BranchStatusInactive, radius, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	branches := []*models.NearbyBranch{}
+	for rows.Next() {
+		var distance float64
+		branch, err := scanBranch(rows, &distance)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, &models.NearbyBranch{Branch: branch, DistanceMeters: distance})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return branches, nil
+}
+
+// Update updates a branch
+func (r *BranchRepository) Update(ctx context.Context, branch *models.Branch) error {
+	openingHoursJSON, specialDaysJSON, err := marshalOpeningHours(branch)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE restaurant_branch
+		SET name = $2, address = $3, phone = $4, latitude = $5, longitude = $6, photos = $7,
+			status = $8, sort_order = $9, time_zone = $10, opening_hours = $11, special_days = $12
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err = r.db.QueryRow(ctx, query,
+		branch.ID,
+		branch.Name,
+		branch.Address,
+		branch.Phone,
+		branch.Latitude,
+		branch.Longitude,
+		stringsParam(branch.Photos),
+		branch.Status,
+		branch.SortOrder,
+		branch.TimeZone,
+		openingHoursJSON,
+		specialDaysJSON,
+	).Scan(&branch.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrResourceNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a branch
+func (r *BranchRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM restaurant_branch WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,784 @@
+package repository
+
+import (
+	"context"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Column lists shared by all catalog queries
+const (
+	catalogCategoryColumns = `
+		id, admin_id, name, description, image, sort_order, is_active, created_at, updated_at
+	`
+	catalogProductColumns = `
+		id, admin_id, category_id, name, description, price, currency, images, is_available,
+		is_active, sort_order, created_at, updated_at
+	`
+	modifierGroupColumns = `
+		id, admin_id, name, min_select, max_select, sort_order, created_at, updated_at
+	`
+)
+
+// CatalogRepository handles database operations for the menu catalog: categories,
+// products and modifier groups
+type CatalogRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCatalogRepository creates a new catalog repository
+func NewCatalogRepository(db *pgxpool.Pool) *CatalogRepository {
+	return &CatalogRepository{
+		db: db,
+	}
+}
+
+// scanCatalogCategory scans a category row selected with catalogCategoryColumns, followed
+// by any extra columns into extra
+func scanCatalogCategory(row pgx.Row, extra ...interface{}) (*models.CatalogCategory, error) {
+	var category models.CatalogCategory
+
+	dest := []interface{}{
+		&category.ID,
+		&category.AdminID,
+		&category.Name,
+		&category.Description,
+		&category.Image,
+		&category.SortOrder,
+		&category.IsActive,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// CreateCategory creates a new category
+func (r *CatalogRepository) CreateCategory(ctx context.Context, category *models.CatalogCategory) error {
+	query := `
+		INSERT INTO catalog_category (admin_id, name, description, image, sort_order, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		category.AdminID,
+		category.Name,
+		category.Description,
+		category.Image,
+		category.SortOrder,
+		category.IsActive,
+	).Scan(
+		&category.ID,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+}
+
+// GetCategoryByID retrieves a category by ID
+func (r *CatalogRepository) GetCategoryByID(ctx context.Context, id int) (*models.CatalogCategory, error) {
+	query := `SELECT ` + catalogCategoryColumns + ` FROM catalog_category WHERE id = $1`
+
+	category, err := scanCatalogCategory(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// catalogCategoryList describes how category lists are filtered and sorted
+var catalogCategoryList = listSpec{
+	table:    "catalog_category",
+	columns:  catalogCategoryColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"name":       {"name", "TEXT"},
+		"sort_order": {"sort_order", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "sort_order",
+	statusColumn:  "CASE WHEN is_active THEN 'active' ELSE 'inactive' END",
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"name"},
+}
+
+// ListCategories retrieves one page of categories
+func (r *CatalogRepository) ListCategories(ctx context.Context, params models.ListParams) ([]*models.CatalogCategory, *models.ListMeta, error) {
+	return queryList(ctx, r.db, catalogCategoryList, params, nil, scanCatalogCategory)
+}
+
+// ListActiveCategories retrieves the active categories of an admin in menu order
+func (r *CatalogRepository) ListActiveCategories(ctx context.Context, adminID int) ([]*models.CatalogCategory, error) {
+	query := `
+		SELECT ` + catalogCategoryColumns + `
+		FROM catalog_category
+		WHERE admin_id = $1 AND is_active
+		ORDER BY sort_order, id
+	`
+
+	rows, err := r.db.Query(ctx, query, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []*models.CatalogCategory{}
+	for rows.Next() {
+		category, err := scanCatalogCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+// UpdateCategory updates a category
+func (r *CatalogRepository) UpdateCategory(ctx context.Context, category *models.CatalogCategory) error {
+	query := `
+		UPDATE catalog_category
+		SET name = $2, description = $3, image = $4, sort_order = $5, is_active = $6
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		category.ID,
+		category.Name,
+		category.Description,
+		category.Image,
+		category.SortOrder,
+		category.IsActive,
+	).Scan(&category.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrResourceNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCategory deletes a category. It fails with a foreign key violation while the
+// category still has products.
+func (r *CatalogRepository) DeleteCategory(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM catalog_category WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
+
+// scanCatalogProduct scans a product row selected with catalogProductColumns, followed by
+// any extra columns into extra. Its modifier group IDs are loaded separately.
+func scanCatalogProduct(row pgx.Row, extra ...interface{}) (*models.CatalogProduct, error) {
+	var product models.CatalogProduct
+
+	dest := []interface{}{
+		&product.ID,
+		&product.AdminID,
+		&product.CategoryID,
+		&product.Name,
+		&product.Description,
+		&product.Price,
+		&product.Currency,
+		&product.Images,
+		&product.IsAvailable,
+		&product.IsActive,
+		&product.SortOrder,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// setProductModifierGroups replaces the modifier groups linked to a product, keeping their order
+func setProductModifierGroups(ctx context.Context, tx pgx.Tx, productID int, groupIDs []int) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM catalog_product_modifier_group WHERE product_id = $1`, productID); err != nil {
+		return err
+	}
+
+	for position, groupID := range groupIDs {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO catalog_product_modifier_group (product_id, group_id, position)
+			VALUES ($1, $2, $3)
+		`, productID, groupID, position)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateProduct creates a new product together with its modifier group links
+func (r *CatalogRepository) CreateProduct(ctx context.Context, product *models.CatalogProduct) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO catalog_product (
+			admin_id, category_id, name, description, price, currency, images, is_available,
+			is_active, sort_order
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		product.AdminID,
+		product.CategoryID,
+		product.Name,
+		product.Description,
+		product.Price,
+		product.Currency,
+		stringsParam(product.Images),
+		product.IsAvailable,
+		product.IsActive,
+		product.SortOrder,
+	).Scan(
+		&product.ID,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := setProductModifierGroups(ctx, tx, product.ID, product.ModifierGroupIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetProductByID retrieves a product by ID together with its modifier group IDs
+func (r *CatalogRepository) GetProductByID(ctx context.Context, id int) (*models.CatalogProduct, error) {
+	query := `SELECT ` + catalogProductColumns + ` FROM catalog_product WHERE id = $1`
+
+	product, err := scanCatalogProduct(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.loadProductModifierGroupIDs(ctx, product); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// GetProductsByIDs retrieves the products of an admin with the given IDs, keyed by ID.
+// Products of other admins are left out.
+func (r *CatalogRepository) GetProductsByIDs(ctx context.Context, adminID int, ids []int) (map[int]*models.CatalogProduct, error) {
+	query := `SELECT ` + catalogProductColumns + ` FROM catalog_product WHERE admin_id = $1 AND id = ANY($2)`
+
+	rows, err := r.db.Query(ctx, query, adminID, idsParam(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make(map[int]*models.CatalogProduct)
+	list := []*models.CatalogProduct{}
+	for rows.Next() {
+		product, err := scanCatalogProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products[product.ID] = product
+		list = append(list, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadProductModifierGroupIDs(ctx, list...); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// catalogProductList describes how product lists are filtered and sorted
+var catalogProductList = listSpec{
+	table:    "catalog_product",
+	columns:  catalogProductColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"name":       {"name", "TEXT"},
+		"price":      {"price", "BIGINT"},
+		"sort_order": {"sort_order", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "sort_order",
+	statusColumn:  "CASE WHEN NOT is_active THEN 'inactive' WHEN is_available THEN 'available' ELSE 'unavailable' END",
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"name", "description"},
+}
+
+// ListProducts retrieves one page of products, optionally of one category only
+func (r *CatalogRepository) ListProducts(ctx context.Context, params models.ListParams, categoryID *int) ([]*models.CatalogProduct, *models.ListMeta, error) {
+	var scope *listQuery
+	if categoryID != nil {
+		scope = &listQuery{}
+		scope.add("category_id = $%d", *categoryID)
+	}
+
+	products, meta, err := queryList(ctx, r.db, catalogProductList, params, scope, scanCatalogProduct)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.loadProductModifierGroupIDs(ctx, products...); err != nil {
+		return nil, nil, err
+	}
+
+	return products, meta, nil
+}
+
+// ListActiveProducts retrieves the active products of an admin in menu order, together
+// with their modifier group IDs
+func (r *CatalogRepository) ListActiveProducts(ctx context.Context, adminID int) ([]*models.CatalogProduct, error) {
+	query := `
+		SELECT ` + catalogProductColumns + `
+		FROM catalog_product
+		WHERE admin_id = $1 AND is_active
+		ORDER BY sort_order, id
+	`
+
+	rows, err := r.db.Query(ctx, query, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []*models.CatalogProduct{}
+	for rows.Next() {
+		product, err := scanCatalogProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadProductModifierGroupIDs(ctx, products...); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// loadProductModifierGroupIDs fills the modifier group IDs of products
+func (r *CatalogRepository) loadProductModifierGroupIDs(ctx context.Context, products ...*models.CatalogProduct) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.CatalogProduct, len(products))
+	ids := make([]int, 0, len(products))
+	for _, product := range products {
+		product.ModifierGroupIDs = []int{}
+		byID[product.ID] = product
+		ids = append(ids, product.ID)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT product_id, group_id
+		FROM catalog_product_modifier_group
+		WHERE product_id = ANY($1)
+		ORDER BY product_id, position
+	`, idsParam(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID, groupID int
+		if err := rows.Scan(&productID, &groupID); err != nil {
+			return err
+		}
+		byID[productID].ModifierGroupIDs = append(byID[productID].ModifierGroupIDs, groupID)
+	}
+
+	return rows.Err()
+}
+
+// UpdateProduct updates a product and replaces its modifier group links
+func (r *CatalogRepository) UpdateProduct(ctx context.Context, product *models.CatalogProduct) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE catalog_product
+		SET category_id = $2, name = $3, description = $4, price = $5, currency = $6, images = $7,
+			is_available = $8, is_active = $9, sort_order = $10
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		product.ID,
+		product.CategoryID,
+		product.Name,
+		product.Description,
+		product.Price,
+		product.Currency,
+		stringsParam(product.Images),
+		product.IsAvailable,
+		product.IsActive,
+		product.SortOrder,
+	).Scan(&product.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrResourceNotFound
+		}
+		return err
+	}
+
+	if err := setProductModifierGroups(ctx, tx, product.ID, product.ModifierGroupIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteProduct deletes a product
+func (r *CatalogRepository) DeleteProduct(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM catalog_product WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
+
+// scanModifierGroup scans a modifier group row selected with modifierGroupColumns,
+// followed by any extra columns into extra. Its options are loaded separately.
+func scanModifierGroup(row pgx.Row, extra ...interface{}) (*models.ModifierGroup, error) {
+	var group models.ModifierGroup
+
+	dest := []interface{}{
+		&group.ID,
+		&group.AdminID,
+		&group.Name,
+		&group.MinSelect,
+		&group.MaxSelect,
+		&group.SortOrder,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// setModifierOptions makes the options of a group match options: options with the ID of
+// one of the group's options update it, the others are added, and options left out are
+// deleted
+func setModifierOptions(ctx context.Context, tx pgx.Tx, groupID int, options []models.ModifierOption) error {
+	keep := []int{}
+	for _, option := range options {
+		if option.ID > 0 {
+			keep = append(keep, option.ID)
+		}
+	}
+
+	_, err := tx.Exec(ctx, `
+		DELETE FROM catalog_modifier_option
+		WHERE group_id = $1 AND NOT (id = ANY($2))
+	`, groupID, idsParam(keep))
+	if err != nil {
+		return err
+	}
+
+	for i := range options {
+		option := &options[i]
+		option.GroupID = groupID
+
+		if option.ID > 0 {
+			result, err := tx.Exec(ctx, `
+				UPDATE catalog_modifier_option
+				SET name = $3, price = $4, is_available = $5, sort_order = $6
+				WHERE id = $1 AND group_id = $2
+			`, option.ID, groupID, option.Name, option.Price, option.IsAvailable, option.SortOrder)
+			if err != nil {
+				return err
+			}
+			if result.RowsAffected() == 0 {
+				return utils.NewInvalidInputError("Modifier option not found in this group")
+			}
+			continue
+		}
+
+		err := tx.QueryRow(ctx, `
+			INSERT INTO catalog_modifier_option (group_id, name, price, is_available, sort_order)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, groupID, option.Name, option.Price, option.IsAvailable, option.SortOrder).Scan(&option.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateModifierGroup creates a new modifier group together with its options
+func (r *CatalogRepository) CreateModifierGroup(ctx context.Context, group *models.ModifierGroup) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO catalog_modifier_group (admin_id, name, min_select, max_select, sort_order)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		group.AdminID,
+		group.Name,
+		group.MinSelect,
+		group.MaxSelect,
+		group.SortOrder,
+	).Scan(
+		&group.ID,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := setModifierOptions(ctx, tx, group.ID, group.Options); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetModifierGroupByID retrieves a modifier group by ID together with its options
+func (r *CatalogRepository) GetModifierGroupByID(ctx context.Context, id int) (*models.ModifierGroup, error) {
+	query := `SELECT ` + modifierGroupColumns + ` FROM catalog_modifier_group WHERE id = $1`
+
+	group, err := scanModifierGroup(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.loadModifierOptions(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// modifierGroupList describes how modifier group lists are filtered and sorted
+var modifierGroupList = listSpec{
+	table:    "catalog_modifier_group",
+	columns:  modifierGroupColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"name":       {"name", "TEXT"},
+		"sort_order": {"sort_order", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "sort_order",
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"name"},
+}
+
+// ListModifierGroups retrieves one page of modifier groups together with their options
+func (r *CatalogRepository) ListModifierGroups(ctx context.Context, params models.ListParams) ([]*models.ModifierGroup, *models.ListMeta, error) {
+	groups, meta, err := queryList(ctx, r.db, modifierGroupList, params, nil, scanModifierGroup)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.loadModifierOptions(ctx, groups...); err != nil {
+		return nil, nil, err
+	}
+
+	return groups, meta, nil
+}
+
+// GetModifierGroupsByIDs retrieves the modifier groups of an admin with the given IDs,
+// together with their options, keyed by ID. Groups of other admins are left out.
+func (r *CatalogRepository) GetModifierGroupsByIDs(ctx context.Context, adminID int, ids []int) (map[int]*models.ModifierGroup, error) {
+	query := `
+		SELECT ` + modifierGroupColumns + `
+		FROM catalog_modifier_group
+		WHERE admin_id = $1 AND id = ANY($2)
+	`
+
+	rows, err := r.db.Query(ctx, query, adminID, idsParam(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := make(map[int]*models.ModifierGroup)
+	list := []*models.ModifierGroup{}
+	for rows.Next() {
+		group, err := scanModifierGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups[group.ID] = group
+		list = append(list, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadModifierOptions(ctx, list...); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// loadModifierOptions fills the options of modifier groups in their order
+func (r *CatalogRepository) loadModifierOptions(ctx context.Context, groups ...*models.ModifierGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.ModifierGroup, len(groups))
+	ids := make([]int, 0, len(groups))
+	for _, group := range groups {
+		group.Options = []models.ModifierOption{}
+		byID[group.ID] = group
+		ids = append(ids, group.ID)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, group_id, name, price, is_available, sort_order
+		FROM catalog_modifier_option
+		WHERE group_id = ANY($1)
+		ORDER BY group_id, sort_order, id
+	`, idsParam(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var option models.ModifierOption
+		if err := rows.Scan(&option.ID, &option.GroupID, &option.Name, &option.Price, &option.IsAvailable, &option.SortOrder); err != nil {
+			return err
+		}
+		byID[option.GroupID].Options = append(byID[option.GroupID].Options, option)
+	}
+
+	return rows.Err()
+}
+
+// UpdateModifierGroup updates a modifier group and its options
+func (r *CatalogRepository) UpdateModifierGroup(ctx context.Context, group *models.ModifierGroup) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE catalog_modifier_group
+		SET name = $2, min_select = $3, max_select = $4, sort_order = $5
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		group.ID,
+		group.Name,
+		group.MinSelect,
+		group.MaxSelect,
+		group.SortOrder,
+	).Scan(&group.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrResourceNotFound
+		}
+		return err
+	}
+
+	if err := setModifierOptions(ctx, tx, group.ID, group.Options); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteModifierGroup deletes a modifier group, its options and its product links
+func (r *CatalogRepository) DeleteModifierGroup(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM catalog_modifier_group WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
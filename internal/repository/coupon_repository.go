@@ -0,0 +1,459 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// couponColumns is the column list shared by all coupon queries
+const couponColumns = `
+	id, code, description, discount_type, discount_percent, discount_amount, currency,
+	duration_months, max_redemptions, redemption_count, valid_tier_ids, valid_from,
+	valid_until, is_active, created_at, updated_at
+`
+
+// CouponRepository handles database operations for coupons and their redemptions
+type CouponRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCouponRepository creates a new coupon repository
+func NewCouponRepository(db *pgxpool.Pool) *CouponRepository {
+	return &CouponRepository{
+		db: db,
+	}
+}
+
+// scanCoupon scans a single coupon row selected with couponColumns, followed by any extra
+// columns into extra
+func scanCoupon(row pgx.Row, extra ...interface{}) (*models.Coupon, error) {
+	var coupon models.Coupon
+	var durationMonths, maxRedemptions sql.NullInt32
+	var validTierIDs []int32
+	var validFrom, validUntil sql.NullTime
+
+	dest := []interface{}{
+		&coupon.ID,
+		&coupon.Code,
+		&coupon.Description,
+		&coupon.DiscountType,
+		&coupon.DiscountPercent,
+		&coupon.DiscountAmount,
+		&coupon.Currency,
+		&durationMonths,
+		&maxRedemptions,
+		&coupon.RedemptionCount,
+		&validTierIDs,
+		&validFrom,
+		&validUntil,
+		&coupon.IsActive,
+		&coupon.CreatedAt,
+		&coupon.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if durationMonths.Valid {
+		val := int(durationMonths.Int32)
+		coupon.DurationMonths = &val
+	}
+
+	if maxRedemptions.Valid {
+		val := int(maxRedemptions.Int32)
+		coupon.MaxRedemptions = &val
+	}
+
+	coupon.ValidTierIDs = make([]int, 0, len(validTierIDs))
+	for _, id := range validTierIDs {
+		coupon.ValidTierIDs = append(coupon.ValidTierIDs, int(id))
+	}
+
+	if validFrom.Valid {
+		coupon.ValidFrom = &validFrom.Time
+	}
+
+	if validUntil.Valid {
+		coupon.ValidUntil = &validUntil.Time
+	}
+
+	return &coupon, nil
+}
+
+// idsParam converts IDs to a value that is never NULL in the database
+func idsParam(ids []int) []int32 {
+	result := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, int32(id))
+	}
+	return result
+}
+
+// Create creates a new coupon
+func (r *CouponRepository) Create(ctx context.Context, coupon *models.Coupon) error {
+	query := `
+		INSERT INTO coupon (
+			code, description, discount_type, discount_percent, discount_amount, currency,
+			duration_months, max_redemptions, valid_tier_ids, valid_from, valid_until, is_active
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, redemption_count, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		coupon.Code,
+		coupon.Description,
+		coupon.DiscountType,
+		coupon.DiscountPercent,
+		coupon.DiscountAmount,
+		coupon.Currency,
+		coupon.DurationMonths,
+		coupon.MaxRedemptions,
+		idsParam(coupon.ValidTierIDs),
+		coupon.ValidFrom,
+		coupon.ValidUntil,
+		coupon.IsActive,
+	).Scan(
+		&coupon.ID,
+		&coupon.RedemptionCount,
+		&coupon.CreatedAt,
+		&coupon.UpdatedAt,
+	)
+}
+
+// GetByID retrieves a coupon by ID
+func (r *CouponRepository) GetByID(ctx context.Context, id int) (*models.Coupon, error) {
+	query := `SELECT ` + couponColumns + ` FROM coupon WHERE id = $1`
+
+	coupon, err := scanCoupon(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// GetByCode retrieves a coupon by its code
+func (r *CouponRepository) GetByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	query := `SELECT ` + couponColumns + ` FROM coupon WHERE code = $1`
+
+	coupon, err := scanCoupon(r.db.QueryRow(ctx, query, code))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// couponList describes how coupon lists are filtered and sorted. The status of a coupon is
+// active or inactive.
+var couponList = listSpec{
+	table:    "coupon",
+	columns:  couponColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":               {"id", "BIGINT"},
+		"created_at":       {"created_at", "TIMESTAMPTZ"},
+		"code":             {"code", "TEXT"},
+		"redemption_count": {"redemption_count", "BIGINT"},
+	},
+	defaultSort:   "created_at",
+	defaultDesc:   true,
+	statusColumn:  "CASE WHEN is_active THEN 'active' ELSE 'inactive' END",
+	dateColumn:    "created_at",
+	searchColumns: []string{"code", "description"},
+}
+
+// List retrieves one page of coupons
+func (r *CouponRepository) List(ctx context.Context, params models.ListParams) ([]*models.Coupon, *models.ListMeta, error) {
+	return queryList(ctx, r.db, couponList, params, nil, scanCoupon)
+}
+
+// Update updates a coupon
+func (r *CouponRepository) Update(ctx context.Context, coupon *models.Coupon) error {
+	query := `
+		UPDATE coupon
+		SET description = $2, discount_type = $3, discount_percent = $4, discount_amount = $5,
+		    currency = $6, duration_months = $7, max_redemptions = $8, valid_tier_ids = $9,
+		    valid_from = $10, valid_until = $11, is_active = $12
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		coupon.ID,
+		coupon.Description,
+		coupon.DiscountType,
+		coupon.DiscountPercent,
+		coupon.DiscountAmount,
+		coupon.Currency,
+		coupon.DurationMonths,
+		coupon.MaxRedemptions,
+		idsParam(coupon.ValidTierIDs),
+		coupon.ValidFrom,
+		coupon.ValidUntil,
+		coupon.IsActive,
+	).Scan(&coupon.UpdatedAt)
+
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrResourceNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a coupon
+func (r *CouponRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM coupon WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
+
+// Redeem records a coupon redemption for an admin. The redemption limit is
+// checked and the counter incremented atomically, so concurrent redemptions
+// can't exceed max_redemptions.
+func (r *CouponRepository) Redeem(ctx context.Context, coupon *models.Coupon, adminID int) (*models.CouponRedemption, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE coupon
+		SET redemption_count = redemption_count + 1
+		WHERE id = $1 AND is_active = true
+		  AND (max_redemptions IS NULL OR redemption_count < max_redemptions)
+	`, coupon.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, utils.NewAppError(utils.ErrInvalidInput, "Coupon is no longer available", 409)
+	}
+
+	redemption := &models.CouponRedemption{
+		CouponID:        coupon.ID,
+		AdminID:         adminID,
+		MonthsRemaining: coupon.DurationMonths,
+		Status:          "active",
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO coupon_redemption (coupon_id, admin_id, months_remaining, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, redeemed_at, created_at, updated_at
+	`,
+		redemption.CouponID,
+		redemption.AdminID,
+		redemption.MonthsRemaining,
+		redemption.Status,
+	).Scan(
+		&redemption.ID,
+		&redemption.RedeemedAt,
+		&redemption.CreatedAt,
+		&redemption.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return redemption, nil
+}
+
+// HasRedeemed reports whether an admin has already redeemed a coupon
+func (r *CouponRepository) HasRedeemed(ctx context.Context, couponID int, adminID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM coupon_redemption WHERE coupon_id = $1 AND admin_id = $2)`
+
+	var exists bool
+	err := r.db.QueryRow(ctx, query, couponID, adminID).Scan(&exists)
+	return exists, err
+}
+
+// GetActiveRedemption retrieves an admin's active redemption together with its coupon
+func (r *CouponRepository) GetActiveRedemption(ctx context.Context, adminID int) (*models.CouponRedemption, *models.Coupon, error) {
+	query := `
+		SELECT id, coupon_id, admin_id, months_remaining, status, redeemed_at, created_at, updated_at
+		FROM coupon_redemption
+		WHERE admin_id = $1 AND status = 'active'
+		ORDER BY redeemed_at DESC
+		LIMIT 1
+	`
+
+	var redemption models.CouponRedemption
+	var monthsRemaining sql.NullInt32
+
+	err := r.db.QueryRow(ctx, query, adminID).Scan(
+		&redemption.ID,
+		&redemption.CouponID,
+		&redemption.AdminID,
+		&monthsRemaining,
+		&redemption.Status,
+		&redemption.RedeemedAt,
+		&redemption.CreatedAt,
+		&redemption.UpdatedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, nil, utils.ErrResourceNotFound
+		}
+		return nil, nil, err
+	}
+
+	if monthsRemaining.Valid {
+		val := int(monthsRemaining.Int32)
+		redemption.MonthsRemaining = &val
+	}
+
+	coupon, err := r.GetByID(ctx, redemption.CouponID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &redemption, coupon, nil
+}
+
+// couponRedemptionList describes how the redemption lists of a coupon are filtered and sorted
+var couponRedemptionList = listSpec{
+	table: "coupon_redemption cr JOIN admin a ON a.id = cr.admin_id",
+	columns: `cr.id, cr.coupon_id, cr.admin_id, a.company_name, cr.months_remaining, cr.status,
+		cr.redeemed_at, cr.created_at, cr.updated_at`,
+	idColumn: "cr.id",
+	sorts: map[string]listSort{
+		"id":          {"cr.id", "BIGINT"},
+		"redeemed_at": {"cr.redeemed_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "redeemed_at",
+	defaultDesc:   true,
+	statusColumn:  "cr.status",
+	dateColumn:    "cr.redeemed_at",
+	adminColumn:   "cr.admin_id",
+	searchColumns: []string{"a.company_name"},
+}
+
+// scanCouponRedemption scans a redemption row selected with the couponRedemptionList
+// columns, followed by any extra columns into extra
+func scanCouponRedemption(row pgx.Row, extra ...interface{}) (*models.CouponRedemption, error) {
+	var redemption models.CouponRedemption
+	var monthsRemaining sql.NullInt32
+
+	dest := []interface{}{
+		&redemption.ID,
+		&redemption.CouponID,
+		&redemption.AdminID,
+		&redemption.AdminName,
+		&monthsRemaining,
+		&redemption.Status,
+		&redemption.RedeemedAt,
+		&redemption.CreatedAt,
+		&redemption.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if monthsRemaining.Valid {
+		val := int(monthsRemaining.Int32)
+		redemption.MonthsRemaining = &val
+	}
+
+	return &redemption, nil
+}
+
+// ListRedemptions retrieves one page of the redemptions of a coupon
+func (r *CouponRepository) ListRedemptions(ctx context.Context, couponID int, params models.ListParams) ([]*models.CouponRedemption, *models.ListMeta, error) {
+	scope := &listQuery{}
+	scope.add("cr.coupon_id = $%d", couponID)
+
+	return queryList(ctx, r.db, couponRedemptionList, params, scope, scanCouponRedemption)
+}
+
+// GetReport aggregates redemption statistics for every coupon. Discounts granted on
+// payments in another currency are converted to the coupon currency with the latest
+// exchange rate; payments without a rate are left out of the total.
+func (r *CouponRepository) GetReport(ctx context.Context) ([]*models.CouponReport, error) {
+	query := `
+		SELECT c.id, c.code, c.redemption_count,
+		       COUNT(DISTINCT cr.id) FILTER (WHERE cr.status = 'active'),
+		       COUNT(ph.id),
+		       COALESCE(ROUND(SUM(
+		           CASE WHEN ph.currency = c.currency THEN ph.discount_amount
+		                ELSE ph.discount_amount * (
+		                    SELECT er.rate FROM exchange_rate er
+		                    WHERE er.base_currency = ph.currency AND er.quote_currency = c.currency
+		                    ORDER BY er.effective_date DESC
+		                    LIMIT 1
+		                )
+		           END
+		       )), 0)::BIGINT,
+		       c.currency
+		FROM coupon c
+		LEFT JOIN coupon_redemption cr ON cr.coupon_id = c.id
+		LEFT JOIN payment_history ph ON ph.coupon_redemption_id = cr.id AND ph.status = 'verified'
+		GROUP BY c.id, c.code, c.redemption_count, c.currency
+		ORDER BY c.redemption_count DESC, c.code
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []*models.CouponReport
+	for rows.Next() {
+		var report models.CouponReport
+
+		err := rows.Scan(
+			&report.CouponID,
+			&report.Code,
+			&report.RedemptionCount,
+			&report.ActiveCount,
+			&report.VerifiedPayments,
+			&report.TotalDiscount,
+			&report.Currency,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, &report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
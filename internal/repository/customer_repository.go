@@ -0,0 +1,426 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// customerColumns is the column list shared by all customer queries
+const customerColumns = `
+	id, admin_id, phone, name, email, marketing_sms, marketing_push, terms_accepted_at,
+	consent_updated_at, last_login_at, created_at, updated_at
+`
+
+// customerAddressColumns is the column list shared by all customer address queries
+const customerAddressColumns = `
+	id, customer_id, label, address, details, latitude, longitude, is_default, created_at,
+	updated_at
+`
+
+// CustomerRepository handles database operations for customers, their addresses and
+// login codes
+type CustomerRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCustomerRepository creates a new customer repository
+func NewCustomerRepository(db *pgxpool.Pool) *CustomerRepository {
+	return &CustomerRepository{
+		db: db,
+	}
+}
+
+// scanCustomer scans a single customer row selected with customerColumns, followed by
+// any extra columns into extra
+func scanCustomer(row pgx.Row, extra ...interface{}) (*models.Customer, error) {
+	var customer models.Customer
+
+	dest := []interface{}{
+		&customer.ID,
+		&customer.AdminID,
+		&customer.Phone,
+		&customer.Name,
+		&customer.Email,
+		&customer.MarketingSMS,
+		&customer.MarketingPush,
+		&customer.TermsAcceptedAt,
+		&customer.ConsentUpdatedAt,
+		&customer.LastLoginAt,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	return &customer, nil
+}
+
+// scanCustomerAddress scans a single address row selected with customerAddressColumns
+func scanCustomerAddress(row pgx.Row) (*models.CustomerAddress, error) {
+	var address models.CustomerAddress
+
+	err := row.Scan(
+		&address.ID,
+		&address.CustomerID,
+		&address.Label,
+		&address.Address,
+		&address.Details,
+		&address.Latitude,
+		&address.Longitude,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+// LoginByPhone returns the customer of an admin with a phone number, creating it on the
+// first login, and records the login time. created tells whether the customer is new.
+func (r *CustomerRepository) LoginByPhone(ctx context.Context, adminID int, phone string) (*models.Customer, bool, error) {
+	// xmax is 0 only for rows inserted rather than updated by the statement
+	query := `
+		INSERT INTO customer (admin_id, phone, last_login_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (admin_id, phone) DO UPDATE SET last_login_at = CURRENT_TIMESTAMP
+		RETURNING ` + customerColumns + `, (xmax = 0)
+	`
+
+	var created bool
+	customer, err := scanCustomer(r.db.QueryRow(ctx, query, adminID, phone), &created)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return customer, created, nil
+}
+
+// GetByID retrieves a customer by ID, without addresses
+func (r *CustomerRepository) GetByID(ctx context.Context, id int) (*models.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customer WHERE id = $1`
+
+	customer, err := scanCustomer(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// customerList describes how customer lists are filtered and sorted
+var customerList = listSpec{
+	table:    "customer",
+	columns:  customerColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":            {"id", "BIGINT"},
+		"name":          {"name", "TEXT"},
+		"phone":         {"phone", "TEXT"},
+		"created_at":    {"created_at", "TIMESTAMPTZ"},
+		"last_login_at": {"COALESCE(last_login_at, created_at)", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "created_at",
+	defaultDesc:   true,
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"phone", "name", "email"},
+}
+
+// List retrieves one page of customers, without addresses
+func (r *CustomerRepository) List(ctx context.Context, params models.ListParams) ([]*models.Customer, *models.ListMeta, error) {
+	return queryList(ctx, r.db, customerList, params, nil, scanCustomer)
+}
+
+// Update updates the profile and consents of a customer
+func (r *CustomerRepository) Update(ctx context.Context, customer *models.Customer) error {
+	query := `
+		UPDATE customer
+		SET name = $2, email = $3, marketing_sms = $4, marketing_push = $5,
+			terms_accepted_at = $6, consent_updated_at = $7
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		customer.ID,
+		customer.Name,
+		customer.Email,
+		customer.MarketingSMS,
+		customer.MarketingPush,
+		customer.TermsAcceptedAt,
+		customer.ConsentUpdatedAt,
+	).Scan(&customer.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrResourceNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ListAddresses retrieves the saved addresses of a customer, the default one first
+func (r *CustomerRepository) ListAddresses(ctx context.Context, customerID int) ([]models.CustomerAddress, error) {
+	query := `
+		SELECT ` + customerAddressColumns + `
+		FROM customer_address
+		WHERE customer_id = $1
+		ORDER BY is_default DESC, id
+	`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := []models.CustomerAddress{}
+	for rows.Next() {
+		address, err := scanCustomerAddress(rows)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, *address)
+	}
+
+	return addresses, rows.Err()
+}
+
+// GetAddressByID retrieves a saved address by ID
+func (r *CustomerRepository) GetAddressByID(ctx context.Context, id int) (*models.CustomerAddress, error) {
+	query := `SELECT ` + customerAddressColumns + ` FROM customer_address WHERE id = $1`
+
+	address, err := scanCustomerAddress(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// CreateAddress saves a new address. The first address of a customer becomes the default,
+// and a new default replaces the previous one.
+func (r *CustomerRepository) CreateAddress(ctx context.Context, address *models.CustomerAddress) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if address.IsDefault {
+		if err := clearDefaultAddress(ctx, tx, address.CustomerID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO customer_address (
+			customer_id, label, address, details, latitude, longitude, is_default
+		)
+		VALUES (
+			$1, $2, $3, $4, $5, $6,
+			$7 OR NOT EXISTS (SELECT 1 FROM customer_address WHERE customer_id = $1)
+		)
+		RETURNING id, is_default, created_at, updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		address.CustomerID,
+		address.Label,
+		address.Address,
+		address.Details,
+		address.Latitude,
+		address.Longitude,
+		address.IsDefault,
+	).Scan(
+		&address.ID,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpdateAddress updates a saved address. A new default replaces the previous one.
+func (r *CustomerRepository) UpdateAddress(ctx context.Context, address *models.CustomerAddress) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if address.IsDefault {
+		if err := clearDefaultAddress(ctx, tx, address.CustomerID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE customer_address
+		SET label = $2, address = $3, details = $4, latitude = $5, longitude = $6,
+			is_default = $7
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		address.ID,
+		address.Label,
+		address.Address,
+		address.Details,
+		address.Latitude,
+		address.Longitude,
+		address.IsDefault,
+	).Scan(&address.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrResourceNotFound
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// clearDefaultAddress unmarks the default address of a customer
+func clearDefaultAddress(ctx context.Context, tx pgx.Tx, customerID int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE customer_address SET is_default = FALSE
+		WHERE customer_id = $1 AND is_default
+	`, customerID)
+	return err
+}
+
+// DeleteAddress deletes a saved address
+func (r *CustomerRepository) DeleteAddress(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM customer_address WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
+
+// CreateOTP stores a new login code
+func (r *CustomerRepository) CreateOTP(ctx context.Context, otp *models.CustomerOTP) error {
+	query := `
+		INSERT INTO customer_otp (admin_id, phone, code_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		otp.AdminID,
+		otp.Phone,
+		otp.CodeHash,
+		otp.ExpiresAt,
+	).Scan(
+		&otp.ID,
+		&otp.CreatedAt,
+	)
+}
+
+// GetLatestOTP retrieves the last login code sent to a phone number of an admin
+func (r *CustomerRepository) GetLatestOTP(ctx context.Context, adminID int, phone string) (*models.CustomerOTP, error) {
+	query := `
+		SELECT id, admin_id, phone, code_hash, attempts, expires_at, consumed_at, created_at
+		FROM customer_otp
+		WHERE admin_id = $1 AND phone = $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	var otp models.CustomerOTP
+	err := r.db.QueryRow(ctx, query, adminID, phone).Scan(
+		&otp.ID,
+		&otp.AdminID,
+		&otp.Phone,
+		&otp.CodeHash,
+		&otp.Attempts,
+		&otp.ExpiresAt,
+		&otp.ConsumedAt,
+		&otp.CreatedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return &otp, nil
+}
+
+// CountOTPsSince counts the login codes sent to a phone number of an admin since a time
+func (r *CustomerRepository) CountOTPsSince(ctx context.Context, adminID int, phone string, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM customer_otp
+		WHERE admin_id = $1 AND phone = $2 AND created_at >= $3
+	`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, adminID, phone, since).Scan(&count)
+	return count, err
+}
+
+// IncrementOTPAttempts records a guess of an unused login code and returns the guesses so
+// far. It returns ErrResourceNotFound once the code has had maxAttempts guesses or was used,
+// so that concurrent guesses can't exceed the limit.
+func (r *CustomerRepository) IncrementOTPAttempts(ctx context.Context, id, maxAttempts int) (int, error) {
+	query := `
+		UPDATE customer_otp SET attempts = attempts + 1
+		WHERE id = $1 AND attempts < $2 AND consumed_at IS NULL
+		RETURNING attempts
+	`
+
+	var attempts int
+	err := r.db.QueryRow(ctx, query, id, maxAttempts).Scan(&attempts)
+	if err != nil {
+		if isNoRows(err) {
+			return 0, utils.ErrResourceNotFound
+		}
+		return 0, err
+	}
+
+	return attempts, nil
+}
+
+// ConsumeOTP marks a login code as used. It reports false when the code was used already,
+// so that concurrent logins with the same code can't both succeed.
+func (r *CustomerRepository) ConsumeOTP(ctx context.Context, id int) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		UPDATE customer_otp SET consumed_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND consumed_at IS NULL
+	`, id)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected() == 1, nil
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// deliveryZoneColumns is the column list shared by all delivery zone queries
+const deliveryZoneColumns = `
+	id, admin_id, branch_id, name, polygon, min_order_amount, currency, fee_type, fee,
+	fee_tiers, eta_minutes, is_active, sort_order, created_at, updated_at
+`
+
+// DeliveryZoneRepository handles database operations for delivery zones
+type DeliveryZoneRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDeliveryZoneRepository creates a new delivery zone repository
+func NewDeliveryZoneRepository(db *pgxpool.Pool) *DeliveryZoneRepository {
+	return &DeliveryZoneRepository{
+		db: db,
+	}
+}
+
+// scanDeliveryZone scans a single delivery zone row selected with deliveryZoneColumns,
+// followed by any extra columns into extra
+func scanDeliveryZone(row pgx.Row, extra ...interface{}) (*models.DeliveryZone, error) {
+	var zone models.DeliveryZone
+	var polygonJSON, feeTiersJSON []byte
+
+	dest := []interface{}{
+		&zone.ID,
+		&zone.AdminID,
+		&zone.BranchID,
+		&zone.Name,
+		&polygonJSON,
+		&zone.MinOrderAmount,
+		&zone.Currency,
+		&zone.FeeType,
+		&zone.Fee,
+		&feeTiersJSON,
+		&zone.EtaMinutes,
+		&zone.IsActive,
+		&zone.SortOrder,
+		&zone.CreatedAt,
+		&zone.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(polygonJSON, &zone.Polygon); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal delivery zone polygon: %w", err)
+	}
+
+	if err := json.Unmarshal(feeTiersJSON, &zone.FeeTiers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal delivery fee tiers: %w", err)
+	}
+
+	return &zone, nil
+}
+
+// marshalDeliveryZone converts the polygon and fee tiers of a delivery zone to JSON
+func marshalDeliveryZone(zone *models.DeliveryZone) ([]byte, []byte, error) {
+	polygonJSON, err := json.Marshal(zone.Polygon)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal delivery zone polygon: %w", err)
+	}
+
+	feeTiers := zone.FeeTiers
+	if feeTiers == nil {
+		feeTiers = []models.DeliveryFeeTier{}
+	}
+	feeTiersJSON, err := json.Marshal(feeTiers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal delivery fee tiers: %w", err)
+	}
+
+	return polygonJSON, feeTiersJSON, nil
+}
+
+// Create creates a new delivery zone
+func (r *DeliveryZoneRepository) Create(ctx context.Context, zone *models.DeliveryZone) error {
+	polygonJSON, feeTiersJSON, err := marshalDeliveryZone(zone)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO delivery_zone (
+			admin_id, branch_id, name, polygon, min_order_amount, currency, fee_type, fee,
+			fee_tiers, eta_minutes, is_active, sort_order
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		zone.AdminID,
+		zone.BranchID,
+		zone.Name,
+		polygonJSON,
+		zone.MinOrderAmount,
+		zone.Currency,
+		zone.FeeType,
+		zone.Fee,
+		feeTiersJSON,
+		zone.EtaMinutes,
+		zone.IsActive,
+		zone.SortOrder,
+	).Scan(
+		&zone.ID,
+		&zone.CreatedAt,
+		&zone.UpdatedAt,
+	)
+}
+
+// GetByID retrieves a delivery zone by ID
+func (r *DeliveryZoneRepository) GetByID(ctx context.Context, id int) (*models.DeliveryZone, error) {
+	query := `SELECT ` + deliveryZoneColumns + ` FROM delivery_zone WHERE id = $1`
+
+	zone, err := scanDeliveryZone(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// deliveryZoneList describes how delivery zone lists are filtered and sorted
+var deliveryZoneList = listSpec{
+	table:    "delivery_zone",
+	columns:  deliveryZoneColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"name":       {"name", "TEXT"},
+		"sort_order": {"sort_order", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "sort_order",
+	statusColumn:  "CASE WHEN is_active THEN 'active' ELSE 'inactive' END",
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"name"},
+}
+
+// List retrieves one page of delivery zones
+func (r *DeliveryZoneRepository) List(ctx context.Context, params models.ListParams) ([]*models.DeliveryZone, *models.ListMeta, error) {
+	return queryList(ctx, r.db, deliveryZoneList, params, nil, scanDeliveryZone)
+}
+
+// ListActiveByAdminID retrieves the active delivery zones of an admin, first matching first
+func (r *DeliveryZoneRepository) ListActiveByAdminID(ctx context.Context, adminID int) ([]*models.DeliveryZone, error) {
+	query := `
+		SELECT ` + deliveryZoneColumns + `
+		FROM delivery_zone
+		WHERE admin_id = $1 AND is_active
+		ORDER BY sort_order, id
+	`
+
+	rows, err := r.db.Query(ctx, query, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	zones := []*models.DeliveryZone{}
+	for rows.Next() {
+		zone, err := scanDeliveryZone(rows)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+	}
+
+	return zones, rows.Err()
+}
+
+// Update updates a delivery zone
+func (r *DeliveryZoneRepository) Update(ctx context.Context, zone *models.DeliveryZone) error {
+	polygonJSON, feeTiersJSON, err := marshalDeliveryZone(zone)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE delivery_zone
+		SET branch_id = $2, name = $3, polygon = $4, min_order_amount = $5, currency = $6,
+			fee_type = $7, fee = $8, fee_tiers = $9, eta_minutes = $10, is_active = $11,
+			sort_order = $12
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err = r.db.QueryRow(ctx, query,
+		zone.ID,
+		zone.BranchID,
+		zone.Name,
+		polygonJSON,
+		zone.MinOrderAmount,
+		zone.Currency,
+		zone.FeeType,
+		zone.Fee,
+		feeTiersJSON,
+		zone.EtaMinutes,
+		zone.IsActive,
+		zone.SortOrder,
+	).Scan(&zone.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrResourceNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete deletes a delivery zone
+func (r *DeliveryZoneRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM delivery_zone WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
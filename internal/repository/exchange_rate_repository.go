@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExchangeRateRepository handles database operations for exchange rates
+type ExchangeRateRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository
+func NewExchangeRateRepository(db *pgxpool.Pool) *ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		db: db,
+	}
+}
+
+// scanExchangeRate scans a single exchange rate row, followed by any extra columns into extra
+func scanExchangeRate(row pgx.Row, extra ...interface{}) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	var createdBy sql.NullInt32
+
+	dest := []interface{}{
+		&rate.ID,
+		&rate.BaseCurrency,
+		&rate.QuoteCurrency,
+		&rate.Rate,
+		&rate.EffectiveDate,
+		&createdBy,
+		&rate.CreatedAt,
+		&rate.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if createdBy.Valid {
+		val := int(createdBy.Int32)
+		rate.CreatedBy = &val
+	}
+
+	return &rate, nil
+}
+
+// Upsert stores an exchange rate, replacing the rate of the same pair and date
+func (r *ExchangeRateRepository) Upsert(ctx context.Context, rate *models.ExchangeRate) error {
+	query := `
+		INSERT INTO exchange_rate (base_currency, quote_currency, rate, effective_date, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (base_currency, quote_currency, effective_date)
+		DO UPDATE SET rate = EXCLUDED.rate, created_by = EXCLUDED.created_by
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		rate.BaseCurrency,
+		rate.QuoteCurrency,
+		rate.Rate,
+		rate.EffectiveDate,
+		rate.CreatedBy,
+	).Scan(
+		&rate.ID,
+		&rate.CreatedAt,
+		&rate.UpdatedAt,
+	)
+}
+
+// exchangeRateList describes how exchange rate lists are filtered and sorted
+var exchangeRateList = listSpec{
+	table:    "exchange_rate",
+	columns:  "id, base_currency, quote_currency, rate, effective_date, created_by, created_at, updated_at",
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":             {"id", "BIGINT"},
+		"effective_date": {"effective_date", "DATE"},
+	},
+	defaultSort:   "effective_date",
+	defaultDesc:   true,
+	dateColumn:    "effective_date",
+	searchColumns: []string{"base_currency", "quote_currency"},
+}
+
+// List retrieves one page of exchange rates, newest first by default
+func (r *ExchangeRateRepository) List(ctx context.Context, params models.ListParams) ([]*models.ExchangeRate, *models.ListMeta, error) {
+	return queryList(ctx, r.db, exchangeRateList, params, nil, scanExchangeRate)
+}
+
+// GetLatest retrieves the most recent rate of a currency pair effective on the given date
+func (r *ExchangeRateRepository) GetLatest(ctx context.Context, base, quote string, at time.Time) (*models.ExchangeRate, error) {
+	query := `
+		SELECT id, base_currency, quote_currency, rate, effective_date, created_by, created_at, updated_at
+		FROM exchange_rate
+		WHERE base_currency = $1 AND quote_currency = $2 AND effective_date <= $3
+		ORDER BY effective_date DESC
+		LIMIT 1
+	`
+
+	rate, err := scanExchangeRate(r.db.QueryRow(ctx, query, base, quote, at))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// Delete deletes an exchange rate
+func (r *ExchangeRateRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM exchange_rate WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
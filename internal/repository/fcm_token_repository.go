@@ -8,6 +8,7 @@ import (
 	"mobilka/internal/models"
 	"mobilka/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -46,7 +47,7 @@ func (r *FCMTokenRepository) Create(ctx context.Context, fcmToken *models.FCMTok
 // GetByID retrieves an FCM token by ID
 func (r *FCMTokenRepository) GetByID(ctx context.Context, id int) (*models.FCMToken, error) {
 	query := `
-		SELECT id, admin_id, fcm_token, created_at, updated_at
+		SELECT id, admin_id, customer_id, fcm_token, created_at, updated_at
 		FROM fcm_token
 		WHERE id = $1
 	`
@@ -55,6 +56,7 @@ func (r *FCMTokenRepository) GetByID(ctx context.Context, id int) (*models.FCMTo
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&fcmToken.ID,
 		&fcmToken.AdminID,
+		&fcmToken.CustomerID,
 		&fcmToken.FCMToken,
 		&fcmToken.CreatedAt,
 		&fcmToken.UpdatedAt,
@@ -73,7 +75,7 @@ func (r *FCMTokenRepository) GetByID(ctx context.Context, id int) (*models.FCMTo
 // GetByToken retrieves an FCM token by the token string
 func (r *FCMTokenRepository) GetByToken(ctx context.Context, token string) (*models.FCMToken, error) {
 	query := `
-		SELECT id, admin_id, fcm_token, created_at, updated_at
+		SELECT id, admin_id, customer_id, fcm_token, created_at, updated_at
 		FROM fcm_token
 		WHERE fcm_token = $1
 	`
@@ -82,6 +84,7 @@ func (r *FCMTokenRepository) GetByToken(ctx context.Context, token string) (*mod
 	err := r.db.QueryRow(ctx, query, token).Scan(
 		&fcmToken.ID,
 		&fcmToken.AdminID,
+		&fcmToken.CustomerID,
 		&fcmToken.FCMToken,
 		&fcmToken.CreatedAt,
 		&fcmToken.UpdatedAt,
@@ -100,7 +103,7 @@ func (r *FCMTokenRepository) GetByToken(ctx context.Context, token string) (*mod
 // GetByAdminID retrieves all FCM tokens for a specific admin
 func (r *FCMTokenRepository) GetByAdminID(ctx context.Context, adminID int) ([]*models.FCMToken, error) {
 	query := `
-		SELECT id, admin_id, fcm_token, created_at, updated_at
+		SELECT id, admin_id, customer_id, fcm_token, created_at, updated_at
 		FROM fcm_token
 		WHERE admin_id = $1
 		ORDER BY id
@@ -118,6 +121,7 @@ func (r *FCMTokenRepository) GetByAdminID(ctx context.Context, adminID int) ([]*
 		err := rows.Scan(
 			&fcmToken.ID,
 			&fcmToken.AdminID,
+			&fcmToken.CustomerID,
 			&fcmToken.FCMToken,
 			&fcmToken.CreatedAt,
 			&fcmToken.UpdatedAt,
@@ -135,41 +139,43 @@ func (r *FCMTokenRepository) GetByAdminID(ctx context.Context, adminID int) ([]*
 	return fcmTokens, nil
 }
 
-// GetAll retrieves all FCM tokens
-func (r *FCMTokenRepository) GetAll(ctx context.Context) ([]*models.FCMToken, error) {
-	query := `
-		SELECT id, admin_id, fcm_token, created_at, updated_at
-		FROM fcm_token
-		ORDER BY id
-	`
-
-	rows, err := r.db.Query(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// fcmTokenList describes how FCM token lists are filtered and sorted
+var fcmTokenList = listSpec{
+	table:    "fcm_token",
+	columns:  "id, admin_id, customer_id, fcm_token, created_at, updated_at",
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort: "id",
+	dateColumn:  "created_at",
+	adminColumn: "admin_id",
+}
 
-	var fcmTokens []*models.FCMToken
-	for rows.Next() {
-		var fcmToken models.FCMToken
-		err := rows.Scan(
-			&fcmToken.ID,
-			&fcmToken.AdminID,
-			&fcmToken.FCMToken,
-			&fcmToken.CreatedAt,
-			&fcmToken.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		fcmTokens = append(fcmTokens, &fcmToken)
+// scanFCMToken scans an FCM token row selected with the fcmTokenList columns, followed by
+// any extra columns into extra
+func scanFCMToken(row pgx.Row, extra ...interface{}) (*models.FCMToken, error) {
+	var fcmToken models.FCMToken
+	dest := []interface{}{
+		&fcmToken.ID,
+		&fcmToken.AdminID,
+		&fcmToken.CustomerID,
+		&fcmToken.FCMToken,
+		&fcmToken.CreatedAt,
+		&fcmToken.UpdatedAt,
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
-	return fcmTokens, nil
+	return &fcmToken, nil
+}
+
+// List retrieves one page of FCM tokens
+func (r *FCMTokenRepository) List(ctx context.Context, params models.ListParams) ([]*models.FCMToken, *models.ListMeta, error) {
+	return queryList(ctx, r.db, fcmTokenList, params, nil, scanFCMToken)
 }
 
 // Delete deletes an FCM token
@@ -206,6 +212,87 @@ func (r *FCMTokenRepository) DeleteByToken(ctx context.Context, token string) er
 	return nil
 }
 
+// GetByCustomerID retrieves the FCM tokens of the devices a customer is logged in on
+func (r *FCMTokenRepository) GetByCustomerID(ctx context.Context, customerID int) ([]*models.FCMToken, error) {
+	query := `
+		SELECT id, admin_id, customer_id, fcm_token, created_at, updated_at
+		FROM fcm_token
+		WHERE customer_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fcmTokens := []*models.FCMToken{}
+	for rows.Next() {
+		fcmToken, err := scanFCMToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		fcmTokens = append(fcmTokens, fcmToken)
+	}
+
+	return fcmTokens, rows.Err()
+}
+
+// SaveForCustomer links an FCM token to the customer logged in on the device, registering
+// the token when it is new. A device only belongs to its last customer.
+func (r *FCMTokenRepository) SaveForCustomer(ctx context.Context, fcmToken *models.FCMToken) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		UPDATE fcm_token SET admin_id = $2, customer_id = $3
+		WHERE id = (SELECT id FROM fcm_token WHERE fcm_token = $1 ORDER BY id LIMIT 1)
+		RETURNING id, created_at, updated_at
+	`, fcmToken.FCMToken, fcmToken.AdminID, fcmToken.CustomerID).Scan(
+		&fcmToken.ID,
+		&fcmToken.CreatedAt,
+		&fcmToken.UpdatedAt,
+	)
+	if isNoRows(err) {
+		err = tx.QueryRow(ctx, `
+			INSERT INTO fcm_token (admin_id, customer_id, fcm_token)
+			VALUES ($1, $2, $3)
+			RETURNING id, created_at, updated_at
+		`, fcmToken.AdminID, fcmToken.CustomerID, fcmToken.FCMToken).Scan(
+			&fcmToken.ID,
+			&fcmToken.CreatedAt,
+			&fcmToken.UpdatedAt,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UnlinkCustomer unlinks an FCM token from a customer logging out, keeping the device
+// registered for the admin's notifications
+func (r *FCMTokenRepository) UnlinkCustomer(ctx context.Context, customerID int, token string) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE fcm_token SET customer_id = NULL
+		WHERE fcm_token = $1 AND customer_id = $2
+	`, token, customerID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
+
 // DeleteByAdminID deletes all FCM tokens for a specific admin
 func (r *FCMTokenRepository) DeleteByAdminID(ctx context.Context, adminID int) error {
 	query := `DELETE FROM fcm_token WHERE admin_id = $1`
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// invoiceColumns are the columns of the invoice view read by scanInvoice
+const invoiceColumns = `
+	id, number, payment_id, admin_id, company_name, issued_at, currency, tier_name,
+	billing_interval, months, period_start, period_end, is_top_up, subtotal, discount_amount,
+	prorated_amount, total, refunded_amount, net_amount, status
+`
+
+// InvoiceRepository handles database operations for invoices, which are derived from verified payments
+type InvoiceRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(db *pgxpool.Pool) *InvoiceRepository {
+	return &InvoiceRepository{
+		db: db,
+	}
+}
+
+// scanInvoice scans an invoice row selected with invoiceColumns, followed by any extra
+// columns into extra
+func scanInvoice(row pgx.Row, extra ...interface{}) (*models.Invoice, error) {
+	var invoice models.Invoice
+	var periodStart, periodEnd sql.NullTime
+
+	dest := []interface{}{
+		&invoice.ID,
+		&invoice.Number,
+		&invoice.PaymentID,
+		&invoice.AdminID,
+		&invoice.CompanyName,
+		&invoice.IssuedAt,
+		&invoice.Currency,
+		&invoice.TierName,
+		&invoice.BillingInterval,
+		&invoice.Months,
+		&periodStart,
+		&periodEnd,
+		&invoice.IsTopUp,
+		&invoice.Subtotal,
+		&invoice.DiscountAmount,
+		&invoice.ProratedAmount,
+		&invoice.Total,
+		&invoice.RefundedAmount,
+		&invoice.NetAmount,
+		&invoice.Status,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if periodStart.Valid {
+		invoice.PeriodStart = &periodStart.Time
+	}
+	if periodEnd.Valid {
+		invoice.PeriodEnd = &periodEnd.Time
+	}
+
+	return &invoice, nil
+}
+
+// GetByID retrieves an invoice by ID, which is the ID of its payment
+func (r *InvoiceRepository) GetByID(ctx context.Context, id int) (*models.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoice WHERE id = $1`
+
+	invoice, err := scanInvoice(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// invoiceList describes how invoice lists are filtered and sorted
+var invoiceList = listSpec{
+	table:    "invoice",
+	columns:  invoiceColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":        {"id", "BIGINT"},
+		"issued_at": {"issued_at", "TIMESTAMPTZ"},
+		"total":     {"total", "BIGINT"},
+	},
+	defaultSort:   "issued_at",
+	defaultDesc:   true,
+	statusColumn:  "status",
+	dateColumn:    "issued_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"number", "company_name"},
+}
+
+// invoiceCurrencyQuery limits invoices to a currency, or returns nil for every currency
+func invoiceCurrencyQuery(currency string) *listQuery {
+	if currency == "" {
+		return nil
+	}
+
+	q := &listQuery{}
+	q.add("currency = $%d", currency)
+	return q
+}
+
+// Stream calls fn for every invoice matching the list filters of params and a currency,
+// oldest first. Rows are read one at a time so exports of any size don't have to fit in memory.
+func (r *InvoiceRepository) Stream(ctx context.Context, params models.ListParams, currency string, fn func(invoice *models.Invoice) error) error {
+	q := &listQuery{}
+	if currency != "" {
+		q.add("currency = $%d", currency)
+	}
+	if err := invoiceList.filters(q, params); err != nil {
+		return err
+	}
+
+	rows, err := r.db.Query(ctx, `SELECT `+invoiceColumns+` FROM invoice `+q.where()+` ORDER BY issued_at, id`, q.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(invoice); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// List retrieves one page of invoices, of one currency when currency is set
+func (r *InvoiceRepository) List(ctx context.Context, params models.ListParams, currency string) ([]*models.Invoice, *models.ListMeta, error) {
+	return queryList(ctx, r.db, invoiceList, params, invoiceCurrencyQuery(currency), scanInvoice)
+}
@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// adminBalanceQuery sums the admin_balance account of admin $1 in currency $2
+const adminBalanceQuery = `
+	SELECT COALESCE(SUM(e.credit - e.debit), 0)::BIGINT
+	FROM ledger_entry e
+	JOIN ledger_transaction t ON t.id = e.transaction_id
+	WHERE t.admin_id = $1 AND t.currency = $2 AND e.account = 'admin_balance'
+`
+
+// LedgerRepository handles database operations for the double-entry billing ledger
+type LedgerRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewLedgerRepository creates a new ledger repository
+func NewLedgerRepository(db *pgxpool.Pool) *LedgerRepository {
+	return &LedgerRepository{
+		db: db,
+	}
+}
+
+// insertLedgerTransaction writes a transaction and its entries inside a database transaction.
+// The database checks once more that the entries balance when the transaction commits.
+func insertLedgerTransaction(ctx context.Context, tx pgx.Tx, txn *models.LedgerTransaction) error {
+	if !txn.IsBalanced() {
+		return utils.NewInvalidInputError("Ledger transaction is not balanced")
+	}
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO ledger_transaction (admin_id, type, currency, amount, description, payment_id, refund_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`,
+		txn.AdminID,
+		txn.Type,
+		txn.Currency,
+		txn.Amount,
+		txn.Description,
+		txn.PaymentID,
+		txn.RefundID,
+		txn.CreatedBy,
+	).Scan(&txn.ID, &txn.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range txn.Entries {
+		entry.TransactionID = txn.ID
+		err = tx.QueryRow(ctx, `
+			INSERT INTO ledger_entry (transaction_id, account, debit, credit)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, entry.TransactionID, entry.Account, entry.Debit, entry.Credit).Scan(&entry.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkBalanceCovers returns a conflict error when transactions of one admin and currency
+// would take the admin's balance below zero. The admin stays locked until the database
+// transaction ends so concurrent postings can't overdraw the balance together.
+func checkBalanceCovers(ctx context.Context, tx pgx.Tx, txns []*models.LedgerTransaction) error {
+	if len(txns) == 0 {
+		return nil
+	}
+
+	var change int64
+	for _, txn := range txns {
+		change += txn.BalanceChange()
+	}
+	if change >= 0 {
+		return nil
+	}
+
+	adminID, currency := txns[0].AdminID, txns[0].Currency
+
+	var id int
+	err := tx.QueryRow(ctx, `SELECT id FROM admin WHERE id = $1 FOR UPDATE`, adminID).Scan(&id)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrUserNotFound
+		}
+		return err
+	}
+
+	var balance int64
+	if err := tx.QueryRow(ctx, adminBalanceQuery, adminID, currency).Scan(&balance); err != nil {
+		return err
+	}
+
+	if balance+change < 0 {
+		return utils.NewConflictError(utils.CodeInsufficientBalance, "Insufficient balance: "+
+			utils.FormatMoney(balance, currency)+" available, "+
+			utils.FormatMoney(-change, currency)+" needed")
+	}
+
+	return nil
+}
+
+// Post writes ledger transactions atomically
+func (r *LedgerRepository) Post(ctx context.Context, txns ...*models.LedgerTransaction) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, txn := range txns {
+		if err := insertLedgerTransaction(ctx, tx, txn); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// PostFromBalance writes transactions atomically, refusing them when they would take
+// the admin's balance below zero
+func (r *LedgerRepository) PostFromBalance(ctx context.Context, txns ...*models.LedgerTransaction) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkBalanceCovers(ctx, tx, txns); err != nil {
+		return err
+	}
+
+	for _, txn := range txns {
+		if err := insertLedgerTransaction(ctx, tx, txn); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetBalance returns an admin's prepaid balance in a currency
+func (r *LedgerRepository) GetBalance(ctx context.Context, adminID int, currency string) (int64, error) {
+	var balance int64
+	err := r.db.QueryRow(ctx, adminBalanceQuery, adminID, currency).Scan(&balance)
+	return balance, err
+}
+
+// GetBalances returns an admin's prepaid balance in every currency with ledger activity
+func (r *LedgerRepository) GetBalances(ctx context.Context, adminID int) (map[string]int64, error) {
+	query := `
+		SELECT t.currency, COALESCE(SUM(e.credit - e.debit), 0)::BIGINT
+		FROM ledger_entry e
+		JOIN ledger_transaction t ON t.id = e.transaction_id
+		WHERE t.admin_id = $1 AND e.account = 'admin_balance'
+		GROUP BY t.currency
+		ORDER BY t.currency
+	`
+
+	rows, err := r.db.Query(ctx, query, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := map[string]int64{}
+	for rows.Next() {
+		var currency string
+		var balance int64
+		if err := rows.Scan(&currency, &balance); err != nil {
+			return nil, err
+		}
+		balances[currency] = balance
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// GetStatement retrieves an admin's balance movements in a currency, oldest first, with
+// the running balance after each. From is inclusive and To exclusive, both optional.
+func (r *LedgerRepository) GetStatement(ctx context.Context, adminID int, currency string, from, to *time.Time) (*models.LedgerStatement, error) {
+	statement := &models.LedgerStatement{
+		AdminID:  adminID,
+		Currency: currency,
+		From:     from,
+		To:       to,
+		Lines:    []*models.LedgerStatementLine{},
+	}
+
+	// The running balance is taken over the whole history so it stays right for any range
+	query := `
+		SELECT id, type, description, payment_id, refund_id, change, balance, created_at
+		FROM (
+			SELECT t.id, t.type, t.description, t.payment_id, t.refund_id, t.created_at,
+			       SUM(e.credit - e.debit)::BIGINT AS change,
+			       (SUM(SUM(e.credit - e.debit)) OVER (ORDER BY t.created_at, t.id))::BIGINT AS balance
+			FROM ledger_transaction t
+			JOIN ledger_entry e ON e.transaction_id = t.id AND e.account = 'admin_balance'
+			WHERE t.admin_id = $1 AND t.currency = $2
+			  AND ($4::TIMESTAMPTZ IS NULL OR t.created_at < $4)
+			GROUP BY t.id
+		) lines
+		WHERE $3::TIMESTAMPTZ IS NULL OR created_at >= $3
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, adminID, currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.LedgerStatementLine
+		var paymentID, refundID sql.NullInt32
+
+		err := rows.Scan(
+			&line.TransactionID,
+			&line.Type,
+			&line.Description,
+			&paymentID,
+			&refundID,
+			&line.Change,
+			&line.Balance,
+			&line.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if paymentID.Valid {
+			val := int(paymentID.Int32)
+			line.PaymentID = &val
+		}
+		if refundID.Valid {
+			val := int(refundID.Int32)
+			line.RefundID = &val
+		}
+
+		statement.Lines = append(statement.Lines, &line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(statement.Lines) > 0 {
+		first, last := statement.Lines[0], statement.Lines[len(statement.Lines)-1]
+		statement.OpeningBalance = first.Balance - first.Change
+		statement.ClosingBalance = last.Balance
+		return statement, nil
+	}
+
+	// Without movements in the range both balances are the balance at its start
+	if from != nil {
+		err = r.db.QueryRow(ctx, adminBalanceQuery+` AND t.created_at < $3`, adminID, currency, *from).Scan(&statement.OpeningBalance)
+		if err != nil {
+			return nil, err
+		}
+	}
+	statement.ClosingBalance = statement.OpeningBalance
+
+	return statement, nil
+}
+
+// GetAdminsToRenew returns the admins whose subscription ends before a time and who have
+// a prepaid balance in their billing currency
+func (r *LedgerRepository) GetAdminsToRenew(ctx context.Context, before time.Time) ([]int, error) {
+	query := `
+		SELECT a.id
+		FROM admin a
+		WHERE a.subscription_status IN ('active', 'trial', 'expired')
+		  AND (a.subscription_expires_at IS NULL OR a.subscription_expires_at < $1)
+		  AND (
+			SELECT COALESCE(SUM(e.credit - e.debit), 0)
+			FROM ledger_entry e
+			JOIN ledger_transaction t ON t.id = e.transaction_id
+			WHERE t.admin_id = a.id AND t.currency = a.billing_currency AND e.account = 'admin_balance'
+		  ) > 0
+		ORDER BY a.subscription_expires_at NULLS FIRST, a.id
+	`
+
+	rows, err := r.db.Query(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
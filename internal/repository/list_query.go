@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// listSort is a sort key of a list: a non-null SQL expression and the type its cursor
+// values are cast back to
+type listSort struct {
+	expr    string
+	sqlType string
+}
+
+// listSpec describes how list parameters map to the SQL of one list. Empty filter columns
+// mean the list doesn't support that filter.
+type listSpec struct {
+	table         string // FROM clause, joins included
+	columns       string // Selected columns, read by the scan function
+	idColumn      string // Unique column that breaks ties between equal sort values
+	sorts         map[string]listSort
+	defaultSort   string
+	defaultDesc   bool
+	statusColumn  string
+	dateColumn    string
+	adminColumn   string
+	searchColumns []string // Searched case-insensitively for the search text
+}
+
+// listQuery collects the conditions and arguments of a WHERE clause
+type listQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add adds a condition with one %d verb per argument, replaced by the argument's placeholder number
+func (q *listQuery) add(condition string, args ...interface{}) {
+	placeholders := make([]interface{}, len(args))
+	for i, arg := range args {
+		q.args = append(q.args, arg)
+		placeholders[i] = len(q.args)
+	}
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, placeholders...))
+}
+
+// where returns the WHERE clause, or an empty string without conditions
+func (q *listQuery) where() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// listCursor is the position after the last item of a page
+type listCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    string `json:"i"`
+}
+
+// encodeListCursor encodes a cursor for the next_cursor field
+func encodeListCursor(cursor listCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeListCursor decodes a cursor and checks that it was issued for the same order
+func decodeListCursor(value string, sortKey string, desc bool) (*listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, utils.NewInvalidInputError("Invalid cursor")
+	}
+
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, utils.NewInvalidInputError("Invalid cursor")
+	}
+
+	if cursor.Sort != sortKey || cursor.Desc != desc {
+		return nil, utils.NewInvalidInputError("Cursor was issued for a different sort order")
+	}
+
+	return &cursor, nil
+}
+
+// filters adds the filter conditions of list parameters to a query, rejecting filters the
+// list doesn't support
+func (spec listSpec) filters(q *listQuery, params models.ListParams) error {
+	unsupported := func(name string) error {
+		return utils.NewInvalidInputError("Filtering by " + name + " is not supported by this list")
+	}
+
+	if params.Status != "" {
+		if spec.statusColumn == "" {
+			return unsupported("status")
+		}
+		q.add(spec.statusColumn+" = $%d", params.Status)
+	}
+
+	if params.From != nil || params.To != nil {
+		if spec.dateColumn == "" {
+			return unsupported("date")
+		}
+		if params.From != nil {
+			q.add(spec.dateColumn+" >= $%d", *params.From)
+		}
+		if params.To != nil {
+			q.add(spec.dateColumn+" < $%d", *params.To)
+		}
+	}
+
+	if params.AdminID != nil {
+		if spec.adminColumn == "" {
+			return unsupported("admin")
+		}
+		q.add(spec.adminColumn+" = $%d", *params.AdminID)
+	}
+
+	if search := strings.TrimSpace(params.Search); search != "" {
+		if len(spec.searchColumns) == 0 {
+			return unsupported("search text")
+		}
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(search) + "%"
+		matches := make([]string, len(spec.searchColumns))
+		for i, column := range spec.searchColumns {
+			matches[i] = column + " ILIKE $%[1]d"
+		}
+		q.add("("+strings.Join(matches, " OR ")+")", pattern)
+	}
+
+	return nil
+}
+
+// queryList runs a list query: it counts the items matching the filters and the scope,
+// then reads one page in the requested order. Pages continue either from an offset or,
+// more efficiently and stable under inserts, from the cursor of the previous page. scan
+// reads the list's columns followed by the extra columns passed to it.
+func queryList[T any](ctx context.Context, db *pgxpool.Pool, spec listSpec, params models.ListParams, scope *listQuery,
+	scan func(row pgx.Row, extra ...interface{}) (T, error)) ([]T, *models.ListMeta, error) {
+
+	sortKey, desc := params.Sort, params.Desc
+	if sortKey == "" {
+		sortKey, desc = spec.defaultSort, spec.defaultDesc
+	}
+	column, ok := spec.sorts[sortKey]
+	if !ok {
+		keys := make([]string, 0, len(spec.sorts))
+		for key := range spec.sorts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return nil, nil, utils.NewInvalidInputError("Unsupported sort " + sortKey + ", expected one of " + strings.Join(keys, ", "))
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = models.DefaultListLimit
+	}
+	if limit > models.MaxListLimit {
+		limit = models.MaxListLimit
+	}
+
+	q := &listQuery{}
+	if scope != nil {
+		q.conditions = append(q.conditions, scope.conditions...)
+		q.args = append(q.args, scope.args...)
+	}
+	if err := spec.filters(q, params); err != nil {
+		return nil, nil, err
+	}
+
+	meta := &models.ListMeta{
+		Limit: limit,
+		Sort:  sortKey,
+		Order: "asc",
+	}
+	if desc {
+		meta.Order = "desc"
+	}
+
+	err := db.QueryRow(ctx, `SELECT COUNT(*) FROM `+spec.table+` `+q.where(), q.args...).Scan(&meta.Total)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if params.Cursor != "" {
+		cursor, err := decodeListCursor(params.Cursor, sortKey, desc)
+		if err != nil {
+			return nil, nil, err
+		}
+		q.add(fmt.Sprintf("(%s, %s) %s ($%%d::%s, $%%d::BIGINT)", column.expr, spec.idColumn, comparison, column.sqlType), cursor.Value, cursor.ID)
+	} else if params.Offset > 0 {
+		meta.Offset = params.Offset
+	}
+
+	// One extra row tells whether there is a next page
+	query := fmt.Sprintf(`SELECT %s, (%s)::TEXT, (%s)::TEXT FROM %s %s ORDER BY %s %s, %s %s LIMIT %d OFFSET %d`,
+		spec.columns, column.expr, spec.idColumn, spec.table, q.where(),
+		column.expr, direction, spec.idColumn, direction, limit+1, meta.Offset)
+
+	rows, err := db.Query(ctx, query, q.args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	items := []T{}
+	var last listCursor
+	for rows.Next() {
+		if len(items) == limit {
+			next := encodeListCursor(listCursor{Sort: sortKey, Desc: desc, Value: last.Value, ID: last.ID})
+			meta.NextCursor = &next
+			break
+		}
+
+		item, err := scan(rows, &last.Value, &last.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return items, meta, nil
+}
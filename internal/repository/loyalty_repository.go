@@ -0,0 +1,411 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// loyaltyProgramColumns is the column list shared by all loyalty program queries
+const loyaltyProgramColumns = `
+	admin_id, is_active, currency, earn_basis_points, visit_points, min_order_amount,
+	point_value, redeem_max_percent, redeem_max_points, min_redeem_points, expiry_days,
+	expiry_notice_days, created_at, updated_at
+`
+
+// loyaltyTransactionColumns is the column list shared by all points ledger queries
+const loyaltyTransactionColumns = `
+	id, admin_id, customer_id, order_id, type, points, remaining, expires_at, description,
+	created_at
+`
+
+// openLotsCondition selects the ledger entries with unspent, unexpired points
+const openLotsCondition = `remaining > 0 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)`
+
+// LoyaltyRepository handles database operations for loyalty programs and the points ledger
+type LoyaltyRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewLoyaltyRepository creates a new loyalty repository
+func NewLoyaltyRepository(db *pgxpool.Pool) *LoyaltyRepository {
+	return &LoyaltyRepository{
+		db: db,
+	}
+}
+
+// scanLoyaltyTransaction scans a ledger row selected with loyaltyTransactionColumns,
+// followed by any extra columns into extra
+func scanLoyaltyTransaction(row pgx.Row, extra ...interface{}) (*models.LoyaltyTransaction, error) {
+	var txn models.LoyaltyTransaction
+
+	dest := []interface{}{
+		&txn.ID,
+		&txn.AdminID,
+		&txn.CustomerID,
+		&txn.OrderID,
+		&txn.Type,
+		&txn.Points,
+		&txn.Remaining,
+		&txn.ExpiresAt,
+		&txn.Description,
+		&txn.CreatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	return &txn, nil
+}
+
+// GetProgram retrieves the loyalty program of an admin
+func (r *LoyaltyRepository) GetProgram(ctx context.Context, adminID int) (*models.LoyaltyProgram, error) {
+	query := `SELECT ` + loyaltyProgramColumns + ` FROM loyalty_program WHERE admin_id = $1`
+
+	var program models.LoyaltyProgram
+	err := r.db.QueryRow(ctx, query, adminID).Scan(
+		&program.AdminID,
+		&program.IsActive,
+		&program.Currency,
+		&program.EarnBasisPoints,
+		&program.VisitPoints,
+		&program.MinOrderAmount,
+		&program.PointValue,
+		&program.RedeemMaxPercent,
+		&program.RedeemMaxPoints,
+		&program.MinRedeemPoints,
+		&program.ExpiryDays,
+		&program.ExpiryNoticeDays,
+		&program.CreatedAt,
+		&program.UpdatedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return &program, nil
+}
+
+// SaveProgram creates or replaces the loyalty program of an admin
+func (r *LoyaltyRepository) SaveProgram(ctx context.Context, program *models.LoyaltyProgram) error {
+	query := `
+		INSERT INTO loyalty_program (
+			admin_id, is_active, currency, earn_basis_points, visit_points, min_order_amount,
+			point_value, redeem_max_percent, redeem_max_points, min_redeem_points, expiry_days,
+			expiry_notice_days
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (admin_id) DO UPDATE SET
+			is_active = EXCLUDED.is_active,
+			currency = EXCLUDED.currency,
+			earn_basis_points = EXCLUDED.earn_basis_points,
+			visit_points = EXCLUDED.visit_points,
+			min_order_amount = EXCLUDED.min_order_amount,
+			point_value = EXCLUDED.point_value,
+			redeem_max_percent = EXCLUDED.redeem_max_percent,
+			redeem_max_points = EXCLUDED.redeem_max_points,
+			min_redeem_points = EXCLUDED.min_redeem_points,
+			expiry_days = EXCLUDED.expiry_days,
+			expiry_notice_days = EXCLUDED.expiry_notice_days
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		program.AdminID,
+		program.IsActive,
+		program.Currency,
+		program.EarnBasisPoints,
+		program.VisitPoints,
+		program.MinOrderAmount,
+		program.PointValue,
+		program.RedeemMaxPercent,
+		program.RedeemMaxPoints,
+		program.MinRedeemPoints,
+		program.ExpiryDays,
+		program.ExpiryNoticeDays,
+	).Scan(
+		&program.CreatedAt,
+		&program.UpdatedAt,
+	)
+}
+
+// GetBalance returns the spendable points of a customer, and how many of them expire
+// before a time together with the earliest of those expiries
+func (r *LoyaltyRepository) GetBalance(ctx context.Context, customerID int, before time.Time) (int64, int64, *time.Time, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(remaining), 0)::BIGINT,
+			COALESCE(SUM(remaining) FILTER (WHERE expires_at <= $2), 0)::BIGINT,
+			MIN(expires_at) FILTER (WHERE expires_at <= $2)
+		FROM loyalty_transaction
+		WHERE customer_id = $1 AND ` + openLotsCondition
+
+	var balance, expiring int64
+	var nextExpiry *time.Time
+	err := r.db.QueryRow(ctx, query, customerID, before).Scan(&balance, &expiring, &nextExpiry)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	return balance, expiring, nextExpiry, nil
+}
+
+// insertLoyaltyTransaction writes a ledger entry inside a database transaction. It reports
+// false without writing when the order already has an entry of the type.
+func insertLoyaltyTransaction(ctx context.Context, tx pgx.Tx, txn *models.LoyaltyTransaction) (bool, error) {
+	err := tx.QueryRow(ctx, `
+		INSERT INTO loyalty_transaction (
+			admin_id, customer_id, order_id, type, points, remaining, expires_at, description
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (order_id, type) DO NOTHING
+		RETURNING id, created_at
+	`,
+		txn.AdminID,
+		txn.CustomerID,
+		txn.OrderID,
+		txn.Type,
+		txn.Points,
+		txn.Remaining,
+		txn.ExpiresAt,
+		txn.Description,
+	).Scan(&txn.ID, &txn.CreatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// redeemPoints spends points of a customer inside a database transaction, taking them
+// from the lots that expire first. The lots stay locked until the transaction ends so
+// concurrent orders can't spend the same points.
+func redeemPoints(ctx context.Context, tx pgx.Tx, txn *models.LoyaltyTransaction) error {
+	rows, err := tx.Query(ctx, `
+		SELECT id, remaining FROM loyalty_transaction
+		WHERE customer_id = $1 AND `+openLotsCondition+`
+		ORDER BY expires_at NULLS LAST, id
+		FOR UPDATE
+	`, txn.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	type lot struct {
+		id        int
+		remaining int64
+	}
+	lots := []lot{}
+	var balance int64
+	for rows.Next() {
+		var l lot
+		if err := rows.Scan(&l.id, &l.remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		lots = append(lots, l)
+		balance += l.remaining
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	needed := -txn.Points
+	if balance < needed {
+		return utils.NewConflictError(utils.CodeInsufficientBalance, "Not enough loyalty points")
+	}
+
+	for _, l := range lots {
+		if needed == 0 {
+			break
+		}
+
+		spent := l.remaining
+		if spent > needed {
+			spent = needed
+		}
+
+		_, err := tx.Exec(ctx, `UPDATE loyalty_transaction SET remaining = remaining - $2 WHERE id = $1`, l.id, spent)
+		if err != nil {
+			return err
+		}
+		needed -= spent
+	}
+
+	created, err := insertLoyaltyTransaction(ctx, tx, txn)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return utils.NewConflictError(utils.CodeConflict, "Points were already spent on this order")
+	}
+
+	return nil
+}
+
+// refundPoints returns the points spent on an order inside a database transaction, as a
+// new lot expiring like freshly earned points. Refunding an order twice does nothing.
+func refundPoints(ctx context.Context, tx pgx.Tx, txn *models.LoyaltyTransaction) error {
+	err := tx.QueryRow(ctx, `
+		INSERT INTO loyalty_transaction (
+			admin_id, customer_id, order_id, type, points, remaining, expires_at, description
+		)
+		SELECT $1, $2, $3, $4, $5, $5,
+			CASE WHEN p.expiry_days > 0
+				THEN CURRENT_TIMESTAMP + make_interval(days => p.expiry_days)
+			END,
+			$6
+		FROM (SELECT 1) AS one
+		LEFT JOIN loyalty_program p ON p.admin_id = $1
+		ON CONFLICT (order_id, type) DO NOTHING
+		RETURNING id, remaining, expires_at, created_at
+	`,
+		txn.AdminID,
+		txn.CustomerID,
+		txn.OrderID,
+		txn.Type,
+		txn.Points,
+		txn.Description,
+	).Scan(&txn.ID, &txn.Remaining, &txn.ExpiresAt, &txn.CreatedAt)
+	if err != nil && !isNoRows(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Earn records the points earned by an order. It reports false when the order already
+// earned its points.
+func (r *LoyaltyRepository) Earn(ctx context.Context, txn *models.LoyaltyTransaction) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	created, err := insertLoyaltyTransaction(ctx, tx, txn)
+	if err != nil || !created {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// ExpirePoints writes off the unspent points of every expired lot, with one expire entry
+// per customer, and returns the number of points written off
+func (r *LoyaltyRepository) ExpirePoints(ctx context.Context) (int64, error) {
+	query := `
+		WITH expired AS (
+			UPDATE loyalty_transaction t
+			SET remaining = 0
+			FROM (
+				SELECT id, remaining FROM loyalty_transaction
+				WHERE remaining > 0 AND expires_at <= CURRENT_TIMESTAMP
+				FOR UPDATE
+			) lot
+			WHERE t.id = lot.id
+			RETURNING t.admin_id, t.customer_id, lot.remaining
+		), written_off AS (
+			INSERT INTO loyalty_transaction (admin_id, customer_id, type, points, description)
+			SELECT admin_id, customer_id, 'expire', -SUM(remaining), 'Points expired'
+			FROM expired
+			GROUP BY admin_id, customer_id
+			RETURNING points
+		)
+		SELECT COALESCE(-SUM(points), 0)::BIGINT FROM written_off
+	`
+
+	var points int64
+	err := r.db.QueryRow(ctx, query).Scan(&points)
+	return points, err
+}
+
+// ListExpiryNotices returns the reminders due to customers whose points expire within the
+// notice period of an active program, grouped by customer. Each lot is reminded of once.
+func (r *LoyaltyRepository) ListExpiryNotices(ctx context.Context) ([]*models.LoyaltyExpiryNotice, error) {
+	query := `
+		SELECT t.admin_id, t.customer_id, SUM(t.remaining)::BIGINT, MIN(t.expires_at), ARRAY_AGG(t.id)
+		FROM loyalty_transaction t
+		JOIN loyalty_program p ON p.admin_id = t.admin_id
+		WHERE t.remaining > 0
+			AND t.expiry_notified_at IS NULL
+			AND t.expires_at > CURRENT_TIMESTAMP
+			AND p.is_active
+			AND p.expiry_notice_days > 0
+			AND t.expires_at <= CURRENT_TIMESTAMP + make_interval(days => p.expiry_notice_days)
+		GROUP BY t.admin_id, t.customer_id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notices := []*models.LoyaltyExpiryNotice{}
+	for rows.Next() {
+		var notice models.LoyaltyExpiryNotice
+		var lotIDs []int32
+		if err := rows.Scan(&notice.AdminID, &notice.CustomerID, &notice.Points, &notice.ExpiresAt, &lotIDs); err != nil {
+			return nil, err
+		}
+		for _, id := range lotIDs {
+			notice.LotIDs = append(notice.LotIDs, int(id))
+		}
+		notices = append(notices, &notice)
+	}
+
+	return notices, rows.Err()
+}
+
+// MarkExpiryNotified records that customers were reminded of the expiry of lots
+func (r *LoyaltyRepository) MarkExpiryNotified(ctx context.Context, lotIDs []int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE loyalty_transaction SET expiry_notified_at = CURRENT_TIMESTAMP
+		WHERE id = ANY($1)
+	`, idsParam(lotIDs))
+	return err
+}
+
+// loyaltyTransactionList describes how points ledger lists are filtered and sorted
+var loyaltyTransactionList = listSpec{
+	table:    "loyalty_transaction",
+	columns:  loyaltyTransactionColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"points":     {"points", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "created_at",
+	defaultDesc:   true,
+	statusColumn:  "type",
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"description"},
+}
+
+// ListTransactions retrieves one page of the points ledger, of one customer when
+// customerID is set
+func (r *LoyaltyRepository) ListTransactions(ctx context.Context, params models.ListParams, customerID *int) ([]*models.LoyaltyTransaction, *models.ListMeta, error) {
+	var scope *listQuery
+	if customerID != nil {
+		scope = &listQuery{}
+		scope.add("customer_id = $%d", *customerID)
+	}
+
+	return queryList(ctx, r.db, loyaltyTransactionList, params, scope, scanLoyaltyTransaction)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// isNoRows reports whether err means a query returned no rows.
+// pgx returns its own pgx.ErrNoRows, which is not sql.ErrNoRows.
+func isNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows)
+}
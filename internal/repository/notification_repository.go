@@ -9,6 +9,7 @@ import (
 	"mobilka/internal/models"
 	"mobilka/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -75,83 +76,47 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id int) (*models.N
 	return &notification, nil
 }
 
-// GetByAdminID retrieves all notifications for a specific admin
-func (r *NotificationRepository) GetByAdminID(ctx context.Context, adminID int) ([]*models.Notification, error) {
-	query := `
-		SELECT id, admin_id, payload, title, body, created_at, updated_at
-		FROM notification
-		WHERE admin_id = $1
-		ORDER BY id DESC
-	`
+// notificationList describes how notification lists are filtered and sorted
+var notificationList = listSpec{
+	table:    "notification",
+	columns:  "id, admin_id, payload, title, body, created_at, updated_at",
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+		"title":      {"title", "TEXT"},
+	},
+	defaultSort:   "created_at",
+	defaultDesc:   true,
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"title", "body"},
+}
 
-	rows, err := r.db.Query(ctx, query, adminID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var notifications []*models.Notification
-	for rows.Next() {
-		var notification models.Notification
-		err := rows.Scan(
-			&notification.ID,
-			&notification.AdminID,
-			&notification.Payload,
-			&notification.Title,
-			&notification.Body,
-			&notification.CreatedAt,
-			&notification.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		notifications = append(notifications, &notification)
+// scanNotification scans a notification row selected with the notificationList columns,
+// followed by any extra columns into extra
+func scanNotification(row pgx.Row, extra ...interface{}) (*models.Notification, error) {
+	var notification models.Notification
+	dest := []interface{}{
+		&notification.ID,
+		&notification.AdminID,
+		&notification.Payload,
+		&notification.Title,
+		&notification.Body,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
-	return notifications, nil
+	return &notification, nil
 }
 
-// GetAll retrieves all notifications
-func (r *NotificationRepository) GetAll(ctx context.Context) ([]*models.Notification, error) {
-	query := `
-		SELECT id, admin_id, payload, title, body, created_at, updated_at
-		FROM notification
-		ORDER BY id DESC
-	`
-
-	rows, err := r.db.Query(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var notifications []*models.Notification
-	for rows.Next() {
-		var notification models.Notification
-		err := rows.Scan(
-			&notification.ID,
-			&notification.AdminID,
-			&notification.Payload,
-			&notification.Title,
-			&notification.Body,
-			&notification.CreatedAt,
-			&notification.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		notifications = append(notifications, &notification)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return notifications, nil
+// List retrieves one page of notifications, newest first by default
+func (r *NotificationRepository) List(ctx context.Context, params models.ListParams) ([]*models.Notification, *models.ListMeta, error) {
+	return queryList(ctx, r.db, notificationList, params, nil, scanNotification)
 }
 
 // Update updates a notification
@@ -202,44 +167,3 @@ func (r *NotificationRepository) Delete(ctx context.Context, id int, adminID int
 
 	return nil
 }
-
-// GetByAdminIDWithPagination retrieves notifications for a specific admin with pagination
-func (r *NotificationRepository) GetByAdminIDWithPagination(ctx context.Context, adminID, skip, step int) ([]*models.Notification, error) {
-	query := `
-		SELECT id, admin_id, payload, title, body, created_at, updated_at
-		FROM notification
-		WHERE admin_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
-
-	rows, err := r.db.Query(ctx, query, adminID, step, skip)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var notifications []*models.Notification
-	for rows.Next() {
-		var notification models.Notification
-		err := rows.Scan(
-			&notification.ID,
-			&notification.AdminID,
-			&notification.Payload,
-			&notification.Title,
-			&notification.Body,
-			&notification.CreatedAt,
-			&notification.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		notifications = append(notifications, &notification)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return notifications, nil
-}
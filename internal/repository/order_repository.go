@@ -0,0 +1,393 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// orderColumns is the column list shared by all order queries
+const orderColumns = `
+	id, admin_id, branch_id, customer_id, tracking_token, type, status, customer_name,
+	customer_phone, delivery_address, delivery_latitude, delivery_longitude, notes, subtotal,
+	delivery_fee, discount, points_redeemed, total, currency, cancel_reason, created_at,
+	updated_at
+`
+
+// OrderRepository handles database operations for orders
+type OrderRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOrderRepository creates a new order repository
+func NewOrderRepository(db *pgxpool.Pool) *OrderRepository {
+	return &OrderRepository{
+		db: db,
+	}
+}
+
+// scanOrder scans an order row selected with orderColumns, followed by any extra columns
+// into extra. Items and history are loaded separately.
+func scanOrder(row pgx.Row, extra ...interface{}) (*models.Order, error) {
+	var order models.Order
+
+	dest := []interface{}{
+		&order.ID,
+		&order.AdminID,
+		&order.BranchID,
+		&order.CustomerID,
+		&order.TrackingToken,
+		&order.Type,
+		&order.Status,
+		&order.CustomerName,
+		&order.CustomerPhone,
+		&order.DeliveryAddress,
+		&order.DeliveryLatitude,
+		&order.DeliveryLongitude,
+		&order.Notes,
+		&order.Subtotal,
+		&order.DeliveryFee,
+		&order.Discount,
+		&order.PointsRedeemed,
+		&order.Total,
+		&order.Currency,
+		&order.CancelReason,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// insertStatusHistory records a status change of an order
+func insertStatusHistory(ctx context.Context, tx pgx.Tx, orderID int, entry *models.OrderStatusHistory) error {
+	return tx.QueryRow(ctx, `
+		INSERT INTO order_status_history (order_id, from_status, to_status, actor, actor_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, orderID, entry.FromStatus, entry.ToStatus, entry.Actor, entry.ActorID, entry.Note).Scan(&entry.CreatedAt)
+}
+
+// Create creates a new order together with its items and first history entry
+func (r *OrderRepository) Create(ctx context.Context, order *models.Order) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO orders (
+			admin_id, branch_id, customer_id, tracking_token, type, status, customer_name,
+			customer_phone, delivery_address, delivery_latitude, delivery_longitude, notes,
+			subtotal, delivery_fee, discount, points_redeemed, total, currency
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		order.AdminID,
+		order.BranchID,
+		order.CustomerID,
+		order.TrackingToken,
+		order.Type,
+		order.Status,
+		order.CustomerName,
+		order.CustomerPhone,
+		order.DeliveryAddress,
+		order.DeliveryLatitude,
+		order.DeliveryLongitude,
+		order.Notes,
+		order.Subtotal,
+		order.DeliveryFee,
+		order.Discount,
+		order.PointsRedeemed,
+		order.Total,
+		order.Currency,
+	).Scan(
+		&order.ID,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.OrderID = order.ID
+
+		err := tx.QueryRow(ctx, `
+			INSERT INTO order_item (order_id, product_id, name, unit_price, quantity, total, notes)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		`, order.ID, item.ProductID, item.Name, item.UnitPrice, item.Quantity, item.Total, item.Notes).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+
+		for j := range item.Modifiers {
+			modifier := &item.Modifiers[j]
+			err := tx.QueryRow(ctx, `
+				INSERT INTO order_item_modifier (order_item_id, option_id, group_name, option_name, price)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			`, item.ID, modifier.OptionID, modifier.GroupName, modifier.OptionName, modifier.Price).Scan(&modifier.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range order.History {
+		if err := insertStatusHistory(ctx, tx, order.ID, &order.History[i]); err != nil {
+			return err
+		}
+	}
+
+	// The points are spent with the order, so a failed order spends none
+	if order.PointsRedeemed > 0 {
+		err := redeemPoints(ctx, tx, &models.LoyaltyTransaction{
+			AdminID:     order.AdminID,
+			CustomerID:  *order.CustomerID,
+			OrderID:     &order.ID,
+			Type:        models.LoyaltyRedeem,
+			Points:      -order.PointsRedeemed,
+			Description: fmt.Sprintf("Spent on order #%d", order.ID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByID retrieves an order by ID together with its items and history
+func (r *OrderRepository) GetByID(ctx context.Context, id int) (*models.Order, error) {
+	return r.getOne(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1`, id)
+}
+
+// GetByTrackingToken retrieves an order by its tracking token together with its items and history
+func (r *OrderRepository) GetByTrackingToken(ctx context.Context, token string) (*models.Order, error) {
+	return r.getOne(ctx, `SELECT `+orderColumns+` FROM orders WHERE tracking_token = $1`, token)
+}
+
+// getOne retrieves a single order together with its items and history
+func (r *OrderRepository) getOne(ctx context.Context, query string, args ...interface{}) (*models.Order, error) {
+	order, err := scanOrder(r.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, order); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadHistory(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// orderList describes how order lists are filtered and sorted
+var orderList = listSpec{
+	table:    "orders",
+	columns:  orderColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"total":      {"total", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "created_at",
+	defaultDesc:   true,
+	statusColumn:  "status",
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"customer_phone", "customer_name", "tracking_token"},
+}
+
+// List retrieves one page of orders together with their items
+func (r *OrderRepository) List(ctx context.Context, params models.ListParams) ([]*models.Order, *models.ListMeta, error) {
+	orders, meta, err := queryList(ctx, r.db, orderList, params, nil, scanOrder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.loadItems(ctx, orders...); err != nil {
+		return nil, nil, err
+	}
+
+	return orders, meta, nil
+}
+
+// loadItems fills the items of orders together with their modifiers
+func (r *OrderRepository) loadItems(ctx context.Context, orders ...*models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.Order, len(orders))
+	ids := make([]int, 0, len(orders))
+	for _, order := range orders {
+		order.Items = []models.OrderItem{}
+		byID[order.ID] = order
+		ids = append(ids, order.ID)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, order_id, product_id, name, unit_price, quantity, total, notes
+		FROM order_item
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, id
+	`, idsParam(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// Items are addressed by index, as appending moves them
+	itemIndex := make(map[int][2]int)
+	itemIDs := []int{}
+	for rows.Next() {
+		var item models.OrderItem
+		if err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.Name, &item.UnitPrice, &item.Quantity, &item.Total, &item.Notes); err != nil {
+			return err
+		}
+		item.Modifiers = []models.OrderItemModifier{}
+
+		order := byID[item.OrderID]
+		order.Items = append(order.Items, item)
+		itemIndex[item.ID] = [2]int{order.ID, len(order.Items) - 1}
+		itemIDs = append(itemIDs, item.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	modifierRows, err := r.db.Query(ctx, `
+		SELECT id, order_item_id, option_id, group_name, option_name, price
+		FROM order_item_modifier
+		WHERE order_item_id = ANY($1)
+		ORDER BY order_item_id, id
+	`, idsParam(itemIDs))
+	if err != nil {
+		return err
+	}
+	defer modifierRows.Close()
+
+	for modifierRows.Next() {
+		var modifier models.OrderItemModifier
+		var itemID int
+		if err := modifierRows.Scan(&modifier.ID, &itemID, &modifier.OptionID, &modifier.GroupName, &modifier.OptionName, &modifier.Price); err != nil {
+			return err
+		}
+
+		index := itemIndex[itemID]
+		item := &byID[index[0]].Items[index[1]]
+		item.Modifiers = append(item.Modifiers, modifier)
+	}
+
+	return modifierRows.Err()
+}
+
+// loadHistory fills the status history of an order, oldest first
+func (r *OrderRepository) loadHistory(ctx context.Context, order *models.Order) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT from_status, to_status, actor, actor_id, note, created_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`, order.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	order.History = []models.OrderStatusHistory{}
+	for rows.Next() {
+		var entry models.OrderStatusHistory
+		if err := rows.Scan(&entry.FromStatus, &entry.ToStatus, &entry.Actor, &entry.ActorID, &entry.Note, &entry.CreatedAt); err != nil {
+			return err
+		}
+		order.History = append(order.History, entry)
+	}
+
+	return rows.Err()
+}
+
+// UpdateStatus moves an order from its current status to entry.ToStatus and records the
+// change. It fails with a conflict when the status changed in the meantime.
+func (r *OrderRepository) UpdateStatus(ctx context.Context, order *models.Order, entry *models.OrderStatusHistory) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	cancelReason := order.CancelReason
+	if entry.ToStatus == models.OrderStatusCancelled {
+		cancelReason = entry.Note
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE orders
+		SET status = $3, cancel_reason = $4
+		WHERE id = $1 AND status = $2
+		RETURNING updated_at
+	`, order.ID, entry.FromStatus, entry.ToStatus, cancelReason).Scan(&order.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.NewConflictError(utils.CodeConflict, "The order status has changed, reload the order")
+		}
+		return err
+	}
+
+	if err := insertStatusHistory(ctx, tx, order.ID, entry); err != nil {
+		return err
+	}
+
+	// Points spent on a cancelled order go back to the customer
+	if entry.ToStatus == models.OrderStatusCancelled && order.PointsRedeemed > 0 && order.CustomerID != nil {
+		err := refundPoints(ctx, tx, &models.LoyaltyTransaction{
+			AdminID:     order.AdminID,
+			CustomerID:  *order.CustomerID,
+			OrderID:     &order.ID,
+			Type:        models.LoyaltyRefund,
+			Points:      order.PointsRedeemed,
+			Description: fmt.Sprintf("Refunded from cancelled order #%d", order.ID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	order.Status = entry.ToStatus
+	order.CancelReason = cancelReason
+	order.History = append(order.History, *entry)
+	return nil
+}
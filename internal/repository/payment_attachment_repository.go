@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PaymentAttachmentRepository handles database operations for payment attachments
+type PaymentAttachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPaymentAttachmentRepository creates a new payment attachment repository
+func NewPaymentAttachmentRepository(db *pgxpool.Pool) *PaymentAttachmentRepository {
+	return &PaymentAttachmentRepository{
+		db: db,
+	}
+}
+
+// scanPaymentAttachment scans a single payment attachment row
+func scanPaymentAttachment(row pgx.Row) (*models.PaymentAttachment, error) {
+	var attachment models.PaymentAttachment
+
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.PaymentID,
+		&attachment.AdminID,
+		&attachment.Filename,
+		&attachment.OriginalName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.CreatedAt,
+		&attachment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.SetURL()
+
+	return &attachment, nil
+}
+
+// Create stores a new payment attachment
+func (r *PaymentAttachmentRepository) Create(ctx context.Context, attachment *models.PaymentAttachment) error {
+	query := `
+		INSERT INTO payment_attachment (payment_id, admin_id, filename, original_name, content_type, size)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		attachment.PaymentID,
+		attachment.AdminID,
+		attachment.Filename,
+		attachment.OriginalName,
+		attachment.ContentType,
+		attachment.Size,
+	).Scan(
+		&attachment.ID,
+		&attachment.CreatedAt,
+		&attachment.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	attachment.SetURL()
+
+	return nil
+}
+
+// GetByID retrieves a payment attachment by ID
+func (r *PaymentAttachmentRepository) GetByID(ctx context.Context, id int) (*models.PaymentAttachment, error) {
+	query := `
+		SELECT id, payment_id, admin_id, filename, original_name, content_type, size, created_at, updated_at
+		FROM payment_attachment
+		WHERE id = $1
+	`
+
+	attachment, err := scanPaymentAttachment(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// GetByPaymentIDs retrieves the attachments of the given payments, keyed by payment ID
+func (r *PaymentAttachmentRepository) GetByPaymentIDs(ctx context.Context, paymentIDs ...int) (map[int][]*models.PaymentAttachment, error) {
+	attachments := make(map[int][]*models.PaymentAttachment)
+	if len(paymentIDs) == 0 {
+		return attachments, nil
+	}
+
+	query := `
+		SELECT id, payment_id, admin_id, filename, original_name, content_type, size, created_at, updated_at
+		FROM payment_attachment
+		WHERE payment_id = ANY($1)
+		ORDER BY payment_id, id
+	`
+
+	rows, err := r.db.Query(ctx, query, paymentIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attachment, err := scanPaymentAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments[attachment.PaymentID] = append(attachments[attachment.PaymentID], attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// CountByPaymentID counts the attachments of a payment
+func (r *PaymentAttachmentRepository) CountByPaymentID(ctx context.Context, paymentID int) (int, error) {
+	query := `SELECT COUNT(*) FROM payment_attachment WHERE payment_id = $1`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, paymentID).Scan(&count)
+	return count, err
+}
+
+// Delete deletes a payment attachment
+func (r *PaymentAttachmentRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM payment_attachment WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.ErrResourceNotFound
+	}
+
+	return nil
+}
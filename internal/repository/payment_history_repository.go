@@ -3,15 +3,24 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"time"
 
 	"mobilka/internal/models"
 	"mobilka/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// paymentHistoryColumns is the column list shared by all payment history queries
+const paymentHistoryColumns = `
+	id, admin_id, amount, currency, payment_date, payment_method, transaction_id,
+	subscription_tier_id, period_start, period_end, billing_interval, months, prorated_amount,
+	status, notes, needs_review, review_reason,
+	coupon_redemption_id, discount_amount, discounted_months, refunded_amount, is_top_up,
+	verified_by, verified_at, created_at, updated_at
+`
+
 // PaymentHistoryRepository handles database operations for payment history
 type PaymentHistoryRepository struct {
 	db *pgxpool.Pool
@@ -24,74 +33,48 @@ func NewPaymentHistoryRepository(db *pgxpool.Pool) *PaymentHistoryRepository {
 	}
 }
 
-// Create creates a new payment history record
-func (r *PaymentHistoryRepository) Create(ctx context.Context, payment *models.PaymentHistory) error {
-	query := `
-		INSERT INTO payment_history (
-			admin_id, amount, payment_date, payment_method, transaction_id, 
-			subscription_tier_id, status, notes
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, created_at, updated_at
-	`
-
-	err := r.db.QueryRow(ctx, query,
-		payment.AdminID,
-		payment.Amount,
-		payment.PaymentDate,
-		payment.PaymentMethod,
-		payment.TransactionID,
-		payment.SubscriptionTierID,
-		payment.Status,
-		payment.Notes,
-	).Scan(
-		&payment.ID,
-		&payment.CreatedAt,
-		&payment.UpdatedAt,
-	)
-
-	return err
-}
-
-// GetByID retrieves a payment history record by ID
-func (r *PaymentHistoryRepository) GetByID(ctx context.Context, id int) (*models.PaymentHistory, error) {
-	query := `
-		SELECT id, admin_id, amount, payment_date, payment_method, transaction_id, 
-		       subscription_tier_id, period_start, period_end, status, notes, 
-		       verified_by, verified_at, created_at, updated_at
-		FROM payment_history
-		WHERE id = $1
-	`
-
+// scanPaymentHistory scans a single payment history row selected with paymentHistoryColumns,
+// followed by any extra columns into extra
+func scanPaymentHistory(row pgx.Row, extra ...interface{}) (*models.PaymentHistory, error) {
 	var payment models.PaymentHistory
 	var subscriptionTierID sql.NullInt32
 	var periodStart sql.NullTime
 	var periodEnd sql.NullTime
+	var couponRedemptionID sql.NullInt32
 	var verifiedBy sql.NullInt32
 	var verifiedAt sql.NullTime
 
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	dest := []interface{}{
 		&payment.ID,
 		&payment.AdminID,
 		&payment.Amount,
+		&payment.Currency,
 		&payment.PaymentDate,
 		&payment.PaymentMethod,
 		&payment.TransactionID,
 		&subscriptionTierID,
 		&periodStart,
 		&periodEnd,
+		&payment.BillingInterval,
+		&payment.Months,
+		&payment.ProratedAmount,
 		&payment.Status,
 		&payment.Notes,
+		&payment.NeedsReview,
+		&payment.ReviewReason,
+		&couponRedemptionID,
+		&payment.DiscountAmount,
+		&payment.DiscountedMonths,
+		&payment.RefundedAmount,
+		&payment.IsTopUp,
 		&verifiedBy,
 		&verifiedAt,
 		&payment.CreatedAt,
 		&payment.UpdatedAt,
-	)
+	}
 
+	err := row.Scan(append(dest, extra...)...)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, utils.ErrResourceNotFound
-		}
 		return nil, err
 	}
 
@@ -108,6 +91,11 @@ func (r *PaymentHistoryRepository) GetByID(ctx context.Context, id int) (*models
 		payment.PeriodEnd = &periodEnd.Time
 	}
 
+	if couponRedemptionID.Valid {
+		val := int(couponRedemptionID.Int32)
+		payment.CouponRedemptionID = &val
+	}
+
 	if verifiedBy.Valid {
 		val := int(verifiedBy.Int32)
 		payment.VerifiedBy = &val
@@ -120,262 +108,307 @@ func (r *PaymentHistoryRepository) GetByID(ctx context.Context, id int) (*models
 	return &payment, nil
 }
 
-// GetByAdminID retrieves all payment history records for a specific admin
-func (r *PaymentHistoryRepository) GetByAdminID(ctx context.Context, adminID int) ([]*models.PaymentHistory, error) {
-	query := `
-		SELECT id, admin_id, amount, payment_date, payment_method, transaction_id, 
-		       subscription_tier_id, period_start, period_end, status, notes, 
-		       verified_by, verified_at, created_at, updated_at
-		FROM payment_history
-		WHERE admin_id = $1
-		ORDER BY payment_date DESC
-	`
+// paymentInsertQuery inserts a payment and starts its audit trail in the same statement,
+// returning the id and timestamps
+const paymentInsertQuery = `
+	WITH created AS (
+		INSERT INTO payment_history (
+			admin_id, amount, currency, payment_date, payment_method, transaction_id,
+			subscription_tier_id, status, notes, billing_interval, months, prorated_amount,
+			needs_review, review_reason, coupon_redemption_id, discount_amount,
+			discounted_months, is_top_up, period_start, period_end, verified_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		RETURNING id, admin_id, amount, currency, created_at, updated_at
+	),
+	logged AS (
+		INSERT INTO payment_audit_log (payment_id, admin_id, action, actor_id, amount, currency)
+		SELECT id, admin_id, 'recorded', admin_id, amount, currency FROM created
+	)
+	SELECT id, created_at, updated_at FROM created
+`
 
-	rows, err := r.db.Query(ctx, query, adminID)
-	if err != nil {
-		return nil, err
+// paymentInsertArgs returns the arguments of paymentInsertQuery
+func paymentInsertArgs(payment *models.PaymentHistory) []interface{} {
+	return []interface{}{
+		payment.AdminID,
+		payment.Amount,
+		payment.Currency,
+		payment.PaymentDate,
+		payment.PaymentMethod,
+		payment.TransactionID,
+		payment.SubscriptionTierID,
+		payment.Status,
+		payment.Notes,
+		payment.BillingInterval,
+		payment.Months,
+		payment.ProratedAmount,
+		payment.NeedsReview,
+		payment.ReviewReason,
+		payment.CouponRedemptionID,
+		payment.DiscountAmount,
+		payment.DiscountedMonths,
+		payment.IsTopUp,
+		payment.PeriodStart,
+		payment.PeriodEnd,
+		payment.VerifiedAt,
 	}
-	defer rows.Close()
+}
 
-	var payments []*models.PaymentHistory
-	for rows.Next() {
-		var payment models.PaymentHistory
-		var subscriptionTierID sql.NullInt32
-		var periodStart sql.NullTime
-		var periodEnd sql.NullTime
-		var verifiedBy sql.NullInt32
-		var verifiedAt sql.NullTime
-
-		err := rows.Scan(
-			&payment.ID,
-			&payment.AdminID,
-			&payment.Amount,
-			&payment.PaymentDate,
-			&payment.PaymentMethod,
-			&payment.TransactionID,
-			&subscriptionTierID,
-			&periodStart,
-			&periodEnd,
-			&payment.Status,
-			&payment.Notes,
-			&verifiedBy,
-			&verifiedAt,
-			&payment.CreatedAt,
-			&payment.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
+// Create creates a new payment history record
+func (r *PaymentHistoryRepository) Create(ctx context.Context, payment *models.PaymentHistory) error {
+	return r.db.QueryRow(ctx, paymentInsertQuery, paymentInsertArgs(payment)...).Scan(
+		&payment.ID,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	)
+}
 
-		if subscriptionTierID.Valid {
-			val := int(subscriptionTierID.Int32)
-			payment.SubscriptionTierID = &val
-		}
+// CreatePaidFromBalance records a payment that is paid from the admin's prepaid balance, and
+// in the same transaction renews the subscription it pays for and posts the charge for it.
+// Fully discounted payments have no charge. The charge is refused with a conflict when the
+// balance doesn't cover it.
+func (r *PaymentHistoryRepository) CreatePaidFromBalance(ctx context.Context, payment *models.PaymentHistory, renewal *models.SubscriptionRenewal, charge *models.LedgerTransaction) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
 
-		if periodStart.Valid {
-			payment.PeriodStart = &periodStart.Time
+	if charge != nil {
+		if err := checkBalanceCovers(ctx, tx, []*models.LedgerTransaction{charge}); err != nil {
+			return err
 		}
+	}
 
-		if periodEnd.Valid {
-			payment.PeriodEnd = &periodEnd.Time
-		}
+	if err := renewSubscription(ctx, tx, renewal); err != nil {
+		return err
+	}
+	payment.PeriodStart, payment.PeriodEnd = renewal.PeriodStart, renewal.PeriodEnd
 
-		if verifiedBy.Valid {
-			val := int(verifiedBy.Int32)
-			payment.VerifiedBy = &val
-		}
+	err = tx.QueryRow(ctx, paymentInsertQuery, paymentInsertArgs(payment)...).Scan(
+		&payment.ID,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
 
-		if verifiedAt.Valid {
-			payment.VerifiedAt = &verifiedAt.Time
+	if charge != nil {
+		charge.PaymentID = &payment.ID
+		if err := insertLedgerTransaction(ctx, tx, charge); err != nil {
+			return err
 		}
-
-		payments = append(payments, &payment)
 	}
 
-	if err := rows.Err(); err != nil {
+	return tx.Commit(ctx)
+}
+
+// GetByID retrieves a payment history record by ID
+func (r *PaymentHistoryRepository) GetByID(ctx context.Context, id int) (*models.PaymentHistory, error) {
+	query := `SELECT ` + paymentHistoryColumns + `
+		FROM payment_history
+		WHERE id = $1
+	`
+
+	payment, err := scanPaymentHistory(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, utils.ErrResourceNotFound
+		}
 		return nil, err
 	}
 
-	return payments, nil
+	return payment, nil
 }
 
-// GetAll retrieves all payment history records
-func (r *PaymentHistoryRepository) GetAll(ctx context.Context) ([]*models.PaymentHistory, error) {
-	query := `
-		SELECT id, admin_id, amount, payment_date, payment_method, transaction_id, 
-		       subscription_tier_id, period_start, period_end, status, notes, 
-		       verified_by, verified_at, created_at, updated_at
+// paymentList describes how payment lists are filtered and sorted
+var paymentList = listSpec{
+	table:    "payment_history",
+	columns:  paymentHistoryColumns,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":           {"id", "BIGINT"},
+		"payment_date": {"payment_date", "TIMESTAMPTZ"},
+		"created_at":   {"created_at", "TIMESTAMPTZ"},
+		"amount":       {"amount", "BIGINT"},
+	},
+	defaultSort:  "payment_date",
+	defaultDesc:  true,
+	statusColumn: "status",
+	dateColumn:   "payment_date",
+	adminColumn:  "admin_id",
+	searchColumns: []string{
+		"(SELECT a.company_name FROM admin a WHERE a.id = payment_history.admin_id)",
+		"transaction_id",
+	},
+}
+
+// List retrieves one page of payments. The payment filter narrows the list further,
+// e.g. by payment method, currency or payments flagged for review.
+func (r *PaymentHistoryRepository) List(ctx context.Context, params models.ListParams, filter models.PaymentFilter) ([]*models.PaymentHistory, *models.ListMeta, error) {
+	return queryList(ctx, r.db, paymentList, params, paymentFilterQuery(filter), scanPaymentHistory)
+}
+
+// Stream calls fn for every payment matching the list filters of params and a payment
+// filter, oldest first, together with the company name of the admin and the name of the
+// tier. Rows are read one at a time so exports of any size don't have to fit in memory.
+func (r *PaymentHistoryRepository) Stream(ctx context.Context, params models.ListParams, filter models.PaymentFilter, fn func(payment *models.PaymentHistory, companyName string, tierName string) error) error {
+	q := paymentFilterQuery(filter)
+	if err := paymentList.filters(q, params); err != nil {
+		return err
+	}
+
+	query := `SELECT ` + paymentHistoryColumns + `,
+			(SELECT a.company_name FROM admin a WHERE a.id = payment_history.admin_id),
+			COALESCE((SELECT st.name FROM subscription_tier st WHERE st.id = payment_history.subscription_tier_id), '')
 		FROM payment_history
-		ORDER BY payment_date DESC
+		` + q.where() + `
+		ORDER BY payment_date, id
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, q.args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var payments []*models.PaymentHistory
 	for rows.Next() {
-		var payment models.PaymentHistory
-		var subscriptionTierID sql.NullInt32
-		var periodStart sql.NullTime
-		var periodEnd sql.NullTime
-		var verifiedBy sql.NullInt32
-		var verifiedAt sql.NullTime
-
-		err := rows.Scan(
-			&payment.ID,
-			&payment.AdminID,
-			&payment.Amount,
-			&payment.PaymentDate,
-			&payment.PaymentMethod,
-			&payment.TransactionID,
-			&subscriptionTierID,
-			&periodStart,
-			&periodEnd,
-			&payment.Status,
-			&payment.Notes,
-			&verifiedBy,
-			&verifiedAt,
-			&payment.CreatedAt,
-			&payment.UpdatedAt,
-		)
+		var companyName sql.NullString
+		var tierName string
+		payment, err := scanPaymentHistory(rows, &companyName, &tierName)
 		if err != nil {
-			return nil, err
-		}
-
-		if subscriptionTierID.Valid {
-			val := int(subscriptionTierID.Int32)
-			payment.SubscriptionTierID = &val
+			return err
 		}
-
-		if periodStart.Valid {
-			payment.PeriodStart = &periodStart.Time
-		}
-
-		if periodEnd.Valid {
-			payment.PeriodEnd = &periodEnd.Time
+		if err := fn(payment, companyName.String, tierName); err != nil {
+			return err
 		}
+	}
 
-		if verifiedBy.Valid {
-			val := int(verifiedBy.Int32)
-			payment.VerifiedBy = &val
-		}
+	return rows.Err()
+}
 
-		if verifiedAt.Valid {
-			payment.VerifiedAt = &verifiedAt.Time
-		}
+// paymentFilterQuery builds the conditions of a payment filter
+func paymentFilterQuery(filter models.PaymentFilter) *listQuery {
+	q := &listQuery{}
 
-		payments = append(payments, &payment)
+	if filter.PaymentMethod != "" {
+		q.add("payment_method = $%d", filter.PaymentMethod)
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
+	if filter.Currency != "" {
+		q.add("currency = $%d", filter.Currency)
+	}
+	if filter.NeedsReview {
+		q.add("needs_review = true")
 	}
 
-	return payments, nil
+	return q
 }
 
-// GetPendingPayments retrieves all pending payment history records
-func (r *PaymentHistoryRepository) GetPendingPayments(ctx context.Context) ([]*models.PaymentHistory, error) {
-	query := `
-		SELECT id, admin_id, amount, payment_date, payment_method, transaction_id, 
-		       subscription_tier_id, period_start, period_end, status, notes, 
-		       verified_by, verified_at, created_at, updated_at
-		FROM payment_history
-		WHERE status = 'pending'
-		ORDER BY payment_date ASC
-	`
-
-	rows, err := r.db.Query(ctx, query)
+// VerifyPayment updates a payment record status to verified or rejected, and in the same
+// database transaction renews the subscription it pays for, if any, and posts the given
+// ledger transactions for it
+func (r *PaymentHistoryRepository) VerifyPayment(ctx context.Context, id int, superAdminID int, status string, notes string, periodStart *time.Time, periodEnd *time.Time, renewal *models.SubscriptionRenewal, postings ...*models.LedgerTransaction) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
-
-	var payments []*models.PaymentHistory
-	for rows.Next() {
-		var payment models.PaymentHistory
-		var subscriptionTierID sql.NullInt32
-		var periodStart sql.NullTime
-		var periodEnd sql.NullTime
-		var verifiedBy sql.NullInt32
-		var verifiedAt sql.NullTime
-
-		err := rows.Scan(
-			&payment.ID,
-			&payment.AdminID,
-			&payment.Amount,
-			&payment.PaymentDate,
-			&payment.PaymentMethod,
-			&payment.TransactionID,
-			&subscriptionTierID,
-			&periodStart,
-			&periodEnd,
-			&payment.Status,
-			&payment.Notes,
-			&verifiedBy,
-			&verifiedAt,
-			&payment.CreatedAt,
-			&payment.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
+	defer tx.Rollback(ctx)
 
-		if subscriptionTierID.Valid {
-			val := int(subscriptionTierID.Int32)
-			payment.SubscriptionTierID = &val
-		}
-
-		if periodStart.Valid {
-			payment.PeriodStart = &periodStart.Time
+	// The renewal settles the period the payment pays for
+	if renewal != nil {
+		if err := renewSubscription(ctx, tx, renewal); err != nil {
+			return err
 		}
+		periodStart, periodEnd = renewal.PeriodStart, renewal.PeriodEnd
+	}
 
-		if periodEnd.Valid {
-			payment.PeriodEnd = &periodEnd.Time
-		}
+	// Only pending payments can be processed, so two super admins can't both extend
+	// the subscription. The change is added to the audit trail in the same statement.
+	query := `
+		WITH updated AS (
+			UPDATE payment_history
+			SET status = $2, notes = $3, verified_by = $4, verified_at = CURRENT_TIMESTAMP,
+			    period_start = $5, period_end = $6
+			WHERE id = $1 AND status = 'pending'
+			RETURNING id, admin_id, status, verified_by, amount, currency, notes, verified_at
+		),
+		logged AS (
+			INSERT INTO payment_audit_log (payment_id, admin_id, action, actor_id, amount, currency, details, created_at)
+			SELECT id, admin_id, status, verified_by, amount, currency, notes, verified_at FROM updated
+		)
+		SELECT verified_at FROM updated
+	`
 
-		if verifiedBy.Valid {
-			val := int(verifiedBy.Int32)
-			payment.VerifiedBy = &val
-		}
+	var verifiedAt time.Time
+	err = tx.QueryRow(ctx, query, id, status, notes, superAdminID, periodStart, periodEnd).Scan(&verifiedAt)
 
-		if verifiedAt.Valid {
-			payment.VerifiedAt = &verifiedAt.Time
+	if err != nil {
+		if isNoRows(err) {
+			return utils.NewAppError(utils.ErrInvalidInput, "Payment is not pending", 409)
 		}
-
-		payments = append(payments, &payment)
+		return err
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+	for _, posting := range postings {
+		posting.PaymentID = &id
+		posting.CreatedBy = &superAdminID
+		if err := insertLedgerTransaction(ctx, tx, posting); err != nil {
+			return err
+		}
 	}
 
-	return payments, nil
+	return tx.Commit(ctx)
 }
 
-// VerifyPayment updates a payment record status to verified or rejected
-func (r *PaymentHistoryRepository) VerifyPayment(ctx context.Context, id int, superAdminID int, status string, notes string, periodStart *time.Time, periodEnd *time.Time) error {
-	query := `
-		UPDATE payment_history
-		SET status = $2, notes = $3, verified_by = $4, verified_at = CURRENT_TIMESTAMP, 
-		    period_start = $5, period_end = $6
+// renewSubscription activates the subscription a payment paid for and uses up the coupon
+// months the payment was discounted for. The admin row stays locked until the transaction
+// ends, so concurrent renewals stack their periods one after another.
+func renewSubscription(ctx context.Context, tx pgx.Tx, renewal *models.SubscriptionRenewal) error {
+	var admin models.Admin
+	err := tx.QueryRow(ctx, `
+		SELECT subscription_status, subscription_expires_at
+		FROM admin
 		WHERE id = $1
-		RETURNING verified_at
-	`
+		FOR UPDATE
+	`, renewal.AdminID).Scan(&admin.SubscriptionStatus, &admin.SubscriptionExpiresAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrUserNotFound
+		}
+		return err
+	}
 
-	var verifiedAt time.Time
-	err := r.db.QueryRow(ctx, query, id, status, notes, superAdminID, periodStart, periodEnd).Scan(&verifiedAt)
+	// Extend from the current expiry so an early renewal keeps the days already paid for
+	if renewal.PeriodEnd == nil {
+		start := admin.BillingPeriodStart(time.Now())
+		end := start.AddDate(0, renewal.Months, 0)
+		renewal.PeriodStart, renewal.PeriodEnd = &start, &end
+	}
 
+	_, err = tx.Exec(ctx, `
+		UPDATE admin
+		SET subscription_tier_id = $2, subscription_status = 'active', subscription_expires_at = $3,
+		    is_access_restricted = false, billing_interval = COALESCE(NULLIF($4, ''), billing_interval)
+		WHERE id = $1
+	`, renewal.AdminID, renewal.SubscriptionTierID, renewal.PeriodEnd, renewal.BillingInterval)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return utils.ErrResourceNotFound
-		}
 		return err
 	}
 
+	if renewal.CouponRedemptionID != nil && renewal.CouponMonths > 0 {
+		_, err = tx.Exec(ctx, `
+			UPDATE coupon_redemption
+			SET months_remaining = GREATEST(months_remaining - $2, 0),
+			    status = CASE WHEN months_remaining - $2 <= 0 THEN 'completed' ELSE status END
+			WHERE id = $1 AND months_remaining IS NOT NULL
+		`, *renewal.CouponRedemptionID, renewal.CouponMonths)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -383,7 +416,7 @@ func (r *PaymentHistoryRepository) VerifyPayment(ctx context.Context, id int, su
 func (r *PaymentHistoryRepository) UpdateAdminSubscription(ctx context.Context, adminID int, subscriptionTierID *int, expiresAt *time.Time, status string, isRestricted bool) error {
 	query := `
 		UPDATE admin
-		SET subscription_tier_id = $2, subscription_expires_at = $3, 
+		SET subscription_tier_id = $2, subscription_expires_at = $3,
 		    subscription_status = $4, is_access_restricted = $5
 		WHERE id = $1
 	`
@@ -394,69 +427,20 @@ func (r *PaymentHistoryRepository) UpdateAdminSubscription(ctx context.Context,
 
 // GetLatestVerifiedPayment gets the most recent verified payment for an admin
 func (r *PaymentHistoryRepository) GetLatestVerifiedPayment(ctx context.Context, adminID int) (*models.PaymentHistory, error) {
-	query := `
-		SELECT id, admin_id, amount, payment_date, payment_method, transaction_id, 
-		       subscription_tier_id, period_start, period_end, status, notes, 
-		       verified_by, verified_at, created_at, updated_at
+	query := `SELECT ` + paymentHistoryColumns + `
 		FROM payment_history
-		WHERE admin_id = $1 AND status = 'verified'
+		WHERE admin_id = $1 AND status = 'verified' AND NOT is_top_up
 		ORDER BY verified_at DESC
 		LIMIT 1
 	`
 
-	var payment models.PaymentHistory
-	var subscriptionTierID sql.NullInt32
-	var periodStart sql.NullTime
-	var periodEnd sql.NullTime
-	var verifiedBy sql.NullInt32
-	var verifiedAt sql.NullTime
-
-	err := r.db.QueryRow(ctx, query, adminID).Scan(
-		&payment.ID,
-		&payment.AdminID,
-		&payment.Amount,
-		&payment.PaymentDate,
-		&payment.PaymentMethod,
-		&payment.TransactionID,
-		&subscriptionTierID,
-		&periodStart,
-		&periodEnd,
-		&payment.Status,
-		&payment.Notes,
-		&verifiedBy,
-		&verifiedAt,
-		&payment.CreatedAt,
-		&payment.UpdatedAt,
-	)
-
+	payment, err := scanPaymentHistory(r.db.QueryRow(ctx, query, adminID))
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if isNoRows(err) {
 			return nil, utils.ErrResourceNotFound
 		}
 		return nil, err
 	}
 
-	if subscriptionTierID.Valid {
-		val := int(subscriptionTierID.Int32)
-		payment.SubscriptionTierID = &val
-	}
-
-	if periodStart.Valid {
-		payment.PeriodStart = &periodStart.Time
-	}
-
-	if periodEnd.Valid {
-		payment.PeriodEnd = &periodEnd.Time
-	}
-
-	if verifiedBy.Valid {
-		val := int(verifiedBy.Int32)
-		payment.VerifiedBy = &val
-	}
-
-	if verifiedAt.Valid {
-		payment.VerifiedAt = &verifiedAt.Time
-	}
-
-	return &payment, nil
+	return payment, nil
 }
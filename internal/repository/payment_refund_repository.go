@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"mobilka/internal/models"
+	"mobilka/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PaymentRefundRepository handles database operations for payment refunds and the payment audit trail
+type PaymentRefundRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPaymentRefundRepository creates a new payment refund repository
+func NewPaymentRefundRepository(db *pgxpool.Pool) *PaymentRefundRepository {
+	return &PaymentRefundRepository{
+		db: db,
+	}
+}
+
+// scanPaymentRefund scans a single payment refund row
+func scanPaymentRefund(row pgx.Row) (*models.PaymentRefund, error) {
+	var refund models.PaymentRefund
+	var newExpiresAt sql.NullTime
+	var createdBy sql.NullInt32
+
+	err := row.Scan(
+		&refund.ID,
+		&refund.PaymentID,
+		&refund.AdminID,
+		&refund.Type,
+		&refund.Amount,
+		&refund.Currency,
+		&refund.Reason,
+		&refund.RemovedSeconds,
+		&newExpiresAt,
+		&createdBy,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if newExpiresAt.Valid {
+		refund.NewExpiresAt = &newExpiresAt.Time
+	}
+
+	if createdBy.Valid {
+		val := int(createdBy.Int32)
+		refund.CreatedBy = &val
+	}
+
+	return &refund, nil
+}
+
+// Create applies a refund in one transaction: it adds the amount to the refunded amount of the
+// payment, shortens the payment period and the admin's subscription by RemovedSeconds, expires
+// the subscription if that leaves no time, gives back restoreCouponMonths discounted months of
+// the coupon, records the refund in the audit trail and posts it to the ledger. paymentStatus
+// is the new payment status. Postings that would overdraw the admin's balance are refused.
+func (r *PaymentRefundRepository) Create(ctx context.Context, refund *models.PaymentRefund, paymentStatus string, couponRedemptionID *int, restoreCouponMonths int, postings ...*models.LedgerTransaction) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkBalanceCovers(ctx, tx, postings); err != nil {
+		return err
+	}
+
+	// Guard against refunding more than was paid when refunds race
+	result, err := tx.Exec(ctx, `
+		UPDATE payment_history
+		SET refunded_amount = refunded_amount + $2,
+		    status = $3,
+		    period_end = period_end - make_interval(secs => $4::BIGINT)
+		WHERE id = $1 AND status = 'verified' AND refunded_amount + $2 <= amount
+	`, refund.PaymentID, refund.Amount, paymentStatus, refund.RemovedSeconds)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return utils.NewAppError(utils.ErrInvalidInput, "Payment is no longer refundable for this amount", 409)
+	}
+
+	// Take the refunded time back from the subscription
+	var newExpiresAt sql.NullTime
+	err = tx.QueryRow(ctx, `
+		UPDATE admin
+		SET subscription_expires_at = subscription_expires_at - make_interval(secs => $2::BIGINT),
+		    subscription_status = CASE
+		        WHEN subscription_status = 'active'
+		         AND subscription_expires_at - make_interval(secs => $2::BIGINT) <= CURRENT_TIMESTAMP
+		        THEN 'expired' ELSE subscription_status END,
+		    is_access_restricted = is_access_restricted OR (
+		        subscription_status = 'active'
+		        AND subscription_expires_at - make_interval(secs => $2::BIGINT) <= CURRENT_TIMESTAMP)
+		WHERE id = $1
+		RETURNING subscription_expires_at
+	`, refund.AdminID, refund.RemovedSeconds).Scan(&newExpiresAt)
+	if err != nil {
+		if isNoRows(err) {
+			return utils.ErrUserNotFound
+		}
+		return err
+	}
+
+	if newExpiresAt.Valid {
+		refund.NewExpiresAt = &newExpiresAt.Time
+	}
+
+	if couponRedemptionID != nil && restoreCouponMonths > 0 {
+		_, err = tx.Exec(ctx, `
+			UPDATE coupon_redemption
+			SET months_remaining = months_remaining + $2, status = 'active'
+			WHERE id = $1 AND months_remaining IS NOT NULL
+		`, *couponRedemptionID, restoreCouponMonths)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO payment_refund (
+			payment_id, admin_id, type, amount, currency, reason, removed_seconds, new_expires_at, created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`,
+		refund.PaymentID,
+		refund.AdminID,
+		refund.Type,
+		refund.Amount,
+		refund.Currency,
+		refund.Reason,
+		refund.RemovedSeconds,
+		refund.NewExpiresAt,
+		refund.CreatedBy,
+	).Scan(
+		&refund.ID,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	action := "refunded"
+	if refund.Type == models.PaymentRefundTypeReversal {
+		action = "reversed"
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO payment_audit_log (payment_id, admin_id, action, actor_id, amount, currency, details)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, refund.PaymentID, refund.AdminID, action, refund.CreatedBy, refund.Amount, refund.Currency, refund.Reason)
+	if err != nil {
+		return err
+	}
+
+	for _, posting := range postings {
+		posting.PaymentID = &refund.PaymentID
+		posting.RefundID = &refund.ID
+		if err := insertLedgerTransaction(ctx, tx, posting); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByPaymentID retrieves the refunds of a payment, oldest first
+func (r *PaymentRefundRepository) GetByPaymentID(ctx context.Context, paymentID int) ([]*models.PaymentRefund, error) {
+	query := `
+		SELECT id, payment_id, admin_id, type, amount, currency, reason, removed_seconds,
+		       new_expires_at, created_by, created_at, updated_at
+		FROM payment_refund
+		WHERE payment_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := []*models.PaymentRefund{}
+	for rows.Next() {
+		refund, err := scanPaymentRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
+
+// GetAuditLog retrieves the audit trail of a payment, oldest first
+func (r *PaymentRefundRepository) GetAuditLog(ctx context.Context, paymentID int) ([]*models.PaymentAuditLog, error) {
+	query := `
+		SELECT id, payment_id, admin_id, action, actor_id, amount, currency, details, created_at
+		FROM payment_audit_log
+		WHERE payment_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.PaymentAuditLog{}
+	for rows.Next() {
+		var entry models.PaymentAuditLog
+		var actorID sql.NullInt32
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.PaymentID,
+			&entry.AdminID,
+			&entry.Action,
+			&actorID,
+			&entry.Amount,
+			&entry.Currency,
+			&entry.Details,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if actorID.Valid {
+			val := int(actorID.Int32)
+			entry.ActorID = &val
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
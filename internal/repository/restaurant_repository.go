@@ -10,6 +10,7 @@ import (
 	"mobilka/internal/models"
 	"mobilka/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -141,107 +142,56 @@ func (r *RestaurantRepository) GetByID(ctx context.Context, id int) (*models.Res
 	return &restaurant, nil
 }
 
-// GetByAdminID retrieves all restaurants for a specific admin
-func (r *RestaurantRepository) GetByAdminID(ctx context.Context, adminID int) ([]*models.Restaurant, error) {
-	query := `
-		SELECT id, admin_id, text, contacts, social_media, created_at, updated_at
-		FROM restaurant
-		WHERE admin_id = $1
-		ORDER BY id
-	`
-
-	rows, err := r.db.Query(ctx, query, adminID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var restaurants []*models.Restaurant
-	for rows.Next() {
-		var restaurant models.Restaurant
-		var contactsJSON, socialMediaJSON []byte
-
-		err := rows.Scan(
-			&restaurant.ID,
-			&restaurant.AdminID,
-			&restaurant.Text,
-			&contactsJSON,
-			&socialMediaJSON,
-			&restaurant.CreatedAt,
-			&restaurant.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		// Unmarshal JSON to structs
-		if err := json.Unmarshal(contactsJSON, &restaurant.Contacts); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal contacts: %w", err)
-		}
+// restaurantList describes how restaurant lists are filtered and sorted
+var restaurantList = listSpec{
+	table:    "restaurant",
+	columns:  "id, admin_id, text, contacts, social_media, created_at, updated_at",
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "id",
+	dateColumn:    "created_at",
+	adminColumn:   "admin_id",
+	searchColumns: []string{"text"},
+}
 
-		if err := json.Unmarshal(socialMediaJSON, &restaurant.SocialMedia); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal social media: %w", err)
-		}
+// scanRestaurant scans a restaurant row selected with the restaurantList columns,
+// followed by any extra columns into extra
+func scanRestaurant(row pgx.Row, extra ...interface{}) (*models.Restaurant, error) {
+	var restaurant models.Restaurant
+	var contactsJSON, socialMediaJSON []byte
 
-		restaurants = append(restaurants, &restaurant)
+	dest := []interface{}{
+		&restaurant.ID,
+		&restaurant.AdminID,
+		&restaurant.Text,
+		&contactsJSON,
+		&socialMediaJSON,
+		&restaurant.CreatedAt,
+		&restaurant.UpdatedAt,
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := row.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
-	return restaurants, nil
-}
-
-// GetAll retrieves all restaurants
-func (r *RestaurantRepository) GetAll(ctx context.Context) ([]*models.Restaurant, error) {
-	query := `
-		SELECT id, admin_id, text, contacts, social_media, created_at, updated_at
-		FROM restaurant
-		ORDER BY id
-	`
-
-	rows, err := r.db.Query(ctx, query)
-	if err != nil {
-		return nil, err
+	// Unmarshal JSON to structs
+	if err := json.Unmarshal(contactsJSON, &restaurant.Contacts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal contacts: %w", err)
 	}
-	defer rows.Close()
-
-	var restaurants []*models.Restaurant
-	for rows.Next() {
-		var restaurant models.Restaurant
-		var contactsJSON, socialMediaJSON []byte
 
-		err := rows.Scan(
-			&restaurant.ID,
-			&restaurant.AdminID,
-			&restaurant.Text,
-			&contactsJSON,
-			&socialMediaJSON,
-			&restaurant.CreatedAt,
-			&restaurant.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		// Unmarshal JSON to structs
-		if err := json.Unmarshal(contactsJSON, &restaurant.Contacts); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal contacts: %w", err)
-		}
-
-		if err := json.Unmarshal(socialMediaJSON, &restaurant.SocialMedia); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal social media: %w", err)
-		}
-
-		restaurants = append(restaurants, &restaurant)
+	if err := json.Unmarshal(socialMediaJSON, &restaurant.SocialMedia); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal social media: %w", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
+	return &restaurant, nil
+}
 
-	return restaurants, nil
+// List retrieves one page of restaurants
+func (r *RestaurantRepository) List(ctx context.Context, params models.ListParams) ([]*models.Restaurant, *models.ListMeta, error) {
+	return queryList(ctx, r.db, restaurantList, params, nil, scanRestaurant)
 }
 
 // Update updates a restaurant
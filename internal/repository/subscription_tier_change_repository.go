@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"mobilka/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SubscriptionTierChangeRepository handles database operations for subscription tier changes
+type SubscriptionTierChangeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSubscriptionTierChangeRepository creates a new subscription tier change repository
+func NewSubscriptionTierChangeRepository(db *pgxpool.Pool) *SubscriptionTierChangeRepository {
+	return &SubscriptionTierChangeRepository{
+		db: db,
+	}
+}
+
+// Create logs a new subscription tier change
+func (r *SubscriptionTierChangeRepository) Create(ctx context.Context, change *models.SubscriptionTierChange) error {
+	query := `
+		INSERT INTO subscription_tier_change (
+			admin_id, old_tier_id, new_tier_id, user_count, reason, status, effective_at, changed_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		change.AdminID,
+		change.OldTierID,
+		change.NewTierID,
+		change.UserCount,
+		change.Reason,
+		change.Status,
+		change.EffectiveAt,
+		change.ChangedBy,
+	).Scan(
+		&change.ID,
+		&change.CreatedAt,
+		&change.UpdatedAt,
+	)
+}
+
+// tierChangeList describes how tier change logs are filtered and sorted
+var tierChangeList = listSpec{
+	table: "subscription_tier_change",
+	columns: `id, admin_id, old_tier_id, new_tier_id, user_count, reason, status,
+		effective_at, changed_by, created_at, updated_at`,
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":           {"id", "BIGINT"},
+		"created_at":   {"created_at", "TIMESTAMPTZ"},
+		"effective_at": {"effective_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "created_at",
+	defaultDesc:   true,
+	statusColumn:  "status",
+	dateColumn:    "created_at",
+	searchColumns: []string{"reason"},
+}
+
+// scanTierChange scans a tier change row selected with the tierChangeList columns,
+// followed by any extra columns into extra
+func scanTierChange(row pgx.Row, extra ...interface{}) (*models.SubscriptionTierChange, error) {
+	var change models.SubscriptionTierChange
+	var oldTierID, newTierID, changedBy sql.NullInt32
+
+	dest := []interface{}{
+		&change.ID,
+		&change.AdminID,
+		&oldTierID,
+		&newTierID,
+		&change.UserCount,
+		&change.Reason,
+		&change.Status,
+		&change.EffectiveAt,
+		&changedBy,
+		&change.CreatedAt,
+		&change.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if oldTierID.Valid {
+		val := int(oldTierID.Int32)
+		change.OldTierID = &val
+	}
+
+	if newTierID.Valid {
+		val := int(newTierID.Int32)
+		change.NewTierID = &val
+	}
+
+	if changedBy.Valid {
+		val := int(changedBy.Int32)
+		change.ChangedBy = &val
+	}
+
+	return &change, nil
+}
+
+// ListByAdminID retrieves one page of the tier changes of a specific admin
+func (r *SubscriptionTierChangeRepository) ListByAdminID(ctx context.Context, adminID int, params models.ListParams) ([]*models.SubscriptionTierChange, *models.ListMeta, error) {
+	scope := &listQuery{}
+	scope.add("admin_id = $%d", adminID)
+
+	return queryList(ctx, r.db, tierChangeList, params, scope, scanTierChange)
+}
+
+// UpdateScheduledStatus moves all scheduled changes of an admin to the given status
+func (r *SubscriptionTierChangeRepository) UpdateScheduledStatus(ctx context.Context, adminID int, status string) error {
+	query := `
+		UPDATE subscription_tier_change
+		SET status = $2
+		WHERE admin_id = $1 AND status = 'scheduled'
+	`
+
+	_, err := r.db.Exec(ctx, query, adminID, status)
+	return err
+}
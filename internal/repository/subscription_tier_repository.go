@@ -8,6 +8,7 @@ import (
 	"mobilka/internal/models"
 	"mobilka/internal/utils"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -26,8 +27,8 @@ func NewSubscriptionTierRepository(db *pgxpool.Pool) *SubscriptionTierRepository
 // Create creates a new subscription tier
 func (r *SubscriptionTierRepository) Create(ctx context.Context, tier *models.SubscriptionTier) error {
 	query := `
-		INSERT INTO subscription_tier (name, min_users, max_users, price, description)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO subscription_tier (name, min_users, max_users, price, currency, trial_days, description)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -36,6 +37,8 @@ func (r *SubscriptionTierRepository) Create(ctx context.Context, tier *models.Su
 		tier.MinUsers,
 		tier.MaxUsers,
 		tier.Price,
+		tier.Currency,
+		tier.TrialDays,
 		tier.Description,
 	).Scan(
 		&tier.ID,
@@ -49,7 +52,7 @@ func (r *SubscriptionTierRepository) Create(ctx context.Context, tier *models.Su
 // GetByID retrieves a subscription tier by ID
 func (r *SubscriptionTierRepository) GetByID(ctx context.Context, id int) (*models.SubscriptionTier, error) {
 	query := `
-		SELECT id, name, min_users, max_users, price, description, created_at, updated_at
+		SELECT id, name, min_users, max_users, price, currency, trial_days, description, created_at, updated_at
 		FROM subscription_tier
 		WHERE id = $1
 	`
@@ -63,6 +66,8 @@ func (r *SubscriptionTierRepository) GetByID(ctx context.Context, id int) (*mode
 		&tier.MinUsers,
 		&maxUsers,
 		&tier.Price,
+		&tier.Currency,
+		&tier.TrialDays,
 		&tier.Description,
 		&tier.CreatedAt,
 		&tier.UpdatedAt,
@@ -83,13 +88,57 @@ func (r *SubscriptionTierRepository) GetByID(ctx context.Context, id int) (*mode
 	return &tier, nil
 }
 
+// subscriptionTierList describes how subscription tier lists are filtered and sorted
+var subscriptionTierList = listSpec{
+	table:    "subscription_tier",
+	columns:  "id, name, min_users, max_users, price, currency, trial_days, description, created_at, updated_at",
+	idColumn: "id",
+	sorts: map[string]listSort{
+		"id":         {"id", "BIGINT"},
+		"min_users":  {"min_users", "BIGINT"},
+		"name":       {"name", "TEXT"},
+		"price":      {"price", "BIGINT"},
+		"created_at": {"created_at", "TIMESTAMPTZ"},
+	},
+	defaultSort:   "min_users",
+	dateColumn:    "created_at",
+	searchColumns: []string{"name", "description"},
+}
+
+// scanSubscriptionTier scans a tier row selected with the subscriptionTierList columns,
+// followed by any extra columns into extra
+func scanSubscriptionTier(row pgx.Row, extra ...interface{}) (*models.SubscriptionTier, error) {
+	var tier models.SubscriptionTier
+	var maxUsers sql.NullInt32
+
+	dest := []interface{}{
+		&tier.ID,
+		&tier.Name,
+		&tier.MinUsers,
+		&maxUsers,
+		&tier.Price,
+		&tier.Currency,
+		&tier.TrialDays,
+		&tier.Description,
+		&tier.CreatedAt,
+		&tier.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if maxUsers.Valid {
+		val := int(maxUsers.Int32)
+		tier.MaxUsers = &val
+	}
+
+	return &tier, nil
+}
+
 // GetAll retrieves all subscription tiers
 func (r *SubscriptionTierRepository) GetAll(ctx context.Context) ([]*models.SubscriptionTier, error) {
-	query := `
-		SELECT id, name, min_users, max_users, price, description, created_at, updated_at
-		FROM subscription_tier
-		ORDER BY min_users
-	`
+	query := `SELECT ` + subscriptionTierList.columns + ` FROM subscription_tier ORDER BY min_users`
 
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
@@ -99,29 +148,11 @@ func (r *SubscriptionTierRepository) GetAll(ctx context.Context) ([]*models.Subs
 
 	var tiers []*models.SubscriptionTier
 	for rows.Next() {
-		var tier models.SubscriptionTier
-		var maxUsers sql.NullInt32
-
-		err := rows.Scan(
-			&tier.ID,
-			&tier.Name,
-			&tier.MinUsers,
-			&maxUsers,
-			&tier.Price,
-			&tier.Description,
-			&tier.CreatedAt,
-			&tier.UpdatedAt,
-		)
+		tier, err := scanSubscriptionTier(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		if maxUsers.Valid {
-			val := int(maxUsers.Int32)
-			tier.MaxUsers = &val
-		}
-
-		tiers = append(tiers, &tier)
+		tiers = append(tiers, tier)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -131,11 +162,17 @@ func (r *SubscriptionTierRepository) GetAll(ctx context.Context) ([]*models.Subs
 	return tiers, nil
 }
 
+// List retrieves one page of subscription tiers
+func (r *SubscriptionTierRepository) List(ctx context.Context, params models.ListParams) ([]*models.SubscriptionTier, *models.ListMeta, error) {
+	return queryList(ctx, r.db, subscriptionTierList, params, nil, scanSubscriptionTier)
+}
+
 // Update updates a subscription tier
 func (r *SubscriptionTierRepository) Update(ctx context.Context, id int, tier *models.SubscriptionTier) error {
 	query := `
 		UPDATE subscription_tier
-		SET name = $2, min_users = $3, max_users = $4, price = $5, description = $6
+		SET name = $2, min_users = $3, max_users = $4, price = $5, currency = $6, trial_days = $7,
+		    description = $8
 		WHERE id = $1
 		RETURNING updated_at
 	`
@@ -146,6 +183,8 @@ func (r *SubscriptionTierRepository) Update(ctx context.Context, id int, tier *m
 		tier.MinUsers,
 		tier.MaxUsers,
 		tier.Price,
+		tier.Currency,
+		tier.TrialDays,
 		tier.Description,
 	).Scan(&tier.UpdatedAt)
 
@@ -179,7 +218,7 @@ func (r *SubscriptionTierRepository) Delete(ctx context.Context, id int) error {
 // GetTierForUserCount retrieves the appropriate subscription tier for a given user count
 func (r *SubscriptionTierRepository) GetTierForUserCount(ctx context.Context, userCount int) (*models.SubscriptionTier, error) {
 	query := `
-		SELECT id, name, min_users, max_users, price, description, created_at, updated_at
+		SELECT id, name, min_users, max_users, price, currency, trial_days, description, created_at, updated_at
 		FROM subscription_tier
 		WHERE min_users <= $1 AND (max_users IS NULL OR max_users >= $1)
 		ORDER BY price DESC
@@ -195,6 +234,8 @@ func (r *SubscriptionTierRepository) GetTierForUserCount(ctx context.Context, us
 		&tier.MinUsers,
 		&maxUsers,
 		&tier.Price,
+		&tier.Currency,
+		&tier.TrialDays,
 		&tier.Description,
 		&tier.CreatedAt,
 		&tier.UpdatedAt,
@@ -214,3 +255,103 @@ func (r *SubscriptionTierRepository) GetTierForUserCount(ctx context.Context, us
 
 	return &tier, nil
 }
+
+// GetPrices retrieves the interval-specific prices of subscription tiers, keyed by tier ID
+func (r *SubscriptionTierRepository) GetPrices(ctx context.Context, tierIDs ...int) (map[int][]models.SubscriptionTierPrice, error) {
+	query := `
+		SELECT id, subscription_tier_id, billing_interval, price, created_at, updated_at
+		FROM subscription_tier_price
+		WHERE subscription_tier_id = ANY($1)
+		ORDER BY subscription_tier_id, billing_interval
+	`
+
+	ids := make([]int32, 0, len(tierIDs))
+	for _, id := range tierIDs {
+		ids = append(ids, int32(id))
+	}
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make(map[int][]models.SubscriptionTierPrice)
+	for rows.Next() {
+		var price models.SubscriptionTierPrice
+		err := rows.Scan(
+			&price.ID,
+			&price.SubscriptionTierID,
+			&price.BillingInterval,
+			&price.Price,
+			&price.CreatedAt,
+			&price.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		prices[price.SubscriptionTierID] = append(prices[price.SubscriptionTierID], price)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// LoadPrices attaches the interval-specific prices to subscription tiers
+func (r *SubscriptionTierRepository) LoadPrices(ctx context.Context, tiers ...*models.SubscriptionTier) error {
+	ids := make([]int, 0, len(tiers))
+	for _, tier := range tiers {
+		if tier != nil {
+			ids = append(ids, tier.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	prices, err := r.GetPrices(ctx, ids...)
+	if err != nil {
+		return err
+	}
+
+	for _, tier := range tiers {
+		if tier != nil {
+			tier.Prices = prices[tier.ID]
+		}
+	}
+
+	return nil
+}
+
+// SetPrices creates, updates or, for nil prices, removes interval-specific tier prices
+func (r *SubscriptionTierRepository) SetPrices(ctx context.Context, tierID int, prices map[string]*int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for interval, price := range prices {
+		if price == nil {
+			_, err = tx.Exec(ctx, `
+				DELETE FROM subscription_tier_price
+				WHERE subscription_tier_id = $1 AND billing_interval = $2
+			`, tierID, interval)
+		} else {
+			_, err = tx.Exec(ctx, `
+				INSERT INTO subscription_tier_price (subscription_tier_id, billing_interval, price)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (subscription_tier_id, billing_interval) DO UPDATE SET price = EXCLUDED.price
+			`, tierID, interval, *price)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SubscriptionTrialRepository handles database operations for subscription trials
+type SubscriptionTrialRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSubscriptionTrialRepository creates a new subscription trial repository
+func NewSubscriptionTrialRepository(db *pgxpool.Pool) *SubscriptionTrialRepository {
+	return &SubscriptionTrialRepository{
+		db: db,
+	}
+}
+
+// HasUsedTrial checks whether a trial was ever started for an email
+func (r *SubscriptionTrialRepository) HasUsedTrial(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM subscription_trial WHERE email = $1)
+	`, email).Scan(&exists)
+
+	return exists, err
+}
+
+// RecordTrial remembers that a trial was started for an email
+func (r *SubscriptionTrialRepository) RecordTrial(ctx context.Context, email string, adminID int, subscriptionTierID *int, startedAt, endsAt time.Time) error {
+	query := `
+		INSERT INTO subscription_trial (email, admin_id, subscription_tier_id, started_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (email) DO NOTHING
+	`
+
+	_, err := r.db.Exec(ctx, query, email, adminID, subscriptionTierID, startedAt, endsAt)
+	return err
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"mobilka/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UsageRepository handles database operations for device activity and active-user rollups
+type UsageRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(db *pgxpool.Pool) *UsageRepository {
+	return &UsageRepository{
+		db: db,
+	}
+}
+
+// RecordDeviceActivity marks a device as active for an admin on the given day
+func (r *UsageRepository) RecordDeviceActivity(ctx context.Context, adminID int, deviceKey string, day time.Time) error {
+	query := `
+		INSERT INTO device_activity (admin_id, device_key, activity_date)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (admin_id, activity_date, device_key) DO NOTHING
+	`
+
+	_, err := r.db.Exec(ctx, query, adminID, deviceKey, day)
+	return err
+}
+
+// RefreshRollups recomputes daily and monthly active users from device activity on or after since
+func (r *UsageRepository) RefreshRollups(ctx context.Context, since time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO admin_active_users (admin_id, period, period_start, active_users)
+		SELECT admin_id, 'day', activity_date, COUNT(*)
+		FROM device_activity
+		WHERE activity_date >= $1
+		GROUP BY admin_id, activity_date
+		ON CONFLICT (admin_id, period, period_start)
+		DO UPDATE SET active_users = EXCLUDED.active_users
+	`, since)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO admin_active_users (admin_id, period, period_start, active_users)
+		SELECT admin_id, 'month', date_trunc('month', activity_date)::date, COUNT(DISTINCT device_key)
+		FROM device_activity
+		WHERE activity_date >= $1
+		GROUP BY admin_id, date_trunc('month', activity_date)
+		ON CONFLICT (admin_id, period, period_start)
+		DO UPDATE SET active_users = EXCLUDED.active_users
+	`, since)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpdateBillableUsers sets admin.users to the highest monthly active users since the given month.
+// Until metering of an admin covers that whole month, its counts are partial and the admin's
+// users only ever grow, so starting to meter doesn't bill an admin as zero users.
+func (r *UsageRepository) UpdateBillableUsers(ctx context.Context, sinceMonth time.Time) (int, error) {
+	query := `
+		UPDATE admin a
+		SET users = GREATEST(COALESCE((
+			SELECT MAX(u.active_users)
+			FROM admin_active_users u
+			WHERE u.admin_id = a.id AND u.period = 'month' AND u.period_start >= $1
+		), 0), CASE WHEN a.usage_metered_since <= $1 THEN 0 ELSE a.users END)
+	`
+
+	result, err := r.db.Exec(ctx, query, sinceMonth)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// DeleteActivityBefore removes raw device activity that is already rolled up
+func (r *UsageRepository) DeleteActivityBefore(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM device_activity WHERE activity_date < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// GetActiveUsers retrieves the rollups of an admin for a period type within a date range
+func (r *UsageRepository) GetActiveUsers(ctx context.Context, adminID int, period string, from, to time.Time) ([]*models.ActiveUsers, error) {
+	query := `
+		SELECT admin_id, period, period_start, active_users
+		FROM admin_active_users
+		WHERE admin_id = $1 AND period = $2 AND period_start >= $3 AND period_start <= $4
+		ORDER BY period_start
+	`
+
+	rows, err := r.db.Query(ctx, query, adminID, period, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rollups := []*models.ActiveUsers{}
+	for rows.Next() {
+		var rollup models.ActiveUsers
+		if err := rows.Scan(&rollup.AdminID, &rollup.Period, &rollup.PeriodStart, &rollup.ActiveUsers); err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, &rollup)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rollups, nil
+}
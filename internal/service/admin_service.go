@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"mobilka/internal/models"
@@ -12,15 +13,24 @@ import (
 
 // AdminService handles admin operations
 type AdminService struct {
-	adminRepo *repository.AdminRepository
+	adminRepo            *repository.AdminRepository
+	usageRepo            *repository.UsageRepository
+	subscriptionTierRepo *repository.SubscriptionTierRepository
+	trialRepo            *repository.SubscriptionTrialRepository
 }
 
 // NewAdminService creates a new admin service
 func NewAdminService(
 	adminRepo *repository.AdminRepository,
+	usageRepo *repository.UsageRepository,
+	subscriptionTierRepo *repository.SubscriptionTierRepository,
+	trialRepo *repository.SubscriptionTrialRepository,
 ) *AdminService {
 	return &AdminService{
-		adminRepo: adminRepo,
+		adminRepo:            adminRepo,
+		usageRepo:            usageRepo,
+		subscriptionTierRepo: subscriptionTierRepo,
+		trialRepo:            trialRepo,
 	}
 }
 
@@ -46,6 +56,11 @@ func (s *AdminService) Create(ctx context.Context, req *models.AdminCreateReques
 		}
 	}
 
+	billingCurrency := utils.NormalizeCurrency(req.BillingCurrency)
+	if !utils.IsSupportedCurrency(billingCurrency) {
+		return nil, utils.NewInvalidInputError("Unsupported billing currency " + billingCurrency)
+	}
+
 	// Create admin
 	admin := &models.Admin{
 		UserName:               req.UserName,
@@ -64,6 +79,14 @@ func (s *AdminService) Create(ctx context.Context, req *models.AdminCreateReques
 		PaymentPassword:        paymentPasswordHash,
 		BotToken:               req.BotToken,
 		BotChatID:              req.BotChatID,
+		BillingCurrency:        billingCurrency,
+		BillingInterval:        models.BillingIntervalMonthly,
+	}
+
+	// Start the subscription, with a trial unless this email already had one
+	err = s.startSubscription(ctx, admin, req.SubscriptionTierID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Save to database
@@ -72,9 +95,68 @@ func (s *AdminService) Create(ctx context.Context, req *models.AdminCreateReques
 		return nil, err
 	}
 
+	// Remember the trial so recreating the account doesn't start another one
+	if admin.SubscriptionStatus == "trial" {
+		err = s.trialRepo.RecordTrial(ctx, normalizeEmail(admin.Email), admin.ID,
+			admin.SubscriptionTierID, admin.CreatedAt, *admin.SubscriptionExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return admin, nil
 }
 
+// startSubscription sets the initial subscription of a new admin. The admin gets a trial
+// on the chosen tier (or the tier for zero users) if the tier offers one and the email
+// never had a trial. Otherwise free tiers are active right away and paid tiers wait
+// for the first verified payment.
+func (s *AdminService) startSubscription(ctx context.Context, admin *models.Admin, tierID *int) error {
+	var tier *models.SubscriptionTier
+	var err error
+
+	if tierID != nil {
+		tier, err = s.subscriptionTierRepo.GetByID(ctx, *tierID)
+		if err != nil {
+			return err
+		}
+	} else {
+		tier, err = s.subscriptionTierRepo.GetTierForUserCount(ctx, 0)
+		if err != nil {
+			// Without tiers there is nothing to bill yet
+			admin.SubscriptionStatus = "active"
+			return nil
+		}
+	}
+
+	admin.SubscriptionTierID = &tier.ID
+
+	usedTrial, err := s.trialRepo.HasUsedTrial(ctx, normalizeEmail(admin.Email))
+	if err != nil {
+		return err
+	}
+
+	// Free tiers never get a trial, it would expire and lock the admin out
+	switch {
+	case tier.Price <= 0:
+		admin.SubscriptionStatus = "active"
+	case tier.TrialDays > 0 && !usedTrial:
+		trialEnd := time.Now().AddDate(0, 0, tier.TrialDays)
+		admin.SubscriptionStatus = "trial"
+		admin.SubscriptionExpiresAt = &trialEnd
+	default:
+		admin.SubscriptionStatus = "expired"
+		admin.IsAccessRestricted = true
+	}
+
+	return nil
+}
+
+// normalizeEmail normalizes an email for trial lookups
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 // GetByID retrieves an admin by ID
 func (s *AdminService) GetByID(ctx context.Context, id int) (*models.Admin, error) {
 	return s.adminRepo.GetByID(ctx, id)
@@ -85,9 +167,9 @@ func (s *AdminService) GetByEmail(ctx context.Context, email string) (*models.Ad
 	return s.adminRepo.GetByEmail(ctx, email)
 }
 
-// GetAll retrieves all admins
-func (s *AdminService) GetAll(ctx context.Context) ([]*models.Admin, error) {
-	return s.adminRepo.GetAll(ctx)
+// List retrieves one page of admins
+func (s *AdminService) List(ctx context.Context, params models.ListParams) ([]*models.Admin, *models.ListMeta, error) {
+	return s.adminRepo.List(ctx, params)
 }
 
 // Update updates an admin
@@ -210,6 +292,14 @@ func (s *AdminService) Update(ctx context.Context, id int, req *models.AdminUpda
 		fmt.Printf("Setting bot_chat_id to: %s\n", req.BotChatID)
 	}
 
+	if req.BillingCurrency != "" {
+		billingCurrency := utils.NormalizeCurrency(req.BillingCurrency)
+		if !utils.IsSupportedCurrency(billingCurrency) {
+			return nil, utils.NewInvalidInputError("Unsupported billing currency " + billingCurrency)
+		}
+		admin.BillingCurrency = billingCurrency
+	}
+
 	// Update in database for non-token fields (including bot fields)
 	fmt.Println("Updating non-token fields including delivery and bot fields")
 	err = s.adminRepo.Update(ctx, id, admin)
@@ -227,22 +317,22 @@ func (s *AdminService) Delete(ctx context.Context, id int) error {
 	return s.adminRepo.Delete(ctx, id)
 }
 
-// GetByIDPublic retrieves an admin by ID and increments the users count
-func (s *AdminService) GetByIDPublic(ctx context.Context, id int) (*models.Admin, error) {
+// GetByIDPublic retrieves an admin by ID and meters the requesting device.
+// Requests without a device ID or FCM token are served but not counted.
+func (s *AdminService) GetByIDPublic(ctx context.Context, id int, deviceID string) (*models.Admin, error) {
 	// Get admin
 	admin, err := s.adminRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Increment users count
-	err = s.adminRepo.IncrementUsersCount(ctx, id)
-	if err != nil {
-		return nil, err
+	// Mark the device active for today; repeated requests are counted once
+	if strings.TrimSpace(deviceID) != "" {
+		err = s.usageRepo.RecordDeviceActivity(ctx, id, hashDeviceKey(deviceID), time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Update local admin object to reflect the incremented count
-	admin.Users++
-
 	return admin, nil
 }
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// AnalyticsService handles revenue and subscription reporting for super admins
+type AnalyticsService struct {
+	analyticsRepo *repository.AnalyticsRepository
+	adminRepo     *repository.AdminRepository
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(analyticsRepo *repository.AnalyticsRepository, adminRepo *repository.AdminRepository) *AnalyticsService {
+	return &AnalyticsService{
+		analyticsRepo: analyticsRepo,
+		adminRepo:     adminRepo,
+	}
+}
+
+// GetSummary retrieves the headline numbers: admins by status, pending payments and current MRR
+func (s *AnalyticsService) GetSummary(ctx context.Context, currency string) (*models.AnalyticsSummary, error) {
+	currency, err := reportCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	activeAdmins, err := s.adminRepo.GetActiveCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byStatus, restricted, err := s.analyticsRepo.CountAdminsByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.analyticsRepo.CountPendingPayments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mrr, err := s.GetMRR(ctx, currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AnalyticsSummary{
+		ActiveAdmins:        activeAdmins,
+		AdminsByStatus:      byStatus,
+		RestrictedAdmins:    restricted,
+		PendingPayments:     pending,
+		MRR:                 mrr.Total,
+		Currency:            currency,
+		UnconvertedPayments: mrr.UnconvertedPayments,
+	}, nil
+}
+
+// GetMRR retrieves the monthly recurring revenue by tier at a point in time
+func (s *AnalyticsService) GetMRR(ctx context.Context, currency string, at time.Time) (*models.MRRReport, error) {
+	currency, err := reportCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers, unconverted, err := s.analyticsRepo.GetMRRByTier(ctx, currency, at)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.MRRReport{
+		At:                  at,
+		Currency:            currency,
+		Tiers:               tiers,
+		UnconvertedPayments: unconverted,
+	}
+	for _, tier := range tiers {
+		report.Total += tier.MRR
+	}
+
+	return report, nil
+}
+
+// GetAdminMovements retrieves new, churned and reactivated admins per month
+func (s *AnalyticsService) GetAdminMovements(ctx context.Context, filter models.AnalyticsFilter) ([]*models.AdminMovement, error) {
+	if err := validateAnalyticsRange(filter); err != nil {
+		return nil, err
+	}
+
+	return s.analyticsRepo.GetAdminMovements(ctx, filter.From, filter.To)
+}
+
+// GetVerifiedRevenue retrieves the verified revenue per day, week or month
+func (s *AnalyticsService) GetVerifiedRevenue(ctx context.Context, filter models.AnalyticsFilter, period string) (*models.RevenueReport, error) {
+	if period == "" {
+		period = "month"
+	}
+	if period != "day" && period != "week" && period != "month" {
+		return nil, utils.NewInvalidInputError("Period must be day, week or month")
+	}
+
+	if err := validateAnalyticsRange(filter); err != nil {
+		return nil, err
+	}
+
+	currency, err := reportCurrency(filter.Currency)
+	if err != nil {
+		return nil, err
+	}
+	filter.Currency = currency
+
+	periods, err := s.analyticsRepo.GetVerifiedRevenue(ctx, filter, period)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.RevenueReport{
+		Period:   period,
+		Currency: currency,
+		Periods:  periods,
+	}
+	for _, revenue := range periods {
+		report.Total += revenue.Revenue
+		report.UnconvertedPayments += revenue.UnconvertedPayments
+	}
+
+	return report, nil
+}
+
+// GetPaymentMethodStats retrieves the payments within a date range by payment method
+func (s *AnalyticsService) GetPaymentMethodStats(ctx context.Context, filter models.AnalyticsFilter) ([]*models.PaymentMethodStats, error) {
+	if err := validateAnalyticsRange(filter); err != nil {
+		return nil, err
+	}
+
+	currency, err := reportCurrency(filter.Currency)
+	if err != nil {
+		return nil, err
+	}
+	filter.Currency = currency
+
+	return s.analyticsRepo.GetPaymentMethodStats(ctx, filter)
+}
+
+// GetVerificationLatency retrieves how long payments processed within a date range waited
+func (s *AnalyticsService) GetVerificationLatency(ctx context.Context, filter models.AnalyticsFilter) (*models.VerificationLatency, error) {
+	if err := validateAnalyticsRange(filter); err != nil {
+		return nil, err
+	}
+
+	return s.analyticsRepo.GetVerificationLatency(ctx, filter.From, filter.To)
+}
+
+// GetAtRiskAdmins retrieves admins whose subscription ends or ended within the given number of days
+func (s *AnalyticsService) GetAtRiskAdmins(ctx context.Context, days int) ([]*models.AtRiskAdmin, error) {
+	if days < 1 || days > 365 {
+		return nil, utils.NewInvalidInputError("Days must be between 1 and 365")
+	}
+
+	admins, err := s.analyticsRepo.GetAtRiskAdmins(ctx, days)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, admin := range admins {
+		admin.DaysLeft = int(math.Ceil(admin.SubscriptionExpiresAt.Sub(now).Hours() / 24))
+
+		switch {
+		case admin.SubscriptionStatus == "expired":
+			admin.Reason = "expired"
+		case admin.SubscriptionStatus == "trial":
+			admin.Reason = "trial_ending"
+		default:
+			admin.Reason = "expiring"
+		}
+	}
+
+	return admins, nil
+}
+
+// reportCurrency normalizes and checks the currency a report is converted to
+func reportCurrency(currency string) (string, error) {
+	currency = utils.NormalizeCurrency(currency)
+	if !utils.IsSupportedCurrency(currency) {
+		return "", utils.NewInvalidInputError("Unsupported currency " + currency)
+	}
+	return currency, nil
+}
+
+// validateAnalyticsRange checks that a report date range is not empty
+func validateAnalyticsRange(filter models.AnalyticsFilter) error {
+	if !filter.To.After(filter.From) {
+		return utils.NewInvalidInputError("To date must not be before from date")
+	}
+	return nil
+}
@@ -47,14 +47,9 @@ func (s *BannerService) GetByID(ctx context.Context, id int) (*models.Banner, er
 	return s.bannerRepo.GetByID(ctx, id)
 }
 
-// GetByAdminID retrieves all banners for a specific admin
-func (s *BannerService) GetByAdminID(ctx context.Context, adminID int) ([]*models.Banner, error) {
-	return s.bannerRepo.GetByAdminID(ctx, adminID)
-}
-
-// GetAll retrieves all banners
-func (s *BannerService) GetAll(ctx context.Context) ([]*models.Banner, error) {
-	return s.bannerRepo.GetAll(ctx)
+// List retrieves one page of banners
+func (s *BannerService) List(ctx context.Context, params models.ListParams) ([]*models.Banner, *models.ListMeta, error) {
+	return s.bannerRepo.List(ctx, params)
 }
 
 // Update updates a banner
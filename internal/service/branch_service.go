@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// BranchService handles restaurant branch operations
+type BranchService struct {
+	branchRepo *repository.BranchRepository
+}
+
+// NewBranchService creates a new branch service
+func NewBranchService(branchRepo *repository.BranchRepository) *BranchService {
+	return &BranchService{
+		branchRepo: branchRepo,
+	}
+}
+
+// Create creates a new branch for an admin
+func (s *BranchService) Create(ctx context.Context, adminID int, req *models.BranchCreateRequest) (*models.Branch, error) {
+	branch := &models.Branch{
+		AdminID:      adminID,
+		Name:         req.Name,
+		Address:      req.Address,
+		Phone:        req.Phone,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		Photos:       req.Photos,
+		Status:       req.Status,
+		SortOrder:    req.SortOrder,
+		TimeZone:     req.TimeZone,
+		OpeningHours: req.OpeningHours,
+		SpecialDays:  req.SpecialDays,
+	}
+
+	if branch.Status == "" {
+		branch.Status = models.BranchStatusActive
+	}
+
+	if branch.TimeZone == "" {
+		branch.TimeZone = "UTC"
+	}
+
+	if err := validateCoordinates(branch); err != nil {
+		return nil, err
+	}
+
+	if err := validateOpeningHours(branch); err != nil {
+		return nil, err
+	}
+
+	if err := s.branchRepo.Create(ctx, branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+// GetByID retrieves a branch by ID
+func (s *BranchService) GetByID(ctx context.Context, id int) (*models.Branch, error) {
+	return s.branchRepo.GetByID(ctx, id)
+}
+
+// GetPublicByID retrieves a branch that is visible to customers
+func (s *BranchService) GetPublicByID(ctx context.Context, id int) (*models.Branch, error) {
+	branch, err := s.branchRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if branch.Status == models.BranchStatusInactive {
+		return nil, utils.ErrResourceNotFound
+	}
+
+	return branch, nil
+}
+
+// List retrieves one page of branches
+func (s *BranchService) List(ctx context.Context, params models.ListParams) ([]*models.Branch, *models.ListMeta, error) {
+	return s.branchRepo.List(ctx, params, false)
+}
+
+// ListPublic retrieves one page of the branches of an admin that are visible to customers
+func (s *BranchService) ListPublic(ctx context.Context, adminID int, params models.ListParams) ([]*models.Branch, *models.ListMeta, error) {
+	params.AdminID = &adminID
+	return s.branchRepo.List(ctx, params, true)
+}
+
+// OpeningStatus tells whether any public branch of an admin is open now and, when none is,
+// when the first of them opens next
+func (s *BranchService) OpeningStatus(ctx context.Context, adminID int) (*bool, *time.Time, error) {
+	branches, err := s.branchRepo.ListPublicByAdminID(ctx, adminID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isOpen, nextOpeningAt := models.CombinedOpeningStatus(branches, time.Now())
+	return isOpen, nextOpeningAt, nil
+}
+
+// Nearby retrieves the public branches of an admin nearest to a point
+func (s *BranchService) Nearby(ctx context.Context, adminID int, query *models.NearbyBranchQuery) ([]*models.NearbyBranch, error) {
+	radius := query.Radius
+	if radius == 0 {
+		radius = models.DefaultNearbyRadius
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = models.DefaultListLimit
+	}
+
+	return s.branchRepo.Nearby(ctx, adminID, *query.Latitude, *query.Longitude, radius, limit)
+}
+
+// Update updates a branch
+func (s *BranchService) Update(ctx context.Context, branch *models.Branch, req *models.BranchUpdateRequest) (*models.Branch, error) {
+	// Update fields if provided
+	if req.Name != "" {
+		branch.Name = req.Name
+	}
+
+	if req.Address != nil {
+		branch.Address = *req.Address
+	}
+
+	if req.Phone != nil {
+		branch.Phone = *req.Phone
+	}
+
+	// Coordinates are replaced together, so a branch can't end up with only one of them
+	if req.Latitude != nil || req.Longitude != nil {
+		branch.Latitude = req.Latitude
+		branch.Longitude = req.Longitude
+	}
+
+	if req.Photos != nil {
+		branch.Photos = *req.Photos
+	}
+
+	if req.Status != "" {
+		branch.Status = req.Status
+	}
+
+	if req.SortOrder != nil {
+		branch.SortOrder = *req.SortOrder
+	}
+
+	if req.TimeZone != "" {
+		branch.TimeZone = req.TimeZone
+	}
+
+	if req.OpeningHours != nil {
+		branch.OpeningHours = *req.OpeningHours
+	}
+
+	if req.SpecialDays != nil {
+		branch.SpecialDays = *req.SpecialDays
+	}
+
+	if err := validateCoordinates(branch); err != nil {
+		return nil, err
+	}
+
+	if err := validateOpeningHours(branch); err != nil {
+		return nil, err
+	}
+
+	if err := s.branchRepo.Update(ctx, branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+// Delete deletes a branch
+func (s *BranchService) Delete(ctx context.Context, id int) error {
+	return s.branchRepo.Delete(ctx, id)
+}
+
+// validateCoordinates checks that a branch has either both coordinates or none
+func validateCoordinates(branch *models.Branch) error {
+	if (branch.Latitude == nil) != (branch.Longitude == nil) {
+		return utils.NewInvalidInputError("Latitude and longitude must be set together")
+	}
+	return nil
+}
+
+// validateOpeningHours checks the time zone, the weekly intervals and the special days of a branch
+func validateOpeningHours(branch *models.Branch) error {
+	if _, err := time.LoadLocation(branch.TimeZone); err != nil || branch.TimeZone == "Local" {
+		return utils.NewInvalidInputError("Unknown time zone " + branch.TimeZone)
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		for _, interval := range branch.OpeningHours.Day(day) {
+			if err := interval.Validate(); err != nil {
+				return utils.NewInvalidInputError("Opening hours of " + strings.ToLower(day.String()) + ": " + err.Error())
+			}
+		}
+	}
+
+	dates := make(map[string]bool, len(branch.SpecialDays))
+	for _, specialDay := range branch.SpecialDays {
+		if _, err := time.Parse("2006-01-02", specialDay.Date); err != nil {
+			return utils.NewInvalidInputError("Invalid special day date " + specialDay.Date + ", expected YYYY-MM-DD")
+		}
+		if dates[specialDay.Date] {
+			return utils.NewInvalidInputError("Special day " + specialDay.Date + " is listed twice")
+		}
+		dates[specialDay.Date] = true
+
+		for _, interval := range specialDay.Intervals {
+			if err := interval.Validate(); err != nil {
+				return utils.NewInvalidInputError("Opening hours of " + specialDay.Date + ": " + err.Error())
+			}
+		}
+	}
+
+	return nil
+}
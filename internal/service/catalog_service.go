@@ -0,0 +1,489 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// PublicCatalogTTL is how long the public catalog of an admin is served from the cache.
+// Changes made through this instance invalidate it right away, other replicas pick them
+// up once it expires.
+const PublicCatalogTTL = time.Minute
+
+// catalogCacheEntry is a cached public catalog
+type catalogCacheEntry struct {
+	catalog   *models.PublicCatalog
+	expiresAt time.Time
+}
+
+// CatalogService handles the menu catalog: categories, products and modifier groups
+type CatalogService struct {
+	catalogRepo  *repository.CatalogRepository
+	imageService *ImageService
+
+	cacheMu sync.Mutex
+	cache   map[int]catalogCacheEntry // Public catalogs by admin ID
+}
+
+// NewCatalogService creates a new catalog service
+func NewCatalogService(catalogRepo *repository.CatalogRepository, imageService *ImageService) *CatalogService {
+	return &CatalogService{
+		catalogRepo:  catalogRepo,
+		imageService: imageService,
+		cache:        make(map[int]catalogCacheEntry),
+	}
+}
+
+// invalidate drops the cached public catalog of an admin
+func (s *CatalogService) invalidate(adminID int) {
+	s.cacheMu.Lock()
+	delete(s.cache, adminID)
+	s.cacheMu.Unlock()
+}
+
+// checkImages checks that images were uploaded through the image service
+func (s *CatalogService) checkImages(images ...string) error {
+	for _, image := range images {
+		if image != "" && !s.imageService.Exists(image) {
+			return utils.NewInvalidInputError("Unknown image " + image + ", upload it first")
+		}
+	}
+	return nil
+}
+
+// CreateCategory creates a new category for an admin
+func (s *CatalogService) CreateCategory(ctx context.Context, adminID int, req *models.CatalogCategoryCreateRequest) (*models.CatalogCategory, error) {
+	if err := s.checkImages(req.Image); err != nil {
+		return nil, err
+	}
+
+	category := &models.CatalogCategory{
+		AdminID:     adminID,
+		Name:        req.Name,
+		Description: req.Description,
+		Image:       req.Image,
+		SortOrder:   req.SortOrder,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+
+	if err := s.catalogRepo.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(adminID)
+	return category, nil
+}
+
+// GetCategoryByID retrieves a category by ID
+func (s *CatalogService) GetCategoryByID(ctx context.Context, id int) (*models.CatalogCategory, error) {
+	return s.catalogRepo.GetCategoryByID(ctx, id)
+}
+
+// ListCategories retrieves one page of categories
+func (s *CatalogService) ListCategories(ctx context.Context, params models.ListParams) ([]*models.CatalogCategory, *models.ListMeta, error) {
+	return s.catalogRepo.ListCategories(ctx, params)
+}
+
+// UpdateCategory updates a category
+func (s *CatalogService) UpdateCategory(ctx context.Context, category *models.CatalogCategory, req *models.CatalogCategoryUpdateRequest) (*models.CatalogCategory, error) {
+	if req.Name != "" {
+		category.Name = req.Name
+	}
+
+	if req.Description != nil {
+		category.Description = *req.Description
+	}
+
+	if req.Image != nil {
+		if err := s.checkImages(*req.Image); err != nil {
+			return nil, err
+		}
+		category.Image = *req.Image
+	}
+
+	if req.SortOrder != nil {
+		category.SortOrder = *req.SortOrder
+	}
+
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+
+	if err := s.catalogRepo.UpdateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(category.AdminID)
+	return category, nil
+}
+
+// DeleteCategory deletes a category without products
+func (s *CatalogService) DeleteCategory(ctx context.Context, category *models.CatalogCategory) error {
+	if err := s.catalogRepo.DeleteCategory(ctx, category.ID); err != nil {
+		return err
+	}
+
+	s.invalidate(category.AdminID)
+	return nil
+}
+
+// checkCategory checks that a category exists and belongs to an admin
+func (s *CatalogService) checkCategory(ctx context.Context, adminID, categoryID int) error {
+	category, err := s.catalogRepo.GetCategoryByID(ctx, categoryID)
+	if err == utils.ErrResourceNotFound || (err == nil && category.AdminID != adminID) {
+		return utils.NewInvalidInputError("Unknown category " + strconv.Itoa(categoryID))
+	}
+	return err
+}
+
+// checkModifierGroups checks that modifier groups exist, belong to an admin and are listed once
+func (s *CatalogService) checkModifierGroups(ctx context.Context, adminID int, groupIDs []int) error {
+	if len(groupIDs) == 0 {
+		return nil
+	}
+
+	groups, err := s.catalogRepo.GetModifierGroupsByIDs(ctx, adminID, groupIDs)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool, len(groupIDs))
+	for _, id := range groupIDs {
+		if _, ok := groups[id]; !ok {
+			return utils.NewInvalidInputError("Unknown modifier group " + strconv.Itoa(id))
+		}
+		if seen[id] {
+			return utils.NewInvalidInputError("Modifier group " + strconv.Itoa(id) + " is listed twice")
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// CreateProduct creates a new product for an admin
+func (s *CatalogService) CreateProduct(ctx context.Context, adminID int, req *models.CatalogProductCreateRequest) (*models.CatalogProduct, error) {
+	currency := utils.NormalizeCurrency(req.Currency)
+	if !utils.IsSupportedCurrency(currency) {
+		return nil, utils.NewInvalidInputError("Unsupported currency " + currency)
+	}
+
+	if err := s.checkCategory(ctx, adminID, req.CategoryID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkModifierGroups(ctx, adminID, req.ModifierGroupIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkImages(req.Images...); err != nil {
+		return nil, err
+	}
+
+	product := &models.CatalogProduct{
+		AdminID:          adminID,
+		CategoryID:       req.CategoryID,
+		Name:             req.Name,
+		Description:      req.Description,
+		Price:            req.Price,
+		Currency:         currency,
+		Images:           req.Images,
+		IsAvailable:      req.IsAvailable == nil || *req.IsAvailable,
+		IsActive:         req.IsActive == nil || *req.IsActive,
+		SortOrder:        req.SortOrder,
+		ModifierGroupIDs: req.ModifierGroupIDs,
+	}
+
+	if err := s.catalogRepo.CreateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(adminID)
+	return product, nil
+}
+
+// GetProductByID retrieves a product by ID
+func (s *CatalogService) GetProductByID(ctx context.Context, id int) (*models.CatalogProduct, error) {
+	return s.catalogRepo.GetProductByID(ctx, id)
+}
+
+// ListProducts retrieves one page of products, optionally of one category only
+func (s *CatalogService) ListProducts(ctx context.Context, params models.ListParams, categoryID *int) ([]*models.CatalogProduct, *models.ListMeta, error) {
+	return s.catalogRepo.ListProducts(ctx, params, categoryID)
+}
+
+// UpdateProduct updates a product
+func (s *CatalogService) UpdateProduct(ctx context.Context, product *models.CatalogProduct, req *models.CatalogProductUpdateRequest) (*models.CatalogProduct, error) {
+	if req.CategoryID != 0 && req.CategoryID != product.CategoryID {
+		if err := s.checkCategory(ctx, product.AdminID, req.CategoryID); err != nil {
+			return nil, err
+		}
+		product.CategoryID = req.CategoryID
+	}
+
+	if req.Name != "" {
+		product.Name = req.Name
+	}
+
+	if req.Description != nil {
+		product.Description = *req.Description
+	}
+
+	if req.Price != nil {
+		product.Price = *req.Price
+	}
+
+	if req.Currency != "" {
+		currency := utils.NormalizeCurrency(req.Currency)
+		if !utils.IsSupportedCurrency(currency) {
+			return nil, utils.NewInvalidInputError("Unsupported currency " + currency)
+		}
+		product.Currency = currency
+	}
+
+	if req.Images != nil {
+		if err := s.checkImages(*req.Images...); err != nil {
+			return nil, err
+		}
+		product.Images = *req.Images
+	}
+
+	if req.IsAvailable != nil {
+		product.IsAvailable = *req.IsAvailable
+	}
+
+	if req.IsActive != nil {
+		product.IsActive = *req.IsActive
+	}
+
+	if req.SortOrder != nil {
+		product.SortOrder = *req.SortOrder
+	}
+
+	if req.ModifierGroupIDs != nil {
+		if err := s.checkModifierGroups(ctx, product.AdminID, *req.ModifierGroupIDs); err != nil {
+			return nil, err
+		}
+		product.ModifierGroupIDs = *req.ModifierGroupIDs
+	}
+
+	if err := s.catalogRepo.UpdateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(product.AdminID)
+	return product, nil
+}
+
+// DeleteProduct deletes a product
+func (s *CatalogService) DeleteProduct(ctx context.Context, product *models.CatalogProduct) error {
+	if err := s.catalogRepo.DeleteProduct(ctx, product.ID); err != nil {
+		return err
+	}
+
+	s.invalidate(product.AdminID)
+	return nil
+}
+
+// modifierOptions converts the options of a modifier group request
+func modifierOptions(requests []models.ModifierOptionRequest) []models.ModifierOption {
+	options := make([]models.ModifierOption, 0, len(requests))
+	for _, req := range requests {
+		options = append(options, models.ModifierOption{
+			ID:          req.ID,
+			Name:        req.Name,
+			Price:       req.Price,
+			IsAvailable: req.IsAvailable == nil || *req.IsAvailable,
+			SortOrder:   req.SortOrder,
+		})
+	}
+	return options
+}
+
+// validateModifierGroup checks the selection rules of a modifier group against its options
+func validateModifierGroup(group *models.ModifierGroup) error {
+	if group.MaxSelect < group.MinSelect {
+		return utils.NewInvalidInputError("max_select can't be less than min_select")
+	}
+	if group.MinSelect > len(group.Options) {
+		return utils.NewInvalidInputError("min_select can't exceed the number of options")
+	}
+	return nil
+}
+
+// CreateModifierGroup creates a new modifier group for an admin
+func (s *CatalogService) CreateModifierGroup(ctx context.Context, adminID int, req *models.ModifierGroupCreateRequest) (*models.ModifierGroup, error) {
+	group := &models.ModifierGroup{
+		AdminID:   adminID,
+		Name:      req.Name,
+		MinSelect: req.MinSelect,
+		MaxSelect: req.MaxSelect,
+		SortOrder: req.SortOrder,
+		Options:   modifierOptions(req.Options),
+	}
+
+	// New groups have no options to update yet
+	for i := range group.Options {
+		group.Options[i].ID = 0
+	}
+
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	if err := s.catalogRepo.CreateModifierGroup(ctx, group); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(adminID)
+	return group, nil
+}
+
+// GetModifierGroupByID retrieves a modifier group by ID
+func (s *CatalogService) GetModifierGroupByID(ctx context.Context, id int) (*models.ModifierGroup, error) {
+	return s.catalogRepo.GetModifierGroupByID(ctx, id)
+}
+
+// ListModifierGroups retrieves one page of modifier groups
+func (s *CatalogService) ListModifierGroups(ctx context.Context, params models.ListParams) ([]*models.ModifierGroup, *models.ListMeta, error) {
+	return s.catalogRepo.ListModifierGroups(ctx, params)
+}
+
+// UpdateModifierGroup updates a modifier group
+func (s *CatalogService) UpdateModifierGroup(ctx context.Context, group *models.ModifierGroup, req *models.ModifierGroupUpdateRequest) (*models.ModifierGroup, error) {
+	if req.Name != "" {
+		group.Name = req.Name
+	}
+
+	if req.MinSelect != nil {
+		group.MinSelect = *req.MinSelect
+	}
+
+	if req.MaxSelect != nil {
+		group.MaxSelect = *req.MaxSelect
+	}
+
+	if req.SortOrder != nil {
+		group.SortOrder = *req.SortOrder
+	}
+
+	if req.Options != nil {
+		group.Options = modifierOptions(*req.Options)
+	}
+
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	if err := s.catalogRepo.UpdateModifierGroup(ctx, group); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(group.AdminID)
+	return group, nil
+}
+
+// DeleteModifierGroup deletes a modifier group
+func (s *CatalogService) DeleteModifierGroup(ctx context.Context, group *models.ModifierGroup) error {
+	if err := s.catalogRepo.DeleteModifierGroup(ctx, group.ID); err != nil {
+		return err
+	}
+
+	s.invalidate(group.AdminID)
+	return nil
+}
+
+// GetPublicCatalog retrieves the menu of an admin as shown in the mobile app, from the
+// cache while it is fresh
+func (s *CatalogService) GetPublicCatalog(ctx context.Context, adminID int) (*models.PublicCatalog, error) {
+	now := time.Now()
+
+	s.cacheMu.Lock()
+	entry, ok := s.cache[adminID]
+	s.cacheMu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.catalog, nil
+	}
+
+	catalog, err := s.buildPublicCatalog(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	catalog.GeneratedAt = now
+
+	s.cacheMu.Lock()
+	s.cache[adminID] = catalogCacheEntry{catalog: catalog, expiresAt: now.Add(PublicCatalogTTL)}
+	s.cacheMu.Unlock()
+
+	return catalog, nil
+}
+
+// buildPublicCatalog reads the active categories and products of an admin and nests them.
+// Categories without active products are left out.
+func (s *CatalogService) buildPublicCatalog(ctx context.Context, adminID int) (*models.PublicCatalog, error) {
+	categories, err := s.catalogRepo.ListActiveCategories(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.catalogRepo.ListActiveProducts(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDs := []int{}
+	for _, product := range products {
+		groupIDs = append(groupIDs, product.ModifierGroupIDs...)
+	}
+
+	groups, err := s.catalogRepo.GetModifierGroupsByIDs(ctx, adminID, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	productsByCategory := make(map[int][]models.PublicCatalogProduct)
+	for _, product := range products {
+		item := models.PublicCatalogProduct{
+			ID:             product.ID,
+			Name:           product.Name,
+			Description:    product.Description,
+			Price:          product.Price,
+			Currency:       product.Currency,
+			Images:         product.ToResponse().Images,
+			IsAvailable:    product.IsAvailable,
+			ModifierGroups: []models.ModifierGroupResponse{},
+		}
+		for _, id := range product.ModifierGroupIDs {
+			if group, ok := groups[id]; ok {
+				item.ModifierGroups = append(item.ModifierGroups, group.ToResponse())
+			}
+		}
+		productsByCategory[product.CategoryID] = append(productsByCategory[product.CategoryID], item)
+	}
+
+	catalog := &models.PublicCatalog{
+		AdminID:    adminID,
+		Categories: []models.PublicCatalogCategory{},
+	}
+	for _, category := range categories {
+		categoryProducts := productsByCategory[category.ID]
+		if len(categoryProducts) == 0 {
+			continue
+		}
+		catalog.Categories = append(catalog.Categories, models.PublicCatalogCategory{
+			ID:          category.ID,
+			Name:        category.Name,
+			Description: category.Description,
+			Image:       category.Image,
+			Products:    categoryProducts,
+		})
+	}
+
+	return catalog, nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// CouponService handles subscription coupon operations
+type CouponService struct {
+	couponRepo *repository.CouponRepository
+	adminRepo  *repository.AdminRepository
+}
+
+// NewCouponService creates a new coupon service
+func NewCouponService(couponRepo *repository.CouponRepository, adminRepo *repository.AdminRepository) *CouponService {
+	return &CouponService{
+		couponRepo: couponRepo,
+		adminRepo:  adminRepo,
+	}
+}
+
+// Create creates a new coupon
+func (s *CouponService) Create(ctx context.Context, req *models.CouponCreateRequest) (*models.Coupon, error) {
+	coupon := &models.Coupon{
+		Code:            normalizeCouponCode(req.Code),
+		Description:     req.Description,
+		DiscountType:    req.DiscountType,
+		DiscountPercent: req.DiscountPercent,
+		DiscountAmount:  req.DiscountAmount,
+		Currency:        utils.NormalizeCurrency(req.Currency),
+		DurationMonths:  req.DurationMonths,
+		MaxRedemptions:  req.MaxRedemptions,
+		ValidTierIDs:    req.ValidTierIDs,
+		ValidFrom:       req.ValidFrom,
+		ValidUntil:      req.ValidUntil,
+		IsActive:        true,
+	}
+
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.couponRepo.GetByCode(ctx, coupon.Code); err == nil {
+		return nil, utils.NewAppError(utils.ErrInvalidInput, "Coupon code already exists", 409)
+	} else if err != utils.ErrResourceNotFound {
+		return nil, err
+	}
+
+	if err := s.couponRepo.Create(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// GetByID retrieves a coupon by ID
+func (s *CouponService) GetByID(ctx context.Context, id int) (*models.Coupon, error) {
+	return s.couponRepo.GetByID(ctx, id)
+}
+
+// List retrieves one page of coupons
+func (s *CouponService) List(ctx context.Context, params models.ListParams) ([]*models.Coupon, *models.ListMeta, error) {
+	return s.couponRepo.List(ctx, params)
+}
+
+// Update updates a coupon. The code can't change once admins may have received it.
+func (s *CouponService) Update(ctx context.Context, id int, req *models.CouponUpdateRequest) (*models.Coupon, error) {
+	coupon, err := s.couponRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != "" {
+		coupon.Description = req.Description
+	}
+	if req.DiscountType != "" && req.DiscountType != coupon.DiscountType {
+		// Switching the type drops the discount of the old one
+		coupon.DiscountType = req.DiscountType
+		coupon.DiscountPercent, coupon.DiscountAmount = 0, 0
+	}
+	if req.DiscountPercent != nil {
+		coupon.DiscountPercent = *req.DiscountPercent
+	}
+	if req.DiscountAmount != nil {
+		coupon.DiscountAmount = *req.DiscountAmount
+	}
+	if req.Currency != "" {
+		coupon.Currency = utils.NormalizeCurrency(req.Currency)
+	}
+	if req.DurationMonths != nil {
+		coupon.DurationMonths = req.DurationMonths
+	}
+	if req.MaxRedemptions != nil {
+		coupon.MaxRedemptions = req.MaxRedemptions
+	}
+	if req.ValidTierIDs != nil {
+		coupon.ValidTierIDs = req.ValidTierIDs
+	}
+	if req.ValidFrom != nil {
+		coupon.ValidFrom = req.ValidFrom
+	}
+	if req.ValidUntil != nil {
+		coupon.ValidUntil = req.ValidUntil
+	}
+	if req.IsActive != nil {
+		coupon.IsActive = *req.IsActive
+	}
+
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+
+	if err := s.couponRepo.Update(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// Delete deletes a coupon
+func (s *CouponService) Delete(ctx context.Context, id int) error {
+	return s.couponRepo.Delete(ctx, id)
+}
+
+// GetRedemptions retrieves one page of the redemptions of a coupon
+func (s *CouponService) GetRedemptions(ctx context.Context, couponID int, params models.ListParams) ([]*models.CouponRedemption, *models.ListMeta, error) {
+	if _, err := s.couponRepo.GetByID(ctx, couponID); err != nil {
+		return nil, nil, err
+	}
+
+	return s.couponRepo.ListRedemptions(ctx, couponID, params)
+}
+
+// GetReport retrieves redemption statistics for all coupons
+func (s *CouponService) GetReport(ctx context.Context) ([]*models.CouponReport, error) {
+	return s.couponRepo.GetReport(ctx)
+}
+
+// ApplyCoupon redeems a coupon for an admin's subscription
+func (s *CouponService) ApplyCoupon(ctx context.Context, adminID int, code string) (*models.CouponRedemption, *models.Coupon, error) {
+	admin, _, err := s.adminRepo.GetByIDWithSubscriptionInfo(ctx, adminID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coupon, err := s.couponRepo.GetByCode(ctx, normalizeCouponCode(code))
+	if err != nil {
+		if err == utils.ErrResourceNotFound {
+			return nil, nil, utils.NewInvalidInputError("Invalid coupon code")
+		}
+		return nil, nil, err
+	}
+
+	if !coupon.IsRedeemable(time.Now()) {
+		return nil, nil, utils.NewInvalidInputError("Coupon is expired or no longer available")
+	}
+
+	if admin.SubscriptionTierID == nil || !coupon.AppliesToTier(*admin.SubscriptionTierID) {
+		return nil, nil, utils.NewInvalidInputError("Coupon is not valid for your subscription tier")
+	}
+
+	redeemed, err := s.couponRepo.HasRedeemed(ctx, coupon.ID, adminID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if redeemed {
+		return nil, nil, utils.NewAppError(utils.ErrInvalidInput, "Coupon has already been redeemed", 409)
+	}
+
+	// Only one discount can run at a time
+	_, _, err = s.couponRepo.GetActiveRedemption(ctx, adminID)
+	if err == nil {
+		return nil, nil, utils.NewAppError(utils.ErrInvalidInput, "Another coupon is already applied to your subscription", 409)
+	}
+	if err != utils.ErrResourceNotFound {
+		return nil, nil, err
+	}
+
+	redemption, err := s.couponRepo.Redeem(ctx, coupon, adminID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return redemption, coupon, nil
+}
+
+// GetActiveCoupon retrieves the coupon currently applied to an admin's subscription
+func (s *CouponService) GetActiveCoupon(ctx context.Context, adminID int) (*models.CouponRedemption, *models.Coupon, error) {
+	return s.couponRepo.GetActiveRedemption(ctx, adminID)
+}
+
+// normalizeCouponCode makes coupon codes case-insensitive
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// validateCoupon checks coupon fields that the request tags can't express
+func validateCoupon(coupon *models.Coupon) error {
+	if coupon.Code == "" {
+		return utils.NewInvalidInputError("Coupon code is required")
+	}
+	if coupon.DiscountType != "percent" && coupon.DiscountType != "fixed" {
+		return utils.NewInvalidInputError("Discount type must be percent or fixed")
+	}
+	if coupon.DiscountType == "percent" && (coupon.DiscountPercent <= 0 || coupon.DiscountAmount != 0) {
+		return utils.NewInvalidInputError("Percent discounts need a discount_percent and no discount_amount")
+	}
+	if coupon.DiscountType == "fixed" && (coupon.DiscountAmount <= 0 || coupon.DiscountPercent != 0) {
+		return utils.NewInvalidInputError("Fixed discounts need a discount_amount and no discount_percent")
+	}
+	if !utils.IsSupportedCurrency(coupon.Currency) {
+		return utils.NewInvalidInputError("Unsupported currency " + coupon.Currency)
+	}
+	if coupon.DiscountPercent > 100 {
+		return utils.NewInvalidInputError("Percent discount can't exceed 100")
+	}
+	if coupon.ValidFrom != nil && coupon.ValidUntil != nil && coupon.ValidUntil.Before(*coupon.ValidFrom) {
+		return utils.NewInvalidInputError("Valid until must be after valid from")
+	}
+	return nil
+}
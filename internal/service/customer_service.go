@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+const (
+	// otpLength is the number of digits of a login code
+	otpLength = 6
+
+	// otpLifetime is how long a login code can be used
+	otpLifetime = 5 * time.Minute
+
+	// otpResendDelay is how long a phone number waits before another code is sent
+	otpResendDelay = time.Minute
+
+	// otpHourlyLimit is the number of codes sent to a phone number per hour, which caps
+	// the SMS cost an attacker can cause the admin
+	otpHourlyLimit = 5
+
+	// otpMaxAttempts is the number of wrong guesses after which a code stops working
+	otpMaxAttempts = 5
+
+	// defaultOTPMessage is the SMS text of admins without their own sms_message
+	defaultOTPMessage = "Your verification code: {code}"
+)
+
+// CustomerService handles customer logins, profiles and saved addresses
+type CustomerService struct {
+	customerRepo *repository.CustomerRepository
+	adminRepo    *repository.AdminRepository
+	sms          *SmsService
+}
+
+// NewCustomerService creates a new customer service
+func NewCustomerService(
+	customerRepo *repository.CustomerRepository,
+	adminRepo *repository.AdminRepository,
+	sms *SmsService,
+) *CustomerService {
+	return &CustomerService{
+		customerRepo: customerRepo,
+		adminRepo:    adminRepo,
+		sms:          sms,
+	}
+}
+
+// normalizePhone converts a phone number to the international format, a plus followed
+// by 9 to 15 digits
+func normalizePhone(phone string) (string, error) {
+	var digits strings.Builder
+	for _, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && digits.Len() == 0, r == ' ', r == '-', r == '(', r == ')':
+		default:
+			return "", utils.NewInvalidInputError("Invalid phone number")
+		}
+	}
+
+	if digits.Len() < 9 || digits.Len() > 15 {
+		return "", utils.NewInvalidInputError("Invalid phone number")
+	}
+
+	return "+" + digits.String(), nil
+}
+
+// otpMessage returns the SMS text carrying a login code. The admin's sms_message may
+// place the code with {code}, otherwise the code is appended.
+func otpMessage(template, code string) string {
+	if strings.TrimSpace(template) == "" {
+		template = defaultOTPMessage
+	}
+	if strings.Contains(template, "{code}") {
+		return strings.ReplaceAll(template, "{code}", code)
+	}
+	return template + " " + code
+}
+
+// generateOTP returns a random numeric login code
+func generateOTP() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < otpLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", otpLength, n), nil
+}
+
+// RequestCode sends a login code by SMS to a phone number, through the SMS account of the
+// admin the customer logs in to
+func (s *CustomerService) RequestCode(ctx context.Context, adminID int, req *models.CustomerOTPRequest) error {
+	phone, err := normalizePhone(req.Phone)
+	if err != nil {
+		return err
+	}
+
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin.IsAccessRestricted {
+		return utils.NewInvalidInputError("This restaurant is not available at the moment")
+	}
+	if !s.sms.IsConfigured(admin) {
+		return utils.NewInvalidInputError("This restaurant doesn't support phone login")
+	}
+
+	latest, err := s.customerRepo.GetLatestOTP(ctx, adminID, phone)
+	if err != nil && err != utils.ErrResourceNotFound {
+		return err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < otpResendDelay {
+		wait := otpResendDelay - time.Since(latest.CreatedAt)
+		return utils.NewAppError(utils.ErrForbidden,
+			fmt.Sprintf("Please wait %d seconds before requesting another code", int(wait.Seconds())+1),
+			http.StatusTooManyRequests)
+	}
+
+	sent, err := s.customerRepo.CountOTPsSince(ctx, adminID, phone, time.Now().Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent >= otpHourlyLimit {
+		return utils.NewAppError(utils.ErrForbidden, "Too many codes requested, please try again later",
+			http.StatusTooManyRequests)
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		return fmt.Errorf("failed to generate login code: %w", err)
+	}
+
+	codeHash, err := utils.HashPassword(code)
+	if err != nil {
+		return err
+	}
+
+	otp := &models.CustomerOTP{
+		AdminID:   adminID,
+		Phone:     phone,
+		CodeHash:  codeHash,
+		ExpiresAt: time.Now().Add(otpLifetime),
+	}
+	if err := s.customerRepo.CreateOTP(ctx, otp); err != nil {
+		return err
+	}
+
+	if err := s.sms.Send(ctx, admin, phone, otpMessage(admin.SmsMessage, code)); err != nil {
+		log.Printf("Failed to send login code of admin %d: %v", adminID, err)
+		return utils.NewAppError(err, "Couldn't send the code, please try again later", http.StatusServiceUnavailable)
+	}
+
+	return nil
+}
+
+// VerifyCode logs a customer in with a login code, signing them up on the first login
+func (s *CustomerService) VerifyCode(ctx context.Context, adminID int, req *models.CustomerOTPVerifyRequest) (*models.CustomerAuthResponse, error) {
+	phone, err := normalizePhone(req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	invalid := utils.NewAppError(utils.ErrInvalidCredentials, "Invalid or expired code", http.StatusUnauthorized)
+
+	otp, err := s.customerRepo.GetLatestOTP(ctx, adminID, phone)
+	if err != nil {
+		if err == utils.ErrResourceNotFound {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if otp.ConsumedAt != nil || time.Now().After(otp.ExpiresAt) || otp.Attempts >= otpMaxAttempts {
+		return nil, invalid
+	}
+
+	// Count the guess before checking it, so that parallel guesses can't exceed the limit
+	if _, err := s.customerRepo.IncrementOTPAttempts(ctx, otp.ID, otpMaxAttempts); err != nil {
+		if err == utils.ErrResourceNotFound {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	if !utils.CheckPassword(req.Code, otp.CodeHash) {
+		return nil, invalid
+	}
+
+	consumed, err := s.customerRepo.ConsumeOTP(ctx, otp.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, invalid
+	}
+
+	customer, created, err := s.customerRepo.LoginByPhone(ctx, adminID, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.Addresses, err = s.customerRepo.ListAddresses(ctx, customer.ID); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateCustomerToken(customer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CustomerAuthResponse{
+		Token:    token,
+		IsNew:    created,
+		Customer: customer.ToResponse(),
+	}, nil
+}
+
+// GetByID retrieves a customer with their saved addresses
+func (s *CustomerService) GetByID(ctx context.Context, id int) (*models.Customer, error) {
+	customer, err := s.customerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.Addresses, err = s.customerRepo.ListAddresses(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// List retrieves one page of customers
+func (s *CustomerService) List(ctx context.Context, params models.ListParams) ([]*models.Customer, *models.ListMeta, error) {
+	return s.customerRepo.List(ctx, params)
+}
+
+// UpdateProfile updates a customer's own profile and consents. Changing a marketing
+// consent records when it changed.
+func (s *CustomerService) UpdateProfile(ctx context.Context, id int, req *models.CustomerUpdateRequest) (*models.Customer, error) {
+	customer, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		customer.Name = strings.TrimSpace(*req.Name)
+	}
+
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if email != "" {
+			if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+				return nil, utils.NewInvalidInputError("Invalid email address")
+			}
+		}
+		customer.Email = email
+	}
+
+	now := time.Now()
+	if req.MarketingSMS != nil && *req.MarketingSMS != customer.MarketingSMS {
+		customer.MarketingSMS = *req.MarketingSMS
+		customer.ConsentUpdatedAt = &now
+	}
+	if req.MarketingPush != nil && *req.MarketingPush != customer.MarketingPush {
+		customer.MarketingPush = *req.MarketingPush
+		customer.ConsentUpdatedAt = &now
+	}
+
+	if req.AcceptTerms != nil {
+		if !*req.AcceptTerms {
+			return nil, utils.NewInvalidInputError("Accepted terms can't be withdrawn, delete the account instead")
+		}
+		if customer.TermsAcceptedAt == nil {
+			customer.TermsAcceptedAt = &now
+		}
+	}
+
+	if err := s.customerRepo.Update(ctx, customer); err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// ListAddresses retrieves the saved addresses of a customer
+func (s *CustomerService) ListAddresses(ctx context.Context, customerID int) ([]models.CustomerAddress, error) {
+	return s.customerRepo.ListAddresses(ctx, customerID)
+}
+
+// CreateAddress saves a new address for a customer
+func (s *CustomerService) CreateAddress(ctx context.Context, customerID int, req *models.CustomerAddressRequest) (*models.CustomerAddress, error) {
+	address := &models.CustomerAddress{CustomerID: customerID}
+	if err := applyAddressRequest(address, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.customerRepo.CreateAddress(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// UpdateAddress replaces a saved address of a customer
+func (s *CustomerService) UpdateAddress(ctx context.Context, customerID, id int, req *models.CustomerAddressRequest) (*models.CustomerAddress, error) {
+	address, err := s.ownAddress(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyAddressRequest(address, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.customerRepo.UpdateAddress(ctx, address); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// DeleteAddress deletes a saved address of a customer
+func (s *CustomerService) DeleteAddress(ctx context.Context, customerID, id int) error {
+	if _, err := s.ownAddress(ctx, customerID, id); err != nil {
+		return err
+	}
+
+	return s.customerRepo.DeleteAddress(ctx, id)
+}
+
+// ownAddress retrieves an address, reporting the addresses of other customers as not found
+func (s *CustomerService) ownAddress(ctx context.Context, customerID, id int) (*models.CustomerAddress, error) {
+	address, err := s.customerRepo.GetAddressByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if address.CustomerID != customerID {
+		return nil, utils.ErrResourceNotFound
+	}
+
+	return address, nil
+}
+
+// applyAddressRequest copies an address request onto an address
+func applyAddressRequest(address *models.CustomerAddress, req *models.CustomerAddressRequest) error {
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return utils.NewInvalidInputError("latitude and longitude must be set together")
+	}
+
+	address.Label = strings.TrimSpace(req.Label)
+	address.Address = strings.TrimSpace(req.Address)
+	address.Details = strings.TrimSpace(req.Details)
+	address.Latitude = req.Latitude
+	address.Longitude = req.Longitude
+	address.IsDefault = req.IsDefault
+
+	if address.Address == "" {
+		return utils.NewInvalidInputError("address is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// DeliveryZoneService handles delivery zones and delivery quotes
+type DeliveryZoneService struct {
+	zoneRepo   *repository.DeliveryZoneRepository
+	branchRepo *repository.BranchRepository
+	adminRepo  *repository.AdminRepository
+}
+
+// NewDeliveryZoneService creates a new delivery zone service
+func NewDeliveryZoneService(
+	zoneRepo *repository.DeliveryZoneRepository,
+	branchRepo *repository.BranchRepository,
+	adminRepo *repository.AdminRepository,
+) *DeliveryZoneService {
+	return &DeliveryZoneService{
+		zoneRepo:   zoneRepo,
+		branchRepo: branchRepo,
+		adminRepo:  adminRepo,
+	}
+}
+
+// Create creates a new delivery zone for an admin
+func (s *DeliveryZoneService) Create(ctx context.Context, adminID int, req *models.DeliveryZoneCreateRequest) (*models.DeliveryZone, error) {
+	zone := &models.DeliveryZone{
+		AdminID:        adminID,
+		BranchID:       req.BranchID,
+		Name:           req.Name,
+		Polygon:        req.Polygon,
+		MinOrderAmount: req.MinOrderAmount,
+		Currency:       utils.NormalizeCurrency(req.Currency),
+		FeeType:        req.FeeType,
+		Fee:            req.Fee,
+		FeeTiers:       req.FeeTiers,
+		EtaMinutes:     req.EtaMinutes,
+		IsActive:       req.IsActive == nil || *req.IsActive,
+		SortOrder:      req.SortOrder,
+	}
+
+	if err := s.validateZone(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	if err := s.zoneRepo.Create(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// GetByID retrieves a delivery zone by ID
+func (s *DeliveryZoneService) GetByID(ctx context.Context, id int) (*models.DeliveryZone, error) {
+	return s.zoneRepo.GetByID(ctx, id)
+}
+
+// List retrieves one page of delivery zones
+func (s *DeliveryZoneService) List(ctx context.Context, params models.ListParams) ([]*models.DeliveryZone, *models.ListMeta, error) {
+	return s.zoneRepo.List(ctx, params)
+}
+
+// ListPublic retrieves the active delivery zones of an admin for the map in the app
+func (s *DeliveryZoneService) ListPublic(ctx context.Context, adminID int) ([]*models.DeliveryZone, error) {
+	return s.zoneRepo.ListActiveByAdminID(ctx, adminID)
+}
+
+// Update updates a delivery zone
+func (s *DeliveryZoneService) Update(ctx context.Context, zone *models.DeliveryZone, req *models.DeliveryZoneUpdateRequest) (*models.DeliveryZone, error) {
+	if req.BranchID != nil {
+		if *req.BranchID == 0 {
+			zone.BranchID = nil
+		} else {
+			zone.BranchID = req.BranchID
+		}
+	}
+
+	if req.Name != "" {
+		zone.Name = req.Name
+	}
+
+	if req.Polygon != nil {
+		zone.Polygon = *req.Polygon
+	}
+
+	if req.MinOrderAmount != nil {
+		zone.MinOrderAmount = *req.MinOrderAmount
+	}
+
+	if req.Currency != "" {
+		zone.Currency = utils.NormalizeCurrency(req.Currency)
+	}
+
+	if req.FeeType != "" {
+		zone.FeeType = req.FeeType
+	}
+
+	if req.Fee != nil {
+		zone.Fee = *req.Fee
+	}
+
+	if req.FeeTiers != nil {
+		zone.FeeTiers = *req.FeeTiers
+	}
+
+	if req.EtaMinutes != nil {
+		zone.EtaMinutes = *req.EtaMinutes
+	}
+
+	if req.IsActive != nil {
+		zone.IsActive = *req.IsActive
+	}
+
+	if req.SortOrder != nil {
+		zone.SortOrder = *req.SortOrder
+	}
+
+	if err := s.validateZone(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	if err := s.zoneRepo.Update(ctx, zone); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+// Delete deletes a delivery zone
+func (s *DeliveryZoneService) Delete(ctx context.Context, id int) error {
+	return s.zoneRepo.Delete(ctx, id)
+}
+
+// validateZone checks the polygon, fee rules and branch of a delivery zone
+func (s *DeliveryZoneService) validateZone(ctx context.Context, zone *models.DeliveryZone) error {
+	if !utils.IsSupportedCurrency(zone.Currency) {
+		return utils.NewInvalidInputError("Unsupported currency " + zone.Currency)
+	}
+
+	if err := validatePolygon(zone.Polygon); err != nil {
+		return err
+	}
+
+	if zone.FeeType == models.DeliveryFeeFlat {
+		if len(zone.FeeTiers) > 0 {
+			return utils.NewInvalidInputError("fee_tiers only apply to distance and order_total fees")
+		}
+	} else {
+		if len(zone.FeeTiers) == 0 {
+			return utils.NewInvalidInputError("fee_tiers are required for " + zone.FeeType + " fees")
+		}
+		if zone.FeeTiers[0].From != 0 {
+			return utils.NewInvalidInputError("The first fee tier must start from 0")
+		}
+		for i := 1; i < len(zone.FeeTiers); i++ {
+			if zone.FeeTiers[i].From <= zone.FeeTiers[i-1].From {
+				return utils.NewInvalidInputError("fee_tiers must be sorted by from, without duplicates")
+			}
+		}
+	}
+
+	if zone.BranchID != nil {
+		branch, err := s.branchRepo.GetByID(ctx, *zone.BranchID)
+		if err != nil && err != utils.ErrResourceNotFound {
+			return err
+		}
+		if branch == nil || branch.AdminID != zone.AdminID {
+			return utils.NewInvalidInputError("Unknown branch " + strconv.Itoa(*zone.BranchID))
+		}
+		if zone.FeeType == models.DeliveryFeeDistance && branch.Latitude == nil {
+			return utils.NewInvalidInputError("Distance fees need a branch with coordinates")
+		}
+	} else if zone.FeeType == models.DeliveryFeeDistance {
+		return utils.NewInvalidInputError("Distance fees need a branch_id to measure from")
+	}
+
+	return nil
+}
+
+// validatePolygon checks that a polygon encloses an area and doesn't cross the 180th meridian
+func validatePolygon(polygon []models.GeoPoint) error {
+	if len(polygon) < 3 {
+		return utils.NewInvalidInputError("A delivery zone needs at least 3 points")
+	}
+
+	minLng, maxLng := polygon[0].Longitude, polygon[0].Longitude
+	area := 0.0
+	for i, point := range polygon {
+		next := polygon[(i+1)%len(polygon)]
+		area += point.Longitude*next.Latitude - next.Longitude*point.Latitude
+
+		if point.Longitude < minLng {
+			minLng = point.Longitude
+		}
+		if point.Longitude > maxLng {
+			maxLng = point.Longitude
+		}
+	}
+
+	if area == 0 {
+		return utils.NewInvalidInputError("The delivery zone polygon encloses no area")
+	}
+	if maxLng-minLng > 180 {
+		return utils.NewInvalidInputError("Delivery zones can't cross the 180th meridian")
+	}
+
+	return nil
+}
+
+// QuoteByAdminID tells whether an admin delivers to a point and at what cost
+func (s *DeliveryZoneService) QuoteByAdminID(ctx context.Context, adminID int, query *models.DeliveryQuoteQuery) (*models.DeliveryQuote, error) {
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	point := &models.GeoPoint{Latitude: *query.Latitude, Longitude: *query.Longitude}
+	return s.Quote(ctx, admin, point, query.Subtotal)
+}
+
+// Quote tells whether an admin delivers to a point and at what cost. The first active
+// zone containing the point applies. Admins without zones deliver anywhere for free, and
+// point may only be nil for them.
+func (s *DeliveryZoneService) Quote(ctx context.Context, admin *models.Admin, point *models.GeoPoint, subtotal int64) (*models.DeliveryQuote, error) {
+	if admin.Delivery == 0 {
+		return &models.DeliveryQuote{Reason: "This restaurant doesn't deliver"}, nil
+	}
+
+	zones, err := s.zoneRepo.ListActiveByAdminID(ctx, admin.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(zones) == 0 {
+		return &models.DeliveryQuote{Deliverable: true, MeetsMinimum: true}, nil
+	}
+
+	if point == nil {
+		return &models.DeliveryQuote{Reason: "The delivery location is required"}, nil
+	}
+
+	var zone *models.DeliveryZone
+	for _, candidate := range zones {
+		if candidate.Contains(*point) {
+			zone = candidate
+			break
+		}
+	}
+
+	if zone == nil {
+		return &models.DeliveryQuote{Reason: "This address is outside the delivery area"}, nil
+	}
+
+	quote := &models.DeliveryQuote{
+		Deliverable:    true,
+		ZoneID:         zone.ID,
+		ZoneName:       zone.Name,
+		Currency:       zone.Currency,
+		MinOrderAmount: zone.MinOrderAmount,
+		MeetsMinimum:   subtotal >= zone.MinOrderAmount,
+		EtaMinutes:     zone.EtaMinutes,
+	}
+
+	distance := 0.0
+	if zone.BranchID != nil {
+		branch, err := s.branchRepo.GetByID(ctx, *zone.BranchID)
+		if err != nil && err != utils.ErrResourceNotFound {
+			return nil, err
+		}
+		if branch != nil && branch.Latitude != nil && branch.Longitude != nil {
+			distance = models.DistanceMeters(models.GeoPoint{Latitude: *branch.Latitude, Longitude: *branch.Longitude}, *point)
+			quote.DistanceMeters = &distance
+		}
+	}
+
+	quote.Fee = zone.FeeFor(distance, subtotal)
+	return quote, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"mobilka/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EventChannel is the Postgres channel events are sent through, so that every replica
+// pushes them to its own subscribers
+const EventChannel = "mobilka_events"
+
+// maxEventPayload keeps event payloads under the 8000 byte limit of NOTIFY
+const maxEventPayload = 7900
+
+// eventBuffer is how many events a subscriber may fall behind before events are dropped
+const eventBuffer = 64
+
+// EventSubscription receives the events of one admin, or of every admin
+type EventSubscription struct {
+	Events  chan *models.Event // Closed when the subscription ends
+	adminID int                // 0 for every admin
+}
+
+// EventService is an in-process pub/sub for admin panel events. Events are published
+// through Postgres NOTIFY and delivered to subscribers by Listen, so every replica
+// receives them.
+type EventService struct {
+	db *pgxpool.Pool
+
+	mu          sync.RWMutex
+	subscribers map[*EventSubscription]struct{}
+	closed      bool
+}
+
+// NewEventService creates a new event service
+func NewEventService(db *pgxpool.Pool) *EventService {
+	return &EventService{
+		db:          db,
+		subscribers: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// Publish sends an event of an admin to every replica. Events are best effort: failures
+// are logged and never fail the change that caused them.
+func (s *EventService) Publish(ctx context.Context, eventType string, adminID int, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", eventType, err)
+		return
+	}
+
+	event := &models.Event{
+		Type:      eventType,
+		AdminID:   adminID,
+		Data:      raw,
+		CreatedAt: time.Now(),
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", eventType, err)
+		return
+	}
+	if len(payload) > maxEventPayload {
+		log.Printf("Dropped %s event of admin %d: payload of %d bytes is too large", eventType, adminID, len(payload))
+		return
+	}
+
+	if _, err := s.db.Exec(ctx, `SELECT pg_notify($1, $2)`, EventChannel, string(payload)); err != nil {
+		// Other replicas miss the event, this one still delivers it
+		log.Printf("Failed to send %s event: %v", eventType, err)
+		s.dispatch(event)
+	}
+}
+
+// Subscribe starts receiving the events of an admin, or of every admin when adminID is 0
+func (s *EventService) Subscribe(adminID int) *EventSubscription {
+	sub := &EventSubscription{
+		Events:  make(chan *models.Event, eventBuffer),
+		adminID: adminID,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		close(sub.Events)
+		return sub
+	}
+
+	s.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe stops a subscription and closes its channel
+func (s *EventService) Unsubscribe(sub *EventSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+// Close ends every subscription, letting open streams finish before shutdown
+func (s *EventService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sub := range s.subscribers {
+		delete(s.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+// dispatch hands an event to the subscribers it belongs to. Subscribers that fall too
+// far behind miss it rather than holding up the others.
+func (s *EventService) dispatch(event *models.Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for sub := range s.subscribers {
+		if sub.adminID != 0 && sub.adminID != event.AdminID {
+			continue
+		}
+
+		select {
+		case sub.Events <- event:
+		default:
+		}
+	}
+}
+
+// Listen receives the events published by every replica and dispatches them until ctx
+// is done or the connection fails
+func (s *EventService) Listen(ctx context.Context) error {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+EventChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			// The connection may still be listening, don't hand it back to the pool
+			conn.Conn().Close(context.Background())
+			return err
+		}
+
+		var event models.Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Printf("Ignored malformed event: %v", err)
+			continue
+		}
+
+		s.dispatch(&event)
+	}
+}
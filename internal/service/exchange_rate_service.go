@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// ExchangeRateService handles the manually maintained exchange rates
+type ExchangeRateService struct {
+	exchangeRateRepo *repository.ExchangeRateRepository
+}
+
+// NewExchangeRateService creates a new exchange rate service
+func NewExchangeRateService(exchangeRateRepo *repository.ExchangeRateRepository) *ExchangeRateService {
+	return &ExchangeRateService{
+		exchangeRateRepo: exchangeRateRepo,
+	}
+}
+
+// SetRate stores the rate of a currency pair for a date
+func (s *ExchangeRateService) SetRate(ctx context.Context, superAdminID int, req *models.ExchangeRateRequest) (*models.ExchangeRate, error) {
+	base := utils.NormalizeCurrency(req.BaseCurrency)
+	quote := utils.NormalizeCurrency(req.QuoteCurrency)
+
+	if !utils.IsSupportedCurrency(base) || !utils.IsSupportedCurrency(quote) {
+		return nil, utils.NewInvalidInputError("Unsupported currency")
+	}
+	if base == quote {
+		return nil, utils.NewInvalidInputError("Base and quote currency must differ")
+	}
+	if req.Rate <= 0 {
+		return nil, utils.NewInvalidInputError("Rate must be greater than zero")
+	}
+
+	effectiveDate := time.Now().Truncate(24 * time.Hour)
+	if req.EffectiveDate != "" {
+		date, err := time.Parse("2006-01-02", req.EffectiveDate)
+		if err != nil {
+			return nil, utils.NewInvalidInputError("Effective date must be in YYYY-MM-DD format")
+		}
+		effectiveDate = date
+	}
+
+	rate := &models.ExchangeRate{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          req.Rate,
+		EffectiveDate: effectiveDate,
+		CreatedBy:     &superAdminID,
+	}
+
+	if err := s.exchangeRateRepo.Upsert(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// List retrieves one page of exchange rates
+func (s *ExchangeRateService) List(ctx context.Context, params models.ListParams) ([]*models.ExchangeRate, *models.ListMeta, error) {
+	return s.exchangeRateRepo.List(ctx, params)
+}
+
+// Delete deletes an exchange rate
+func (s *ExchangeRateService) Delete(ctx context.Context, id int) error {
+	return s.exchangeRateRepo.Delete(ctx, id)
+}
+
+// Convert converts an amount in minor units between currencies
+func (s *ExchangeRateService) Convert(ctx context.Context, amount int64, from, to string, at time.Time) (int64, error) {
+	return convertAmount(ctx, s.exchangeRateRepo, amount, from, to, at)
+}
+
+// convertAmount converts an amount in minor units with the latest rate effective at the
+// given time. A missing direct rate falls back to the inverse of the opposite pair.
+func convertAmount(ctx context.Context, exchangeRateRepo *repository.ExchangeRateRepository, amount int64, from, to string, at time.Time) (int64, error) {
+	if from == to || amount == 0 {
+		return amount, nil
+	}
+
+	rate, err := exchangeRateRepo.GetLatest(ctx, from, to, at)
+	if err == nil {
+		return int64(math.Round(float64(amount) * rate.Rate)), nil
+	}
+	if err != utils.ErrResourceNotFound {
+		return 0, err
+	}
+
+	inverse, err := exchangeRateRepo.GetLatest(ctx, to, from, at)
+	if err == nil {
+		return int64(math.Round(float64(amount) / inverse.Rate)), nil
+	}
+	if err != utils.ErrResourceNotFound {
+		return 0, err
+	}
+
+	return 0, utils.NewAppError(utils.ErrResourceNotFound,
+		fmt.Sprintf("No exchange rate from %s to %s", from, to), 422)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// ExportService handles spreadsheet exports for accounting
+type ExportService struct {
+	paymentRepo *repository.PaymentHistoryRepository
+	adminRepo   *repository.AdminRepository
+	invoiceRepo *repository.InvoiceRepository
+}
+
+// NewExportService creates a new export service
+func NewExportService(paymentRepo *repository.PaymentHistoryRepository, adminRepo *repository.AdminRepository, invoiceRepo *repository.InvoiceRepository) *ExportService {
+	return &ExportService{
+		paymentRepo: paymentRepo,
+		adminRepo:   adminRepo,
+		invoiceRepo: invoiceRepo,
+	}
+}
+
+// ValidateFormat checks an export format before the response starts streaming
+func (s *ExportService) ValidateFormat(format string) error {
+	if format != utils.ExportFormatCSV && format != utils.ExportFormatXLSX {
+		return utils.NewInvalidInputError("Format must be csv or xlsx")
+	}
+	return nil
+}
+
+// ExportPayments writes the payments matching the list filters and a payment filter as a
+// CSV or XLSX table. Amounts are written in major units of the payment currency.
+func (s *ExportService) ExportPayments(ctx context.Context, w io.Writer, format string, params models.ListParams, filter models.PaymentFilter) error {
+	table, err := utils.NewTableWriter(w, format, "Payments")
+	if err != nil {
+		return err
+	}
+
+	err = table.WriteRow(
+		"ID", "Admin ID", "Company", "Payment date", "Amount", "Currency", "Prorated amount",
+		"Discount amount", "Refunded amount", "Payment method", "Transaction ID", "Tier", "Billing interval", "Months",
+		"Period start", "Period end", "Status", "Needs review", "Review reason", "Notes",
+		"Verified by", "Verified at", "Created at",
+	)
+	if err != nil {
+		return err
+	}
+
+	err = s.paymentRepo.Stream(ctx, params, filter, func(payment *models.PaymentHistory, companyName string, tierName string) error {
+		var verifiedBy interface{}
+		if payment.VerifiedBy != nil {
+			verifiedBy = *payment.VerifiedBy
+		}
+
+		return table.WriteRow(
+			payment.ID,
+			payment.AdminID,
+			companyName,
+			payment.PaymentDate,
+			utils.Number(utils.FormatAmount(payment.Amount, payment.Currency)),
+			payment.Currency,
+			utils.Number(utils.FormatAmount(payment.ProratedAmount, payment.Currency)),
+			utils.Number(utils.FormatAmount(payment.DiscountAmount, payment.Currency)),
+			utils.Number(utils.FormatAmount(payment.RefundedAmount, payment.Currency)),
+			payment.PaymentMethod,
+			payment.TransactionID,
+			tierName,
+			payment.BillingInterval,
+			payment.Months,
+			payment.PeriodStart,
+			payment.PeriodEnd,
+			payment.Status,
+			payment.NeedsReview,
+			payment.ReviewReason,
+			payment.Notes,
+			verifiedBy,
+			payment.VerifiedAt,
+			payment.CreatedAt,
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	return table.Close()
+}
+
+// ExportAdmins writes the admins matching the list filters and an admin filter as a CSV or
+// XLSX table. Credentials and tokens are never exported.
+func (s *ExportService) ExportAdmins(ctx context.Context, w io.Writer, format string, params models.ListParams, filter models.AdminFilter) error {
+	table, err := utils.NewTableWriter(w, format, "Admins")
+	if err != nil {
+		return err
+	}
+
+	err = table.WriteRow(
+		"ID", "User name", "Email", "Company", "Users", "Delivery", "Tier", "Subscription status",
+		"Subscription expires at", "Access restricted", "Billing currency", "Billing interval", "Created at",
+	)
+	if err != nil {
+		return err
+	}
+
+	err = s.adminRepo.Stream(ctx, params, filter, func(admin *models.Admin, tierName string) error {
+		return table.WriteRow(
+			admin.ID,
+			admin.UserName,
+			admin.Email,
+			admin.CompanyName,
+			admin.Users,
+			admin.Delivery,
+			tierName,
+			admin.SubscriptionStatus,
+			admin.SubscriptionExpiresAt,
+			admin.IsAccessRestricted,
+			admin.BillingCurrency,
+			admin.BillingInterval,
+			admin.CreatedAt,
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	return table.Close()
+}
+
+// ExportInvoices writes the invoices matching the list filters, of one currency when
+// currency is set, as a CSV or XLSX table. Amounts are written in major units of the
+// invoice currency.
+func (s *ExportService) ExportInvoices(ctx context.Context, w io.Writer, format string, params models.ListParams, currency string) error {
+	table, err := utils.NewTableWriter(w, format, "Invoices")
+	if err != nil {
+		return err
+	}
+
+	err = table.WriteRow(
+		"Number", "Payment ID", "Admin ID", "Company", "Issued at", "Currency", "Tier", "Billing interval",
+		"Months", "Period start", "Period end", "Top-up", "Subtotal", "Discount amount", "Prorated amount",
+		"Total", "Refunded amount", "Net amount", "Status",
+	)
+	if err != nil {
+		return err
+	}
+
+	err = s.invoiceRepo.Stream(ctx, params, currency, func(invoice *models.Invoice) error {
+		return table.WriteRow(
+			invoice.Number,
+			invoice.PaymentID,
+			invoice.AdminID,
+			invoice.CompanyName,
+			invoice.IssuedAt,
+			invoice.Currency,
+			invoice.TierName,
+			invoice.BillingInterval,
+			invoice.Months,
+			invoice.PeriodStart,
+			invoice.PeriodEnd,
+			invoice.IsTopUp,
+			utils.Number(utils.FormatAmount(invoice.Subtotal, invoice.Currency)),
+			utils.Number(utils.FormatAmount(invoice.DiscountAmount, invoice.Currency)),
+			utils.Number(utils.FormatAmount(invoice.ProratedAmount, invoice.Currency)),
+			utils.Number(utils.FormatAmount(invoice.Total, invoice.Currency)),
+			utils.Number(utils.FormatAmount(invoice.RefundedAmount, invoice.Currency)),
+			utils.Number(utils.FormatAmount(invoice.NetAmount, invoice.Currency)),
+			invoice.Status,
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	return table.Close()
+}
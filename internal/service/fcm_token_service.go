@@ -36,6 +36,26 @@ func (s *FCMTokenService) Create(ctx context.Context, adminID int, req *models.F
 	return fcmToken, nil
 }
 
+// SaveForCustomer registers the device of a logged-in customer for their push notifications
+func (s *FCMTokenService) SaveForCustomer(ctx context.Context, adminID, customerID int, req *models.FCMTokenCreateRequest) (*models.FCMToken, error) {
+	fcmToken := &models.FCMToken{
+		AdminID:    adminID,
+		CustomerID: &customerID,
+		FCMToken:   req.FCMToken,
+	}
+
+	if err := s.fcmTokenRepo.SaveForCustomer(ctx, fcmToken); err != nil {
+		return nil, err
+	}
+
+	return fcmToken, nil
+}
+
+// UnlinkCustomer stops the push notifications of a customer on a device
+func (s *FCMTokenService) UnlinkCustomer(ctx context.Context, customerID int, token string) error {
+	return s.fcmTokenRepo.UnlinkCustomer(ctx, customerID, token)
+}
+
 // GetByID retrieves an FCM token by ID
 func (s *FCMTokenService) GetByID(ctx context.Context, id int) (*models.FCMToken, error) {
 	return s.fcmTokenRepo.GetByID(ctx, id)
@@ -46,14 +66,9 @@ func (s *FCMTokenService) GetByToken(ctx context.Context, token string) (*models
 	return s.fcmTokenRepo.GetByToken(ctx, token)
 }
 
-// GetByAdminID retrieves all FCM tokens for a specific admin
-func (s *FCMTokenService) GetByAdminID(ctx context.Context, adminID int) ([]*models.FCMToken, error) {
-	return s.fcmTokenRepo.GetByAdminID(ctx, adminID)
-}
-
-// GetAll retrieves all FCM tokens
-func (s *FCMTokenService) GetAll(ctx context.Context) ([]*models.FCMToken, error) {
-	return s.fcmTokenRepo.GetAll(ctx)
+// List retrieves one page of FCM tokens
+func (s *FCMTokenService) List(ctx context.Context, params models.ListParams) ([]*models.FCMToken, *models.ListMeta, error) {
+	return s.fcmTokenRepo.List(ctx, params)
 }
 
 // Delete deletes an FCM token
@@ -83,3 +83,13 @@ func (s *ImageService) DeleteImage(filename string) error {
 func (s *ImageService) GetImagePath(filename string) string {
 	return filepath.Join(s.uploadPath, filename)
 }
+
+// Exists reports whether an image with the filename returned by SaveImage exists
+func (s *ImageService) Exists(filename string) bool {
+	if filename == "" || filename != filepath.Base(filename) {
+		return false
+	}
+
+	info, err := os.Stat(filepath.Join(s.uploadPath, filename))
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+)
+
+// InvoiceService handles the invoices of verified payments
+type InvoiceService struct {
+	invoiceRepo *repository.InvoiceRepository
+}
+
+// NewInvoiceService creates a new invoice service
+func NewInvoiceService(invoiceRepo *repository.InvoiceRepository) *InvoiceService {
+	return &InvoiceService{
+		invoiceRepo: invoiceRepo,
+	}
+}
+
+// GetByID retrieves an invoice by ID
+func (s *InvoiceService) GetByID(ctx context.Context, id int) (*models.Invoice, error) {
+	return s.invoiceRepo.GetByID(ctx, id)
+}
+
+// List retrieves one page of invoices, of one currency when currency is set
+func (s *InvoiceService) List(ctx context.Context, params models.ListParams, currency string) ([]*models.Invoice, *models.ListMeta, error) {
+	return s.invoiceRepo.List(ctx, params, currency)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// LedgerService handles admin balances and statements of the billing ledger
+type LedgerService struct {
+	ledgerRepo *repository.LedgerRepository
+	adminRepo  *repository.AdminRepository
+}
+
+// NewLedgerService creates a new ledger service
+func NewLedgerService(ledgerRepo *repository.LedgerRepository, adminRepo *repository.AdminRepository) *LedgerService {
+	return &LedgerService{
+		ledgerRepo: ledgerRepo,
+		adminRepo:  adminRepo,
+	}
+}
+
+// GetStatement retrieves an admin's statement in a currency, by default the billing currency
+func (s *LedgerService) GetStatement(ctx context.Context, adminID int, currency string, from, to *time.Time) (*models.LedgerStatement, error) {
+	currency, err := s.ledgerCurrency(ctx, adminID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, utils.NewInvalidInputError("to must be after from")
+	}
+
+	return s.ledgerRepo.GetStatement(ctx, adminID, currency, from, to)
+}
+
+// GetBalances retrieves an admin's prepaid balance in every currency, always including
+// the billing currency
+func (s *LedgerService) GetBalances(ctx context.Context, adminID int) (map[string]int64, error) {
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.ledgerRepo.GetBalances(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := balances[admin.BillingCurrency]; !ok {
+		balances[admin.BillingCurrency] = 0
+	}
+
+	return balances, nil
+}
+
+// Credit grants balance to an admin, e.g. as goodwill or compensation
+func (s *LedgerService) Credit(ctx context.Context, adminID int, superAdminID int, req *models.LedgerCreditRequest) (*models.LedgerTransaction, error) {
+	if req.Amount <= 0 {
+		return nil, utils.NewInvalidInputError("Amount must be greater than zero")
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, utils.NewInvalidInputError("Description is required")
+	}
+
+	currency, err := s.ledgerCurrency(ctx, adminID, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	txn := models.NewLedgerTransaction(adminID, models.LedgerTypeCredit, currency, req.Amount,
+		models.LedgerAccountCredits, models.LedgerAccountAdminBalance, description)
+	txn.CreatedBy = &superAdminID
+
+	if err := s.ledgerRepo.Post(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	return txn, nil
+}
+
+// Adjust corrects an admin's balance by a signed amount. Lowering the balance below zero is refused.
+func (s *LedgerService) Adjust(ctx context.Context, adminID int, superAdminID int, req *models.LedgerAdjustmentRequest) (*models.LedgerTransaction, error) {
+	if req.Amount == 0 {
+		return nil, utils.NewInvalidInputError("Amount must not be zero")
+	}
+
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, utils.NewInvalidInputError("Description is required")
+	}
+
+	currency, err := s.ledgerCurrency(ctx, adminID, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var txn *models.LedgerTransaction
+	if req.Amount > 0 {
+		txn = models.NewLedgerTransaction(adminID, models.LedgerTypeAdjustment, currency, req.Amount,
+			models.LedgerAccountAdjustments, models.LedgerAccountAdminBalance, description)
+	} else {
+		txn = models.NewLedgerTransaction(adminID, models.LedgerTypeAdjustment, currency, -req.Amount,
+			models.LedgerAccountAdminBalance, models.LedgerAccountAdjustments, description)
+	}
+	txn.CreatedBy = &superAdminID
+
+	if err := s.ledgerRepo.PostFromBalance(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	return txn, nil
+}
+
+// ledgerCurrency validates a requested currency, defaulting to the admin's billing currency
+func (s *LedgerService) ledgerCurrency(ctx context.Context, adminID int, currency string) (string, error) {
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return "", err
+	}
+
+	if currency == "" {
+		return admin.BillingCurrency, nil
+	}
+
+	currency = utils.NormalizeCurrency(currency)
+	if !utils.IsSupportedCurrency(currency) {
+		return "", utils.NewInvalidInputError("Unsupported currency " + currency)
+	}
+
+	return currency, nil
+}
+
+// paymentPostings returns the ledger transactions of a verified payment: the money received
+// goes to the admin's balance, and unless the payment is a top-up it is spent on the
+// subscription period right away
+func paymentPostings(payment *models.PaymentHistory) []*models.LedgerTransaction {
+	if payment.Amount <= 0 {
+		return nil
+	}
+
+	postings := []*models.LedgerTransaction{
+		models.NewLedgerTransaction(payment.AdminID, models.LedgerTypePayment, payment.Currency, payment.Amount,
+			models.LedgerAccountCash, models.LedgerAccountAdminBalance, fmt.Sprintf("Payment #%d", payment.ID)),
+	}
+
+	if !payment.IsTopUp {
+		postings = append(postings, models.NewLedgerTransaction(payment.AdminID, models.LedgerTypeCharge, payment.Currency, payment.Amount,
+			models.LedgerAccountAdminBalance, models.LedgerAccountRevenue, fmt.Sprintf("Subscription paid by payment #%d", payment.ID)))
+	}
+
+	return postings
+}
+
+// refundPostings returns the ledger transactions of a refund. The refunded share of a
+// subscription charge is credited back to the balance first, then paid out of it, except
+// for payments made from the balance, whose refund stays on the balance.
+func refundPostings(payment *models.PaymentHistory, refund *models.PaymentRefund) []*models.LedgerTransaction {
+	var postings []*models.LedgerTransaction
+
+	if !payment.IsTopUp {
+		postings = append(postings, models.NewLedgerTransaction(payment.AdminID, models.LedgerTypeCredit, refund.Currency, refund.Amount,
+			models.LedgerAccountRevenue, models.LedgerAccountAdminBalance, fmt.Sprintf("Subscription refunded for payment #%d", payment.ID)))
+	}
+
+	if payment.PaymentMethod != models.PaymentMethodBalance {
+		postings = append(postings, models.NewLedgerTransaction(payment.AdminID, models.LedgerTypeRefund, refund.Currency, refund.Amount,
+			models.LedgerAccountAdminBalance, models.LedgerAccountCash, refund.Reason))
+	}
+
+	for _, posting := range postings {
+		posting.CreatedBy = refund.CreatedBy
+	}
+
+	return postings
+}
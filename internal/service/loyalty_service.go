@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+const (
+	// defaultExpiryWindow is how far ahead a balance shows expiring points when the
+	// program has no notice period
+	defaultExpiryWindow = 30 * 24 * time.Hour
+
+	// defaultPointValue is the value of a point in a new program: one major currency unit
+	defaultPointValue = 100
+)
+
+// LoyaltyService handles loyalty programs, the points customers earn and spend on orders,
+// and their expiry
+type LoyaltyService struct {
+	loyaltyRepo *repository.LoyaltyRepository
+	push        *PushService
+}
+
+// NewLoyaltyService creates a new loyalty service
+func NewLoyaltyService(loyaltyRepo *repository.LoyaltyRepository, push *PushService) *LoyaltyService {
+	return &LoyaltyService{
+		loyaltyRepo: loyaltyRepo,
+		push:        push,
+	}
+}
+
+// GetProgram retrieves the loyalty program of an admin, or an inactive default program
+// when the admin hasn't set one up yet
+func (s *LoyaltyService) GetProgram(ctx context.Context, adminID int) (*models.LoyaltyProgram, error) {
+	program, err := s.loyaltyRepo.GetProgram(ctx, adminID)
+	if err == utils.ErrResourceNotFound {
+		return &models.LoyaltyProgram{
+			AdminID:          adminID,
+			Currency:         utils.DefaultCurrency,
+			PointValue:       defaultPointValue,
+			RedeemMaxPercent: 100,
+		}, nil
+	}
+
+	return program, err
+}
+
+// activeProgram retrieves the loyalty program of an admin, or nil when it isn't running
+func (s *LoyaltyService) activeProgram(ctx context.Context, adminID int) (*models.LoyaltyProgram, error) {
+	program, err := s.loyaltyRepo.GetProgram(ctx, adminID)
+	if err == utils.ErrResourceNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !program.IsActive {
+		return nil, nil
+	}
+
+	return program, nil
+}
+
+// SaveProgram creates or replaces the loyalty program of an admin
+func (s *LoyaltyService) SaveProgram(ctx context.Context, adminID int, req *models.LoyaltyProgramRequest) (*models.LoyaltyProgram, error) {
+	program := &models.LoyaltyProgram{
+		AdminID:          adminID,
+		IsActive:         req.IsActive,
+		Currency:         utils.NormalizeCurrency(req.Currency),
+		EarnBasisPoints:  req.EarnBasisPoints,
+		VisitPoints:      req.VisitPoints,
+		MinOrderAmount:   req.MinOrderAmount,
+		PointValue:       req.PointValue,
+		RedeemMaxPercent: req.RedeemMaxPercent,
+		RedeemMaxPoints:  req.RedeemMaxPoints,
+		MinRedeemPoints:  req.MinRedeemPoints,
+		ExpiryDays:       req.ExpiryDays,
+		ExpiryNoticeDays: req.ExpiryNoticeDays,
+	}
+
+	if !utils.IsSupportedCurrency(program.Currency) {
+		return nil, utils.NewInvalidInputError("Unsupported currency " + program.Currency)
+	}
+	if program.ExpiryNoticeDays > 0 && program.ExpiryDays == 0 {
+		return nil, utils.NewInvalidInputError("expiry_notice_days needs points that expire")
+	}
+	if program.ExpiryNoticeDays >= program.ExpiryDays && program.ExpiryDays > 0 {
+		return nil, utils.NewInvalidInputError("expiry_notice_days must be shorter than expiry_days")
+	}
+	if program.RedeemMaxPoints > 0 && program.MinRedeemPoints > program.RedeemMaxPoints {
+		return nil, utils.NewInvalidInputError("min_redeem_points can't exceed redeem_max_points")
+	}
+
+	if err := s.loyaltyRepo.SaveProgram(ctx, program); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// GetBalance returns the points balance of a customer of an admin
+func (s *LoyaltyService) GetBalance(ctx context.Context, adminID, customerID int) (*models.LoyaltyBalance, error) {
+	program, err := s.activeProgram(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := defaultExpiryWindow
+	if program != nil && program.ExpiryNoticeDays > 0 {
+		window = time.Duration(program.ExpiryNoticeDays) * 24 * time.Hour
+	}
+
+	points, expiring, nextExpiry, err := s.loyaltyRepo.GetBalance(ctx, customerID, time.Now().Add(window))
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &models.LoyaltyBalance{
+		CustomerID: customerID,
+		Points:     points,
+		Program:    program,
+	}
+	if program != nil {
+		balance.Value = points * program.PointValue
+		balance.Currency = program.Currency
+	}
+	if expiring > 0 && nextExpiry != nil {
+		balance.Expiring = &models.LoyaltyExpiry{Points: expiring, ExpiresAt: *nextExpiry}
+	}
+
+	return balance, nil
+}
+
+// ListTransactions retrieves one page of the points ledger, of one customer when
+// customerID is set
+func (s *LoyaltyService) ListTransactions(ctx context.Context, params models.ListParams, customerID *int) ([]*models.LoyaltyTransaction, *models.ListMeta, error) {
+	return s.loyaltyRepo.ListTransactions(ctx, params, customerID)
+}
+
+// RedeemDiscount checks that a customer may spend points on an order with subtotal under
+// the burn rules, and returns the discount they are worth. The points are spent when the
+// order is stored.
+func (s *LoyaltyService) RedeemDiscount(ctx context.Context, adminID, customerID int, points, subtotal int64, currency string) (int64, error) {
+	program, err := s.activeProgram(ctx, adminID)
+	if err != nil {
+		return 0, err
+	}
+	if program == nil {
+		return 0, utils.NewInvalidInputError("This restaurant has no loyalty program")
+	}
+	if program.Currency != currency {
+		return 0, utils.NewInvalidInputError("Loyalty points can only be spent on orders in " + program.Currency)
+	}
+	if points < program.MinRedeemPoints {
+		return 0, utils.NewInvalidInputError(fmt.Sprintf("At least %d points must be spent at once", program.MinRedeemPoints))
+	}
+
+	balance, _, _, err := s.loyaltyRepo.GetBalance(ctx, customerID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if points > balance {
+		return 0, utils.NewConflictError(utils.CodeInsufficientBalance, fmt.Sprintf("Not enough loyalty points: %d available", balance))
+	}
+
+	if max := program.MaxRedeemable(subtotal, balance); points > max {
+		return 0, utils.NewInvalidInputError(fmt.Sprintf("At most %d points can be spent on this order", max))
+	}
+
+	return points * program.PointValue, nil
+}
+
+// AwardOrder credits the points a completed order of a customer earns under the earn rules,
+// and tells the customer by push notification. Orders earn once.
+func (s *LoyaltyService) AwardOrder(ctx context.Context, order *models.Order) error {
+	if order.CustomerID == nil || order.Status != models.OrderStatusCompleted {
+		return nil
+	}
+
+	program, err := s.activeProgram(ctx, order.AdminID)
+	if err != nil || program == nil {
+		return err
+	}
+	if program.Currency != order.Currency {
+		return nil
+	}
+
+	// Cashback is earned on what the customer paid for the food, not on delivery
+	points := program.EarnedPoints(order.Total - order.DeliveryFee)
+	if points <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	txn := &models.LoyaltyTransaction{
+		AdminID:     order.AdminID,
+		CustomerID:  *order.CustomerID,
+		OrderID:     &order.ID,
+		Type:        models.LoyaltyEarn,
+		Points:      points,
+		Remaining:   points,
+		ExpiresAt:   program.ExpiresAt(now),
+		Description: fmt.Sprintf("Earned on order #%d", order.ID),
+	}
+
+	created, err := s.loyaltyRepo.Earn(ctx, txn)
+	if err != nil || !created {
+		return err
+	}
+
+	// The status change doesn't wait for FCM
+	go s.notifyEarned(txn)
+
+	return nil
+}
+
+// notifyEarned sends the push notification about points earned on an order
+func (s *LoyaltyService) notifyEarned(txn *models.LoyaltyTransaction) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	body := fmt.Sprintf("Thanks for your order #%d!", *txn.OrderID)
+	if points, _, _, err := s.loyaltyRepo.GetBalance(ctx, txn.CustomerID, time.Now()); err == nil {
+		body += fmt.Sprintf(" Your balance is now %d points.", points)
+	}
+
+	err := s.push.SendToCustomer(ctx, txn.CustomerID, fmt.Sprintf("You earned %d points", txn.Points), body, map[string]string{
+		"type":     "loyalty.earned",
+		"order_id": strconv.Itoa(*txn.OrderID),
+		"points":   strconv.FormatInt(txn.Points, 10),
+	})
+	if err != nil {
+		log.Printf("Failed to notify customer %d of earned points: %v", txn.CustomerID, err)
+	}
+}
+
+// ExpirePoints writes off every expired point and returns how many were written off
+func (s *LoyaltyService) ExpirePoints(ctx context.Context) (int64, error) {
+	return s.loyaltyRepo.ExpirePoints(ctx)
+}
+
+// NotifyExpiring reminds customers by push notification of points expiring within their
+// program's notice period. Each earned lot is reminded of once.
+func (s *LoyaltyService) NotifyExpiring(ctx context.Context) error {
+	notices, err := s.loyaltyRepo.ListExpiryNotices(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, notice := range notices {
+		err := s.push.SendToCustomer(ctx, notice.CustomerID, "Your points expire soon",
+			fmt.Sprintf("%d points expire on %s. Spend them on your next order!", notice.Points, notice.ExpiresAt.Format("2 Jan 2006")),
+			map[string]string{
+				"type":       "loyalty.expiring",
+				"points":     strconv.FormatInt(notice.Points, 10),
+				"expires_at": notice.ExpiresAt.Format(time.RFC3339),
+			})
+		if err != nil {
+			log.Printf("Failed to remind customer %d of expiring points: %v", notice.CustomerID, err)
+			continue
+		}
+
+		if err := s.loyaltyRepo.MarkExpiryNotified(ctx, notice.LotIDs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"mobilka/internal/models"
 	"mobilka/internal/repository"
@@ -12,16 +13,19 @@ import (
 type NotificationService struct {
 	notificationRepo *repository.NotificationRepository
 	fcmTokenRepo     *repository.FCMTokenRepository
+	events           *EventService
 }
 
 // NewNotificationService creates a new notification service
 func NewNotificationService(
 	notificationRepo *repository.NotificationRepository,
 	fcmTokenRepo *repository.FCMTokenRepository,
+	events *EventService,
 ) *NotificationService {
 	return &NotificationService{
 		notificationRepo: notificationRepo,
 		fcmTokenRepo:     fcmTokenRepo,
+		events:           events,
 	}
 }
 
@@ -39,6 +43,7 @@ func (s *NotificationService) Create(ctx context.Context, adminID int, req *mode
 		return nil, err
 	}
 
+	s.publishStats(ctx, notification)
 	return notification, nil
 }
 
@@ -47,14 +52,9 @@ func (s *NotificationService) GetByID(ctx context.Context, id int) (*models.Noti
 	return s.notificationRepo.GetByID(ctx, id)
 }
 
-// GetByAdminID retrieves all notifications for a specific admin
-func (s *NotificationService) GetByAdminID(ctx context.Context, adminID int) ([]*models.Notification, error) {
-	return s.notificationRepo.GetByAdminID(ctx, adminID)
-}
-
-// GetAll retrieves all notifications
-func (s *NotificationService) GetAll(ctx context.Context) ([]*models.Notification, error) {
-	return s.notificationRepo.GetAll(ctx)
+// List retrieves one page of notifications
+func (s *NotificationService) List(ctx context.Context, params models.ListParams) ([]*models.Notification, *models.ListMeta, error) {
+	return s.notificationRepo.List(ctx, params)
 }
 
 // Update updates a notification
@@ -98,7 +98,17 @@ func (s *NotificationService) Delete(ctx context.Context, id int, adminID int) e
 	return s.notificationRepo.Delete(ctx, id, adminID)
 }
 
-// GetByAdminIDWithPagination retrieves notifications for a specific admin with pagination
-func (s *NotificationService) GetByAdminIDWithPagination(ctx context.Context, adminID, skip, step int) ([]*models.Notification, error) {
-	return s.notificationRepo.GetByAdminIDWithPagination(ctx, adminID, skip, step)
+// publishStats pushes the number of devices a new notification reaches to the admin panel
+func (s *NotificationService) publishStats(ctx context.Context, notification *models.Notification) {
+	tokens, err := s.fcmTokenRepo.GetByAdminID(ctx, notification.AdminID)
+	if err != nil {
+		log.Printf("Failed to count devices for notification %d: %v", notification.ID, err)
+		return
+	}
+
+	s.events.Publish(ctx, models.EventNotificationStats, notification.AdminID, models.NotificationStatsEventData{
+		NotificationID: notification.ID,
+		Title:          notification.Title,
+		Devices:        len(tokens),
+	})
 }
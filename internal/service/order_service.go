@@ -0,0 +1,564 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// OrderService handles orders placed against the catalog and their status changes
+type OrderService struct {
+	orderRepo   *repository.OrderRepository
+	catalogRepo *repository.CatalogRepository
+	branchRepo  *repository.BranchRepository
+	adminRepo   *repository.AdminRepository
+	zones       *DeliveryZoneService
+	loyalty     *LoyaltyService
+	events      *EventService
+	telegram    *TelegramService
+	publicURL   string // Base URL Telegram sends button presses to
+}
+
+// NewOrderService creates a new order service
+func NewOrderService(
+	orderRepo *repository.OrderRepository,
+	catalogRepo *repository.CatalogRepository,
+	branchRepo *repository.BranchRepository,
+	adminRepo *repository.AdminRepository,
+	zones *DeliveryZoneService,
+	loyalty *LoyaltyService,
+	events *EventService,
+	telegram *TelegramService,
+	publicURL string,
+) *OrderService {
+	return &OrderService{
+		orderRepo:   orderRepo,
+		catalogRepo: catalogRepo,
+		branchRepo:  branchRepo,
+		adminRepo:   adminRepo,
+		zones:       zones,
+		loyalty:     loyalty,
+		events:      events,
+		telegram:    telegram,
+		publicURL:   publicURL,
+	}
+}
+
+// Create places an order with an admin. Products and modifier options are checked
+// against the catalog and their names and prices copied onto the order. Orders of a
+// logged-in customer carry customerID and may spend loyalty points as a discount.
+func (s *OrderService) Create(ctx context.Context, adminID int, customerID *int, req *models.OrderCreateRequest) (*models.Order, error) {
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if admin.IsAccessRestricted {
+		return nil, utils.NewInvalidInputError("This restaurant doesn't accept orders at the moment")
+	}
+
+	if req.Type == models.OrderTypeDelivery {
+		if strings.TrimSpace(req.DeliveryAddress) == "" {
+			return nil, utils.NewInvalidInputError("delivery_address is required for delivery orders")
+		}
+	}
+	if req.RedeemPoints > 0 && customerID == nil {
+		return nil, utils.NewInvalidInputError("Log in to spend loyalty points")
+	}
+	if (req.DeliveryLatitude == nil) != (req.DeliveryLongitude == nil) {
+		return nil, utils.NewInvalidInputError("delivery_latitude and delivery_longitude must be set together")
+	}
+
+	if req.BranchID != nil {
+		branch, err := s.branchRepo.GetByID(ctx, *req.BranchID)
+		if err != nil && err != utils.ErrResourceNotFound {
+			return nil, err
+		}
+		if branch == nil || branch.AdminID != adminID || branch.Status != models.BranchStatusActive {
+			return nil, utils.NewInvalidInputError("Unknown or closed branch " + strconv.Itoa(*req.BranchID))
+		}
+	}
+
+	items, currency, err := s.buildItems(ctx, adminID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &models.Order{
+		AdminID:           adminID,
+		BranchID:          req.BranchID,
+		CustomerID:        customerID,
+		TrackingToken:     uuid.New().String(),
+		Type:              req.Type,
+		Status:            models.OrderStatusNew,
+		CustomerName:      req.CustomerName,
+		CustomerPhone:     req.CustomerPhone,
+		DeliveryAddress:   req.DeliveryAddress,
+		DeliveryLatitude:  req.DeliveryLatitude,
+		DeliveryLongitude: req.DeliveryLongitude,
+		Notes:             req.Notes,
+		Currency:          currency,
+		Items:             items,
+		History: []models.OrderStatusHistory{{
+			ToStatus: models.OrderStatusNew,
+			Actor:    models.OrderActorCustomer,
+		}},
+	}
+
+	for _, item := range items {
+		order.Subtotal += item.Total
+	}
+
+	if req.RedeemPoints > 0 {
+		discount, err := s.loyalty.RedeemDiscount(ctx, adminID, *customerID, req.RedeemPoints, order.Subtotal, currency)
+		if err != nil {
+			return nil, err
+		}
+		order.Discount += discount
+		order.PointsRedeemed = req.RedeemPoints
+	}
+
+	if order.Type == models.OrderTypeDelivery {
+		if err := s.applyDeliveryZone(ctx, admin, order); err != nil {
+			return nil, err
+		}
+	}
+
+	order.Total = order.Subtotal + order.DeliveryFee - order.Discount
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, models.EventOrderCreated, order.AdminID, orderEventData(order, ""))
+
+	// The customer doesn't wait for Telegram
+	go s.sendTelegramAlert(admin, order)
+
+	return order, nil
+}
+
+// applyDeliveryZone checks that the admin delivers to the order location under the rules
+// of its delivery zone, and charges the zone's delivery fee
+func (s *OrderService) applyDeliveryZone(ctx context.Context, admin *models.Admin, order *models.Order) error {
+	var point *models.GeoPoint
+	if order.DeliveryLatitude != nil && order.DeliveryLongitude != nil {
+		point = &models.GeoPoint{Latitude: *order.DeliveryLatitude, Longitude: *order.DeliveryLongitude}
+	}
+
+	quote, err := s.zones.Quote(ctx, admin, point, order.Subtotal)
+	if err != nil {
+		return err
+	}
+
+	if !quote.Deliverable {
+		return utils.NewInvalidInputError(quote.Reason)
+	}
+	if quote.Currency != "" && quote.Currency != order.Currency {
+		return utils.NewInvalidInputError("Delivery to this address is priced in " + quote.Currency + ", the order is in " + order.Currency)
+	}
+	if !quote.MeetsMinimum {
+		return utils.NewInvalidInputError("The minimum order for delivery to this address is " + utils.FormatMoney(quote.MinOrderAmount, quote.Currency))
+	}
+
+	order.DeliveryFee = quote.Fee
+	return nil
+}
+
+// buildItems turns the requested items into order items priced from the catalog and
+// returns them with their currency. All products of an order must share one currency.
+func (s *OrderService) buildItems(ctx context.Context, adminID int, reqs []models.OrderItemRequest) ([]models.OrderItem, string, error) {
+	productIDs := make([]int, 0, len(reqs))
+	for _, req := range reqs {
+		productIDs = append(productIDs, req.ProductID)
+	}
+
+	products, err := s.catalogRepo.GetProductsByIDs(ctx, adminID, productIDs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	groupIDs := []int{}
+	for _, product := range products {
+		groupIDs = append(groupIDs, product.ModifierGroupIDs...)
+	}
+
+	groups, err := s.catalogRepo.GetModifierGroupsByIDs(ctx, adminID, groupIDs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	currency := ""
+	items := make([]models.OrderItem, 0, len(reqs))
+	for _, req := range reqs {
+		product, ok := products[req.ProductID]
+		if !ok || !product.IsActive {
+			return nil, "", utils.NewInvalidInputError("Unknown product " + strconv.Itoa(req.ProductID))
+		}
+		if !product.IsAvailable {
+			return nil, "", utils.NewInvalidInputError(product.Name + " is not available")
+		}
+
+		if currency == "" {
+			currency = product.Currency
+		} else if product.Currency != currency {
+			return nil, "", utils.NewInvalidInputError("All products of an order must have the same currency")
+		}
+
+		modifiers, err := selectModifiers(product, groups, req.Modifiers)
+		if err != nil {
+			return nil, "", err
+		}
+
+		productID := product.ID
+		item := models.OrderItem{
+			ProductID: &productID,
+			Name:      product.Name,
+			UnitPrice: product.Price,
+			Quantity:  req.Quantity,
+			Notes:     req.Notes,
+			Modifiers: modifiers,
+		}
+		for _, modifier := range modifiers {
+			item.UnitPrice += modifier.Price
+		}
+		item.Total = item.UnitPrice * int64(item.Quantity)
+
+		items = append(items, item)
+	}
+
+	return items, currency, nil
+}
+
+// selectModifiers checks the options selected for a product against its modifier groups
+// and returns them as order item modifiers
+func selectModifiers(product *models.CatalogProduct, groups map[int]*models.ModifierGroup, reqs []models.OrderModifierRequest) ([]models.OrderItemModifier, error) {
+	type choice struct {
+		group  *models.ModifierGroup
+		option models.ModifierOption
+	}
+
+	options := make(map[int]choice)
+	for _, groupID := range product.ModifierGroupIDs {
+		group, ok := groups[groupID]
+		if !ok {
+			continue
+		}
+		for _, option := range group.Options {
+			options[option.ID] = choice{group: group, option: option}
+		}
+	}
+
+	modifiers := make([]models.OrderItemModifier, 0, len(reqs))
+	selected := make(map[int]int)
+	seen := make(map[int]bool)
+	for _, req := range reqs {
+		choice, ok := options[req.OptionID]
+		if !ok {
+			return nil, utils.NewInvalidInputError("Option " + strconv.Itoa(req.OptionID) + " doesn't belong to " + product.Name)
+		}
+		if !choice.option.IsAvailable {
+			return nil, utils.NewInvalidInputError(choice.option.Name + " is not available")
+		}
+		if seen[req.OptionID] {
+			return nil, utils.NewInvalidInputError(choice.option.Name + " is selected more than once")
+		}
+		seen[req.OptionID] = true
+		selected[choice.group.ID]++
+
+		optionID := choice.option.ID
+		modifiers = append(modifiers, models.OrderItemModifier{
+			OptionID:   &optionID,
+			GroupName:  choice.group.Name,
+			OptionName: choice.option.Name,
+			Price:      choice.option.Price,
+		})
+	}
+
+	for _, groupID := range product.ModifierGroupIDs {
+		group, ok := groups[groupID]
+		if !ok {
+			continue
+		}
+		count := selected[group.ID]
+		if count < group.MinSelect {
+			return nil, utils.NewInvalidInputError(product.Name + ": select at least " + strconv.Itoa(group.MinSelect) + " of " + group.Name)
+		}
+		if count > group.MaxSelect {
+			return nil, utils.NewInvalidInputError(product.Name + ": select at most " + strconv.Itoa(group.MaxSelect) + " of " + group.Name)
+		}
+	}
+
+	return modifiers, nil
+}
+
+// GetByID retrieves an order by ID
+func (s *OrderService) GetByID(ctx context.Context, id int) (*models.Order, error) {
+	return s.orderRepo.GetByID(ctx, id)
+}
+
+// GetByTrackingToken retrieves an order by its tracking token
+func (s *OrderService) GetByTrackingToken(ctx context.Context, token string) (*models.Order, error) {
+	return s.orderRepo.GetByTrackingToken(ctx, token)
+}
+
+// List retrieves one page of orders
+func (s *OrderService) List(ctx context.Context, params models.ListParams) ([]*models.Order, *models.ListMeta, error) {
+	return s.orderRepo.List(ctx, params)
+}
+
+// ChangeStatus moves an order to a status allowed by the order state machine
+func (s *OrderService) ChangeStatus(ctx context.Context, order *models.Order, status, actor string, actorID *int, note string) (*models.Order, error) {
+	if !order.CanTransition(status) {
+		return nil, utils.NewConflictError(utils.CodeConflict, "An order can't move from "+order.Status+" to "+status)
+	}
+
+	entry := &models.OrderStatusHistory{
+		FromStatus: order.Status,
+		ToStatus:   status,
+		Actor:      actor,
+		ActorID:    actorID,
+		Note:       note,
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, order, entry); err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(ctx, models.EventOrderStatusChanged, order.AdminID, orderEventData(order, entry.FromStatus))
+
+	// The status change stands even if crediting the points fails
+	if status == models.OrderStatusCompleted && order.CustomerID != nil {
+		if err := s.loyalty.AwardOrder(ctx, order); err != nil {
+			log.Printf("Failed to award loyalty points for order %d: %v", order.ID, err)
+		}
+	}
+
+	return order, nil
+}
+
+// CustomerCancel cancels an order for the customer. Customers can only cancel orders the
+// restaurant hasn't accepted yet.
+func (s *OrderService) CustomerCancel(ctx context.Context, order *models.Order, reason string) (*models.Order, error) {
+	if order.Status != models.OrderStatusNew {
+		return nil, utils.NewConflictError(utils.CodeConflict, "The order was already accepted, contact the restaurant to cancel it")
+	}
+
+	return s.ChangeStatus(ctx, order, models.OrderStatusCancelled, models.OrderActorCustomer, nil, reason)
+}
+
+// orderEventData summarizes an order for the admin panel events
+func orderEventData(order *models.Order, fromStatus string) models.OrderEventData {
+	return models.OrderEventData{
+		OrderID:      order.ID,
+		Type:         order.Type,
+		Status:       order.Status,
+		FromStatus:   fromStatus,
+		CustomerName: order.CustomerName,
+		Total:        order.Total,
+		Currency:     order.Currency,
+	}
+}
+
+// Actions of the inline buttons under Telegram order alerts
+const (
+	telegramActionAccept = "accept"
+	telegramActionReject = "reject"
+)
+
+// sendTelegramAlert posts a new order to the admin's Telegram chat with buttons to accept
+// or reject it
+func (s *OrderService) sendTelegramAlert(admin *models.Admin, order *models.Order) {
+	if admin.BotToken == "" || admin.BotChatID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	id := strconv.Itoa(order.ID)
+	buttons := [][]models.TelegramInlineButton{{
+		{Text: "Accept", CallbackData: "order:" + telegramActionAccept + ":" + id},
+		{Text: "Reject", CallbackData: "order:" + telegramActionReject + ":" + id},
+	}}
+
+	if err := s.telegram.SendMessageWithButtons(ctx, admin.BotToken, admin.BotChatID, telegramOrderText(order), buttons); err != nil {
+		log.Printf("Failed to send order %d to the Telegram chat of admin %d: %v", order.ID, admin.ID, err)
+	}
+}
+
+// telegramOrderText formats an order for Telegram
+func telegramOrderText(order *models.Order) string {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "New %s order #%d\n\n", order.Type, order.ID)
+
+	for _, item := range order.Items {
+		fmt.Fprintf(&text, "%d x %s", item.Quantity, item.Name)
+		if len(item.Modifiers) > 0 {
+			names := make([]string, 0, len(item.Modifiers))
+			for _, modifier := range item.Modifiers {
+				names = append(names, modifier.OptionName)
+			}
+			fmt.Fprintf(&text, " (%s)", strings.Join(names, ", "))
+		}
+		fmt.Fprintf(&text, " - %s\n", utils.FormatMoney(item.Total, order.Currency))
+		if item.Notes != "" {
+			fmt.Fprintf(&text, "   Note: %s\n", item.Notes)
+		}
+	}
+
+	if order.DeliveryFee > 0 {
+		fmt.Fprintf(&text, "\nDelivery: %s", utils.FormatMoney(order.DeliveryFee, order.Currency))
+	}
+	if order.Discount > 0 {
+		fmt.Fprintf(&text, "\nDiscount: -%s", utils.FormatMoney(order.Discount, order.Currency))
+	}
+	fmt.Fprintf(&text, "\nTotal: %s\n", utils.FormatMoney(order.Total, order.Currency))
+
+	fmt.Fprintf(&text, "\nPhone: %s", order.CustomerPhone)
+	if order.CustomerName != "" {
+		fmt.Fprintf(&text, "\nName: %s", order.CustomerName)
+	}
+	if order.DeliveryAddress != "" {
+		fmt.Fprintf(&text, "\nAddress: %s", order.DeliveryAddress)
+	}
+	if order.Notes != "" {
+		fmt.Fprintf(&text, "\nNotes: %s", order.Notes)
+	}
+
+	return text.String()
+}
+
+// telegramWebhookSecret is the secret Telegram sends with the updates of an admin's bot.
+// It is derived from the bot token, so changing the bot requires registering again.
+func telegramWebhookSecret(admin *models.Admin) string {
+	mac := hmac.New(sha256.New, []byte(admin.BotToken))
+	mac.Write([]byte("order-webhook:" + strconv.Itoa(admin.ID)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RegisterTelegramWebhook points the admin's Telegram bot at the order webhook, so the
+// buttons under order alerts work
+func (s *OrderService) RegisterTelegramWebhook(ctx context.Context, adminID int) error {
+	if s.publicURL == "" {
+		return utils.NewInvalidInputError("PUBLIC_URL is not configured on the server")
+	}
+
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin.BotToken == "" || admin.BotChatID == "" {
+		return utils.NewInvalidInputError("Set the bot token and chat ID first")
+	}
+
+	url := s.publicURL + "/api/public/telegram/orders/" + strconv.Itoa(admin.ID)
+	if err := s.telegram.SetWebhook(ctx, admin.BotToken, url, telegramWebhookSecret(admin)); err != nil {
+		// Only Telegram's own explanation goes back, transport errors are not for the admin
+		var apiErr *TelegramAPIError
+		if errors.As(err, &apiErr) {
+			return utils.NewAppError(err, "Telegram refused the webhook: "+apiErr.Description, 502)
+		}
+		return utils.NewAppError(err, "Failed to reach Telegram", 502)
+	}
+
+	return nil
+}
+
+// HandleTelegramUpdate applies an Accept or Reject button press from the admin's
+// Telegram chat to the order. Updates that don't carry the admin's webhook secret are
+// rejected, presses from other chats are ignored.
+func (s *OrderService) HandleTelegramUpdate(ctx context.Context, adminID int, secret string, update *models.TelegramUpdate) error {
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+	if admin.BotToken == "" || !hmac.Equal([]byte(secret), []byte(telegramWebhookSecret(admin))) {
+		return utils.NewAppError(utils.ErrUnauthorized, "Invalid webhook secret", 401)
+	}
+
+	query := update.CallbackQuery
+	if query == nil || query.Message == nil {
+		return nil
+	}
+
+	reply := s.applyTelegramAction(ctx, admin, query)
+
+	if err := s.telegram.AnswerCallbackQuery(ctx, admin.BotToken, query.ID, reply); err != nil {
+		log.Printf("Failed to answer Telegram button press for admin %d: %v", admin.ID, err)
+	}
+
+	return nil
+}
+
+// applyTelegramAction changes the status of the order a button was pressed for and
+// returns the reply shown to the user who pressed it
+func (s *OrderService) applyTelegramAction(ctx context.Context, admin *models.Admin, query *models.TelegramCallbackQuery) string {
+	if strconv.FormatInt(query.Message.Chat.ID, 10) != admin.BotChatID {
+		return "This chat can't manage orders"
+	}
+
+	parts := strings.Split(query.Data, ":")
+	if len(parts) != 3 || parts[0] != "order" {
+		return "Unknown action"
+	}
+
+	orderID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return "Unknown action"
+	}
+
+	var status, done string
+	switch parts[1] {
+	case telegramActionAccept:
+		status, done = models.OrderStatusAccepted, "Accepted"
+	case telegramActionReject:
+		status, done = models.OrderStatusCancelled, "Rejected"
+	default:
+		return "Unknown action"
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil || order.AdminID != admin.ID {
+		return "Order #" + parts[2] + " not found"
+	}
+
+	by := query.From.FirstName
+	if query.From.Username != "" {
+		by = "@" + query.From.Username
+	}
+
+	note := ""
+	if status == models.OrderStatusCancelled {
+		note = "Rejected in Telegram by " + by
+	}
+
+	if _, err := s.ChangeStatus(ctx, order, status, models.OrderActorTelegram, nil, note); err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) && appErr.Code == 409 {
+			return "Order #" + parts[2] + " is already " + order.Status
+		}
+		log.Printf("Failed to update order %d from Telegram: %v", order.ID, err)
+		return "Failed to update the order, try again"
+	}
+
+	text := query.Message.Text + "\n\n" + done + " by " + by
+	if err := s.telegram.EditMessageText(ctx, admin.BotToken, query.Message.Chat.ID, query.Message.MessageID, text); err != nil {
+		log.Printf("Failed to update Telegram message of order %d: %v", order.ID, err)
+	}
+
+	return "Order #" + parts[2] + " " + strings.ToLower(done)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// paymentAttachmentTypes lists the content types accepted as proof of payment
+var paymentAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// PaymentAttachmentService handles proofs attached to manual payments
+type PaymentAttachmentService struct {
+	attachmentRepo *repository.PaymentAttachmentRepository
+	paymentRepo    *repository.PaymentHistoryRepository
+	fileService    *ImageService
+}
+
+// NewPaymentAttachmentService creates a new payment attachment service. Files are stored
+// through an image service of their own so they are not served by the public image route.
+func NewPaymentAttachmentService(
+	attachmentRepo *repository.PaymentAttachmentRepository,
+	paymentRepo *repository.PaymentHistoryRepository,
+	fileService *ImageService,
+) *PaymentAttachmentService {
+	return &PaymentAttachmentService{
+		attachmentRepo: attachmentRepo,
+		paymentRepo:    paymentRepo,
+		fileService:    fileService,
+	}
+}
+
+// Upload attaches files to a pending payment of the admin
+func (s *PaymentAttachmentService) Upload(ctx context.Context, adminID int, paymentID int, files []*multipart.FileHeader) ([]*models.PaymentAttachment, error) {
+	payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Other admins' payments are reported as missing
+	if payment.AdminID != adminID {
+		return nil, utils.ErrResourceNotFound
+	}
+
+	if payment.Status != "pending" {
+		return nil, utils.NewAppError(utils.ErrInvalidInput, "Attachments can only be added to pending payments", 409)
+	}
+
+	if len(files) == 0 {
+		return nil, utils.NewInvalidInputError("No file provided")
+	}
+
+	count, err := s.attachmentRepo.CountByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if count+len(files) > utils.MaxPaymentAttachments {
+		return nil, utils.NewInvalidInputError(fmt.Sprintf("A payment can have at most %d attachments", utils.MaxPaymentAttachments))
+	}
+
+	// Check every file before storing any of them
+	contentTypes := make([]string, len(files))
+	for i, file := range files {
+		if file.Size > utils.MaxPaymentAttachmentSize {
+			return nil, utils.NewInvalidInputError("File size exceeds the maximum allowed size")
+		}
+
+		contentTypes[i], err = detectContentType(file)
+		if err != nil {
+			return nil, err
+		}
+		if !paymentAttachmentTypes[contentTypes[i]] {
+			return nil, utils.NewInvalidInputError("Only JPEG, PNG, WebP and PDF files can be attached")
+		}
+	}
+
+	var attachments []*models.PaymentAttachment
+	for i, file := range files {
+		filename, err := s.fileService.SaveImage(file)
+		if err != nil {
+			return nil, err
+		}
+
+		attachment := &models.PaymentAttachment{
+			PaymentID:    paymentID,
+			AdminID:      adminID,
+			Filename:     filename,
+			OriginalName: filepath.Base(file.Filename),
+			ContentType:  contentTypes[i],
+			Size:         file.Size,
+		}
+
+		if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+			_ = s.fileService.DeleteImage(filename)
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, nil
+}
+
+// GetForDownload retrieves an attachment and the path of its file. Only the paying admin
+// and super admins have access.
+func (s *PaymentAttachmentService) GetForDownload(ctx context.Context, id int, userID int, role string) (*models.PaymentAttachment, string, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if role != utils.RoleSuperAdmin && attachment.AdminID != userID {
+		return nil, "", utils.NewForbiddenError()
+	}
+
+	return attachment, s.fileService.GetImagePath(attachment.Filename), nil
+}
+
+// Delete removes an attachment from a pending payment of the admin
+func (s *PaymentAttachmentService) Delete(ctx context.Context, adminID int, id int) error {
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if attachment.AdminID != adminID {
+		return utils.ErrResourceNotFound
+	}
+
+	payment, err := s.paymentRepo.GetByID(ctx, attachment.PaymentID)
+	if err != nil {
+		return err
+	}
+
+	// Proofs of processed payments are kept for the record
+	if payment.Status != "pending" {
+		return utils.NewAppError(utils.ErrInvalidInput, "Attachments can only be removed from pending payments", 409)
+	}
+
+	if err := s.attachmentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// The record is gone either way, a missing file is not worth failing for
+	if err := s.fileService.DeleteImage(attachment.Filename); err != nil && err != utils.ErrResourceNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// GetByPaymentIDs retrieves the attachments of the given payments, keyed by payment ID
+func (s *PaymentAttachmentService) GetByPaymentIDs(ctx context.Context, paymentIDs ...int) (map[int][]*models.PaymentAttachment, error) {
+	return s.attachmentRepo.GetByPaymentIDs(ctx, paymentIDs...)
+}
+
+// detectContentType sniffs the content type of an uploaded file instead of trusting the client
+func detectContentType(file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer src.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(src, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+
+	return http.DetectContentType(buf[:n]), nil
+}
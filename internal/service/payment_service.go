@@ -2,6 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
 	"time"
 
 	"mobilka/internal/models"
@@ -14,6 +19,11 @@ type PaymentService struct {
 	paymentRepo          *repository.PaymentHistoryRepository
 	adminRepo            *repository.AdminRepository
 	subscriptionTierRepo *repository.SubscriptionTierRepository
+	couponRepo           *repository.CouponRepository
+	exchangeRateRepo     *repository.ExchangeRateRepository
+	refundRepo           *repository.PaymentRefundRepository
+	ledgerRepo           *repository.LedgerRepository
+	events               *EventService
 }
 
 // NewPaymentService creates a new payment service
@@ -21,43 +31,120 @@ func NewPaymentService(
 	paymentRepo *repository.PaymentHistoryRepository,
 	adminRepo *repository.AdminRepository,
 	subscriptionTierRepo *repository.SubscriptionTierRepository,
+	couponRepo *repository.CouponRepository,
+	exchangeRateRepo *repository.ExchangeRateRepository,
+	refundRepo *repository.PaymentRefundRepository,
+	ledgerRepo *repository.LedgerRepository,
+	events *EventService,
 ) *PaymentService {
 	return &PaymentService{
 		paymentRepo:          paymentRepo,
 		adminRepo:            adminRepo,
 		subscriptionTierRepo: subscriptionTierRepo,
+		couponRepo:           couponRepo,
+		exchangeRateRepo:     exchangeRateRepo,
+		refundRepo:           refundRepo,
+		ledgerRepo:           ledgerRepo,
+		events:               events,
 	}
 }
 
 // RecordPayment records a new payment from an admin
 func (s *PaymentService) RecordPayment(ctx context.Context, adminID int, req *models.PaymentCreateRequest) (*models.PaymentHistory, error) {
-	// Get admin to check if they exist
-	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	// Get admin with current subscription tier to check if they exist
+	admin, currentTier, err := s.adminRepo.GetByIDWithSubscriptionInfo(ctx, adminID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Determine appropriate subscription tier based on user count
-	tier, err := s.subscriptionTierRepo.GetTierForUserCount(ctx, admin.Users)
-	if err != nil {
-		// If no specific tier found, don't associate payment with a tier
-		tier = nil
+	// Use the requested tier if given, keep pinned admins on their tier,
+	// otherwise determine it based on user count
+	var tier *models.SubscriptionTier
+	if req.SubscriptionTierID != nil {
+		tier, err = s.subscriptionTierRepo.GetByID(ctx, *req.SubscriptionTierID)
+		if err != nil {
+			return nil, err
+		}
+	} else if admin.SubscriptionTierPinned && currentTier != nil {
+		tier = currentTier
+	} else {
+		tier, err = s.subscriptionTierRepo.GetTierForUserCount(ctx, admin.Users)
+		if err != nil {
+			// If no specific tier found, don't associate payment with a tier
+			tier = nil
+		}
+	}
+
+	// Payments default to the admin's billing currency
+	currency := admin.BillingCurrency
+	if req.Currency != "" {
+		currency = utils.NormalizeCurrency(req.Currency)
+	}
+	if !utils.IsSupportedCurrency(currency) {
+		return nil, utils.NewInvalidInputError("Unsupported currency " + currency)
+	}
+
+	// Payments default to the admin's billing interval
+	interval := admin.BillingInterval
+	if req.BillingInterval != "" {
+		interval = req.BillingInterval
+	}
+	intervalMonths, ok := models.BillingIntervalMonths[interval]
+	if !ok {
+		return nil, utils.NewInvalidInputError("Unknown billing interval " + interval)
+	}
+
+	// A top-up only adds to the prepaid balance, subscription periods are paid from it later
+	if req.TopUp {
+		if req.Amount <= 0 {
+			return nil, utils.NewInvalidInputError("Top-up amount must be greater than zero")
+		}
+
+		payment := &models.PaymentHistory{
+			AdminID:         adminID,
+			Amount:          req.Amount,
+			Currency:        currency,
+			PaymentDate:     time.Now(),
+			PaymentMethod:   req.PaymentMethod,
+			TransactionID:   req.TransactionID,
+			BillingInterval: interval,
+			Status:          "pending",
+			Notes:           req.Notes,
+			IsTopUp:         true,
+		}
+
+		if err := s.paymentRepo.Create(ctx, payment); err != nil {
+			return nil, err
+		}
+
+		return payment, nil
 	}
 
 	// Create payment record
 	payment := &models.PaymentHistory{
-		AdminID:       adminID,
-		Amount:        req.Amount,
-		PaymentDate:   time.Now(),
-		PaymentMethod: req.PaymentMethod,
-		TransactionID: req.TransactionID,
-		Status:        "pending",
-		Notes:         req.Notes,
+		AdminID:         adminID,
+		Amount:          req.Amount,
+		Currency:        currency,
+		PaymentDate:     time.Now(),
+		PaymentMethod:   req.PaymentMethod,
+		TransactionID:   req.TransactionID,
+		BillingInterval: interval,
+		Months:          intervalMonths,
+		Status:          "pending",
+		Notes:           req.Notes,
 	}
 
-	// Associate with subscription tier if found
 	if tier != nil {
+		// Associate with subscription tier
 		payment.SubscriptionTierID = &tier.ID
+
+		err = s.applyTierPricing(ctx, admin, currentTier, tier, payment)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		payment.NeedsReview = true
+		payment.ReviewReason = "No subscription tier matches this payment"
 	}
 
 	// Save payment to database
@@ -69,30 +156,214 @@ func (s *PaymentService) RecordPayment(ctx context.Context, adminID int, req *mo
 	return payment, nil
 }
 
+// applyTierPricing maps a payment amount to billing periods of a tier, charging any
+// upgrade proration and coupon discount first, and flags amounts that don't match.
+// Tier prices are converted to the payment currency.
+func (s *PaymentService) applyTierPricing(ctx context.Context, admin *models.Admin, currentTier, tier *models.SubscriptionTier, payment *models.PaymentHistory) error {
+	now := time.Now()
+
+	if err := s.subscriptionTierRepo.LoadPrices(ctx, tier, currentTier); err != nil {
+		return err
+	}
+
+	// Without an exchange rate the amount can only be checked by hand
+	var appErr *utils.AppError
+	price, periodMonths, err := s.intervalPrice(ctx, tier, payment.BillingInterval, payment.Currency, now)
+	if errors.As(err, &appErr) {
+		payment.NeedsReview = true
+		payment.ReviewReason = appErr.Message
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Moving to a more expensive tier mid-cycle costs the prorated difference
+	// for the rest of the current period
+	if currentTier != nil && currentTier.ID != tier.ID && admin.HasActivePeriod(now) {
+		prorated, _, err := s.proratedTierChange(ctx, admin, currentTier, tier, payment.Currency, now)
+		if errors.As(err, &appErr) {
+			payment.NeedsReview = true
+			payment.ReviewReason = appErr.Message
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		payment.ProratedAmount = prorated
+	}
+
+	// An applied coupon lowers the price of the periods it covers
+	var coupon *models.Coupon
+	discountMonths := 0
+	redemption, activeCoupon, err := s.couponRepo.GetActiveRedemption(ctx, admin.ID)
+	if err != nil && err != utils.ErrResourceNotFound {
+		return err
+	}
+	if redemption != nil && activeCoupon.AppliesToTier(tier.ID) && activeCoupon.AppliesToCurrency(payment.Currency) {
+		coupon, discountMonths = activeCoupon, couponMonths(redemption)
+	}
+	firstPeriodPrice := price
+	if coupon != nil {
+		firstPeriodPrice = coupon.DiscountedPrice(price, periodMonths, coveredMonths(discountMonths, periodMonths))
+	}
+
+	// Map the rest of the amount to a number of billing periods
+	periods, discountedMonths, discount, remainder := periodsForDiscountedAmount(
+		payment.Amount-payment.ProratedAmount, price, periodMonths, coupon, discountMonths)
+	if periods > 0 {
+		payment.Months = periods * periodMonths
+	}
+
+	if discountedMonths > 0 {
+		payment.CouponRedemptionID = &redemption.ID
+		payment.DiscountedMonths = discountedMonths
+		payment.DiscountAmount = discount
+	}
+
+	// Flag amounts that don't match the tier price for review
+	switch {
+	case price <= 0:
+		payment.NeedsReview = true
+		payment.ReviewReason = fmt.Sprintf("Payment recorded for free tier %s", tier.Name)
+	case periods < 1:
+		payment.NeedsReview = true
+		payment.ReviewReason = fmt.Sprintf("Amount %s is less than the %s %s tier price %s",
+			utils.FormatMoney(payment.Amount, payment.Currency), payment.BillingInterval, tier.Name,
+			utils.FormatMoney(firstPeriodPrice+payment.ProratedAmount, payment.Currency))
+	case remainder > amountTolerance:
+		payment.NeedsReview = true
+		payment.ReviewReason = fmt.Sprintf("Amount %s is not a multiple of the %s %s tier price %s (%s left over)",
+			utils.FormatMoney(payment.Amount, payment.Currency), payment.BillingInterval, tier.Name,
+			utils.FormatMoney(firstPeriodPrice, payment.Currency), utils.FormatMoney(remainder, payment.Currency))
+	}
+
+	return nil
+}
+
+// intervalPrice returns the price of one billing interval of a tier in the given currency,
+// together with the interval length in months. Tier prices must be loaded.
+func (s *PaymentService) intervalPrice(ctx context.Context, tier *models.SubscriptionTier, interval string, currency string, at time.Time) (int64, int, error) {
+	price, months := tier.PriceFor(interval)
+
+	converted, err := convertAmount(ctx, s.exchangeRateRepo, price, tier.Currency, currency, at)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return converted, months, nil
+}
+
+// proratedTierChange returns what moving from the current to a new tier costs for the rest
+// of the admin's current billing period, together with the days remaining. Tier prices
+// must be loaded.
+func (s *PaymentService) proratedTierChange(ctx context.Context, admin *models.Admin, currentTier, tier *models.SubscriptionTier, currency string, now time.Time) (int64, int, error) {
+	currentPrice, periodMonths, err := s.intervalPrice(ctx, currentTier, admin.BillingInterval, currency, now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	newPrice, _, err := s.intervalPrice(ctx, tier, admin.BillingInterval, currency, now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	amount, remainingDays := proratedUpgradeAmount(currentPrice, newPrice, periodMonths, *admin.SubscriptionExpiresAt, now)
+	return amount, remainingDays, nil
+}
+
+// QuoteTierChange calculates what an admin has to pay to move to another tier
+func (s *PaymentService) QuoteTierChange(ctx context.Context, adminID int, tierID int) (*models.TierChangeQuote, error) {
+	admin, currentTier, err := s.adminRepo.GetByIDWithSubscriptionInfo(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	tier, err := s.subscriptionTierRepo.GetByID(ctx, tierID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionTierRepo.LoadPrices(ctx, tier, currentTier); err != nil {
+		return nil, err
+	}
+
+	// Quote in the admin's billing currency and interval
+	now := time.Now()
+	monthlyPrice, _, err := s.intervalPrice(ctx, tier, models.BillingIntervalMonthly, admin.BillingCurrency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	price, _, err := s.intervalPrice(ctx, tier, admin.BillingInterval, admin.BillingCurrency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &models.TierChangeQuote{
+		CurrentTierID:   admin.SubscriptionTierID,
+		NewTierID:       tier.ID,
+		NewTierName:     tier.Name,
+		MonthlyPrice:    monthlyPrice,
+		BillingInterval: admin.BillingInterval,
+		IntervalPrice:   price,
+		Currency:        admin.BillingCurrency,
+	}
+
+	if admin.HasActivePeriod(now) {
+		quote.CurrentPeriodTo = admin.SubscriptionExpiresAt
+		if currentTier != nil && currentTier.ID != tier.ID {
+			quote.ProratedAmount, quote.RemainingDays, err = s.proratedTierChange(ctx, admin, currentTier, tier, admin.BillingCurrency, now)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return quote, nil
+}
+
 // GetPaymentByID retrieves a payment by ID
 func (s *PaymentService) GetPaymentByID(ctx context.Context, id int) (*models.PaymentHistory, error) {
 	return s.paymentRepo.GetByID(ctx, id)
 }
 
-// GetPaymentsByAdminID retrieves all payments for a specific admin
-func (s *PaymentService) GetPaymentsByAdminID(ctx context.Context, adminID int) ([]*models.PaymentHistory, error) {
+// GetPaymentsByAdminID retrieves one page of the payments of a specific admin
+func (s *PaymentService) GetPaymentsByAdminID(ctx context.Context, adminID int, params models.ListParams) ([]*models.PaymentHistory, *models.ListMeta, error) {
 	// Verify admin exists
 	_, err := s.adminRepo.GetByID(ctx, adminID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return s.paymentRepo.GetByAdminID(ctx, adminID)
+	params.AdminID = &adminID
+	return s.paymentRepo.List(ctx, params, models.PaymentFilter{})
+}
+
+// GetAllPayments retrieves one page of the payments matching a filter
+func (s *PaymentService) GetAllPayments(ctx context.Context, params models.ListParams, filter models.PaymentFilter) ([]*models.PaymentHistory, *models.ListMeta, error) {
+	return s.paymentRepo.List(ctx, params, filter)
 }
 
-// GetAllPayments retrieves all payments
-func (s *PaymentService) GetAllPayments(ctx context.Context) ([]*models.PaymentHistory, error) {
-	return s.paymentRepo.GetAll(ctx)
+// GetPendingPayments retrieves one page of the pending payments, oldest first by default
+func (s *PaymentService) GetPendingPayments(ctx context.Context, params models.ListParams) ([]*models.PaymentHistory, *models.ListMeta, error) {
+	params.Status = "pending"
+	return s.paymentRepo.List(ctx, oldestPaymentsFirst(params), models.PaymentFilter{})
 }
 
-// GetPendingPayments retrieves all pending payments
-func (s *PaymentService) GetPendingPayments(ctx context.Context) ([]*models.PaymentHistory, error) {
-	return s.paymentRepo.GetPendingPayments(ctx)
+// GetFlaggedPayments retrieves one page of the pending payments whose amount needs manual
+// review, oldest first by default
+func (s *PaymentService) GetFlaggedPayments(ctx context.Context, params models.ListParams) ([]*models.PaymentHistory, *models.ListMeta, error) {
+	params.Status = "pending"
+	return s.paymentRepo.List(ctx, oldestPaymentsFirst(params), models.PaymentFilter{NeedsReview: true})
+}
+
+// oldestPaymentsFirst orders a review queue by payment date unless another order was requested
+func oldestPaymentsFirst(params models.ListParams) models.ListParams {
+	if params.Sort == "" {
+		params.Sort, params.Desc = "payment_date", false
+	}
+	return params
 }
 
 // VerifyPayment verifies a payment and updates admin's subscription status
@@ -103,12 +374,46 @@ func (s *PaymentService) VerifyPayment(ctx context.Context, paymentID int, super
 		return err
 	}
 
-	// Get admin
-	admin, err := s.adminRepo.GetByID(ctx, payment.AdminID)
+	// A processed payment must not extend the subscription a second time
+	if payment.Status != "pending" {
+		return utils.NewAppError(utils.ErrInvalidInput, "Payment has already been "+payment.Status, 409)
+	}
+
+	// Get admin with the current subscription period
+	admin, _, err := s.adminRepo.GetByIDWithSubscriptionInfo(ctx, payment.AdminID)
 	if err != nil {
 		return err
 	}
 
+	periodStart, periodEnd := req.PeriodStart, req.PeriodEnd
+	if payment.IsTopUp {
+		// Top-ups don't pay for a period of their own
+		periodStart, periodEnd = nil, nil
+	}
+
+	// Post the money received to the ledger and renew the subscription it pays for
+	// together with the status change
+	var postings []*models.LedgerTransaction
+	var renewal *models.SubscriptionRenewal
+	if req.Status == "verified" {
+		postings = paymentPostings(payment)
+
+		if !payment.IsTopUp {
+			// Paying for another interval switches the admin to it. Without a given end
+			// the period is stacked on the current one when the payment is verified.
+			renewal = &models.SubscriptionRenewal{
+				AdminID:            admin.ID,
+				SubscriptionTierID: payment.SubscriptionTierID,
+				Months:             payment.Months,
+				PeriodStart:        periodStart,
+				PeriodEnd:          periodEnd,
+				BillingInterval:    payment.BillingInterval,
+				CouponRedemptionID: payment.CouponRedemptionID,
+				CouponMonths:       payment.DiscountedMonths,
+			}
+		}
+	}
+
 	// Update payment status
 	err = s.paymentRepo.VerifyPayment(
 		ctx,
@@ -116,34 +421,37 @@ func (s *PaymentService) VerifyPayment(ctx context.Context, paymentID int, super
 		superAdminID,
 		req.Status,
 		req.Notes,
-		req.PeriodStart,
-		req.PeriodEnd,
+		periodStart,
+		periodEnd,
+		renewal,
+		postings...,
 	)
 	if err != nil {
 		return err
 	}
 
-	// If payment is verified, update admin subscription status
 	if req.Status == "verified" {
-		// Default to 1 month subscription period if not specified
-		periodEnd := req.PeriodEnd
-		if periodEnd == nil {
-			defaultEnd := time.Now().AddDate(0, 1, 0) // 1 month from now
-			periodEnd = &defaultEnd
+		s.events.Publish(ctx, models.EventPaymentVerified, payment.AdminID, models.PaymentEventData{
+			PaymentID: payment.ID,
+			Amount:    payment.Amount,
+			Currency:  payment.Currency,
+			IsTopUp:   payment.IsTopUp,
+		})
+	}
+
+	// A top-up reactivates a lapsed subscription if the balance now covers a period
+	if req.Status == "verified" && payment.IsTopUp {
+		if admin.HasActivePeriod(time.Now()) {
+			return nil
 		}
 
-		// Update admin subscription status
-		err = s.adminRepo.UpdateSubscriptionStatus(
-			ctx,
-			admin.ID,
-			payment.SubscriptionTierID,
-			"active",
-			periodEnd,
-			false, // remove access restriction
-		)
-		if err != nil {
-			return err
+		_, err = s.RenewFromBalance(ctx, admin.ID)
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) {
+			// Not enough balance or no payable tier yet, the money stays on the balance
+			return nil
 		}
+		return err
 	}
 
 	return nil
@@ -166,9 +474,114 @@ func (s *PaymentService) RejectPayment(ctx context.Context, paymentID int, super
 		notes,
 		nil,
 		nil,
+		nil,
 	)
 }
 
+// RefundPayment returns money for a verified payment, by default everything not refunded yet.
+// The subscription time the payment bought is taken back in proportion to the amount.
+func (s *PaymentService) RefundPayment(ctx context.Context, paymentID int, superAdminID int, req *models.PaymentRefundRequest) (*models.PaymentRefund, error) {
+	return s.refund(ctx, paymentID, superAdminID, models.PaymentRefundTypeRefund, req.Amount, req.Reason)
+}
+
+// ReversePayment undoes a payment that was verified by mistake, taking back all the
+// subscription time it bought that hasn't been refunded yet
+func (s *PaymentService) ReversePayment(ctx context.Context, paymentID int, superAdminID int, req *models.PaymentReversalRequest) (*models.PaymentRefund, error) {
+	return s.refund(ctx, paymentID, superAdminID, models.PaymentRefundTypeReversal, nil, req.Reason)
+}
+
+// refund applies a refund or reversal of a verified payment
+func (s *PaymentService) refund(ctx context.Context, paymentID int, superAdminID int, refundType string, amount *int64, reason string) (*models.PaymentRefund, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, utils.NewInvalidInputError("Reason is required")
+	}
+
+	payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status != "verified" {
+		return nil, utils.NewAppError(utils.ErrInvalidInput, "Only verified payments can be refunded, this one is "+payment.Status, 409)
+	}
+
+	refundable := payment.Amount - payment.RefundedAmount
+	refundAmount := refundable
+	if amount != nil {
+		refundAmount = *amount
+	}
+	if refundAmount <= 0 || refundAmount > refundable {
+		return nil, utils.NewInvalidInputError("Refund amount must be greater than zero and at most " +
+			utils.FormatMoney(refundable, payment.Currency))
+	}
+
+	removedSeconds := refundedPeriodSeconds(payment, refundAmount)
+
+	paymentStatus := "verified"
+	fullyRefunded := refundAmount == refundable
+	switch {
+	case refundType == models.PaymentRefundTypeReversal:
+		paymentStatus = "reversed"
+	case fullyRefunded:
+		paymentStatus = "refunded"
+	}
+
+	// Coupon months spent on a payment that is fully given back can be used again
+	restoreCouponMonths := 0
+	if fullyRefunded {
+		restoreCouponMonths = payment.DiscountedMonths
+	}
+
+	refund := &models.PaymentRefund{
+		PaymentID:      payment.ID,
+		AdminID:        payment.AdminID,
+		Type:           refundType,
+		Amount:         refundAmount,
+		Currency:       payment.Currency,
+		Reason:         strings.TrimSpace(reason),
+		RemovedSeconds: removedSeconds,
+		CreatedBy:      &superAdminID,
+	}
+
+	err = s.refundRepo.Create(ctx, refund, paymentStatus, payment.CouponRedemptionID, restoreCouponMonths, refundPostings(payment, refund)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// refundedPeriodSeconds returns the share of a payment's paid period, in seconds, that a
+// refund of refundAmount takes back. Earlier refunds already shortened the period, so what
+// is left of it is worth what is left to refund.
+func refundedPeriodSeconds(payment *models.PaymentHistory, refundAmount int64) int64 {
+	refundable := payment.Amount - payment.RefundedAmount
+	if payment.PeriodStart == nil || payment.PeriodEnd == nil || !payment.PeriodEnd.After(*payment.PeriodStart) || refundable <= 0 {
+		return 0
+	}
+
+	period := payment.PeriodEnd.Sub(*payment.PeriodStart).Seconds()
+	return int64(math.Round(period * float64(refundAmount) / float64(refundable)))
+}
+
+// GetPaymentRefunds retrieves the refunds of a payment
+func (s *PaymentService) GetPaymentRefunds(ctx context.Context, paymentID int) ([]*models.PaymentRefund, error) {
+	if _, err := s.paymentRepo.GetByID(ctx, paymentID); err != nil {
+		return nil, err
+	}
+
+	return s.refundRepo.GetByPaymentID(ctx, paymentID)
+}
+
+// GetPaymentAuditLog retrieves the audit trail of a payment
+func (s *PaymentService) GetPaymentAuditLog(ctx context.Context, paymentID int) ([]*models.PaymentAuditLog, error) {
+	if _, err := s.paymentRepo.GetByID(ctx, paymentID); err != nil {
+		return nil, err
+	}
+
+	return s.refundRepo.GetAuditLog(ctx, paymentID)
+}
+
 // CheckSubscriptionStatus checks admin's subscription status and updates if needed
 func (s *PaymentService) CheckSubscriptionStatus(ctx context.Context, adminID int) (*models.Admin, error) {
 	// Get admin with subscription info
@@ -199,8 +612,8 @@ func (s *PaymentService) CheckSubscriptionStatus(ctx context.Context, adminID in
 		}
 	}
 
-	// Check if subscription has expired
-	if admin.SubscriptionStatus == "active" &&
+	// Check if subscription or trial has expired
+	if (admin.SubscriptionStatus == "active" || admin.SubscriptionStatus == "trial") &&
 		admin.SubscriptionExpiresAt != nil &&
 		admin.SubscriptionExpiresAt.Before(time.Now()) {
 
@@ -252,14 +665,194 @@ func (s *PaymentService) ExpireSubscriptions(ctx context.Context) (int, error) {
 	return s.adminRepo.ExpireSubscriptions(ctx)
 }
 
-// CalculateMonthlySubscriptionFee calculates the monthly subscription fee based on user count
-func (s *PaymentService) CalculateMonthlySubscriptionFee(ctx context.Context, userCount int) (float64, *models.SubscriptionTier, error) {
+// balanceRenewalLeadTime is how long before expiry a subscription is renewed from the prepaid balance
+const balanceRenewalLeadTime = 24 * time.Hour
+
+// RenewSubscriptionsFromBalance renews the subscriptions that end soon or have ended for
+// every admin whose prepaid balance covers the next billing period, returning how many
+// were renewed
+func (s *PaymentService) RenewSubscriptionsFromBalance(ctx context.Context) (int, error) {
+	adminIDs, err := s.ledgerRepo.GetAdminsToRenew(ctx, time.Now().Add(balanceRenewalLeadTime))
+	if err != nil {
+		return 0, err
+	}
+
+	renewed := 0
+	for _, adminID := range adminIDs {
+		if _, err := s.RenewFromBalance(ctx, adminID); err != nil {
+			var appErr *utils.AppError
+			if !errors.As(err, &appErr) {
+				log.Printf("Failed to renew subscription of admin %d from balance: %v", adminID, err)
+			}
+			continue
+		}
+		renewed++
+	}
+
+	return renewed, nil
+}
+
+// RenewFromBalance pays the admin's next billing period from the prepaid balance. The period
+// is priced like a payment: the pinned or user count tier in the billing interval and currency,
+// less the discount of an active coupon. It fails with a conflict when the balance is too low.
+func (s *PaymentService) RenewFromBalance(ctx context.Context, adminID int) (*models.PaymentHistory, error) {
+	admin, currentTier, err := s.adminRepo.GetByIDWithSubscriptionInfo(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	tier := currentTier
+	if !admin.SubscriptionTierPinned || currentTier == nil {
+		tier, err = s.subscriptionTierRepo.GetTierForUserCount(ctx, admin.Users)
+		if err != nil {
+			if err == utils.ErrResourceNotFound {
+				return nil, utils.NewAppError(utils.ErrInvalidInput, "No subscription tier matches the admin", 409)
+			}
+			return nil, err
+		}
+	}
+
+	if err := s.subscriptionTierRepo.LoadPrices(ctx, tier); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	price, periodMonths, err := s.intervalPrice(ctx, tier, admin.BillingInterval, admin.BillingCurrency, now)
+	if err != nil {
+		return nil, err
+	}
+	if price <= 0 {
+		return nil, utils.NewAppError(utils.ErrInvalidInput, "Tier "+tier.Name+" is free and can't be paid from the balance", 409)
+	}
+
+	payment := &models.PaymentHistory{
+		AdminID:            adminID,
+		Amount:             price,
+		Currency:           admin.BillingCurrency,
+		PaymentDate:        now,
+		PaymentMethod:      models.PaymentMethodBalance,
+		SubscriptionTierID: &tier.ID,
+		BillingInterval:    admin.BillingInterval,
+		Months:             periodMonths,
+		Status:             "verified",
+		Notes:              "Paid from prepaid balance",
+		VerifiedAt:         &now,
+	}
+
+	// An applied coupon discounts the months of the period it still covers
+	redemption, coupon, err := s.couponRepo.GetActiveRedemption(ctx, adminID)
+	if err != nil && err != utils.ErrResourceNotFound {
+		return nil, err
+	}
+	if redemption != nil && coupon.AppliesToTier(tier.ID) && coupon.AppliesToCurrency(payment.Currency) {
+		if months := coveredMonths(couponMonths(redemption), periodMonths); months > 0 {
+			payment.Amount = coupon.DiscountedPrice(price, periodMonths, months)
+			payment.CouponRedemptionID = &redemption.ID
+			payment.DiscountAmount = price - payment.Amount
+			payment.DiscountedMonths = months
+		}
+	}
+
+	charge := models.NewLedgerTransaction(adminID, models.LedgerTypeCharge, payment.Currency, payment.Amount,
+		models.LedgerAccountAdminBalance, models.LedgerAccountRevenue,
+		fmt.Sprintf("%s %s subscription for %d months", tier.Name, admin.BillingInterval, periodMonths))
+
+	// A fully discounted period costs nothing
+	if payment.Amount == 0 {
+		charge = nil
+	}
+
+	renewal := &models.SubscriptionRenewal{
+		AdminID:            adminID,
+		SubscriptionTierID: &tier.ID,
+		Months:             periodMonths,
+		BillingInterval:    admin.BillingInterval,
+		CouponRedemptionID: payment.CouponRedemptionID,
+		CouponMonths:       payment.DiscountedMonths,
+	}
+
+	if err := s.paymentRepo.CreatePaidFromBalance(ctx, payment, renewal, charge); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Renewed subscription of admin %d from balance until %s for %s",
+		adminID, payment.PeriodEnd.Format(time.RFC3339), utils.FormatMoney(payment.Amount, payment.Currency))
+
+	s.events.Publish(ctx, models.EventPaymentVerified, adminID, models.PaymentEventData{
+		PaymentID: payment.ID,
+		Amount:    payment.Amount,
+		Currency:  payment.Currency,
+	})
+
+	return payment, nil
+}
+
+// CalculateMonthlySubscriptionFee calculates the monthly subscription fee based on user count,
+// including the discount of the admin's active coupon. The fee is returned in minor units
+// of the admin's billing currency.
+func (s *PaymentService) CalculateMonthlySubscriptionFee(ctx context.Context, adminID int, userCount int) (int64, *models.SubscriptionTier, error) {
+	return s.CalculateSubscriptionFee(ctx, adminID, userCount, models.BillingIntervalMonthly)
+}
+
+// CalculateSubscriptionFee calculates the subscription fee for one billing interval based on
+// user count, including the discount of the admin's active coupon. The fee is returned in
+// minor units of the admin's billing currency.
+func (s *PaymentService) CalculateSubscriptionFee(ctx context.Context, adminID int, userCount int, interval string) (int64, *models.SubscriptionTier, error) {
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return 0, nil, err
+	}
+
 	tier, err := s.subscriptionTierRepo.GetTierForUserCount(ctx, userCount)
 	if err != nil {
 		return 0, nil, err
 	}
 
-	return tier.Price, tier, nil
+	if err := s.subscriptionTierRepo.LoadPrices(ctx, tier); err != nil {
+		return 0, nil, err
+	}
+
+	fee, periodMonths, err := s.intervalPrice(ctx, tier, interval, admin.BillingCurrency, time.Now())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	redemption, coupon, err := s.couponRepo.GetActiveRedemption(ctx, adminID)
+	if err != nil {
+		if err == utils.ErrResourceNotFound {
+			return fee, tier, nil
+		}
+		return 0, nil, err
+	}
+
+	if !coupon.AppliesToTier(tier.ID) || !coupon.AppliesToCurrency(admin.BillingCurrency) {
+		return fee, tier, nil
+	}
+
+	return coupon.DiscountedPrice(fee, periodMonths, coveredMonths(couponMonths(redemption), periodMonths)), tier, nil
+}
+
+// SetBillingInterval changes how often an admin pays. The new interval applies from the next payment.
+func (s *PaymentService) SetBillingInterval(ctx context.Context, adminID int, interval string) (*models.Admin, error) {
+	if _, ok := models.BillingIntervalMonths[interval]; !ok {
+		return nil, utils.NewInvalidInputError("Billing interval must be monthly, quarterly or yearly")
+	}
+
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin.BillingInterval == interval {
+		return admin, nil
+	}
+
+	if err := s.adminRepo.UpdateBillingInterval(ctx, adminID, interval); err != nil {
+		return nil, err
+	}
+	admin.BillingInterval = interval
+
+	return admin, nil
 }
 
 // CheckAdminAccess checks if an admin has access to features based on payment status
@@ -269,3 +862,105 @@ func (s *PaymentService) CheckAdminAccess(ctx context.Context, adminID int) (boo
 	// Check database
 	return s.adminRepo.CheckAdminAccess(ctx, adminID)
 }
+
+// amountTolerance is the largest difference in minor units still treated as an exact amount match
+const amountTolerance = 1
+
+// periodsForAmount returns how many whole billing periods an amount in minor units pays
+// for at the given period price, together with the amount left over
+func periodsForAmount(amount, price int64) (int, int64) {
+	if price <= 0 || amount <= 0 {
+		return 0, amount
+	}
+
+	periods := (amount + amountTolerance) / price
+	remainder := amount - periods*price
+	if remainder < 0 {
+		remainder = 0
+	}
+
+	return int(periods), remainder
+}
+
+// periodsForDiscountedAmount maps an amount to billing periods of periodMonths months when
+// a coupon discounts the first couponMonths months of them (-1 means every month, a nil
+// coupon none). A period the coupon only partly covers is discounted for the covered months.
+// It returns the periods paid for, the months discounted, the discount granted and the
+// amount left over.
+func periodsForDiscountedAmount(amount, price int64, periodMonths int, coupon *models.Coupon, couponMonths int) (int, int, int64, int64) {
+	if coupon == nil || couponMonths == 0 || coupon.DiscountedPrice(price, periodMonths, periodMonths) >= price {
+		periods, remainder := periodsForAmount(amount, price)
+		return periods, 0, 0, remainder
+	}
+
+	if couponMonths < 0 {
+		discountedPrice := coupon.DiscountedPrice(price, periodMonths, periodMonths)
+		periods, remainder := periodsForAmount(amount, discountedPrice)
+		return periods, periods * periodMonths, int64(periods) * (price - discountedPrice), remainder
+	}
+
+	// The periods the coupon covers are paid first, one by one
+	periods, discountedMonths, discount := 0, 0, int64(0)
+	for couponMonths > 0 {
+		months := coveredMonths(couponMonths, periodMonths)
+		discountedPrice := coupon.DiscountedPrice(price, periodMonths, months)
+		if amount <= 0 || amount+amountTolerance < discountedPrice {
+			if amount < 0 {
+				amount = 0
+			}
+			return periods, discountedMonths, discount, amount
+		}
+
+		amount -= discountedPrice
+		periods++
+		discountedMonths += months
+		discount += price - discountedPrice
+		couponMonths -= months
+	}
+	if amount < 0 {
+		amount = 0
+	}
+
+	more, remainder := periodsForAmount(amount, price)
+	return periods + more, discountedMonths, discount, remainder
+}
+
+// couponMonths returns how many months an active coupon redemption still discounts, -1 when
+// it never ends
+func couponMonths(redemption *models.CouponRedemption) int {
+	if redemption.MonthsRemaining == nil {
+		return -1
+	}
+	return *redemption.MonthsRemaining
+}
+
+// coveredMonths returns how many months of a billing period of periodMonths months a coupon
+// with couponMonths months left (-1 for every month) discounts
+func coveredMonths(couponMonths, periodMonths int) int {
+	if couponMonths < 0 || couponMonths > periodMonths {
+		return periodMonths
+	}
+	return couponMonths
+}
+
+// proratedUpgradeAmount returns the price difference in minor units between two tiers for
+// the rest of the current billing period of periodMonths months, together with the number
+// of days remaining
+func proratedUpgradeAmount(currentPrice, newPrice int64, periodMonths int, expiresAt time.Time, now time.Time) (int64, int) {
+	if newPrice <= currentPrice || !expiresAt.After(now) {
+		return 0, 0
+	}
+
+	periodStart := expiresAt.AddDate(0, -periodMonths, 0)
+	periodLength := expiresAt.Sub(periodStart)
+	remaining := expiresAt.Sub(now)
+	if remaining > periodLength {
+		remaining = periodLength
+	}
+
+	fraction := float64(remaining) / float64(periodLength)
+	amount := int64(math.Round(float64(newPrice-currentPrice) * fraction))
+	remainingDays := int(math.Ceil(remaining.Hours() / 24))
+
+	return amount, remainingDays
+}
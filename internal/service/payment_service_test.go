@@ -0,0 +1,166 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"mobilka/internal/models"
+)
+
+func TestPeriodsForDiscountedAmount(t *testing.T) {
+	half := &models.Coupon{DiscountType: "percent", DiscountPercent: 50}
+	fixed := &models.Coupon{DiscountType: "fixed", DiscountAmount: 1000}
+
+	tests := []struct {
+		name           string
+		amount         int64
+		price          int64
+		periodMonths   int
+		coupon         *models.Coupon
+		couponMonths   int
+		wantPeriods    int
+		wantDiscounted int
+		wantDiscount   int64
+		wantRemainder  int64
+	}{
+		{"no coupon", 20000, 10000, 1, nil, 0, 2, 0, 0, 0},
+		{"coupon used up", 20000, 10000, 1, half, 0, 2, 0, 0, 0},
+		{"percent monthly", 5000, 10000, 1, half, 3, 1, 1, 5000, 0},
+		{"percent monthly beyond the coupon", 35000, 10000, 1, half, 3, 5, 3, 15000, 0},
+		{"percent monthly forever", 15000, 10000, 1, half, -1, 3, 3, 15000, 0},
+		{"percent quarterly", 13500, 27000, 3, half, 3, 1, 3, 13500, 0},
+		{"percent quarterly partly covered", 22500, 27000, 3, half, 1, 1, 1, 4500, 0},
+		{"percent quarterly covered then partly", 36000, 27000, 3, half, 4, 2, 4, 18000, 0},
+		{"percent yearly partly covered", 84000, 96000, 12, half, 3, 1, 3, 12000, 0},
+		{"percent yearly forever", 96000, 96000, 12, half, -1, 2, 24, 96000, 0},
+		{"fixed monthly", 18000, 10000, 1, fixed, 2, 2, 2, 2000, 0},
+		{"fixed quarterly", 24000, 27000, 3, fixed, 6, 1, 3, 3000, 0},
+		{"fixed quarterly two periods", 48000, 27000, 3, fixed, 6, 2, 6, 6000, 0},
+		{"fixed yearly", 84000, 96000, 12, fixed, 12, 1, 12, 12000, 0},
+		{"fixed yearly partly covered", 93000, 96000, 12, fixed, 3, 1, 3, 3000, 0},
+		{"fixed yearly forever", 168000, 96000, 12, fixed, -1, 2, 24, 24000, 0},
+		{"amount below the discounted price", 4000, 10000, 1, half, 3, 0, 0, 0, 4000},
+		{"amount left over", 7000, 10000, 1, half, 3, 1, 1, 5000, 2000},
+		{"within the tolerance", 4999, 10000, 1, half, 3, 1, 1, 5000, 0},
+		{"zero percent coupon", 20000, 10000, 1, &models.Coupon{DiscountType: "percent"}, 3, 2, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periods, discounted, discount, remainder := periodsForDiscountedAmount(tt.amount, tt.price, tt.periodMonths, tt.coupon, tt.couponMonths)
+			if periods != tt.wantPeriods || discounted != tt.wantDiscounted || discount != tt.wantDiscount {
+				t.Errorf("periodsForDiscountedAmount(%d) = %d periods, %d discounted months, discount %d, want %d, %d, %d",
+					tt.amount, periods, discounted, discount, tt.wantPeriods, tt.wantDiscounted, tt.wantDiscount)
+			}
+			if remainder != tt.wantRemainder {
+				t.Errorf("remainder = %d, want %d", remainder, tt.wantRemainder)
+			}
+		})
+	}
+}
+
+func TestPeriodsForAmount(t *testing.T) {
+	tests := []struct {
+		name          string
+		amount        int64
+		price         int64
+		wantPeriods   int
+		wantRemainder int64
+	}{
+		{"one period", 10000, 10000, 1, 0},
+		{"several periods", 30000, 10000, 3, 0},
+		{"partial payment", 5000, 10000, 0, 5000},
+		{"amount left over", 25000, 10000, 2, 5000},
+		{"one below the price", 9999, 10000, 1, 0},
+		{"one above the price", 10001, 10000, 1, 1},
+		{"two below the price", 9998, 10000, 0, 9998},
+		{"zero amount", 0, 10000, 0, 0},
+		{"free price", 10000, 0, 0, 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periods, remainder := periodsForAmount(tt.amount, tt.price)
+			if periods != tt.wantPeriods || remainder != tt.wantRemainder {
+				t.Errorf("periodsForAmount(%d, %d) = %d, %d, want %d, %d",
+					tt.amount, tt.price, periods, remainder, tt.wantPeriods, tt.wantRemainder)
+			}
+		})
+	}
+}
+
+func TestProratedUpgradeAmount(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name          string
+		currentPrice  int64
+		newPrice      int64
+		periodMonths  int
+		expiresAt     string
+		now           string
+		wantAmount    int64
+		wantRemaining int
+	}{
+		{"half a month left", 10000, 20000, 1, "2026-11-01 00:00", "2026-10-16 12:00", 5000, 16},
+		{"whole month left", 10000, 20000, 1, "2026-11-01 00:00", "2026-10-01 00:00", 10000, 31},
+		{"last hour", 10000, 34000, 1, "2026-11-01 00:00", "2026-10-31 23:00", 32, 1},
+		{"quarter of a year left", 0, 36500, 12, "2027-01-01 00:00", "2026-10-02 00:00", 9100, 91},
+		{"quarterly", 27000, 54000, 3, "2027-01-01 00:00", "2026-11-16 00:00", 13500, 46},
+		{"stacked periods are charged for one period", 10000, 20000, 1, "2026-12-01 00:00", "2026-10-01 00:00", 10000, 30},
+		{"downgrade", 20000, 10000, 1, "2026-11-01 00:00", "2026-10-16 00:00", 0, 0},
+		{"same price", 10000, 10000, 1, "2026-11-01 00:00", "2026-10-16 00:00", 0, 0},
+		{"expired", 10000, 20000, 1, "2026-10-01 00:00", "2026-10-16 00:00", 0, 0},
+		{"expires now", 10000, 20000, 1, "2026-10-16 00:00", "2026-10-16 00:00", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, remaining := proratedUpgradeAmount(tt.currentPrice, tt.newPrice, tt.periodMonths, at(tt.expiresAt), at(tt.now))
+			if amount != tt.wantAmount || remaining != tt.wantRemaining {
+				t.Errorf("proratedUpgradeAmount() = %d, %d days, want %d, %d days", amount, remaining, tt.wantAmount, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestRefundedPeriodSeconds(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	day := int64(24 * 60 * 60)
+	payment := func(amount, refunded int64, days int) *models.PaymentHistory {
+		end := start.AddDate(0, 0, days)
+		return &models.PaymentHistory{Amount: amount, RefundedAmount: refunded, PeriodStart: &start, PeriodEnd: &end}
+	}
+
+	tests := []struct {
+		name         string
+		payment      *models.PaymentHistory
+		refundAmount int64
+		want         int64
+	}{
+		{"full refund", payment(30000, 0, 30), 30000, 30 * day},
+		{"half refund", payment(30000, 0, 30), 15000, 15 * day},
+		{"third refund", payment(30000, 0, 30), 10000, 10 * day},
+		// A first refund of 10000 already took 10 of the 30 days back
+		{"rest after a partial refund", payment(30000, 10000, 20), 20000, 20 * day},
+		{"half of the rest after a partial refund", payment(30000, 10000, 20), 10000, 10 * day},
+		{"rounds to a second", payment(3, 0, 1), 1, 28800},
+		{"top-up without a period", &models.PaymentHistory{Amount: 30000}, 30000, 0},
+		{"empty period", payment(30000, 0, 0), 30000, 0},
+		{"nothing left to refund", payment(30000, 30000, 30), 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refundedPeriodSeconds(tt.payment, tt.refundAmount); got != tt.want {
+				t.Errorf("refundedPeriodSeconds(%d) = %d, want %d", tt.refundAmount, got, tt.want)
+			}
+		})
+	}
+}
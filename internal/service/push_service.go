@@ -0,0 +1,236 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"mobilka/internal/repository"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// fcmScope is the OAuth scope of the FCM HTTP v1 API
+	fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+	// fcmSendURL is the send endpoint of the FCM HTTP v1 API for a project
+	fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+)
+
+// errFCMTokenUnregistered means the app was uninstalled or the token was replaced
+var errFCMTokenUnregistered = fmt.Errorf("fcm token is no longer registered")
+
+// fcmServiceAccount holds the fields of a Firebase service account key file used to send
+// push notifications
+type fcmServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// PushService sends push notifications to the devices of customers through Firebase Cloud
+// Messaging. Without a service account it logs the notifications instead of sending them.
+type PushService struct {
+	client       *http.Client
+	fcmTokenRepo *repository.FCMTokenRepository
+	account      *fcmServiceAccount
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewPushService creates a new push service with the Firebase service account key file
+// at credentialsFile
+func NewPushService(credentialsFile string, fcmTokenRepo *repository.FCMTokenRepository) *PushService {
+	s := &PushService{
+		client:       &http.Client{Timeout: 10 * time.Second},
+		fcmTokenRepo: fcmTokenRepo,
+	}
+
+	if credentialsFile == "" {
+		return s
+	}
+
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		log.Printf("Push notifications disabled, failed to read FCM credentials: %v", err)
+		return s
+	}
+
+	var account fcmServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil || account.ProjectID == "" || account.PrivateKey == "" {
+		log.Printf("Push notifications disabled, invalid FCM credentials file %s", credentialsFile)
+		return s
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	s.account = &account
+	return s
+}
+
+// SendToCustomer sends a notification to every device a customer is logged in on. Tokens
+// FCM no longer knows are removed.
+func (s *PushService) SendToCustomer(ctx context.Context, customerID int, title, body string, data map[string]string) error {
+	tokens, err := s.fcmTokenRepo.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	if s.account == nil {
+		log.Printf("Push notification to customer %d not sent, FCM is not configured: %s", customerID, title)
+		return nil
+	}
+
+	accessToken, err := s.getAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		err := s.send(ctx, accessToken, token.FCMToken, title, body, data)
+		if err == errFCMTokenUnregistered {
+			if err := s.fcmTokenRepo.DeleteByToken(ctx, token.FCMToken); err != nil {
+				log.Printf("Failed to remove unregistered FCM token %d: %v", token.ID, err)
+			}
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to send push notification to FCM token %d: %v", token.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// send sends one notification to one device
+func (s *PushService) send(ctx context.Context, accessToken, token, title, body string, data map[string]string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": token,
+			"notification": map[string]string{
+				"title": title,
+				"body":  body,
+			},
+			"data": data,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal fcm message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(fcmSendURL, s.account.ProjectID), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create fcm request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call fcm: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var result struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+
+	if resp.StatusCode == http.StatusNotFound || result.Error.Status == "UNREGISTERED" {
+		return errFCMTokenUnregistered
+	}
+
+	return fmt.Errorf("fcm send failed with status %d: %s", resp.StatusCode, result.Error.Message)
+}
+
+// fcmAssertionClaims are the claims of the JWT exchanged for an OAuth access token
+type fcmAssertionClaims struct {
+	Scope string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// getAccessToken returns an OAuth access token of the service account, reusing it until
+// shortly before it expires
+func (s *PushService) getAccessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && time.Now().Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(s.account.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse fcm private key: %w", err)
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, fcmAssertionClaims{
+		Scope: fcmScope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.account.ClientEmail,
+			Audience:  jwt.ClaimStrings{s.account.TokenURI},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}).SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign fcm assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create fcm token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get fcm access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode fcm token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.AccessToken == "" {
+		return "", fmt.Errorf("fcm access token request failed: %s", result.ErrorDescription)
+	}
+
+	// Renew a minute early so a token doesn't expire mid-send
+	s.accessToken = result.AccessToken
+	s.expiresAt = now.Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return s.accessToken, nil
+}
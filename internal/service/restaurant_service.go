@@ -47,14 +47,9 @@ func (s *RestaurantService) GetByID(ctx context.Context, id int) (*models.Restau
 	return s.restaurantRepo.GetByID(ctx, id)
 }
 
-// GetByAdminID retrieves all restaurants for a specific admin
-func (s *RestaurantService) GetByAdminID(ctx context.Context, adminID int) ([]*models.Restaurant, error) {
-	return s.restaurantRepo.GetByAdminID(ctx, adminID)
-}
-
-// GetAll retrieves all restaurants
-func (s *RestaurantService) GetAll(ctx context.Context) ([]*models.Restaurant, error) {
-	return s.restaurantRepo.GetAll(ctx)
+// List retrieves one page of restaurants
+func (s *RestaurantService) List(ctx context.Context, params models.ListParams) ([]*models.Restaurant, *models.ListMeta, error) {
+	return s.restaurantRepo.List(ctx, params)
 }
 
 // Update updates a restaurant
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+)
+
+const (
+	// smsAPIURL is the base URL of the Eskiz SMS gateway the admins' SMS credentials belong to
+	smsAPIURL = "https://notify.eskiz.uz/api"
+
+	// smsTokenLifetime is how long a gateway token is reused. Eskiz tokens expire after
+	// 30 days, so they are renewed a day early.
+	smsTokenLifetime = 29 * 24 * time.Hour
+
+	// smsSender is the sender name of messages sent without an own alpha name
+	smsSender = "4546"
+)
+
+// errSmsUnauthorized means the gateway rejected the token
+var errSmsUnauthorized = fmt.Errorf("sms gateway rejected the token")
+
+// SmsService sends SMS messages through the gateway account of an admin
+type SmsService struct {
+	client    *http.Client
+	adminRepo *repository.AdminRepository
+}
+
+// NewSmsService creates a new SMS service
+func NewSmsService(adminRepo *repository.AdminRepository) *SmsService {
+	return &SmsService{
+		client:    &http.Client{Timeout: 10 * time.Second},
+		adminRepo: adminRepo,
+	}
+}
+
+// IsConfigured reports whether an admin has SMS gateway credentials
+func (s *SmsService) IsConfigured(admin *models.Admin) bool {
+	return admin.SmsEmail != "" && admin.SmsPassword != ""
+}
+
+// Send sends a message to a phone number in international format. The gateway token of
+// the admin is renewed when it is missing, old or rejected.
+func (s *SmsService) Send(ctx context.Context, admin *models.Admin, phone, message string) error {
+	if !s.IsConfigured(admin) {
+		return fmt.Errorf("sms gateway credentials are not configured")
+	}
+
+	token := admin.SmsToken
+	if token == "" || time.Since(admin.SmsTokenUpdatedTime) > smsTokenLifetime {
+		var err error
+		if token, err = s.refreshToken(ctx, admin); err != nil {
+			return err
+		}
+	}
+
+	err := s.send(ctx, token, phone, message)
+	if err == errSmsUnauthorized {
+		if token, err = s.refreshToken(ctx, admin); err != nil {
+			return err
+		}
+		err = s.send(ctx, token, phone, message)
+	}
+
+	return err
+}
+
+// refreshToken logs in to the gateway with the admin's credentials and saves the new token
+func (s *SmsService) refreshToken(ctx context.Context, admin *models.Admin) (string, error) {
+	form := url.Values{
+		"email":    {admin.SmsEmail},
+		"password": {admin.SmsPassword},
+	}
+
+	var result struct {
+		Message string `json:"message"`
+		Data    struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+
+	status, err := s.post(ctx, "/auth/login", "", form, &result)
+	if err != nil {
+		return "", err
+	}
+	if status != http.StatusOK || result.Data.Token == "" {
+		return "", fmt.Errorf("sms gateway login failed: %s", result.Message)
+	}
+
+	if err := s.adminRepo.UpdateSmsToken(ctx, admin.ID, result.Data.Token); err != nil {
+		return "", fmt.Errorf("failed to save sms token: %w", err)
+	}
+
+	admin.SmsToken = result.Data.Token
+	admin.SmsTokenUpdatedTime = time.Now()
+	return result.Data.Token, nil
+}
+
+// send sends one message with a gateway token
+func (s *SmsService) send(ctx context.Context, token, phone, message string) error {
+	form := url.Values{
+		// The gateway takes the number without the leading plus
+		"mobile_phone": {strings.TrimPrefix(phone, "+")},
+		"message":      {message},
+		"from":         {smsSender},
+	}
+
+	var result struct {
+		Message string `json:"message"`
+	}
+
+	status, err := s.post(ctx, "/message/sms/send", token, form, &result)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusUnauthorized {
+		return errSmsUnauthorized
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("sms gateway send failed: %s", result.Message)
+	}
+
+	return nil
+}
+
+// post posts a form to a gateway endpoint and decodes the JSON response into result
+func (s *SmsService) post(ctx context.Context, path, token string, form url.Values, result interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, smsAPIURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create sms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call sms gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return resp.StatusCode, nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to decode sms gateway response: %w", err)
+	}
+
+	return resp.StatusCode, nil
+}
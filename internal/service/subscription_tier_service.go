@@ -5,6 +5,7 @@ import (
 
 	"mobilka/internal/models"
 	"mobilka/internal/repository"
+	"mobilka/internal/utils"
 )
 
 // SubscriptionTierService handles subscription tier operations
@@ -21,11 +22,22 @@ func NewSubscriptionTierService(subscriptionTierRepo *repository.SubscriptionTie
 
 // Create creates a new subscription tier
 func (s *SubscriptionTierService) Create(ctx context.Context, req *models.SubscriptionTierCreateRequest) (*models.SubscriptionTier, error) {
+	currency := utils.NormalizeCurrency(req.Currency)
+	if !utils.IsSupportedCurrency(currency) {
+		return nil, utils.NewInvalidInputError("Unsupported currency " + currency)
+	}
+
+	if err := validateIntervalPrices(req.IntervalPrices); err != nil {
+		return nil, err
+	}
+
 	tier := &models.SubscriptionTier{
 		Name:        req.Name,
 		MinUsers:    req.MinUsers,
 		MaxUsers:    req.MaxUsers,
 		Price:       req.Price,
+		Currency:    currency,
+		TrialDays:   req.TrialDays,
 		Description: req.Description,
 	}
 
@@ -34,17 +46,44 @@ func (s *SubscriptionTierService) Create(ctx context.Context, req *models.Subscr
 		return nil, err
 	}
 
+	if len(req.IntervalPrices) > 0 {
+		if err := s.subscriptionTierRepo.SetPrices(ctx, tier.ID, req.IntervalPrices); err != nil {
+			return nil, err
+		}
+		if err := s.subscriptionTierRepo.LoadPrices(ctx, tier); err != nil {
+			return nil, err
+		}
+	}
+
 	return tier, nil
 }
 
 // GetByID retrieves a subscription tier by ID
 func (s *SubscriptionTierService) GetByID(ctx context.Context, id int) (*models.SubscriptionTier, error) {
-	return s.subscriptionTierRepo.GetByID(ctx, id)
+	tier, err := s.subscriptionTierRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionTierRepo.LoadPrices(ctx, tier); err != nil {
+		return nil, err
+	}
+
+	return tier, nil
 }
 
-// GetAll retrieves all subscription tiers
-func (s *SubscriptionTierService) GetAll(ctx context.Context) ([]*models.SubscriptionTier, error) {
-	return s.subscriptionTierRepo.GetAll(ctx)
+// List retrieves one page of subscription tiers together with their prices
+func (s *SubscriptionTierService) List(ctx context.Context, params models.ListParams) ([]*models.SubscriptionTier, *models.ListMeta, error) {
+	tiers, meta, err := s.subscriptionTierRepo.List(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.subscriptionTierRepo.LoadPrices(ctx, tiers...); err != nil {
+		return nil, nil, err
+	}
+
+	return tiers, meta, nil
 }
 
 // Update updates a subscription tier
@@ -74,19 +113,61 @@ func (s *SubscriptionTierService) Update(ctx context.Context, id int, req *model
 		tier.Price = req.Price
 	}
 
+	if req.Currency != "" {
+		currency := utils.NormalizeCurrency(req.Currency)
+		if !utils.IsSupportedCurrency(currency) {
+			return nil, utils.NewInvalidInputError("Unsupported currency " + currency)
+		}
+		tier.Currency = currency
+	}
+
+	if req.TrialDays != nil {
+		tier.TrialDays = *req.TrialDays
+	}
+
 	if req.Description != "" {
 		tier.Description = req.Description
 	}
 
+	if err := validateIntervalPrices(req.IntervalPrices); err != nil {
+		return nil, err
+	}
+
 	// Update in database
 	err = s.subscriptionTierRepo.Update(ctx, id, tier)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(req.IntervalPrices) > 0 {
+		if err := s.subscriptionTierRepo.SetPrices(ctx, id, req.IntervalPrices); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.subscriptionTierRepo.LoadPrices(ctx, tier); err != nil {
+		return nil, err
+	}
+
 	return tier, nil
 }
 
+// validateIntervalPrices checks interval names and amounts of interval-specific prices
+func validateIntervalPrices(prices map[string]*int64) error {
+	for interval, price := range prices {
+		if _, ok := models.BillingIntervalMonths[interval]; !ok {
+			return utils.NewInvalidInputError("Unknown billing interval " + interval)
+		}
+		if interval == models.BillingIntervalMonthly {
+			return utils.NewInvalidInputError("The monthly price is set with price")
+		}
+		if price != nil && *price < 0 {
+			return utils.NewInvalidInputError("Price for " + interval + " can't be negative")
+		}
+	}
+	return nil
+}
+
 // Delete deletes a subscription tier
 func (s *SubscriptionTierService) Delete(ctx context.Context, id int) error {
 	return s.subscriptionTierRepo.Delete(ctx, id)
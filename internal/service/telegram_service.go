@@ -0,0 +1,137 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"mobilka/internal/models"
+)
+
+// telegramAPIURL is the base URL of the Telegram Bot API
+const telegramAPIURL = "https://api.telegram.org"
+
+// TelegramAPIError is a request the Bot API refused. Its description is Telegram's own
+// explanation and safe to show to the admin.
+type TelegramAPIError struct {
+	Method      string
+	Description string
+}
+
+func (e *TelegramAPIError) Error() string {
+	return fmt.Sprintf("telegram %s failed: %s", e.Method, e.Description)
+}
+
+// TelegramService sends messages through the Telegram Bot API using an admin's bot
+type TelegramService struct {
+	client *http.Client
+}
+
+// NewTelegramService creates a new Telegram service
+func NewTelegramService() *TelegramService {
+	return &TelegramService{
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendMessage sends a text message to a chat with the given bot token
+func (s *TelegramService) SendMessage(ctx context.Context, botToken, chatID, text string) error {
+	return s.call(ctx, botToken, "sendMessage", map[string]interface{}{
+		"chat_id": chatID,
+		"text":    text,
+	})
+}
+
+// SendMessageWithButtons sends a text message with rows of inline buttons under it
+func (s *TelegramService) SendMessageWithButtons(ctx context.Context, botToken, chatID, text string, buttons [][]models.TelegramInlineButton) error {
+	return s.call(ctx, botToken, "sendMessage", map[string]interface{}{
+		"chat_id": chatID,
+		"text":    text,
+		"reply_markup": map[string]interface{}{
+			"inline_keyboard": buttons,
+		},
+	})
+}
+
+// EditMessageText replaces the text of a sent message, removing its inline buttons
+func (s *TelegramService) EditMessageText(ctx context.Context, botToken string, chatID int64, messageID int, text string) error {
+	return s.call(ctx, botToken, "editMessageText", map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+		"text":       text,
+	})
+}
+
+// AnswerCallbackQuery ends the loading state of a pressed inline button, showing text
+// to the user who pressed it
+func (s *TelegramService) AnswerCallbackQuery(ctx context.Context, botToken, callbackQueryID, text string) error {
+	return s.call(ctx, botToken, "answerCallbackQuery", map[string]interface{}{
+		"callback_query_id": callbackQueryID,
+		"text":              text,
+	})
+}
+
+// SetWebhook makes Telegram send the button presses of a bot to url. Telegram sends the
+// secret in the X-Telegram-Bot-Api-Secret-Token header of every update.
+func (s *TelegramService) SetWebhook(ctx context.Context, botToken, url, secret string) error {
+	return s.call(ctx, botToken, "setWebhook", map[string]interface{}{
+		"url":             url,
+		"secret_token":    secret,
+		"allowed_updates": []string{"callback_query"},
+	})
+}
+
+// call invokes a Bot API method with a JSON payload
+func (s *TelegramService) call(ctx context.Context, botToken, method string, payload interface{}) error {
+	if botToken == "" {
+		return fmt.Errorf("telegram bot token is not configured")
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram payload: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/%s", telegramAPIURL, botToken, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telegram request: %w", withoutURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call telegram %s: %w", method, withoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode telegram response: %w", err)
+	}
+
+	if !result.OK {
+		return &TelegramAPIError{Method: method, Description: result.Description}
+	}
+
+	return nil
+}
+
+// withoutURL strips the request URL from an HTTP client error. Bot API URLs carry the bot
+// token, which must not end up in logs or responses.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+
+	return err
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+	"mobilka/internal/utils"
+)
+
+// TierChangeService keeps admins on the subscription tier that matches their usage
+type TierChangeService struct {
+	adminRepo            *repository.AdminRepository
+	subscriptionTierRepo *repository.SubscriptionTierRepository
+	tierChangeRepo       *repository.SubscriptionTierChangeRepository
+	telegramService      *TelegramService
+}
+
+// NewTierChangeService creates a new tier change service
+func NewTierChangeService(
+	adminRepo *repository.AdminRepository,
+	subscriptionTierRepo *repository.SubscriptionTierRepository,
+	tierChangeRepo *repository.SubscriptionTierChangeRepository,
+	telegramService *TelegramService,
+) *TierChangeService {
+	return &TierChangeService{
+		adminRepo:            adminRepo,
+		subscriptionTierRepo: subscriptionTierRepo,
+		tierChangeRepo:       tierChangeRepo,
+		telegramService:      telegramService,
+	}
+}
+
+// RecalculateTiers applies due tier changes and schedules new ones based on user counts.
+// It returns the number of changes scheduled and applied.
+func (s *TierChangeService) RecalculateTiers(ctx context.Context) (int, int, error) {
+	admins, err := s.adminRepo.GetAllForTierRecalculation(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tiers, err := s.subscriptionTierRepo.GetAll(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tierNames := make(map[int]string)
+	for _, tier := range tiers {
+		tierNames[tier.ID] = tier.Name
+	}
+
+	now := time.Now()
+	scheduled, applied := 0, 0
+
+	for _, admin := range admins {
+		// Pinned admins are managed manually by a super admin
+		if admin.SubscriptionTierPinned {
+			continue
+		}
+
+		// Apply a previously scheduled change once its billing period has started
+		if admin.PendingTierID != nil && admin.PendingTierEffectiveAt != nil && !admin.PendingTierEffectiveAt.After(now) {
+			if err := s.applyPendingTier(ctx, admin); err != nil {
+				log.Printf("Failed to apply pending tier for admin %d: %v", admin.ID, err)
+				continue
+			}
+			applied++
+			continue
+		}
+
+		tier, err := s.subscriptionTierRepo.GetTierForUserCount(ctx, admin.Users)
+		if err != nil {
+			log.Printf("No subscription tier for admin %d with %d users: %v", admin.ID, admin.Users, err)
+			continue
+		}
+
+		// Usage went back to the current tier, so a scheduled change is no longer needed
+		if admin.SubscriptionTierID != nil && *admin.SubscriptionTierID == tier.ID {
+			if admin.PendingTierID != nil {
+				if err := s.cancelPendingTier(ctx, admin.ID); err != nil {
+					log.Printf("Failed to cancel pending tier for admin %d: %v", admin.ID, err)
+				}
+			}
+			continue
+		}
+
+		// The change is already scheduled
+		if admin.PendingTierID != nil && *admin.PendingTierID == tier.ID {
+			continue
+		}
+
+		isApplied, err := s.scheduleTierChange(ctx, admin, tier, now)
+		if err != nil {
+			log.Printf("Failed to schedule tier change for admin %d: %v", admin.ID, err)
+			continue
+		}
+
+		if isApplied {
+			applied++
+		} else {
+			scheduled++
+		}
+
+		s.notifyTierChange(ctx, admin, tierNames, tier, isApplied)
+	}
+
+	return scheduled, applied, nil
+}
+
+// scheduleTierChange schedules a usage-based tier change for the next billing period.
+// Admins without a running period are moved right away; the result reports whether
+// the change was applied immediately.
+func (s *TierChangeService) scheduleTierChange(ctx context.Context, admin *models.Admin, tier *models.SubscriptionTier, now time.Time) (bool, error) {
+	if admin.PendingTierID != nil {
+		if err := s.cancelPendingTier(ctx, admin.ID); err != nil {
+			return false, err
+		}
+	}
+
+	change := &models.SubscriptionTierChange{
+		AdminID:     admin.ID,
+		OldTierID:   admin.SubscriptionTierID,
+		NewTierID:   &tier.ID,
+		UserCount:   admin.Users,
+		Reason:      "usage",
+		EffectiveAt: now,
+	}
+
+	if admin.HasActivePeriod(now) {
+		change.Status = "scheduled"
+		change.EffectiveAt = *admin.SubscriptionExpiresAt
+
+		if err := s.adminRepo.SetPendingTier(ctx, admin.ID, &tier.ID, &change.EffectiveAt); err != nil {
+			return false, err
+		}
+	} else {
+		change.Status = "applied"
+
+		if err := s.adminRepo.UpdateSubscriptionTier(ctx, admin.ID, &tier.ID, false); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.tierChangeRepo.Create(ctx, change); err != nil {
+		return false, err
+	}
+
+	log.Printf("Subscription tier change %s for admin %d: %v -> %d (%d users, effective %s)",
+		change.Status, admin.ID, formatTierID(admin.SubscriptionTierID), tier.ID, admin.Users,
+		change.EffectiveAt.Format(time.RFC3339))
+
+	return change.Status == "applied", nil
+}
+
+// applyPendingTier moves an admin to their scheduled tier
+func (s *TierChangeService) applyPendingTier(ctx context.Context, admin *models.Admin) error {
+	if err := s.adminRepo.UpdateSubscriptionTier(ctx, admin.ID, admin.PendingTierID, false); err != nil {
+		return err
+	}
+
+	if err := s.tierChangeRepo.UpdateScheduledStatus(ctx, admin.ID, "applied"); err != nil {
+		return err
+	}
+
+	log.Printf("Applied scheduled subscription tier %d for admin %d", *admin.PendingTierID, admin.ID)
+	return nil
+}
+
+// cancelPendingTier clears an admin's scheduled tier change
+func (s *TierChangeService) cancelPendingTier(ctx context.Context, adminID int) error {
+	if err := s.adminRepo.SetPendingTier(ctx, adminID, nil, nil); err != nil {
+		return err
+	}
+
+	return s.tierChangeRepo.UpdateScheduledStatus(ctx, adminID, "cancelled")
+}
+
+// notifyTierChange tells the admin about a tier change through their Telegram bot
+func (s *TierChangeService) notifyTierChange(ctx context.Context, admin *models.Admin, tierNames map[int]string, tier *models.SubscriptionTier, isApplied bool) {
+	if admin.BotToken == "" || admin.BotChatID == "" {
+		return
+	}
+
+	oldName := "none"
+	if admin.SubscriptionTierID != nil {
+		oldName = tierNames[*admin.SubscriptionTierID]
+	}
+
+	var text string
+	if isApplied {
+		text = fmt.Sprintf("%s: your subscription tier changed from %s to %s because your app now has %d users.",
+			admin.CompanyName, oldName, tier.Name, admin.Users)
+	} else {
+		text = fmt.Sprintf("%s: your app now has %d users. Your subscription tier will change from %s to %s on %s.",
+			admin.CompanyName, admin.Users, oldName, tier.Name, admin.SubscriptionExpiresAt.Format("2006-01-02"))
+	}
+
+	if err := s.telegramService.SendMessage(ctx, admin.BotToken, admin.BotChatID, text); err != nil {
+		log.Printf("Failed to notify admin %d about tier change: %v", admin.ID, err)
+	}
+}
+
+// PinTier pins an admin to a tier chosen by a super admin, or releases the pin
+func (s *TierChangeService) PinTier(ctx context.Context, adminID int, superAdminID int, req *models.SubscriptionTierPinRequest) (*models.Admin, error) {
+	admin, _, err := s.adminRepo.GetByIDWithSubscriptionInfo(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	tierID := admin.SubscriptionTierID
+	if req.SubscriptionTierID != nil {
+		if _, err := s.subscriptionTierRepo.GetByID(ctx, *req.SubscriptionTierID); err != nil {
+			return nil, err
+		}
+		tierID = req.SubscriptionTierID
+	}
+
+	if req.Pinned && tierID == nil {
+		return nil, utils.NewInvalidInputError("Subscription tier is required to pin an admin")
+	}
+
+	if admin.PendingTierID != nil {
+		if err := s.tierChangeRepo.UpdateScheduledStatus(ctx, adminID, "cancelled"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.adminRepo.UpdateSubscriptionTier(ctx, adminID, tierID, req.Pinned); err != nil {
+		return nil, err
+	}
+
+	// Log the manual change when the tier actually moved
+	if !sameTier(admin.SubscriptionTierID, tierID) {
+		change := &models.SubscriptionTierChange{
+			AdminID:     adminID,
+			OldTierID:   admin.SubscriptionTierID,
+			NewTierID:   tierID,
+			UserCount:   admin.Users,
+			Reason:      "manual",
+			Status:      "applied",
+			EffectiveAt: time.Now(),
+			ChangedBy:   &superAdminID,
+		}
+		if err := s.tierChangeRepo.Create(ctx, change); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("Super admin %d set subscription tier %v for admin %d (pinned: %t)",
+		superAdminID, formatTierID(tierID), adminID, req.Pinned)
+
+	admin.SubscriptionTierID = tierID
+	admin.SubscriptionTierPinned = req.Pinned
+	admin.PendingTierID = nil
+	admin.PendingTierEffectiveAt = nil
+
+	return admin, nil
+}
+
+// GetChangesByAdminID retrieves one page of the tier change log of an admin
+func (s *TierChangeService) GetChangesByAdminID(ctx context.Context, adminID int, params models.ListParams) ([]*models.SubscriptionTierChange, *models.ListMeta, error) {
+	return s.tierChangeRepo.ListByAdminID(ctx, adminID, params)
+}
+
+// sameTier reports whether two optional tier IDs point to the same tier
+func sameTier(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// formatTierID formats an optional tier ID for logging
+func formatTierID(id *int) string {
+	if id == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%d", *id)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"mobilka/internal/models"
+	"mobilka/internal/repository"
+)
+
+// UsageService meters unique devices per admin and maintains active-user rollups
+type UsageService struct {
+	usageRepo *repository.UsageRepository
+	adminRepo *repository.AdminRepository
+}
+
+// NewUsageService creates a new usage service
+func NewUsageService(
+	usageRepo *repository.UsageRepository,
+	adminRepo *repository.AdminRepository,
+) *UsageService {
+	return &UsageService{
+		usageRepo: usageRepo,
+		adminRepo: adminRepo,
+	}
+}
+
+// RefreshRollups recomputes active-user rollups and the billable users of every admin.
+// Billing uses the higher of the previous and the current month's active users, so
+// counts don't drop to zero at the start of a month.
+func (s *UsageService) RefreshRollups(ctx context.Context) error {
+	now := time.Now().UTC()
+	previousMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+
+	if err := s.usageRepo.RefreshRollups(ctx, previousMonth); err != nil {
+		return err
+	}
+
+	if _, err := s.usageRepo.UpdateBillableUsers(ctx, previousMonth); err != nil {
+		return err
+	}
+
+	// Older months are final, so their raw activity is no longer needed
+	deleted, err := s.usageRepo.DeleteActivityBefore(ctx, previousMonth)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		log.Printf("Removed %d rolled up device activity rows", deleted)
+	}
+
+	return nil
+}
+
+// GetUsage retrieves daily and monthly active users of an admin within a date range
+func (s *UsageService) GetUsage(ctx context.Context, adminID int, from, to time.Time) (*models.UsageResponse, error) {
+	admin, err := s.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	daily, err := s.usageRepo.GetActiveUsers(ctx, adminID, "day", from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	monthStart := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	monthly, err := s.usageRepo.GetActiveUsers(ctx, adminID, "month", monthStart, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UsageResponse{
+		AdminID: adminID,
+		Users:   admin.Users,
+		Daily:   daily,
+		Monthly: monthly,
+	}, nil
+}
+
+// hashDeviceKey turns a device ID or FCM token into the key stored for metering
+func hashDeviceKey(deviceID string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(deviceID)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,63 @@
+package tasks
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"mobilka/internal/service"
+)
+
+// EventListener keeps the event service listening to the events of every replica,
+// reconnecting when the connection drops
+type EventListener struct {
+	eventService *service.EventService
+	retryDelay   time.Duration
+	cancel       context.CancelFunc
+	done         chan struct{}
+}
+
+// NewEventListener creates a new event listener task
+func NewEventListener(eventService *service.EventService, retryDelay time.Duration) *EventListener {
+	return &EventListener{
+		eventService: eventService,
+		retryDelay:   retryDelay,
+		done:         make(chan struct{}),
+	}
+}
+
+// Start starts the event listener task
+func (el *EventListener) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	el.cancel = cancel
+
+	go func() {
+		defer close(el.done)
+
+		for {
+			err := el.eventService.Listen(ctx)
+			if ctx.Err() != nil {
+				log.Println("Event listener stopped")
+				return
+			}
+
+			log.Printf("Event listener disconnected: %v. Retrying in %v...", err, el.retryDelay)
+
+			select {
+			case <-time.After(el.retryDelay):
+			case <-ctx.Done():
+				log.Println("Event listener stopped")
+				return
+			}
+		}
+	}()
+
+	log.Println("Event listener started")
+}
+
+// Stop stops the event listener task and ends every event subscription
+func (el *EventListener) Stop() {
+	el.cancel()
+	<-el.done
+	el.eventService.Close()
+}
@@ -0,0 +1,71 @@
+package tasks
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"mobilka/internal/service"
+)
+
+// LoyaltyExpiry periodically writes off expired loyalty points and reminds customers of
+// points about to expire
+type LoyaltyExpiry struct {
+	loyaltyService *service.LoyaltyService
+	interval       time.Duration
+	stopChan       chan struct{}
+}
+
+// NewLoyaltyExpiry creates a new loyalty expiry task
+func NewLoyaltyExpiry(loyaltyService *service.LoyaltyService, interval time.Duration) *LoyaltyExpiry {
+	return &LoyaltyExpiry{
+		loyaltyService: loyaltyService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start starts the loyalty expiry task
+func (le *LoyaltyExpiry) Start() {
+	go func() {
+		ticker := time.NewTicker(le.interval)
+		defer ticker.Stop()
+
+		// Run immediately on start
+		le.processPoints()
+
+		for {
+			select {
+			case <-ticker.C:
+				le.processPoints()
+			case <-le.stopChan:
+				log.Println("Loyalty expiry stopped")
+				return
+			}
+		}
+	}()
+
+	log.Printf("Loyalty expiry started with interval: %s", le.interval)
+}
+
+// Stop stops the loyalty expiry task
+func (le *LoyaltyExpiry) Stop() {
+	close(le.stopChan)
+}
+
+// processPoints expires points past their expiry, then sends the due reminders
+func (le *LoyaltyExpiry) processPoints() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	expired, err := le.loyaltyService.ExpirePoints(ctx)
+	if err != nil {
+		log.Printf("Error expiring loyalty points: %v", err)
+	} else if expired > 0 {
+		log.Printf("Expired %d loyalty points", expired)
+	}
+
+	if err := le.loyaltyService.NotifyExpiring(ctx); err != nil {
+		log.Printf("Error sending loyalty expiry reminders: %v", err)
+	}
+}
@@ -57,6 +57,14 @@ func (sc *SubscriptionChecker) checkExpiredSubscriptions() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Renew what prepaid balances cover before anything is expired
+	renewed, err := sc.paymentService.RenewSubscriptionsFromBalance(ctx)
+	if err != nil {
+		log.Printf("Error renewing subscriptions from balance: %v", err)
+	} else if renewed > 0 {
+		log.Printf("Renewed %d subscriptions from prepaid balance", renewed)
+	}
+
 	// Try to expire subscriptions, but handle database schema issues gracefully
 	count, err := sc.paymentService.ExpireSubscriptions(ctx)
 	if err != nil {
@@ -0,0 +1,69 @@
+package tasks
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"mobilka/internal/service"
+)
+
+// TierRecalculator periodically moves admins to the subscription tier matching their usage
+type TierRecalculator struct {
+	tierChangeService *service.TierChangeService
+	interval          time.Duration
+	stopChan          chan struct{}
+}
+
+// NewTierRecalculator creates a new tier recalculator
+func NewTierRecalculator(tierChangeService *service.TierChangeService, interval time.Duration) *TierRecalculator {
+	return &TierRecalculator{
+		tierChangeService: tierChangeService,
+		interval:          interval,
+		stopChan:          make(chan struct{}),
+	}
+}
+
+// Start starts the tier recalculator
+func (tr *TierRecalculator) Start() {
+	go func() {
+		ticker := time.NewTicker(tr.interval)
+		defer ticker.Stop()
+
+		// Run immediately on start
+		tr.recalculateTiers()
+
+		for {
+			select {
+			case <-ticker.C:
+				tr.recalculateTiers()
+			case <-tr.stopChan:
+				log.Println("Tier recalculator stopped")
+				return
+			}
+		}
+	}()
+
+	log.Printf("Tier recalculator started with interval: %s", tr.interval)
+}
+
+// Stop stops the tier recalculator
+func (tr *TierRecalculator) Stop() {
+	close(tr.stopChan)
+}
+
+// recalculateTiers applies due tier changes and schedules new ones
+func (tr *TierRecalculator) recalculateTiers() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	scheduled, applied, err := tr.tierChangeService.RecalculateTiers(ctx)
+	if err != nil {
+		log.Printf("Error recalculating subscription tiers: %v", err)
+		return
+	}
+
+	if scheduled > 0 || applied > 0 {
+		log.Printf("Subscription tiers recalculated: %d scheduled, %d applied", scheduled, applied)
+	}
+}
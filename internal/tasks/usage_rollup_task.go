@@ -0,0 +1,63 @@
+package tasks
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"mobilka/internal/service"
+)
+
+// UsageRollup periodically recomputes active-user rollups and billable user counts
+type UsageRollup struct {
+	usageService *service.UsageService
+	interval     time.Duration
+	stopChan     chan struct{}
+}
+
+// NewUsageRollup creates a new usage rollup task
+func NewUsageRollup(usageService *service.UsageService, interval time.Duration) *UsageRollup {
+	return &UsageRollup{
+		usageService: usageService,
+		interval:     interval,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start starts the usage rollup task
+func (ur *UsageRollup) Start() {
+	go func() {
+		ticker := time.NewTicker(ur.interval)
+		defer ticker.Stop()
+
+		// Run immediately on start
+		ur.refreshRollups()
+
+		for {
+			select {
+			case <-ticker.C:
+				ur.refreshRollups()
+			case <-ur.stopChan:
+				log.Println("Usage rollup stopped")
+				return
+			}
+		}
+	}()
+
+	log.Printf("Usage rollup started with interval: %s", ur.interval)
+}
+
+// Stop stops the usage rollup task
+func (ur *UsageRollup) Stop() {
+	close(ur.stopChan)
+}
+
+// refreshRollups recomputes daily and monthly active users
+func (ur *UsageRollup) refreshRollups() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := ur.usageService.RefreshRollups(ctx); err != nil {
+		log.Printf("Error refreshing usage rollups: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ package utils
 const (
 	RoleSuperAdmin = "superadmin"
 	RoleAdmin      = "admin"
+	RoleCustomer   = "customer"
 )
 
 // Image upload constants
@@ -12,10 +13,23 @@ const (
 	MaxImageSize    = 10 * 1024 * 1024 // 10MB
 )
 
+// Payment proof upload constants
+const (
+	PaymentProofUploadPath   = "./uploads/payment-proofs/"
+	MaxPaymentAttachments    = 5
+	MaxPaymentAttachmentSize = MaxImageSize
+)
+
+// MaxRequestBodySize fits the largest upload, a full set of payment attachments, plus 1MB
+// for other data
+const MaxRequestBodySize = MaxPaymentAttachments*MaxPaymentAttachmentSize + 1024*1024
+
 // Context keys
 const (
-	ContextUserID   = "userID"
-	ContextUserRole = "userRole"
+	ContextUserID    = "userID"
+	ContextUserRole  = "userRole"
+	ContextAdminID   = "adminID" // The admin a customer belongs to
+	ContextRequestID = "requestid"
 )
 
 // Response status messages
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Currency constants
+const (
+	CurrencyUZS     = "UZS"
+	CurrencyUSD     = "USD"
+	CurrencyEUR     = "EUR"
+	CurrencyRUB     = "RUB"
+	DefaultCurrency = CurrencyUSD
+)
+
+// currencyMinorDigits holds the number of minor-unit digits of each supported currency
+var currencyMinorDigits = map[string]int{
+	CurrencyUZS: 2,
+	CurrencyUSD: 2,
+	CurrencyEUR: 2,
+	CurrencyRUB: 2,
+}
+
+// NormalizeCurrency upper-cases a currency code and falls back to the default currency
+func NormalizeCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency
+	}
+	return code
+}
+
+// IsSupportedCurrency reports whether a currency code can be used for billing
+func IsSupportedCurrency(code string) bool {
+	_, ok := currencyMinorDigits[code]
+	return ok
+}
+
+// FormatMoney formats an amount in minor units, e.g. 150000 UZS as "1500.00 UZS"
+func FormatMoney(amount int64, currency string) string {
+	return FormatAmount(amount, currency) + " " + currency
+}
+
+// FormatAmount formats an amount in minor units in major units, e.g. 150000 UZS as "1500.00"
+func FormatAmount(amount int64, currency string) string {
+	digits := currencyMinorDigits[currency]
+	if digits == 0 {
+		return fmt.Sprintf("%d", amount)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	scale := int64(1)
+	for i := 0; i < digits; i++ {
+		scale *= 10
+	}
+
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, digits, amount%scale)
+}
@@ -3,6 +3,10 @@ package utils
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // Application errors
@@ -19,11 +23,35 @@ var (
 	ErrImageUpload           = errors.New("image upload failed")
 )
 
+// Error codes returned to clients in the code field of error responses. They are stable,
+// clients branch on them instead of on messages.
+const (
+	CodeInvalidInput         = "INVALID_INPUT"
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeInvalidReference     = "INVALID_REFERENCE"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeInvalidCredentials   = "INVALID_CREDENTIALS"
+	CodeInvalidToken         = "INVALID_TOKEN"
+	CodeForbidden            = "FORBIDDEN"
+	CodeNotFound             = "NOT_FOUND"
+	CodeAdminNotFound        = "ADMIN_NOT_FOUND"
+	CodeConflict             = "CONFLICT"
+	CodeInsufficientBalance  = "INSUFFICIENT_BALANCE"
+	CodeAlreadyExists        = "ALREADY_EXISTS"
+	CodePayloadTooLarge      = "PAYLOAD_TOO_LARGE"
+	CodeSubscriptionRequired = "SUBSCRIPTION_REQUIRED"
+	CodeRateLimited          = "RATE_LIMITED"
+	CodeUnavailable          = "SERVICE_UNAVAILABLE"
+	CodeInternal             = "INTERNAL_ERROR"
+)
+
 // AppError represents an application error
 type AppError struct {
-	Err     error
-	Message string
-	Code    int
+	Err       error
+	Message   string
+	Code      int         // HTTP status
+	ErrorCode string      // Stable error code, derived from Err or Code when empty
+	Details   interface{} // Optional structured details, e.g. the failed fields
 }
 
 // Error returns the error message
@@ -34,6 +62,11 @@ func (e *AppError) Error() string {
 	return e.Err.Error()
 }
 
+// Unwrap returns the underlying error
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 // NewAppError creates a new application error
 func NewAppError(err error, message string, code int) *AppError {
 	return &AppError{
@@ -70,6 +103,16 @@ func NewForbiddenError() *AppError {
 	}
 }
 
+// NewConflictError creates a new conflict error with a stable error code
+func NewConflictError(code string, message string) *AppError {
+	return &AppError{
+		Err:       ErrInvalidInput,
+		Message:   message,
+		Code:      409,
+		ErrorCode: code,
+	}
+}
+
 // NewInvalidInputError creates a new invalid input error
 func NewInvalidInputError(message string) *AppError {
 	if message == "" {
@@ -90,3 +133,156 @@ func NewInternalServerError(err error) *AppError {
 		Code:    500,
 	}
 }
+
+// sentinelErrors maps the application errors to their status, code and default message
+var sentinelErrors = []struct {
+	err     error
+	status  int
+	code    string
+	message string
+}{
+	{ErrInvalidCredentials, 401, CodeInvalidCredentials, "Invalid credentials"},
+	{ErrUserNotFound, 404, CodeAdminNotFound, "Admin not found"},
+	{ErrUnauthorized, 401, CodeUnauthorized, "Unauthorized access"},
+	{ErrForbidden, 403, CodeForbidden, "Forbidden action"},
+	{ErrInvalidToken, 401, CodeInvalidToken, "Invalid token"},
+	{ErrInvalidInput, 400, CodeInvalidInput, "Invalid input data"},
+	{ErrResourceNotFound, 404, CodeNotFound, "Resource not found"},
+	{ErrResourceAlreadyExists, 409, CodeAlreadyExists, "Resource already exists"},
+	{ErrImageUpload, 400, CodeInvalidInput, "Image upload failed"},
+}
+
+// StatusErrorCode returns the generic error code of an HTTP status
+func StatusErrorCode(status int) string {
+	switch {
+	case status == 400:
+		return CodeInvalidInput
+	case status == 401:
+		return CodeUnauthorized
+	case status == 402:
+		return CodeSubscriptionRequired
+	case status == 403:
+		return CodeForbidden
+	case status == 404:
+		return CodeNotFound
+	case status == 409:
+		return CodeConflict
+	case status == 413:
+		return CodePayloadTooLarge
+	case status == 422:
+		return CodeValidationFailed
+	case status == 429:
+		return CodeRateLimited
+	case status == 503:
+		return CodeUnavailable
+	case status >= 500:
+		return CodeInternal
+	default:
+		return CodeInvalidInput
+	}
+}
+
+// ResolveError maps any error to an application error with a status and a stable code:
+// application errors keep their status, validation errors become 422 with the failed
+// fields, the sentinel errors above and PostgreSQL constraint violations (e.g. a duplicate
+// email becomes 409) get their own status, and everything else is an internal error.
+func ResolveError(err error) *AppError {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return &AppError{
+			Err:       err,
+			Message:   "Validation failed",
+			Code:      422,
+			ErrorCode: CodeValidationFailed,
+			Details:   validationErr.Fields,
+		}
+	}
+
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		// A server error wrapping a client error, e.g. a fallback around a unique
+		// violation, is reported as the client error
+		if appErr.Code >= 500 && appErr.Err != nil {
+			if cause := ResolveError(appErr.Err); cause.Code < 500 {
+				return cause
+			}
+		}
+
+		resolved := *appErr
+		if resolved.ErrorCode == "" {
+			resolved.ErrorCode = StatusErrorCode(resolved.Code)
+			for _, sentinel := range sentinelErrors {
+				if errors.Is(resolved.Err, sentinel.err) && sentinel.status == resolved.Code {
+					resolved.ErrorCode = sentinel.code
+					break
+				}
+			}
+		}
+		if resolved.Message == "" && resolved.Err != nil {
+			resolved.Message = resolved.Err.Error()
+		}
+		return &resolved
+	}
+
+	for _, sentinel := range sentinelErrors {
+		if errors.Is(err, sentinel.err) {
+			return &AppError{Err: err, Message: sentinel.message, Code: sentinel.status, ErrorCode: sentinel.code}
+		}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if resolved := resolvePgError(pgErr); resolved != nil {
+			return resolved
+		}
+	}
+
+	return NewInternalServerError(err)
+}
+
+// pgKeyColumns extracts the columns from the detail of a constraint violation,
+// e.g. "Key (email)=(a@b.c) already exists."
+var pgKeyColumns = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// resolvePgError maps PostgreSQL constraint and data errors to client errors
+func resolvePgError(pgErr *pgconn.PgError) *AppError {
+	details := map[string]string{"constraint": pgErr.ConstraintName}
+	field := pgErr.ColumnName
+	if match := pgKeyColumns.FindStringSubmatch(pgErr.Detail); match != nil {
+		field = match[1]
+	}
+	if field != "" {
+		details["field"] = field
+	}
+
+	switch pgErr.Code {
+	case "23505": // unique_violation
+		message := "Resource already exists"
+		if field != "" {
+			message = "A resource with this " + field + " already exists"
+		}
+		return &AppError{Err: pgErr, Message: message, Code: 409, ErrorCode: CodeAlreadyExists, Details: details}
+
+	case "23503": // foreign_key_violation
+		// Deleting a row that is still referenced, as opposed to inserting a dangling reference
+		if strings.HasPrefix(pgErr.Message, "update or delete on") {
+			return &AppError{Err: pgErr, Message: "Resource is still referenced by other records", Code: 409, ErrorCode: CodeConflict, Details: details}
+		}
+		message := "Referenced resource does not exist"
+		if field != "" {
+			message = "Referenced " + field + " does not exist"
+		}
+		return &AppError{Err: pgErr, Message: message, Code: 422, ErrorCode: CodeInvalidReference, Details: details}
+
+	case "23502", "23514": // not_null_violation, check_violation
+		return &AppError{Err: pgErr, Message: "Value violates a data constraint", Code: 422, ErrorCode: CodeValidationFailed, Details: details}
+
+	case "22P02", "22003", "22001", "22007", "22008": // invalid text, out of range, too long, invalid date and time values
+		return &AppError{Err: pgErr, Message: "Invalid value", Code: 400, ErrorCode: CodeInvalidInput}
+
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return &AppError{Err: pgErr, Message: "Concurrent update, please retry", Code: 409, ErrorCode: CodeConflict}
+	}
+
+	return nil
+}
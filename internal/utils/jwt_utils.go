@@ -29,8 +29,9 @@ func InitJWTSecret() error {
 
 // Claims represents the JWT claims
 type Claims struct {
-	ID   int    `json:"id"`
-	Role string `json:"role"` // "admin" or "superadmin"
+	ID      int    `json:"id"`
+	Role    string `json:"role"`               // "admin", "superadmin" or "customer"
+	AdminID int    `json:"admin_id,omitempty"` // The admin a customer belongs to
 	jwt.RegisteredClaims
 }
 
@@ -84,6 +85,32 @@ func GenerateSuperAdminToken(superAdmin *models.SuperAdmin) (string, error) {
 	return tokenString, nil
 }
 
+// GenerateCustomerToken generates a new JWT token for the provided customer
+func GenerateCustomerToken(customer *models.Customer) (string, error) {
+	// Customers stay logged in on their phones, so their tokens live longer
+	claims := Claims{
+		ID:      customer.ID,
+		Role:    RoleCustomer,
+		AdminID: customer.AdminID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(30 * 24 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	// Create token with claims
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	// Generate the JWT
+	tokenString, err := token.SignedString(JWTSecret)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
 // ParseToken parses and validates a JWT token
 func ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		name string
+		cell interface{}
+		want string
+	}{
+		{"plain text", "Acme", "Acme"},
+		{"empty text", "", ""},
+		{"formula", "=HYPERLINK(\"x\")", "'=HYPERLINK(\"x\")"},
+		{"plus", "+1", "'+1"},
+		{"minus", "-1", "'-1"},
+		{"at", "@SUM(A1)", "'@SUM(A1)"},
+		{"tab", "\tx", "'\tx"},
+		{"negative number", Number("-12.50"), "-12.50"},
+		{"negative integer", int64(-3), "-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewCSVWriter(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WriteRow(tt.cell, "end"); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			record, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(buf.Bytes(), []byte("\ufeff")))).Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := record[0]
+			if got != tt.want {
+				t.Errorf("WriteRow(%q) wrote %q, want %q", tt.cell, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package utils
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError describes a field of a request body that failed validation
+type FieldError struct {
+	Field   string `json:"field"`   // JSON path of the field, e.g. contacts.phone
+	Rule    string `json:"rule"`    // Failed rule, e.g. required or oneof
+	Param   string `json:"param"`   // Parameter of the rule, e.g. the allowed values of oneof
+	Message string `json:"message"` // Human readable description
+}
+
+// ValidationError holds every field of a request body that failed validation
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error returns the messages of all failed fields
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap makes validation errors match ErrInvalidInput
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+// validationRule is one rule of a validate tag, e.g. min=1
+type validationRule struct {
+	name  string
+	param string
+}
+
+// validatedField is a struct field together with the rules of its validate tag
+type validatedField struct {
+	index     int
+	name      string
+	omitEmpty bool
+	rules     []validationRule
+	element   *validatedField // Rules after dive, applied to each element of a slice
+}
+
+// validatedFields caches the parsed validate tags per struct type
+var validatedFields sync.Map
+
+// Validate checks a struct, or a pointer to one, against the validate tags of its fields
+// and of nested structs. The tags use the usual validator syntax, e.g.
+// validate:"required,email" or validate:"omitempty,oneof=monthly yearly":
+//   - required: not the zero value, pointers not nil
+//   - omitempty: skip the other rules for the zero value
+//   - min, max, len: length of strings, slices and maps, value of numbers
+//   - gt, gte, lt, lte: value of numbers
+//   - oneof: one of the space separated values
+//   - email: a plain email address
+//   - dive: validate each element of a slice with the rules after dive, and element
+//     structs with their own tags. A second dive reaches the elements of nested slices.
+//
+// It returns a *ValidationError listing every failed field, or nil. An unknown rule is a
+// programming error and panics.
+func Validate(v interface{}) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []FieldError
+	validateStruct(value, "", &fields)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Fields: fields}
+}
+
+// validateStruct checks the fields of a struct value, prefixing field names with path
+func validateStruct(value reflect.Value, path string, errs *[]FieldError) {
+	for _, field := range structFields(value.Type()) {
+		fieldValue := value.Field(field.index)
+		name := path + field.name
+
+		if fieldFails(fieldValue, name, field, errs) {
+			continue
+		}
+
+		// Nested structs are validated with their own tags
+		for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+			fieldValue = fieldValue.Elem()
+		}
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type() != reflect.TypeOf(time.Time{}) {
+			validateStruct(fieldValue, name+".", errs)
+		}
+
+		if field.element != nil && (fieldValue.Kind() == reflect.Slice || fieldValue.Kind() == reflect.Array) {
+			validateElements(fieldValue, name, *field.element, errs)
+		}
+	}
+}
+
+// validateElements checks each element of a slice against the element rules, and element
+// structs against their own tags
+func validateElements(value reflect.Value, path string, element validatedField, errs *[]FieldError) {
+	for i := 0; i < value.Len(); i++ {
+		elementValue := value.Index(i)
+		name := path + "[" + strconv.Itoa(i) + "]"
+
+		if fieldFails(elementValue, name, element, errs) {
+			continue
+		}
+
+		for elementValue.Kind() == reflect.Ptr && !elementValue.IsNil() {
+			elementValue = elementValue.Elem()
+		}
+		if elementValue.Kind() == reflect.Struct && elementValue.Type() != reflect.TypeOf(time.Time{}) {
+			validateStruct(elementValue, name+".", errs)
+		}
+
+		if element.element != nil && (elementValue.Kind() == reflect.Slice || elementValue.Kind() == reflect.Array) {
+			validateElements(elementValue, name, *element.element, errs)
+		}
+	}
+}
+
+// fieldFails applies the rules of a field and records the first failure, if any
+func fieldFails(value reflect.Value, name string, field validatedField, errs *[]FieldError) bool {
+	if field.omitEmpty && value.IsZero() {
+		return false
+	}
+
+	for _, rule := range field.rules {
+		if rule.name == "required" {
+			if value.IsZero() {
+				*errs = append(*errs, FieldError{Field: name, Rule: rule.name, Message: name + " is required"})
+				return true
+			}
+			continue
+		}
+
+		// Remaining rules apply to the value a pointer points to
+		target := value
+		for target.Kind() == reflect.Ptr {
+			if target.IsNil() {
+				break
+			}
+			target = target.Elem()
+		}
+		if target.Kind() == reflect.Ptr {
+			continue
+		}
+
+		if message, ok := checkRule(target, rule); !ok {
+			*errs = append(*errs, FieldError{Field: name, Rule: rule.name, Param: rule.param, Message: name + " " + message})
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkRule applies a single rule to a value and returns the failure message
+func checkRule(value reflect.Value, rule validationRule) (string, bool) {
+	switch rule.name {
+	case "email":
+		address, err := mail.ParseAddress(value.String())
+		if err != nil || address.Address != value.String() {
+			return "must be a valid email address", false
+		}
+		return "", true
+
+	case "oneof":
+		allowed := strings.Fields(rule.param)
+		current := fmt.Sprint(value.Interface())
+		for _, option := range allowed {
+			if current == option {
+				return "", true
+			}
+		}
+		return "must be one of: " + strings.Join(allowed, ", "), false
+
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		return compareRule(value, rule)
+	}
+
+	panic("validation: unknown rule " + rule.name)
+}
+
+// compareRule applies a size or value comparison. Strings, slices and maps are compared
+// by length, numbers by value.
+func compareRule(value reflect.Value, rule validationRule) (string, bool) {
+	limit, err := strconv.ParseFloat(rule.param, 64)
+	if err != nil {
+		panic("validation: invalid parameter for " + rule.name + ": " + rule.param)
+	}
+
+	var actual float64
+	unit := ""
+	switch value.Kind() {
+	case reflect.String:
+		actual, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		actual, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	default:
+		panic("validation: " + rule.name + " does not apply to " + value.Kind().String())
+	}
+
+	switch rule.name {
+	case "min", "gte":
+		if actual < limit {
+			if unit != "" {
+				return "must have at least " + rule.param + unit, false
+			}
+			return "must be at least " + rule.param, false
+		}
+	case "max", "lte":
+		if actual > limit {
+			if unit != "" {
+				return "must have at most " + rule.param + unit, false
+			}
+			return "must be at most " + rule.param, false
+		}
+	case "len":
+		if actual != limit {
+			if unit != "" {
+				return "must have exactly " + rule.param + unit, false
+			}
+			return "must equal " + rule.param, false
+		}
+	case "gt":
+		if actual <= limit {
+			return "must be greater than " + rule.param, false
+		}
+	case "lt":
+		if actual >= limit {
+			return "must be less than " + rule.param, false
+		}
+	}
+
+	return "", true
+}
+
+// structFields returns the validated fields of a struct type: fields with a validate tag
+// and nested structs, named after their JSON keys
+func structFields(t reflect.Type) []validatedField {
+	if cached, ok := validatedFields.Load(t); ok {
+		return cached.([]validatedField)
+	}
+
+	var fields []validatedField
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		tag := structField.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+
+		fieldType := structField.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		nested := fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeOf(time.Time{})
+		if tag == "" && !nested {
+			continue
+		}
+
+		field := validatedField{index: i, name: jsonFieldName(structField)}
+		target := &field
+		for _, part := range strings.Split(tag, ",") {
+			switch part {
+			case "":
+			case "omitempty":
+				target.omitEmpty = true
+			case "dive":
+				target.element = &validatedField{}
+				target = target.element
+			default:
+				name, param, _ := strings.Cut(part, "=")
+				target.rules = append(target.rules, validationRule{name: name, param: param})
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	validatedFields.Store(t, fields)
+	return fields
+}
+
+// jsonFieldName returns the key of a struct field in JSON request bodies
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
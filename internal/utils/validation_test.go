@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type diveOption struct {
+	Name  string `json:"name" validate:"required,max=5"`
+	Price int64  `json:"price" validate:"min=0"`
+}
+
+type diveRequest struct {
+	Images   []string      `json:"images" validate:"max=2,dive,required,max=5"`
+	IDs      []int         `json:"ids" validate:"dive,gt=0"`
+	Options  []diveOption  `json:"options" validate:"required,dive"`
+	Pointers []*diveOption `json:"pointers" validate:"dive"`
+	Patch    *[]int        `json:"patch" validate:"omitempty,min=1,dive,gt=0"`
+	Optional []string      `json:"optional" validate:"dive,omitempty,len=2"`
+	Matrix   [][]int       `json:"matrix" validate:"max=2,dive,min=1,dive,lte=9"`
+}
+
+func TestValidateDive(t *testing.T) {
+	valid := func() diveRequest {
+		return diveRequest{
+			Images:  []string{"a.png"},
+			IDs:     []int{1, 2},
+			Options: []diveOption{{Name: "Small", Price: 0}},
+		}
+	}
+	ints := func(values ...int) *[]int {
+		return &values
+	}
+
+	type failure struct{ field, rule string }
+	tests := []struct {
+		name   string
+		modify func(r *diveRequest)
+		want   []failure
+	}{
+		{"valid", func(r *diveRequest) {}, nil},
+		{"empty slices skip element rules", func(r *diveRequest) { r.Images = nil; r.IDs = nil }, nil},
+		{"rule before dive applies to the slice", func(r *diveRequest) { r.Images = []string{"a", "b", "c"} }, []failure{{"images", "max"}}},
+		{"required element", func(r *diveRequest) { r.Images = []string{"a", ""} }, []failure{{"images[1]", "required"}}},
+		{"element max", func(r *diveRequest) { r.Images = []string{"toolong"} }, []failure{{"images[0]", "max"}}},
+		{"every element is reported", func(r *diveRequest) { r.IDs = []int{0, 3, -1} }, []failure{{"ids[0]", "gt"}, {"ids[2]", "gt"}}},
+		{"required slice", func(r *diveRequest) { r.Options = nil }, []failure{{"options", "required"}}},
+		{"element struct tags", func(r *diveRequest) { r.Options = append(r.Options, diveOption{Price: -1}) }, []failure{{"options[1].name", "required"}, {"options[1].price", "min"}}},
+		{"pointer element struct tags", func(r *diveRequest) { r.Pointers = []*diveOption{{Name: "toolong"}} }, []failure{{"pointers[0].name", "max"}}},
+		{"nil pointer element", func(r *diveRequest) { r.Pointers = []*diveOption{nil} }, nil},
+		{"omitted pointer to slice", func(r *diveRequest) { r.Patch = nil }, nil},
+		{"pointer to slice rule before dive", func(r *diveRequest) { r.Patch = ints() }, []failure{{"patch", "min"}}},
+		{"pointer to slice elements", func(r *diveRequest) { r.Patch = ints(1, 0) }, []failure{{"patch[1]", "gt"}}},
+		{"omitempty element", func(r *diveRequest) { r.Optional = []string{"", "ab", "abc"} }, []failure{{"optional[2]", "len"}}},
+		{"nested dive", func(r *diveRequest) { r.Matrix = [][]int{{1, 10}, {}} }, []failure{{"matrix[0][1]", "lte"}, {"matrix[1]", "min"}}},
+		{"nested dive rule before dive", func(r *diveRequest) { r.Matrix = [][]int{{1}, {2}, {3}} }, []failure{{"matrix", "max"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid()
+			tt.modify(&request)
+
+			err := Validate(&request)
+			var got []failure
+			if err != nil {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("Validate() error = %v, want a *ValidationError", err)
+				}
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("Validate() error doesn't match ErrInvalidInput")
+				}
+				for _, field := range validationErr.Fields {
+					got = append(got, failure{field.Field, field.Rule})
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() failed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+-- Make sure subscription columns exist on the admin table
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS subscription_tier_id INTEGER REFERENCES subscription_tier(id) ON DELETE SET NULL;
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS subscription_status VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS subscription_expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS is_access_restricted BOOLEAN NOT NULL DEFAULT false;
+
+-- Create payment_history table if it doesn't exist
+CREATE TABLE IF NOT EXISTS payment_history (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    amount DECIMAL(10, 2) NOT NULL,
+    payment_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    payment_method VARCHAR(50) NOT NULL,
+    transaction_id VARCHAR(255) NOT NULL DEFAULT '',
+    subscription_tier_id INTEGER REFERENCES subscription_tier(id) ON DELETE SET NULL,
+    period_start TIMESTAMP WITH TIME ZONE,
+    period_end TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    notes TEXT NOT NULL DEFAULT '',
+    verified_by INTEGER REFERENCES super_admin(id) ON DELETE SET NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_payment_history_timestamp ON payment_history;
+CREATE TRIGGER update_payment_history_timestamp BEFORE UPDATE ON payment_history
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX IF NOT EXISTS idx_payment_history_admin_id ON payment_history(admin_id);
+CREATE INDEX IF NOT EXISTS idx_payment_history_status ON payment_history(status);
+
+-- Number of billing months a payment pays for
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS months INTEGER NOT NULL DEFAULT 1;
+
+-- Part of the amount spent on upgrading the remainder of the current period
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS prorated_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- Payments whose amount doesn't match the tier price are flagged for manual review
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS needs_review BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS review_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_payment_history_needs_review ON payment_history(needs_review) WHERE needs_review = true;
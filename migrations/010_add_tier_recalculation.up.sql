@@ -0,0 +1,27 @@
+-- Tier change scheduled by usage recalculation, applied at the next billing period
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS pending_subscription_tier_id INTEGER REFERENCES subscription_tier(id) ON DELETE SET NULL;
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS pending_tier_effective_at TIMESTAMP WITH TIME ZONE;
+
+-- Admins pinned by a super admin keep their tier regardless of usage
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS subscription_tier_pinned BOOLEAN NOT NULL DEFAULT false;
+
+-- Create subscription_tier_change table to log every tier change
+CREATE TABLE IF NOT EXISTS subscription_tier_change (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    old_tier_id INTEGER REFERENCES subscription_tier(id) ON DELETE SET NULL,
+    new_tier_id INTEGER REFERENCES subscription_tier(id) ON DELETE SET NULL,
+    user_count INTEGER NOT NULL DEFAULT 0,
+    reason VARCHAR(20) NOT NULL,     -- usage, manual
+    status VARCHAR(20) NOT NULL,     -- scheduled, applied, cancelled
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    changed_by INTEGER REFERENCES super_admin(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create trigger for updating timestamp
+CREATE TRIGGER update_subscription_tier_change_timestamp BEFORE UPDATE ON subscription_tier_change
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_subscription_tier_change_admin_id ON subscription_tier_change(admin_id);
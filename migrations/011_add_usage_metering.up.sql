@@ -0,0 +1,31 @@
+-- Create device_activity table: one row per device per admin per day
+-- device_key is a SHA-256 hash of the device ID or FCM token sent by the app
+CREATE TABLE IF NOT EXISTS device_activity (
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    device_key VARCHAR(64) NOT NULL,
+    activity_date DATE NOT NULL,
+    CONSTRAINT device_activity_pkey PRIMARY KEY (admin_id, activity_date, device_key)
+);
+
+-- Create admin_active_users table for daily and monthly active-user rollups
+CREATE TABLE IF NOT EXISTS admin_active_users (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    period VARCHAR(10) NOT NULL,     -- day, month
+    period_start DATE NOT NULL,
+    active_users INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_admin_active_users_period UNIQUE (admin_id, period, period_start)
+);
+
+-- Create trigger for updating timestamp
+CREATE TRIGGER update_admin_active_users_timestamp BEFORE UPDATE ON admin_active_users
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+-- admin.users is now derived from monthly active users by the usage rollup task
+COMMENT ON COLUMN admin.users IS 'Monthly active users, derived from admin_active_users';
+
+-- Day from which device activity of an admin is metered: the deploy for existing admins,
+-- the sign-up for new ones. Monthly counts are only final for months metered in full.
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS usage_metered_since DATE NOT NULL DEFAULT CURRENT_DATE;
@@ -0,0 +1,23 @@
+-- Add configurable trial length to subscription tiers
+ALTER TABLE subscription_tier ADD COLUMN IF NOT EXISTS trial_days INTEGER NOT NULL DEFAULT 0;
+
+-- Existing paid tiers get a 14-day trial. Free tiers never expire, so they get none.
+UPDATE subscription_tier SET trial_days = 14 WHERE price > 0;
+
+-- Create subscription_trial table to remember which emails already had a trial.
+-- It has no foreign key on purpose: the record must survive deleting the admin.
+CREATE TABLE IF NOT EXISTS subscription_trial (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(100) NOT NULL,
+    admin_id INTEGER,
+    subscription_tier_id INTEGER,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT unique_subscription_trial_email UNIQUE (email)
+);
+
+-- Existing admins count as having used their trial
+INSERT INTO subscription_trial (email, admin_id, subscription_tier_id, started_at, ends_at)
+SELECT LOWER(TRIM(email)), id, subscription_tier_id, created_at, created_at
+FROM admin
+ON CONFLICT (email) DO NOTHING;
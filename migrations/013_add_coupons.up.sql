@@ -0,0 +1,45 @@
+-- Create coupon table
+CREATE TABLE IF NOT EXISTS coupon (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    discount_type VARCHAR(10) NOT NULL,          -- percent, fixed
+    discount_value DECIMAL(10, 2) NOT NULL,
+    duration_months INTEGER,                     -- NULL means the discount never ends
+    max_redemptions INTEGER,                     -- NULL means unlimited
+    redemption_count INTEGER NOT NULL DEFAULT 0,
+    valid_tier_ids INTEGER[] NOT NULL DEFAULT '{}', -- empty means every tier
+    valid_from TIMESTAMP WITH TIME ZONE,
+    valid_until TIMESTAMP WITH TIME ZONE,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_coupon_code UNIQUE (code)
+);
+
+-- Create coupon_redemption table
+CREATE TABLE IF NOT EXISTS coupon_redemption (
+    id SERIAL PRIMARY KEY,
+    coupon_id INTEGER NOT NULL REFERENCES coupon(id) ON DELETE CASCADE,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    months_remaining INTEGER,                    -- NULL means the discount never ends
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, completed
+    redeemed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_coupon_redemption_admin UNIQUE (coupon_id, admin_id)
+);
+
+-- Create triggers for updating timestamp
+CREATE TRIGGER update_coupon_timestamp BEFORE UPDATE ON coupon
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE TRIGGER update_coupon_redemption_timestamp BEFORE UPDATE ON coupon_redemption
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_coupon_redemption_admin_id ON coupon_redemption(admin_id);
+
+-- Track the coupon discount granted on each payment
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS coupon_redemption_id INTEGER REFERENCES coupon_redemption(id) ON DELETE SET NULL;
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS discounted_months INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,41 @@
+-- Money is stored as integer minor units (tiyin, cents) together with an ISO 4217 currency code.
+-- Existing prices and payments were entered in US dollars.
+
+-- Subscription tier prices
+ALTER TABLE subscription_tier ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100);
+ALTER TABLE subscription_tier ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Currency the admin is billed in
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS billing_currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Payment amounts
+ALTER TABLE payment_history ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+ALTER TABLE payment_history ALTER COLUMN prorated_amount TYPE BIGINT USING ROUND(prorated_amount * 100);
+ALTER TABLE payment_history ALTER COLUMN discount_amount TYPE BIGINT USING ROUND(discount_amount * 100);
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Fixed coupon discounts move to integer minor units of the coupon currency, percent
+-- discounts to a percentage column of their own
+ALTER TABLE coupon ADD COLUMN IF NOT EXISTS discount_percent DECIMAL(5, 2) NOT NULL DEFAULT 0;
+ALTER TABLE coupon ADD COLUMN IF NOT EXISTS discount_amount BIGINT NOT NULL DEFAULT 0;
+UPDATE coupon SET discount_percent = discount_value WHERE discount_type = 'percent';
+UPDATE coupon SET discount_amount = ROUND(discount_value * 100) WHERE discount_type = 'fixed';
+ALTER TABLE coupon DROP COLUMN discount_value;
+ALTER TABLE coupon ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Manually maintained exchange rates used for reporting and price conversion.
+-- One unit of base_currency is worth rate units of quote_currency.
+CREATE TABLE IF NOT EXISTS exchange_rate (
+    id SERIAL PRIMARY KEY,
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(20, 8) NOT NULL CHECK (rate > 0),
+    effective_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    created_by INTEGER REFERENCES super_admin(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_exchange_rate_date UNIQUE (base_currency, quote_currency, effective_date)
+);
+
+CREATE TRIGGER update_exchange_rate_timestamp BEFORE UPDATE ON exchange_rate
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
@@ -0,0 +1,21 @@
+-- Interval-specific tier prices, e.g. a discounted yearly price.
+-- subscription_tier.price stays the monthly price; intervals without a row cost the
+-- monthly price times the number of months.
+CREATE TABLE IF NOT EXISTS subscription_tier_price (
+    id SERIAL PRIMARY KEY,
+    subscription_tier_id INTEGER NOT NULL REFERENCES subscription_tier(id) ON DELETE CASCADE,
+    billing_interval VARCHAR(20) NOT NULL,        -- monthly, quarterly, yearly
+    price BIGINT NOT NULL CHECK (price >= 0),     -- minor units of the tier currency
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_subscription_tier_interval UNIQUE (subscription_tier_id, billing_interval)
+);
+
+CREATE TRIGGER update_subscription_tier_price_timestamp BEFORE UPDATE ON subscription_tier_price
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+-- Interval the admin is billed on
+ALTER TABLE admin ADD COLUMN IF NOT EXISTS billing_interval VARCHAR(20) NOT NULL DEFAULT 'monthly';
+
+-- Interval a payment was made for
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS billing_interval VARCHAR(20) NOT NULL DEFAULT 'monthly';
@@ -0,0 +1,18 @@
+-- Create payment_attachment table for proofs of manual payments, e.g. receipt photos or PDFs
+CREATE TABLE IF NOT EXISTS payment_attachment (
+    id SERIAL PRIMARY KEY,
+    payment_id INTEGER NOT NULL REFERENCES payment_history(id) ON DELETE CASCADE,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,              -- stored file name, never served publicly
+    original_name VARCHAR(255) NOT NULL DEFAULT '',
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create trigger for updating timestamp
+CREATE TRIGGER update_payment_attachment_timestamp BEFORE UPDATE ON payment_attachment
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_payment_attachment_payment_id ON payment_attachment(payment_id);
@@ -0,0 +1,48 @@
+-- Part of a verified payment that has been refunded
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS refunded_amount BIGINT NOT NULL DEFAULT 0;
+
+-- Create payment_refund table for refunds and reversals of verified payments
+CREATE TABLE IF NOT EXISTS payment_refund (
+    id SERIAL PRIMARY KEY,
+    payment_id INTEGER NOT NULL REFERENCES payment_history(id) ON DELETE CASCADE,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,                   -- refund, reversal
+    amount BIGINT NOT NULL CHECK (amount > 0),   -- minor units of currency
+    currency VARCHAR(3) NOT NULL,
+    reason TEXT NOT NULL,
+    removed_seconds BIGINT NOT NULL DEFAULT 0,   -- subscription time taken back
+    new_expires_at TIMESTAMP WITH TIME ZONE,
+    created_by INTEGER REFERENCES super_admin(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_payment_refund_timestamp BEFORE UPDATE ON payment_refund
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_payment_refund_payment_id ON payment_refund(payment_id);
+
+-- Create payment_audit_log table recording every change of a payment
+CREATE TABLE IF NOT EXISTS payment_audit_log (
+    id SERIAL PRIMARY KEY,
+    payment_id INTEGER NOT NULL REFERENCES payment_history(id) ON DELETE CASCADE,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,                 -- recorded, verified, rejected, refunded, reversed
+    actor_id INTEGER,                            -- admin for recorded, super admin otherwise
+    amount BIGINT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_audit_log_payment_id ON payment_audit_log(payment_id);
+
+-- Backfill the trail of existing payments
+INSERT INTO payment_audit_log (payment_id, admin_id, action, actor_id, amount, currency, details, created_at)
+SELECT id, admin_id, 'recorded', admin_id, amount, currency, '', created_at
+FROM payment_history;
+
+INSERT INTO payment_audit_log (payment_id, admin_id, action, actor_id, amount, currency, details, created_at)
+SELECT id, admin_id, status, verified_by, amount, currency, notes, verified_at
+FROM payment_history
+WHERE status IN ('verified', 'rejected') AND verified_at IS NOT NULL;
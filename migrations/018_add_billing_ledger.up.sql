@@ -0,0 +1,99 @@
+-- Top-up payments add to the admin's prepaid balance instead of paying for a period
+ALTER TABLE payment_history ADD COLUMN IF NOT EXISTS is_top_up BOOLEAN NOT NULL DEFAULT false;
+
+-- Create ledger_transaction table, one row per business event of an admin's account
+CREATE TABLE IF NOT EXISTS ledger_transaction (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,                   -- payment, charge, credit, refund, adjustment
+    currency VARCHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),   -- minor units of currency
+    description TEXT NOT NULL DEFAULT '',
+    payment_id INTEGER REFERENCES payment_history(id) ON DELETE SET NULL,
+    refund_id INTEGER REFERENCES payment_refund(id) ON DELETE SET NULL,
+    created_by INTEGER REFERENCES super_admin(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ledger_transaction_admin_id ON ledger_transaction(admin_id, created_at);
+
+-- Create ledger_entry table with the debit and credit lines of each transaction
+CREATE TABLE IF NOT EXISTS ledger_entry (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES ledger_transaction(id) ON DELETE CASCADE,
+    account VARCHAR(30) NOT NULL,                -- admin_balance, cash, revenue, credits, adjustments
+    debit BIGINT NOT NULL DEFAULT 0,
+    credit BIGINT NOT NULL DEFAULT 0,
+    CONSTRAINT ledger_entry_one_side CHECK (debit >= 0 AND credit >= 0 AND (debit = 0) <> (credit = 0))
+);
+
+CREATE INDEX idx_ledger_entry_transaction_id ON ledger_entry(transaction_id);
+
+-- Every transaction must balance once its entries are written
+CREATE OR REPLACE FUNCTION check_ledger_balanced()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT COALESCE(SUM(debit), 0) <> COALESCE(SUM(credit), 0)
+        FROM ledger_entry WHERE transaction_id = NEW.transaction_id) THEN
+        RAISE EXCEPTION 'ledger transaction % is not balanced', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entry_balanced AFTER INSERT OR UPDATE ON ledger_entry
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE PROCEDURE check_ledger_balanced();
+
+-- Backfill verified payments: the money received and the period it was spent on
+WITH posted AS (
+    INSERT INTO ledger_transaction (admin_id, type, currency, amount, description, payment_id, created_by, created_at)
+    SELECT admin_id, 'payment', currency, amount, 'Payment #' || id, id, verified_by, verified_at
+    FROM payment_history
+    WHERE status IN ('verified', 'refunded') AND amount > 0 AND verified_at IS NOT NULL
+    RETURNING id, amount
+)
+INSERT INTO ledger_entry (transaction_id, account, debit, credit)
+SELECT id, 'cash', amount, 0 FROM posted
+UNION ALL
+SELECT id, 'admin_balance', 0, amount FROM posted;
+
+WITH posted AS (
+    INSERT INTO ledger_transaction (admin_id, type, currency, amount, description, payment_id, created_by, created_at)
+    SELECT admin_id, 'charge', currency, amount, 'Subscription paid by payment #' || id, id, verified_by, verified_at
+    FROM payment_history
+    WHERE status IN ('verified', 'refunded') AND amount > 0 AND verified_at IS NOT NULL
+    RETURNING id, amount
+)
+INSERT INTO ledger_entry (transaction_id, account, debit, credit)
+SELECT id, 'admin_balance', amount, 0 FROM posted
+UNION ALL
+SELECT id, 'revenue', 0, amount FROM posted;
+
+-- Backfill refunds: the charge given back and the money paid out
+WITH posted AS (
+    INSERT INTO ledger_transaction (admin_id, type, currency, amount, description, payment_id, refund_id, created_by, created_at)
+    SELECT r.admin_id, 'credit', r.currency, r.amount, 'Subscription refunded for payment #' || r.payment_id,
+           r.payment_id, r.id, r.created_by, r.created_at
+    FROM payment_refund r
+    JOIN payment_history p ON p.id = r.payment_id
+    WHERE p.status IN ('verified', 'refunded')
+    RETURNING id, amount
+)
+INSERT INTO ledger_entry (transaction_id, account, debit, credit)
+SELECT id, 'revenue', amount, 0 FROM posted
+UNION ALL
+SELECT id, 'admin_balance', 0, amount FROM posted;
+
+WITH posted AS (
+    INSERT INTO ledger_transaction (admin_id, type, currency, amount, description, payment_id, refund_id, created_by, created_at)
+    SELECT r.admin_id, 'refund', r.currency, r.amount, r.reason, r.payment_id, r.id, r.created_by, r.created_at
+    FROM payment_refund r
+    JOIN payment_history p ON p.id = r.payment_id
+    WHERE p.status IN ('verified', 'refunded')
+    RETURNING id, amount
+)
+INSERT INTO ledger_entry (transaction_id, account, debit, credit)
+SELECT id, 'admin_balance', amount, 0 FROM posted
+UNION ALL
+SELECT id, 'cash', 0, amount FROM posted;
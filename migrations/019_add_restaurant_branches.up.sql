@@ -0,0 +1,31 @@
+-- Create restaurant_branch table for the locations of an admin's restaurant. The
+-- restaurant row stays the admin's profile (description, social media), while name,
+-- address, phone and coordinates are kept per branch.
+CREATE TABLE IF NOT EXISTS restaurant_branch (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    photos TEXT[] NOT NULL DEFAULT '{}',         -- image URLs
+    status VARCHAR(20) NOT NULL DEFAULT 'active' -- active, temporarily_closed, inactive
+        CHECK (status IN ('active', 'temporarily_closed', 'inactive')),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT restaurant_branch_coordinates CHECK ((latitude IS NULL) = (longitude IS NULL))
+);
+
+CREATE TRIGGER update_restaurant_branch_timestamp BEFORE UPDATE ON restaurant_branch
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_restaurant_branch_admin_id ON restaurant_branch(admin_id);
+
+-- Every existing restaurant becomes the first branch of its admin
+INSERT INTO restaurant_branch (admin_id, name, address, phone, created_at)
+SELECT r.admin_id, a.company_name, COALESCE(r.contacts->>'location', ''),
+       COALESCE(r.contacts->>'phone', ''), r.created_at
+FROM restaurant r
+JOIN admin a ON a.id = r.admin_id;
@@ -0,0 +1,8 @@
+-- Opening hours of branches, evaluated in the branch's time zone
+ALTER TABLE restaurant_branch ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- Weekly intervals per weekday, e.g. {"monday": [{"opens": "09:00", "closes": "02:00"}]}
+ALTER TABLE restaurant_branch ADD COLUMN IF NOT EXISTS opening_hours JSONB NOT NULL DEFAULT '{}';
+
+-- Dates that replace the weekly hours, e.g. [{"date": "2026-12-31", "name": "New Year", "intervals": []}]
+ALTER TABLE restaurant_branch ADD COLUMN IF NOT EXISTS special_days JSONB NOT NULL DEFAULT '[]';
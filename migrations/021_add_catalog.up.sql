@@ -0,0 +1,79 @@
+-- Create catalog_category table for the menu categories of an admin
+CREATE TABLE IF NOT EXISTS catalog_category (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    image VARCHAR(255) NOT NULL DEFAULT '',
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_catalog_category_timestamp BEFORE UPDATE ON catalog_category
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_catalog_category_admin_id ON catalog_category(admin_id);
+
+-- Create catalog_product table. A category can't be deleted while it has products.
+CREATE TABLE IF NOT EXISTS catalog_product (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES catalog_category(id),
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price BIGINT NOT NULL CHECK (price >= 0),    -- minor units of currency
+    currency VARCHAR(3) NOT NULL,
+    images TEXT[] NOT NULL DEFAULT '{}',         -- filenames from the image service
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,  -- false while sold out
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,     -- false hides the product from the menu
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_catalog_product_timestamp BEFORE UPDATE ON catalog_product
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_catalog_product_admin_id ON catalog_product(admin_id);
+CREATE INDEX idx_catalog_product_category_id ON catalog_product(category_id);
+
+-- Create catalog_modifier_group table for choices like size or extras
+CREATE TABLE IF NOT EXISTS catalog_modifier_group (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    min_select INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INTEGER NOT NULL DEFAULT 1 CHECK (max_select >= 1 AND max_select >= min_select),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_catalog_modifier_group_timestamp BEFORE UPDATE ON catalog_modifier_group
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_catalog_modifier_group_admin_id ON catalog_modifier_group(admin_id);
+
+-- Create catalog_modifier_option table for the options of a modifier group
+CREATE TABLE IF NOT EXISTS catalog_modifier_option (
+    id SERIAL PRIMARY KEY,
+    group_id INTEGER NOT NULL REFERENCES catalog_modifier_group(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    price BIGINT NOT NULL DEFAULT 0 CHECK (price >= 0), -- added to the product price
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_catalog_modifier_option_group_id ON catalog_modifier_option(group_id);
+
+-- Create catalog_product_modifier_group table linking products to their modifier groups
+CREATE TABLE IF NOT EXISTS catalog_product_modifier_group (
+    product_id INTEGER NOT NULL REFERENCES catalog_product(id) ON DELETE CASCADE,
+    group_id INTEGER NOT NULL REFERENCES catalog_modifier_group(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (product_id, group_id)
+);
+
+CREATE INDEX idx_catalog_product_modifier_group_group_id ON catalog_product_modifier_group(group_id);
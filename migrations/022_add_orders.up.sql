@@ -0,0 +1,69 @@
+-- Create orders table for orders placed from the mobile app
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    branch_id INTEGER REFERENCES restaurant_branch(id) ON DELETE SET NULL,
+    tracking_token VARCHAR(64) NOT NULL UNIQUE,  -- lets the customer track the order
+    type VARCHAR(20) NOT NULL CHECK (type IN ('pickup', 'delivery')),
+    status VARCHAR(20) NOT NULL DEFAULT 'new',   -- new, accepted, cooking, ready, out_for_delivery, completed, cancelled
+    customer_name VARCHAR(255) NOT NULL DEFAULT '',
+    customer_phone VARCHAR(50) NOT NULL,
+    delivery_address TEXT NOT NULL DEFAULT '',
+    delivery_latitude DOUBLE PRECISION,
+    delivery_longitude DOUBLE PRECISION,
+    notes TEXT NOT NULL DEFAULT '',
+    subtotal BIGINT NOT NULL CHECK (subtotal >= 0),  -- minor units of currency
+    delivery_fee BIGINT NOT NULL DEFAULT 0 CHECK (delivery_fee >= 0),
+    discount BIGINT NOT NULL DEFAULT 0 CHECK (discount >= 0),
+    total BIGINT NOT NULL CHECK (total >= 0),
+    currency VARCHAR(3) NOT NULL,
+    cancel_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_orders_timestamp BEFORE UPDATE ON orders
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_orders_admin_id_created_at ON orders(admin_id, created_at);
+CREATE INDEX idx_orders_admin_id_status ON orders(admin_id, status);
+
+-- Create order_item table. Names and prices are copied from the catalog.
+CREATE TABLE IF NOT EXISTS order_item (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id INTEGER REFERENCES catalog_product(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    unit_price BIGINT NOT NULL,                  -- product price with its modifiers
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    total BIGINT NOT NULL,
+    notes TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_order_item_order_id ON order_item(order_id);
+
+-- Create order_item_modifier table for the modifier options selected for an item
+CREATE TABLE IF NOT EXISTS order_item_modifier (
+    id SERIAL PRIMARY KEY,
+    order_item_id INTEGER NOT NULL REFERENCES order_item(id) ON DELETE CASCADE,
+    option_id INTEGER REFERENCES catalog_modifier_option(id) ON DELETE SET NULL,
+    group_name VARCHAR(255) NOT NULL,
+    option_name VARCHAR(255) NOT NULL,
+    price BIGINT NOT NULL
+);
+
+CREATE INDEX idx_order_item_modifier_order_item_id ON order_item_modifier(order_item_id);
+
+-- Create order_status_history table recording every status change of an order
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL DEFAULT '',
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(20) NOT NULL,                  -- admin, customer
+    actor_id INTEGER,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_status_history_order_id ON order_status_history(order_id);
@@ -0,0 +1,23 @@
+-- Create delivery_zone table for the areas an admin delivers to and their fees
+CREATE TABLE IF NOT EXISTS delivery_zone (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    branch_id INTEGER REFERENCES restaurant_branch(id) ON DELETE SET NULL,  -- distance fees are measured from it
+    name VARCHAR(255) NOT NULL,
+    polygon JSONB NOT NULL,                      -- [{"lat": ..., "lng": ...}, ...] vertices
+    min_order_amount BIGINT NOT NULL DEFAULT 0 CHECK (min_order_amount >= 0),  -- minor units of currency
+    currency VARCHAR(3) NOT NULL,
+    fee_type VARCHAR(20) NOT NULL CHECK (fee_type IN ('flat', 'distance', 'order_total')),
+    fee BIGINT NOT NULL DEFAULT 0 CHECK (fee >= 0),  -- flat fee
+    fee_tiers JSONB NOT NULL DEFAULT '[]',       -- [{"from": ..., "fee": ...}, ...] by meters or order total
+    eta_minutes INTEGER NOT NULL CHECK (eta_minutes > 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INTEGER NOT NULL DEFAULT 0,       -- the first matching zone applies where zones overlap
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_delivery_zone_timestamp BEFORE UPDATE ON delivery_zone
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_delivery_zone_admin_id ON delivery_zone(admin_id);
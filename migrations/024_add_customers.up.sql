@@ -0,0 +1,53 @@
+-- Create customer table for the app users of an admin's restaurant, who log in by phone
+CREATE TABLE IF NOT EXISTS customer (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL,                  -- international format, e.g. +998901234567
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    marketing_sms BOOLEAN NOT NULL DEFAULT FALSE,   -- consents to promotional SMS
+    marketing_push BOOLEAN NOT NULL DEFAULT FALSE,  -- consents to promotional push notifications
+    terms_accepted_at TIMESTAMP WITH TIME ZONE,
+    consent_updated_at TIMESTAMP WITH TIME ZONE,
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (admin_id, phone)
+);
+
+CREATE TRIGGER update_customer_timestamp BEFORE UPDATE ON customer
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+-- Create customer_address table for the saved delivery addresses of a customer
+CREATE TABLE IF NOT EXISTS customer_address (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+    label VARCHAR(50) NOT NULL DEFAULT '',       -- e.g. Home, Work
+    address TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',            -- entrance, floor, apartment
+    latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT customer_address_coordinates CHECK ((latitude IS NULL) = (longitude IS NULL))
+);
+
+CREATE TRIGGER update_customer_address_timestamp BEFORE UPDATE ON customer_address
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+CREATE INDEX idx_customer_address_customer_id ON customer_address(customer_id);
+
+-- Create customer_otp table for the one-time login codes sent by SMS
+CREATE TABLE IF NOT EXISTS customer_otp (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL,
+    code_hash VARCHAR(255) NOT NULL,             -- bcrypt hash, the code itself is never stored
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    consumed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_customer_otp_admin_id_phone ON customer_otp(admin_id, phone, created_at);
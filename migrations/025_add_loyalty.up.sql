@@ -0,0 +1,53 @@
+-- Link orders to the customers who placed them while logged in, and record the loyalty
+-- points spent on them
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_id INTEGER REFERENCES customer(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS points_redeemed BIGINT NOT NULL DEFAULT 0 CHECK (points_redeemed >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders(customer_id);
+
+-- Link FCM tokens to the customer logged in on the device, for personal push notifications
+ALTER TABLE fcm_token ADD COLUMN IF NOT EXISTS customer_id INTEGER REFERENCES customer(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_fcm_token_customer_id ON fcm_token(customer_id);
+
+-- Create loyalty_program table for the earn and burn rules of an admin's loyalty program
+CREATE TABLE IF NOT EXISTS loyalty_program (
+    admin_id INTEGER PRIMARY KEY REFERENCES admin(id) ON DELETE CASCADE,
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,
+    currency VARCHAR(3) NOT NULL,
+    earn_basis_points INTEGER NOT NULL DEFAULT 0 CHECK (earn_basis_points BETWEEN 0 AND 10000), -- 250 = 2.5% of the order total
+    visit_points BIGINT NOT NULL DEFAULT 0 CHECK (visit_points >= 0),           -- earned per completed order
+    min_order_amount BIGINT NOT NULL DEFAULT 0 CHECK (min_order_amount >= 0),   -- orders below earn nothing
+    point_value BIGINT NOT NULL CHECK (point_value > 0),                         -- minor units a point is worth when spent
+    redeem_max_percent INTEGER NOT NULL DEFAULT 100 CHECK (redeem_max_percent BETWEEN 1 AND 100), -- of the order subtotal
+    redeem_max_points BIGINT NOT NULL DEFAULT 0 CHECK (redeem_max_points >= 0), -- per order, 0 means no cap
+    min_redeem_points BIGINT NOT NULL DEFAULT 0 CHECK (min_redeem_points >= 0),
+    expiry_days INTEGER NOT NULL DEFAULT 0 CHECK (expiry_days >= 0),             -- 0 means points never expire
+    expiry_notice_days INTEGER NOT NULL DEFAULT 0 CHECK (expiry_notice_days >= 0), -- 0 means no reminder
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_loyalty_program_timestamp BEFORE UPDATE ON loyalty_program
+FOR EACH ROW EXECUTE PROCEDURE update_timestamp();
+
+-- Create loyalty_transaction table, the points ledger. Positive entries are lots that are
+-- spent oldest expiry first; remaining is what is left of a lot.
+CREATE TABLE IF NOT EXISTS loyalty_transaction (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admin(id) ON DELETE CASCADE,
+    customer_id INTEGER NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+    order_id INTEGER REFERENCES orders(id) ON DELETE SET NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('earn', 'redeem', 'refund', 'expire')),
+    points BIGINT NOT NULL,                      -- positive for earn and refund, negative for redeem and expire
+    remaining BIGINT NOT NULL DEFAULT 0 CHECK (remaining >= 0),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    expiry_notified_at TIMESTAMP WITH TIME ZONE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (order_id, type)                      -- an order earns, spends and refunds points once
+);
+
+CREATE INDEX idx_loyalty_transaction_customer_id ON loyalty_transaction(customer_id, created_at);
+CREATE INDEX idx_loyalty_transaction_admin_id ON loyalty_transaction(admin_id, created_at);
+CREATE INDEX idx_loyalty_transaction_open_lots ON loyalty_transaction(expires_at) WHERE remaining > 0;
@@ -0,0 +1,28 @@
+-- Create invoice view: every payment that was verified is invoiced, in the currency it was
+-- paid in. Refunds and reversals stay on the invoice as the refunded amount.
+CREATE OR REPLACE VIEW invoice AS
+SELECT
+    p.id,
+    'INV-' || to_char(p.verified_at AT TIME ZONE 'UTC', 'YYYY') || '-' || lpad(p.id::TEXT, 6, '0') AS number,
+    p.id AS payment_id,
+    p.admin_id,
+    COALESCE(a.company_name, '') AS company_name,
+    p.verified_at AS issued_at,
+    p.currency,
+    COALESCE(st.name, '') AS tier_name,
+    p.billing_interval,
+    p.months,
+    p.period_start,
+    p.period_end,
+    p.is_top_up,
+    p.amount + p.discount_amount AS subtotal,    -- before the coupon discount
+    p.discount_amount,
+    p.prorated_amount,                           -- upgrade proration included in the total
+    p.amount AS total,
+    p.refunded_amount,
+    p.amount - p.refunded_amount AS net_amount,
+    p.status
+FROM payment_history p
+LEFT JOIN admin a ON a.id = p.admin_id
+LEFT JOIN subscription_tier st ON st.id = p.subscription_tier_id
+WHERE p.status IN ('verified', 'refunded', 'reversed') AND p.verified_at IS NOT NULL;